@@ -48,6 +48,10 @@ def generate_config_file(
 
     # apiserver.conf
     apiserver_file_template_str = '''
+[apikey]
+required = false
+defaultQPS = 100
+defaultBurst = 200
     '''
 
     template = FileTemplate(apiserver_file_template_str)
@@ -0,0 +1,83 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apidoc
+
+import (
+	"context"
+	"fmt"
+
+	"configcenter/src/apimachinery/discovery"
+	"configcenter/src/apimachinery/rest"
+	"configcenter/src/apimachinery/util"
+	"configcenter/src/common/openapi"
+	"configcenter/src/common/types"
+)
+
+type APIDocInterface interface {
+	// GetDocument get the OpenAPI document of the module
+	GetDocument(ctx context.Context, moduleName string) (*openapi.Document, error)
+}
+
+func NewAPIDocClient(capability *util.Capability, disc discovery.DiscoveryInterface) APIDocInterface {
+	return &apiDoc{
+		capability: capability,
+		disc:       disc,
+	}
+}
+
+type apiDoc struct {
+	capability *util.Capability
+	disc       discovery.DiscoveryInterface
+}
+
+func (a *apiDoc) GetDocument(ctx context.Context, moduleName string) (*openapi.Document, error) {
+	capability := *a.capability
+	switch moduleName {
+	case types.CC_MODULE_DATACOLLECTION:
+		capability.Discover = a.disc.DataCollect()
+
+	case types.CC_MODULE_HOST:
+		capability.Discover = a.disc.HostServer()
+
+	case types.CC_MODULE_MIGRATE:
+		capability.Discover = a.disc.MigrateServer()
+
+	case types.CC_MODULE_PROC:
+		capability.Discover = a.disc.ProcServer()
+
+	case types.CC_MODULE_TOPO:
+		capability.Discover = a.disc.TopoServer()
+
+	case types.CC_MODULE_EVENTSERVER:
+		capability.Discover = a.disc.EventServer()
+
+	case types.CC_MODULE_CORESERVICE:
+		capability.Discover = a.disc.CoreService()
+
+	default:
+		return nil, fmt.Errorf("unsupported api document module: %s", moduleName)
+	}
+
+	doc := new(openapi.Document)
+	client := rest.NewRESTClient(&capability, "/")
+	err := client.Get().
+		WithContext(ctx).
+		SubResource(openapi.DocumentPath).
+		Body(nil).
+		Do().
+		Into(doc)
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
@@ -14,6 +14,7 @@ package apimachinery
 
 import (
 	"configcenter/src/apimachinery/adminserver"
+	"configcenter/src/apimachinery/apidoc"
 	"configcenter/src/apimachinery/apiserver"
 	"configcenter/src/apimachinery/coreservice"
 	"configcenter/src/apimachinery/discovery"
@@ -37,6 +38,7 @@ type ClientSetInterface interface {
 	CoreService() coreservice.CoreServiceClientInterface
 
 	Healthz() healthz.HealthzInterface
+	APIDoc() apidoc.APIDocInterface
 }
 
 func NewApiMachinery(c *util.APIMachineryConfig, discover discovery.DiscoveryInterface) (ClientSetInterface, error) {
@@ -147,6 +149,14 @@ func (cs *ClientSet) Healthz() healthz.HealthzInterface {
 	return healthz.NewHealthzClient(c, cs.discover)
 }
 
+func (cs *ClientSet) APIDoc() apidoc.APIDocInterface {
+	c := &util.Capability{
+		Client:   cs.client,
+		Throttle: cs.throttle,
+	}
+	return apidoc.NewAPIDocClient(c, cs.discover)
+}
+
 func (cs *ClientSet) CoreService() coreservice.CoreServiceClientInterface {
 	c := &util.Capability{
 		Client:   cs.client,
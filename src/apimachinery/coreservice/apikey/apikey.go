@@ -0,0 +1,60 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"configcenter/src/common/metadata"
+)
+
+func (t *apiKey) CreateAPIKey(ctx context.Context, h http.Header, dat *metadata.APIKey) (resp *metadata.APIKeyResult, err error) {
+	subPath := "/create/apikey"
+	resp = new(metadata.APIKeyResult)
+	err = t.client.Post().
+		WithContext(ctx).
+		Body(dat).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (t *apiKey) SearchAPIKey(ctx context.Context, h http.Header, dat *metadata.SearchAPIKeyRequest) (resp *metadata.SearchAPIKeyResult, err error) {
+	subPath := "/read/apikey"
+	resp = new(metadata.SearchAPIKeyResult)
+	err = t.client.Post().
+		WithContext(ctx).
+		Body(dat).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (t *apiKey) DeleteAPIKey(ctx context.Context, h http.Header, id int64) (resp *metadata.BaseResp, err error) {
+	subPath := fmt.Sprintf("/delete/apikey/%d", id)
+	resp = new(metadata.BaseResp)
+	err = t.client.Delete().
+		WithContext(ctx).
+		Body(nil).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
@@ -15,6 +15,7 @@ package coreservice
 import (
 	"fmt"
 
+	"configcenter/src/apimachinery/coreservice/apikey"
 	"configcenter/src/apimachinery/coreservice/association"
 	"configcenter/src/apimachinery/coreservice/auditlog"
 	"configcenter/src/apimachinery/coreservice/cloudsync"
@@ -25,6 +26,7 @@ import (
 	"configcenter/src/apimachinery/coreservice/model"
 	"configcenter/src/apimachinery/coreservice/privilege"
 	"configcenter/src/apimachinery/coreservice/process"
+	"configcenter/src/apimachinery/coreservice/recyclebin"
 	"configcenter/src/apimachinery/coreservice/synchronize"
 	"configcenter/src/apimachinery/coreservice/topographics"
 	"configcenter/src/apimachinery/rest"
@@ -44,6 +46,8 @@ type CoreServiceClientInterface interface {
 	Label() label.LabelInterface
 	Privilege() privilege.PrivilegeInterface
 	TopoGraphics() topographics.TopoGraphicsInterface
+	APIKey() apikey.APIKeyInterface
+	RecycleBin() recyclebin.RecycleBinInterface
 }
 
 func NewCoreServiceClient(c *util.Capability, version string) CoreServiceClientInterface {
@@ -104,3 +108,11 @@ func (c *coreService) Privilege() privilege.PrivilegeInterface {
 func (c *coreService) TopoGraphics() topographics.TopoGraphicsInterface {
 	return topographics.NewTopoGraphicsInterface(c.restCli)
 }
+
+func (c *coreService) APIKey() apikey.APIKeyInterface {
+	return apikey.NewAPIKeyInterface(c.restCli)
+}
+
+func (c *coreService) RecycleBin() recyclebin.RecycleBinInterface {
+	return recyclebin.NewRecycleBinInterface(c.restCli)
+}
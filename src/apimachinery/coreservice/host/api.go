@@ -283,6 +283,20 @@ func (h *host) GetUserConfigDetail(ctx context.Context, businessID string, id st
 	return
 }
 
+func (h *host) ListDynamicGroupInstances(ctx context.Context, header http.Header, option *metadata.ListDynamicGroupInstances) (resp *metadata.ListDynamicGroupInstancesResult, err error) {
+	resp = new(metadata.ListDynamicGroupInstancesResult)
+	subPath := "/findmany/userapi/instances"
+
+	err = h.client.Post().
+		WithContext(ctx).
+		Body(option).
+		SubResource(subPath).
+		WithHeaders(header).
+		Do().
+		Into(resp)
+	return
+}
+
 func (h *host) AddUserCustom(ctx context.Context, user string, header http.Header, dat map[string]interface{}) (resp *metadata.BaseResp, err error) {
 	resp = new(metadata.BaseResp)
 	subPath := fmt.Sprintf("/create/usercustom/%s", user)
@@ -90,6 +90,20 @@ func (inst *instance) ReadInstance(ctx context.Context, h http.Header, objID str
 	return
 }
 
+func (inst *instance) StatisticsInstance(ctx context.Context, h http.Header, objID string, input *metadata.StatisticsOption) (resp *metadata.StatisticsInstanceResult, err error) {
+	resp = new(metadata.StatisticsInstanceResult)
+	subPath := fmt.Sprintf("/read/model/%s/instances/statistics", objID)
+
+	err = inst.client.Post().
+		WithContext(ctx).
+		Body(input).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
 func (inst *instance) DeleteInstance(ctx context.Context, h http.Header, objID string, input *metadata.DeleteOption) (resp *metadata.DeletedOptionResult, err error) {
 	resp = new(metadata.DeletedOptionResult)
 	subPath := fmt.Sprintf("/delete/model/%s/instance", objID)
@@ -482,3 +482,194 @@ func (m *model) GetModelStatistics(ctx context.Context, h http.Header) (resp *me
 		Into(resp)
 	return
 }
+
+func (m *model) ReadModelSchemaVersion(ctx context.Context, h http.Header, objID string, inputParam metadata.QueryCondition) (resp *metadata.ReadModelSchemaVersionResult, err error) {
+	resp = new(metadata.ReadModelSchemaVersionResult)
+	subPath := fmt.Sprintf("/read/model/%s/schema/versions", objID)
+
+	err = m.client.Post().
+		WithContext(ctx).
+		Body(inputParam).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) GetModelSchemaVersion(ctx context.Context, h http.Header, objID string, version int64) (resp *metadata.ModelSchemaVersionResult, err error) {
+	resp = new(metadata.ModelSchemaVersionResult)
+	subPath := fmt.Sprintf("/read/model/%s/schema/version/%d", objID, version)
+
+	err = m.client.Get().
+		WithContext(ctx).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) RollbackModelSchema(ctx context.Context, h http.Header, objID string, version int64) (resp *metadata.ModelSchemaVersionResult, err error) {
+	resp = new(metadata.ModelSchemaVersionResult)
+	subPath := fmt.Sprintf("/rollback/model/%s/schema/version/%d", objID, version)
+
+	err = m.client.Post().
+		WithContext(ctx).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) ExportModelBundle(ctx context.Context, h http.Header, input metadata.ExportModelBundleOption) (resp *metadata.ExportModelBundleResult, err error) {
+	resp = new(metadata.ExportModelBundleResult)
+	subPath := "/read/model/bundle"
+
+	err = m.client.Post().
+		WithContext(ctx).
+		Body(input).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) PlanModelBundle(ctx context.Context, h http.Header, bundle metadata.ModelBundle) (resp *metadata.ModelBundlePlanResult, err error) {
+	resp = new(metadata.ModelBundlePlanResult)
+	subPath := "/plan/model/bundle"
+
+	err = m.client.Post().
+		WithContext(ctx).
+		Body(bundle).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) ApplyModelBundle(ctx context.Context, h http.Header, bundle metadata.ModelBundle) (resp *metadata.ModelBundleApplyResult, err error) {
+	resp = new(metadata.ModelBundleApplyResult)
+	subPath := "/apply/model/bundle"
+
+	err = m.client.Post().
+		WithContext(ctx).
+		Body(bundle).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) SetObjectLifecycle(ctx context.Context, h http.Header, objID string, lifecycle metadata.ObjectLifecycle) (resp *metadata.BaseResp, err error) {
+	resp = new(metadata.BaseResp)
+	subPath := fmt.Sprintf("/update/model/%s/lifecycle", objID)
+
+	err = m.client.Post().
+		WithContext(ctx).
+		Body(lifecycle).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) GetObjectLifecycle(ctx context.Context, h http.Header, objID string) (resp *metadata.ObjectLifecycleResult, err error) {
+	resp = new(metadata.ObjectLifecycleResult)
+	subPath := fmt.Sprintf("/read/model/%s/lifecycle", objID)
+
+	err = m.client.Get().
+		WithContext(ctx).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) DeleteObjectLifecycle(ctx context.Context, h http.Header, objID string) (resp *metadata.BaseResp, err error) {
+	resp = new(metadata.BaseResp)
+	subPath := fmt.Sprintf("/delete/model/%s/lifecycle", objID)
+
+	err = m.client.Delete().
+		WithContext(ctx).
+		Body(nil).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) SearchLifecycleHistory(ctx context.Context, h http.Header, objID string, inputParam metadata.QueryCondition) (resp *metadata.SearchLifecycleHistoryResult, err error) {
+	resp = new(metadata.SearchLifecycleHistoryResult)
+	subPath := fmt.Sprintf("/read/model/%s/lifecycle/history", objID)
+
+	err = m.client.Post().
+		WithContext(ctx).
+		Body(inputParam).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) SetFieldSourcePriority(ctx context.Context, h http.Header, objID string, priority metadata.FieldSourcePriority) (resp *metadata.BaseResp, err error) {
+	resp = new(metadata.BaseResp)
+	subPath := fmt.Sprintf("/update/model/%s/field_source/priority", objID)
+
+	err = m.client.Post().
+		WithContext(ctx).
+		Body(priority).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) GetFieldSourcePriorities(ctx context.Context, h http.Header, objID string) (resp *metadata.FieldSourcePriorityResult, err error) {
+	resp = new(metadata.FieldSourcePriorityResult)
+	subPath := fmt.Sprintf("/read/model/%s/field_source/priority", objID)
+
+	err = m.client.Get().
+		WithContext(ctx).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) DeleteFieldSourcePriority(ctx context.Context, h http.Header, objID, propertyID string) (resp *metadata.BaseResp, err error) {
+	resp = new(metadata.BaseResp)
+	subPath := fmt.Sprintf("/delete/model/%s/field_source/priority/%s", objID, propertyID)
+
+	err = m.client.Delete().
+		WithContext(ctx).
+		Body(nil).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (m *model) SearchInstFieldSources(ctx context.Context, h http.Header, objID string, instID int64) (resp *metadata.InstFieldSourceResult, err error) {
+	resp = new(metadata.InstFieldSourceResult)
+	subPath := fmt.Sprintf("/read/instance/%s/%d/field_source", objID, instID)
+
+	err = m.client.Get().
+		WithContext(ctx).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
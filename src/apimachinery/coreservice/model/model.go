@@ -59,6 +59,24 @@ type ModelClientInterface interface {
 	UpdateModelAttrUnique(ctx context.Context, h http.Header, objID string, id uint64, data metadata.UpdateModelAttrUnique) (*metadata.UpdatedOptionResult, error)
 	DeleteModelAttrUnique(ctx context.Context, h http.Header, objID string, id uint64, data metadata.DeleteModelAttrUnique) (*metadata.DeletedOptionResult, error)
 	ReadModelAttrUnique(ctx context.Context, h http.Header, inputParam metadata.QueryCondition) (*metadata.ReadModelUniqueResult, error)
+
+	ReadModelSchemaVersion(ctx context.Context, h http.Header, objID string, inputParam metadata.QueryCondition) (*metadata.ReadModelSchemaVersionResult, error)
+	GetModelSchemaVersion(ctx context.Context, h http.Header, objID string, version int64) (*metadata.ModelSchemaVersionResult, error)
+	RollbackModelSchema(ctx context.Context, h http.Header, objID string, version int64) (*metadata.ModelSchemaVersionResult, error)
+
+	ExportModelBundle(ctx context.Context, h http.Header, input metadata.ExportModelBundleOption) (*metadata.ExportModelBundleResult, error)
+	PlanModelBundle(ctx context.Context, h http.Header, bundle metadata.ModelBundle) (*metadata.ModelBundlePlanResult, error)
+	ApplyModelBundle(ctx context.Context, h http.Header, bundle metadata.ModelBundle) (*metadata.ModelBundleApplyResult, error)
+
+	SetObjectLifecycle(ctx context.Context, h http.Header, objID string, lifecycle metadata.ObjectLifecycle) (*metadata.BaseResp, error)
+	GetObjectLifecycle(ctx context.Context, h http.Header, objID string) (*metadata.ObjectLifecycleResult, error)
+	DeleteObjectLifecycle(ctx context.Context, h http.Header, objID string) (*metadata.BaseResp, error)
+	SearchLifecycleHistory(ctx context.Context, h http.Header, objID string, inputParam metadata.QueryCondition) (*metadata.SearchLifecycleHistoryResult, error)
+
+	SetFieldSourcePriority(ctx context.Context, h http.Header, objID string, priority metadata.FieldSourcePriority) (*metadata.BaseResp, error)
+	GetFieldSourcePriorities(ctx context.Context, h http.Header, objID string) (*metadata.FieldSourcePriorityResult, error)
+	DeleteFieldSourcePriority(ctx context.Context, h http.Header, objID, propertyID string) (*metadata.BaseResp, error)
+	SearchInstFieldSources(ctx context.Context, h http.Header, objID string, instID int64) (*metadata.InstFieldSourceResult, error)
 }
 
 func NewModelClientInterface(client rest.ClientInterface) ModelClientInterface {
@@ -0,0 +1,73 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recyclebin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"configcenter/src/common/metadata"
+)
+
+func (t *recycleBin) RecycleInstances(ctx context.Context, h http.Header, objID string, dat *metadata.RecycleInstancesOption) (resp *metadata.RecycleInstancesResult, err error) {
+	subPath := fmt.Sprintf("/create/recycle/instance/%s", objID)
+	resp = new(metadata.RecycleInstancesResult)
+	err = t.client.Post().
+		WithContext(ctx).
+		Body(dat).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (t *recycleBin) SearchRecycleItems(ctx context.Context, h http.Header, dat *metadata.QueryCondition) (resp *metadata.SearchRecycleItemResult, err error) {
+	subPath := "/read/recycle/items"
+	resp = new(metadata.SearchRecycleItemResult)
+	err = t.client.Post().
+		WithContext(ctx).
+		Body(dat).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (t *recycleBin) RestoreRecycleItem(ctx context.Context, h http.Header, id int64) (resp *metadata.RestoreRecycleItemResp, err error) {
+	subPath := fmt.Sprintf("/restore/recycle/item/%d", id)
+	resp = new(metadata.RestoreRecycleItemResp)
+	err = t.client.Post().
+		WithContext(ctx).
+		Body(nil).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (t *recycleBin) PurgeRecycleItems(ctx context.Context, h http.Header, dat *metadata.PurgeRecycleItemsOption) (resp *metadata.BaseResp, err error) {
+	subPath := "/delete/recycle/items"
+	resp = new(metadata.BaseResp)
+	err = t.client.Post().
+		WithContext(ctx).
+		Body(dat).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
@@ -102,3 +102,31 @@ func (sync *synchronize) SetIdentifierFlag(ctx context.Context, h http.Header, i
 		Into(resp)
 	return
 }
+
+func (sync *synchronize) SearchConflict(ctx context.Context, h http.Header, input *metadata.SynchronizeConflictSearchParameter) (resp *metadata.SynchronizeConflictResult, err error) {
+	resp = new(metadata.SynchronizeConflictResult)
+	subPath := "/read/synchronize/conflict"
+
+	err = sync.client.Post().
+		WithContext(ctx).
+		Body(input).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (sync *synchronize) ResolveConflict(ctx context.Context, h http.Header, input *metadata.SynchronizeConflictResolveParameter) (resp *metadata.SynchronizeResult, err error) {
+	resp = new(metadata.SynchronizeResult)
+	subPath := "/update/synchronize/conflict/resolve"
+
+	err = sync.client.Post().
+		WithContext(ctx).
+		Body(input).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
@@ -27,6 +27,8 @@ type SynchronizeClientInterface interface {
 	SynchronizeFind(ctx context.Context, h http.Header, input *metadata.SynchronizeFindInfoParameter) (resp *metadata.ResponseInstData, err error)
 	SynchronizeClearData(ctx context.Context, h http.Header, input *metadata.SynchronizeClearDataParameter) (resp *metadata.Response, err error)
 	SetIdentifierFlag(ctx context.Context, h http.Header, input *metadata.SetIdenifierFlag) (resp *metadata.SynchronizeResult, err error)
+	SearchConflict(ctx context.Context, h http.Header, input *metadata.SynchronizeConflictSearchParameter) (resp *metadata.SynchronizeConflictResult, err error)
+	ResolveConflict(ctx context.Context, h http.Header, input *metadata.SynchronizeConflictResolveParameter) (resp *metadata.SynchronizeResult, err error)
 }
 
 // NewSynchronizeClientInterface new public api
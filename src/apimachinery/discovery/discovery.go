@@ -47,8 +47,11 @@ type Interface interface {
 
 // NewServiceDiscovery new a simple discovery module which can be used to get alive server address
 func NewServiceDiscovery(client *zk.ZkClient) (DiscoveryInterface, error) {
-	disc := registerdiscover.NewRegDiscoverEx(client)
+	return NewServiceDiscoveryWithRegDiscover(registerdiscover.NewRegDiscoverEx(client))
+}
 
+// NewServiceDiscoveryWithRegDiscover new a discovery module on top of any register-discover service
+func NewServiceDiscoveryWithRegDiscover(disc *registerdiscover.RegDiscover) (DiscoveryInterface, error) {
 	d := &discover{
 		servers: make(map[string]*server),
 	}
@@ -720,6 +720,20 @@ func (hs *hostServer) GetUserCustomQueryResult(ctx context.Context, businessID,
 	return
 }
 
+func (hs *hostServer) GetDynamicGroupData(ctx context.Context, businessID, id string, h http.Header, dat *metadata.DynamicGroupDataRequest) (resp *metadata.Response, err error) {
+	resp = new(metadata.Response)
+	subPath := fmt.Sprintf("/userapi/data/%s/%s", businessID, id)
+
+	err = hs.client.Post().
+		WithContext(ctx).
+		Body(dat).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
 func (hs *hostServer) HostSearch(ctx context.Context, h http.Header, params *metadata.HostCommonSearch) (resp *metadata.QueryInstResult, err error) {
 
 	resp = new(metadata.QueryInstResult)
@@ -79,6 +79,7 @@ type HostServerClientInterface interface {
 	GetUserCustomQuery(ctx context.Context, businessID string, h http.Header, dat *metadata.QueryInput) (resp *metadata.Response, err error)
 	GetUserCustomQueryDetail(ctx context.Context, businessID string, id string, h http.Header) (resp *metadata.UserCustomQueryDetailResult, err error)
 	GetUserCustomQueryResult(ctx context.Context, businessID, id, start, limit string, h http.Header) (resp *metadata.Response, err error)
+	GetDynamicGroupData(ctx context.Context, businessID, id string, h http.Header, dat *metadata.DynamicGroupDataRequest) (resp *metadata.Response, err error)
 	HostSearch(ctx context.Context, h http.Header, params *metadata.HostCommonSearch) (resp *metadata.QueryInstResult, err error)
 }
 
@@ -33,6 +33,7 @@ import (
 
 	"configcenter/src/apimachinery/util"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/tracing"
 	commonUtil "configcenter/src/common/util"
 )
 
@@ -241,6 +242,15 @@ func (r *Request) Do() *Result {
 		return r.handleMockResult()
 	}
 
+	span := tracing.StartClientSpan(r.ctx, r.headers, string(r.verb)+" "+r.subPath)
+	if span != nil {
+		span.SetAttribute("http.method", string(r.verb))
+		defer func() {
+			span.SetAttribute("http.status_code", strconv.Itoa(result.StatusCode))
+			span.Finish(result.Err)
+		}()
+	}
+
 	client := r.capability.Client
 	if client == nil {
 		client = http.DefaultClient
@@ -274,6 +284,11 @@ func (r *Request) Do() *Result {
 			}
 			req.Header.Set("Content-Type", "application/json")
 			req.Header.Set("Accept", "application/json")
+			if span != nil {
+				// the header is shared by the requests of the caller, do not overwrite its traceparent
+				req.Header = commonUtil.CloneHeader(req.Header)
+				tracing.InjectHeader(span, req.Header)
+			}
 
 			if retries > 0 {
 				r.tryThrottle(url)
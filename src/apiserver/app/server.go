@@ -76,12 +76,20 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 
 	svc.SetConfig(authConf.Enable, engine, client, engine.Discovery(), authorize)
 
+	apiKeyConf, err := service.ParseAPIKeyConfig("apikey", apiSvr.Config)
+	if err != nil {
+		return err
+	}
+	blog.Infof("api key required: %v", apiKeyConf.Required)
+	svc.SetAPIKeyConfig(apiKeyConf)
+
 	ctnr := restful.NewContainer()
 	ctnr.Router(restful.CurlyRouter{})
 	ctnr.Router(restful.CurlyRouter{})
 	for _, item := range svc.WebServices(authConf) {
 		ctnr.Add(item)
 	}
+	svc.SetHandler(ctnr)
 	apiSvr.Core = engine
 
 	if err := backbone.StartServer(ctx, engine, ctnr); err != nil {
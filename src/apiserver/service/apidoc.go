@@ -0,0 +1,96 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/openapi"
+	"configcenter/src/common/types"
+	"configcenter/src/common/util"
+	"configcenter/src/common/version"
+
+	"github.com/emicklei/go-restful"
+)
+
+// publicModules the modules whose apis are proxied by apiserver
+var publicModules = []string{
+	types.CC_MODULE_TOPO,
+	types.CC_MODULE_HOST,
+	types.CC_MODULE_PROC,
+	types.CC_MODULE_EVENTSERVER,
+	types.CC_MODULE_DATACOLLECTION,
+}
+
+// publicPathPrefixes the candidate prefixes to convert a backend service path to the public path,
+// it's the reverse of the url rewrite rules in URLPath.
+var publicPathPrefixes = []struct {
+	service string
+	public  string
+}{
+	{service: "/topo/v3/app", public: rootPath + "/biz"},
+	{service: "/topo/v3/objectattr", public: rootPath + "/object/attr"},
+	{service: "/topo/v3", public: rootPath},
+	{service: "/host/v3", public: rootPath},
+	{service: "/process/v3", public: rootPath + "/proc"},
+	{service: "/process/v3", public: rootPath},
+	{service: "/event/v3", public: rootPath + "/event"},
+	{service: "/collector/v3", public: rootPath + "/collector"},
+}
+
+// publicPath convert the backend service path to the apiserver's public path, the path is
+// public only when apiserver rewrite the public path back to the same backend service path.
+func publicPath(servicePath string) (string, bool) {
+	for _, prefix := range publicPathPrefixes {
+		if !strings.HasPrefix(servicePath, prefix.service) {
+			continue
+		}
+		public := prefix.public + servicePath[len(prefix.service):]
+		req := restful.NewRequest(&http.Request{URL: &url.URL{Path: public}, RequestURI: public})
+		if _, err := URLPath(public).FilterChain(req); err != nil {
+			continue
+		}
+		if req.Request.URL.Path == servicePath {
+			return public, true
+		}
+	}
+	return "", false
+}
+
+// OpenAPIDocument serve the OpenAPI document of the public apis, which is merged with
+// the apis of apiserver itself and the apis proxied to the backend services.
+// the unreachable modules are skipped and listed in the description of the document.
+func (s *service) OpenAPIDocument(req *restful.Request, resp *restful.Response) {
+	rid := util.GetHTTPCCRequestID(req.Request.Header)
+
+	info := openapi.Info{Title: types.CC_MODULE_APISERVER, Version: version.CCVersion}
+	doc := openapi.Build(info, s.webServices)
+	unavailable := make([]string, 0)
+	for _, module := range publicModules {
+		moduleDoc, err := s.engine.CoreAPI.APIDoc().GetDocument(req.Request.Context(), module)
+		if err != nil {
+			blog.Warnf("get api document of %s failed, skip it, err: %v, rid: %s", module, err, rid)
+			unavailable = append(unavailable, module)
+			continue
+		}
+		doc.Merge(moduleDoc, publicPath)
+	}
+	if len(unavailable) > 0 {
+		doc.Info.Description = "the apis of the unavailable modules are not included: " + strings.Join(unavailable, ", ")
+	}
+
+	resp.WriteAsJson(doc)
+}
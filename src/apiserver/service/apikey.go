@@ -0,0 +1,460 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"configcenter/src/apimachinery/flowctrl"
+	"configcenter/src/auth/meta"
+	"configcenter/src/auth/parser"
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/emicklei/go-restful"
+)
+
+const (
+	// apiKeyLength the byte length of the random api key
+	apiKeyLength = 24
+	// apiKeyPrefixLength the length of api key prefix which is saved for user to recognise the api key
+	apiKeyPrefixLength = 8
+	// apiKeyCacheTTL how long a api key is cached in apiserver, a revoked api key
+	// may still be used on other apiserver instances in this period.
+	apiKeyCacheTTL = time.Minute
+	// apiKeyCacheSize the max count of cached api keys, including the not exist ones,
+	// so that the random api keys can not grow the cache without bound.
+	apiKeyCacheSize = 10000
+
+	// apiKeyAttribute the request attribute which save the api key of the request
+	apiKeyAttribute = "bk_api_key"
+
+	defaultAPIKeyQPS   = 100
+	defaultAPIKeyBurst = 200
+)
+
+// APIKeyConfig the api key config of apiserver
+type APIKeyConfig struct {
+	// Required all the requests must carry a api key when it's true
+	Required bool
+	// DefaultQPS and DefaultBurst are used when the api key not set it's own quota
+	DefaultQPS   int64
+	DefaultBurst int64
+}
+
+// ParseAPIKeyConfig parse the api key config from the config map with the prefix
+func ParseAPIKeyConfig(prefix string, configmap map[string]string) (APIKeyConfig, error) {
+	conf := APIKeyConfig{
+		DefaultQPS:   defaultAPIKeyQPS,
+		DefaultBurst: defaultAPIKeyBurst,
+	}
+
+	var err error
+	if val, exist := configmap[prefix+".required"]; exist && val != "" {
+		if conf.Required, err = strconv.ParseBool(val); err != nil {
+			return conf, fmt.Errorf("invalid %s.required value: %s", prefix, val)
+		}
+	}
+	if val, exist := configmap[prefix+".defaultQPS"]; exist && val != "" {
+		if conf.DefaultQPS, err = strconv.ParseInt(val, 10, 64); err != nil || conf.DefaultQPS <= 0 {
+			return conf, fmt.Errorf("invalid %s.defaultQPS value: %s", prefix, val)
+		}
+	}
+	if val, exist := configmap[prefix+".defaultBurst"]; exist && val != "" {
+		if conf.DefaultBurst, err = strconv.ParseInt(val, 10, 64); err != nil || conf.DefaultBurst <= 0 {
+			return conf, fmt.Errorf("invalid %s.defaultBurst value: %s", prefix, val)
+		}
+	}
+	return conf, nil
+}
+
+type cachedAPIKey struct {
+	apiKey    *metadata.APIKey
+	fetchTime time.Time
+}
+
+// apiKeyManager cache the api keys and the rate limiters of them
+type apiKeyManager struct {
+	conf     APIKeyConfig
+	lock     sync.RWMutex
+	keys     map[string]cachedAPIKey
+	limiters map[int64]flowctrl.RateLimiter
+}
+
+func newAPIKeyManager(conf APIKeyConfig) *apiKeyManager {
+	return &apiKeyManager{
+		conf:     conf,
+		keys:     make(map[string]cachedAPIKey),
+		limiters: make(map[int64]flowctrl.RateLimiter),
+	}
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateAPIKey() (string, error) {
+	buf := make([]byte, apiKeyLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (m *apiKeyManager) getCache(keyHash string) (*metadata.APIKey, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	cached, exist := m.keys[keyHash]
+	if !exist || time.Since(cached.fetchTime) > apiKeyCacheTTL {
+		return nil, false
+	}
+	return cached.apiKey, true
+}
+
+func (m *apiKeyManager) setCache(keyHash string, apiKey *metadata.APIKey) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, exist := m.keys[keyHash]; !exist && len(m.keys) >= apiKeyCacheSize {
+		m.sweep()
+	}
+	if _, exist := m.keys[keyHash]; !exist && len(m.keys) >= apiKeyCacheSize {
+		// the cache is full of unexpired api keys, the not exist api key is not worth to evict them
+		if apiKey == nil {
+			return
+		}
+		for hash := range m.keys {
+			delete(m.keys, hash)
+			break
+		}
+	}
+	m.keys[keyHash] = cachedAPIKey{apiKey: apiKey, fetchTime: time.Now()}
+}
+
+// sweep remove the expired api keys from the cache, the caller must hold the write lock
+func (m *apiKeyManager) sweep() {
+	for hash, cached := range m.keys {
+		if time.Since(cached.fetchTime) > apiKeyCacheTTL {
+			delete(m.keys, hash)
+		}
+	}
+}
+
+func (m *apiKeyManager) removeCache(id int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for hash, cached := range m.keys {
+		if cached.apiKey != nil && cached.apiKey.ID == id {
+			delete(m.keys, hash)
+		}
+	}
+	delete(m.limiters, id)
+}
+
+// limiter get the rate limiter of the api key, the rate limiter is rebuilt when the quota is changed
+func (m *apiKeyManager) limiter(apiKey *metadata.APIKey) flowctrl.RateLimiter {
+	qps, burst := apiKey.QPS, apiKey.Burst
+	if qps <= 0 {
+		qps = m.conf.DefaultQPS
+	}
+	if burst <= 0 {
+		burst = m.conf.DefaultBurst
+	}
+
+	m.lock.RLock()
+	limiter, exist := m.limiters[apiKey.ID]
+	m.lock.RUnlock()
+	if exist && limiter.QPS() == qps && limiter.Burst() == burst {
+		return limiter
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	// check again, the limiter may be rebuilt by other request while waiting for the lock
+	limiter, exist = m.limiters[apiKey.ID]
+	if !exist || limiter.QPS() != qps || limiter.Burst() != burst {
+		limiter = flowctrl.NewRateLimiter(qps, burst)
+		m.limiters[apiKey.ID] = limiter
+	}
+	return limiter
+}
+
+// getAPIKey get the api key info with the api key, the not exist api key is cached as nil too.
+func (s *service) getAPIKey(ctx context.Context, header http.Header, key string) (*metadata.APIKey, error) {
+	keyHash := hashAPIKey(key)
+	if apiKey, exist := s.apiKeys.getCache(keyHash); exist {
+		return apiKey, nil
+	}
+
+	// api key is unique in all the supplier accounts
+	h := util.CloneHeader(header)
+	h.Set(common.BKHTTPOwnerID, common.BKSuperOwnerID)
+	h.Set(common.BKHTTPHeaderUser, common.CCSystemOperatorUserName)
+	cond := &metadata.SearchAPIKeyRequest{KeyHash: keyHash, Page: metadata.BasePage{Limit: 1}}
+	result, err := s.engine.CoreAPI.CoreService().APIKey().SearchAPIKey(ctx, h, cond)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Result {
+		return nil, fmt.Errorf("search api key failed, %s", result.ErrMsg)
+	}
+
+	var apiKey *metadata.APIKey
+	if len(result.Data.Info) > 0 {
+		apiKey = &result.Data.Info[0]
+	}
+	s.apiKeys.setCache(keyHash, apiKey)
+	return apiKey, nil
+}
+
+// checkAPIKeyScope check if the request is allowed in the scope of the api key
+func (s *service) checkAPIKeyScope(req *restful.Request, scope metadata.APIKeyScope) (bool, error) {
+	if !scope.ReadOnly && len(scope.BizIDs) == 0 {
+		return true, nil
+	}
+
+	attribute, err := parser.ParseAttribute(req, s.engine)
+	if err != nil {
+		return false, err
+	}
+	for _, resource := range attribute.Resources {
+		if scope.ReadOnly && resource.Action != meta.Find && resource.Action != meta.FindMany && resource.Action != meta.SkipAction {
+			return false, nil
+		}
+		if !scope.AllowBiz(resource.BusinessID) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func isAPIKeyManagePath(path string) bool {
+	return strings.HasPrefix(path, rootPath+"/apikey/")
+}
+
+// apiKeyFilter authenticate the request with the api key, and limit the request rate of each api key.
+// the user and supplier account of the request is replaced with the api key's.
+func (s *service) apiKeyFilter(errFunc func() errors.CCErrorIf) func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
+	return func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
+		header := req.Request.Header
+		rid := util.GetHTTPCCRequestID(header)
+		defErr := errFunc().CreateDefaultCCErrorIf(util.GetLanguage(header))
+		writeError := func(status int, code int) {
+			rsp := metadata.BaseResp{
+				Code:   code,
+				ErrMsg: defErr.Error(code).Error(),
+				Result: false,
+			}
+			resp.WriteHeaderAndJson(status, rsp, restful.MIME_JSON)
+		}
+
+		key := header.Get(common.BKHTTPAPIKey)
+		if key == "" {
+			// the api keys can only be managed without api key, the request is still authorized by the auth filter
+			if s.apiKeys.conf.Required && !isAPIKeyManagePath(req.Request.URL.Path) {
+				blog.Errorf("request %s %s without api key, rid: %s", req.Request.Method, req.Request.URL.Path, rid)
+				writeError(http.StatusUnauthorized, common.CCErrAPIKeyRequired)
+				return
+			}
+			fchain.ProcessFilter(req, resp)
+			return
+		}
+
+		// can not manage api key with a api key, which may be used to issue a key with more permission.
+		if isAPIKeyManagePath(req.Request.URL.Path) {
+			blog.Errorf("manage api key with api key is not allowed, rid: %s", rid)
+			writeError(http.StatusForbidden, common.CCErrAPIKeyManageForbidden)
+			return
+		}
+
+		apiKey, err := s.getAPIKey(req.Request.Context(), header, key)
+		if err != nil {
+			blog.Errorf("get api key failed, err: %v, rid: %s", err, rid)
+			writeError(http.StatusInternalServerError, common.CCErrCommHTTPDoRequestFailed)
+			return
+		}
+		if apiKey == nil || apiKey.IsExpired(time.Now()) {
+			blog.Errorf("request with invalid or expired api key, rid: %s", rid)
+			writeError(http.StatusUnauthorized, common.CCErrAPIKeyInvalid)
+			return
+		}
+
+		limiter := s.apiKeys.limiter(apiKey)
+		resp.AddHeader("X-RateLimit-Limit", strconv.FormatInt(limiter.QPS(), 10))
+		resp.AddHeader("X-RateLimit-Burst", strconv.FormatInt(limiter.Burst(), 10))
+		if !limiter.TryAccept() {
+			blog.Warnf("api key %d of app %s is rate limited, qps: %d, burst: %d, rid: %s", apiKey.ID, apiKey.AppCode, limiter.QPS(), limiter.Burst(), rid)
+			resp.AddHeader("Retry-After", "1")
+			writeError(http.StatusTooManyRequests, common.CCErrAPIKeyRateLimited)
+			return
+		}
+
+		header.Set(common.BKHTTPHeaderUser, apiKey.User)
+		header.Set(common.BKHTTPOwnerID, apiKey.OwnerID)
+		header.Set(common.BKHTTPAppCode, apiKey.AppCode)
+		header.Del(common.BKHTTPAPIKey)
+		req.SetAttribute(apiKeyAttribute, key)
+
+		allowed, err := s.checkAPIKeyScope(req, apiKey.Scope)
+		if err != nil {
+			blog.Errorf("check api key %d scope failed, parse auth attribute for %s %s failed, err: %v, rid: %s", apiKey.ID, req.Request.Method, req.Request.URL.Path, err, rid)
+			writeError(http.StatusBadRequest, common.CCErrCommParseAuthAttributeFailed)
+			return
+		}
+		if !allowed {
+			blog.Errorf("api key %d has no permission to %s %s, rid: %s", apiKey.ID, req.Request.Method, req.Request.URL.Path, rid)
+			writeError(http.StatusForbidden, common.CCErrAPIKeyScopeForbidden)
+			return
+		}
+
+		fchain.ProcessFilter(req, resp)
+	}
+}
+
+// CreateAPIKey issue a api key for the request user, the api key is returned only once.
+func (s *service) CreateAPIKey(req *restful.Request, resp *restful.Response) {
+	pheader := req.Request.Header
+	defErr := s.engine.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pheader))
+	rid := util.GetHTTPCCRequestID(pheader)
+
+	body := metadata.CreateAPIKeyRequest{}
+	if err := json.NewDecoder(req.Request.Body).Decode(&body); err != nil {
+		blog.Errorf("create api key, but decode body failed, err: %v, rid: %s", err, rid)
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	if body.AppCode == "" {
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsNeedSet, "bk_app_code")})
+		return
+	}
+	if body.QPS < 0 || body.Burst < 0 {
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsInvalid, "qps")})
+		return
+	}
+
+	key, err := generateAPIKey()
+	if err != nil {
+		blog.Errorf("create api key, but generate key failed, err: %v, rid: %s", err, rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: defErr.Error(common.CCErrCommInternalServerError)})
+		return
+	}
+
+	apiKey := &metadata.APIKey{
+		AppCode:    body.AppCode,
+		User:       util.GetUser(pheader),
+		KeyHash:    hashAPIKey(key),
+		KeyPrefix:  key[:apiKeyPrefixLength],
+		Scope:      body.Scope,
+		QPS:        body.QPS,
+		Burst:      body.Burst,
+		ExpireTime: body.ExpireTime,
+	}
+	result, err := s.engine.CoreAPI.CoreService().APIKey().CreateAPIKey(req.Request.Context(), pheader, apiKey)
+	if err != nil {
+		blog.Errorf("create api key, but save api key failed, err: %v, rid: %s", err, rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: defErr.Error(common.CCErrCommHTTPDoRequestFailed)})
+		return
+	}
+	if !result.Result {
+		blog.Errorf("create api key, but save api key failed, err: %s, rid: %s", result.ErrMsg, rid)
+		resp.WriteEntity(result)
+		return
+	}
+
+	data := metadata.CreateAPIKeyResult{}
+	data.Data.APIKey = result.Data
+	data.Data.KeyHash = ""
+	data.Data.Key = key
+	resp.WriteEntity(metadata.NewSuccessResp(data.Data))
+}
+
+// SearchAPIKey search the api keys of the request user
+func (s *service) SearchAPIKey(req *restful.Request, resp *restful.Response) {
+	pheader := req.Request.Header
+	defErr := s.engine.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pheader))
+	rid := util.GetHTTPCCRequestID(pheader)
+
+	body := metadata.SearchAPIKeyRequest{}
+	if err := json.NewDecoder(req.Request.Body).Decode(&body); err != nil {
+		blog.Errorf("search api key, but decode body failed, err: %v, rid: %s", err, rid)
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	body.User = util.GetUser(pheader)
+	body.KeyHash = ""
+
+	result, err := s.engine.CoreAPI.CoreService().APIKey().SearchAPIKey(req.Request.Context(), pheader, &body)
+	if err != nil {
+		blog.Errorf("search api key, but search failed, err: %v, rid: %s", err, rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: defErr.Error(common.CCErrCommHTTPDoRequestFailed)})
+		return
+	}
+	for idx := range result.Data.Info {
+		result.Data.Info[idx].KeyHash = ""
+	}
+	resp.WriteEntity(result)
+}
+
+// DeleteAPIKey revoke a api key of the request user
+func (s *service) DeleteAPIKey(req *restful.Request, resp *restful.Response) {
+	pheader := req.Request.Header
+	defErr := s.engine.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pheader))
+	rid := util.GetHTTPCCRequestID(pheader)
+
+	id, err := strconv.ParseInt(req.PathParameter("id"), 10, 64)
+	if err != nil {
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsInvalid, "id")})
+		return
+	}
+
+	// only the api key of the request user can be revoked
+	cond := &metadata.SearchAPIKeyRequest{ID: id, User: util.GetUser(pheader), Page: metadata.BasePage{Limit: 1}}
+	searchResult, err := s.engine.CoreAPI.CoreService().APIKey().SearchAPIKey(req.Request.Context(), pheader, cond)
+	if err != nil {
+		blog.Errorf("delete api key, but search api key %d failed, err: %v, rid: %s", id, err, rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: defErr.Error(common.CCErrCommHTTPDoRequestFailed)})
+		return
+	}
+	if !searchResult.Result {
+		resp.WriteEntity(searchResult)
+		return
+	}
+	if len(searchResult.Data.Info) == 0 {
+		resp.WriteError(http.StatusNotFound, &metadata.RespError{Msg: defErr.Error(common.CCErrCommNotFound)})
+		return
+	}
+
+	result, err := s.engine.CoreAPI.CoreService().APIKey().DeleteAPIKey(req.Request.Context(), pheader, id)
+	if err != nil {
+		blog.Errorf("delete api key %d failed, err: %v, rid: %s", id, err, rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: defErr.Error(common.CCErrCommHTTPDoRequestFailed)})
+		return
+	}
+	if result.Result {
+		s.apiKeys.removeCache(id)
+	}
+	resp.WriteEntity(result)
+}
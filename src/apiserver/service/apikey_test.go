@@ -0,0 +1,130 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/backbone"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+)
+
+// newFilterTestService build a service whose handler only has the global filters of apiserver and
+// a api echo the user and supplier account of the request.
+func newFilterTestService() *service {
+	s := &service{
+		engine:  &backbone.Engine{CCErr: errors.NewFromCtx(map[string]errors.ErrorCode{})},
+		apiKeys: newAPIKeyManager(APIKeyConfig{DefaultQPS: defaultAPIKeyQPS, DefaultBurst: defaultAPIKeyBurst}),
+	}
+	s.apiKeys.setCache(hashAPIKey("valid"), &metadata.APIKey{ID: 1, AppCode: "app", User: "app_user", OwnerID: "0"})
+	s.apiKeys.setCache(hashAPIKey("invalid"), nil)
+
+	getErrFun := func() errors.CCErrorIf {
+		return s.engine.CCErr
+	}
+	ws := new(restful.WebService)
+	ws.Path(rootPath).Produces(restful.MIME_JSON)
+	s.addGlobalFilters(ws, getErrFun)
+	ws.Route(ws.GET("/echo").To(func(req *restful.Request, resp *restful.Response) {
+		resp.WriteEntity(metadata.NewSuccessResp(map[string]string{
+			"user":     req.Request.Header.Get(common.BKHTTPHeaderUser),
+			"owner":    req.Request.Header.Get(common.BKHTTPOwnerID),
+			"app_code": req.Request.Header.Get(common.BKHTTPAppCode),
+			"api_key":  req.Request.Header.Get(common.BKHTTPAPIKey),
+		}))
+	}))
+
+	container := restful.NewContainer()
+	container.Add(ws)
+	s.handler = container
+	return s
+}
+
+func TestGlobalFilters(t *testing.T) {
+	s := newFilterTestService()
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		status int
+		// code the expected error code, 0 means success
+		code int
+		echo map[string]string
+	}{
+		{
+			name:   "api key only",
+			header: map[string]string{common.BKHTTPAPIKey: "valid"},
+			status: http.StatusOK,
+			echo:   map[string]string{"user": "app_user", "owner": "0", "app_code": "app", "api_key": ""},
+		},
+		{
+			name: "api key replace the user of request",
+			header: map[string]string{
+				common.BKHTTPAPIKey:     "valid",
+				common.BKHTTPHeaderUser: "admin",
+				common.BKHTTPOwnerID:    "1",
+			},
+			status: http.StatusOK,
+			echo:   map[string]string{"user": "app_user", "owner": "0", "app_code": "app", "api_key": ""},
+		},
+		{
+			name:   "invalid api key",
+			header: map[string]string{common.BKHTTPAPIKey: "invalid"},
+			status: http.StatusUnauthorized,
+			code:   common.CCErrAPIKeyInvalid,
+		},
+		{
+			name:   "user without api key",
+			header: map[string]string{common.BKHTTPHeaderUser: "admin", common.BKHTTPOwnerID: "0"},
+			status: http.StatusOK,
+			echo:   map[string]string{"user": "admin", "owner": "0", "app_code": "", "api_key": ""},
+		},
+		{
+			name:   "neither user nor api key",
+			header: map[string]string{},
+			status: http.StatusInternalServerError,
+			code:   common.CCErrCommNotAuthItem,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, rootPath+"/echo", nil)
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			s.handler.ServeHTTP(recorder, req)
+			require.Equal(t, tt.status, recorder.Code)
+			require.NotEmpty(t, recorder.Header().Get(common.BKHTTPCCRequestID))
+
+			result := struct {
+				metadata.BaseResp
+				Data map[string]string `json:"data"`
+			}{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+			require.Equal(t, tt.code, result.Code)
+			if tt.code == 0 {
+				require.Equal(t, tt.echo, result.Data)
+			}
+		})
+	}
+}
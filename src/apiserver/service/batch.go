@@ -0,0 +1,173 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/emicklei/go-restful"
+)
+
+const (
+	// batchPath the path of the batch request
+	batchPath = rootPath + "/batch"
+	// batchMaxRequests the max number of sub requests in a batch request
+	batchMaxRequests = 500
+	// batchDefaultConcurrency the default number of sub requests dispatched at the same time
+	batchDefaultConcurrency = 10
+	// batchMaxConcurrency the max number of sub requests dispatched at the same time
+	batchMaxConcurrency = 50
+)
+
+// batchResponseWriter hold the response of a sub request in memory
+type batchResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBatchResponseWriter() *batchResponseWriter {
+	return &batchResponseWriter{header: make(http.Header)}
+}
+
+func (w *batchResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *batchResponseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(data)
+}
+
+func (w *batchResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// Batch dispatch the sub requests through the same filters and proxy with the normal request,
+// so each sub request is authenticated, authorized and rate limited on it's own.
+func (s *service) Batch(req *restful.Request, resp *restful.Response) {
+	pheader := req.Request.Header
+	defErr := s.engine.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pheader))
+	rid := util.GetHTTPCCRequestID(pheader)
+
+	body := metadata.BatchRequest{}
+	if err := json.NewDecoder(req.Request.Body).Decode(&body); err != nil {
+		blog.Errorf("batch request, but decode body failed, err: %v, rid: %s", err, rid)
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	if len(body.Requests) == 0 {
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsNeedSet, "requests")})
+		return
+	}
+	if len(body.Requests) > batchMaxRequests {
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommXXExceedLimit, "requests", batchMaxRequests)})
+		return
+	}
+
+	concurrency := body.Concurrency
+	if concurrency <= 0 {
+		concurrency = batchDefaultConcurrency
+	}
+	if concurrency > batchMaxConcurrency {
+		concurrency = batchMaxConcurrency
+	}
+
+	for idx, sub := range body.Requests {
+		switch strings.ToUpper(sub.Method) {
+		case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete:
+		default:
+			blog.Errorf("batch request, but sub request %d has invalid method: %s, rid: %s", idx, sub.Method, rid)
+			resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsInvalid, "method")})
+			return
+		}
+		if !strings.HasPrefix(sub.Path, rootPath+"/") || strings.HasPrefix(sub.Path, batchPath) {
+			blog.Errorf("batch request, but sub request %d has invalid path: %s, rid: %s", idx, sub.Path, rid)
+			resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsInvalid, "path")})
+			return
+		}
+	}
+
+	results := make([]metadata.BatchSubResponse, len(body.Requests))
+	bucket := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	for idx := range body.Requests {
+		bucket <- struct{}{}
+		wg.Add(1)
+		go func(idx int) {
+			defer func() {
+				<-bucket
+				wg.Done()
+			}()
+			results[idx] = s.dispatchBatchSubRequest(req, idx, body.Requests[idx])
+		}(idx)
+	}
+	wg.Wait()
+
+	resp.WriteEntity(metadata.NewSuccessResp(results))
+}
+
+func (s *service) dispatchBatchSubRequest(req *restful.Request, idx int, sub metadata.BatchSubRequest) metadata.BatchSubResponse {
+	rid := util.GetHTTPCCRequestID(req.Request.Header)
+	result := metadata.BatchSubResponse{Index: idx}
+
+	subReq, err := http.NewRequest(strings.ToUpper(sub.Method), sub.Path, bytes.NewReader(sub.Body))
+	if err != nil {
+		blog.Errorf("batch request, but new sub request %d failed, err: %v, rid: %s", idx, err, rid)
+		result.Status = http.StatusBadRequest
+		result.Body, _ = json.Marshal(metadata.BaseResp{Code: common.CCErrCommParamsInvalid, ErrMsg: err.Error()})
+		return result
+	}
+	subReq = subReq.WithContext(req.Request.Context())
+	subReq.RequestURI = subReq.URL.RequestURI()
+	subReq.RemoteAddr = req.Request.RemoteAddr
+	for key, values := range req.Request.Header {
+		if key == "Content-Length" {
+			continue
+		}
+		subReq.Header[key] = values
+	}
+	// the api key header is removed by api key filter, restore it so that the sub request is
+	// checked with the scope of the api key too.
+	if key, ok := req.Attribute(apiKeyAttribute).(string); ok && key != "" {
+		subReq.Header.Set(common.BKHTTPAPIKey, key)
+	}
+
+	writer := newBatchResponseWriter()
+	s.handler.ServeHTTP(writer, subReq)
+
+	result.Status = writer.status
+	if result.Status == 0 {
+		result.Status = http.StatusOK
+	}
+	data := writer.body.Bytes()
+	if json.Valid(data) {
+		result.Body = json.RawMessage(data)
+	} else {
+		result.Body, _ = json.Marshal(string(data))
+	}
+	return result
+}
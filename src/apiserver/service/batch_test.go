@@ -0,0 +1,208 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/backbone"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+)
+
+// newBatchTestService build a service whose handler serve the batch api and some fake apis,
+// the fake auth filter only allow the request with the api key and deny the forbidden path.
+func newBatchTestService() *service {
+	s := &service{
+		engine: &backbone.Engine{CCErr: errors.NewFromCtx(map[string]errors.ErrorCode{})},
+	}
+
+	ws := new(restful.WebService)
+	ws.Path(rootPath).Produces(restful.MIME_JSON)
+	ws.Filter(func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
+		// remove the api key like the api key filter does
+		if key := req.Request.Header.Get(common.BKHTTPAPIKey); key != "" {
+			req.Request.Header.Del(common.BKHTTPAPIKey)
+			req.SetAttribute(apiKeyAttribute, key)
+		}
+		if req.Request.URL.Path == batchPath {
+			fchain.ProcessFilter(req, resp)
+			return
+		}
+		if req.Attribute(apiKeyAttribute) != "valid" || strings.HasSuffix(req.Request.URL.Path, "/forbidden") {
+			resp.WriteHeaderAndJson(http.StatusForbidden, metadata.BaseResp{Code: common.CCErrCommAuthNotHavePermission}, restful.MIME_JSON)
+			return
+		}
+		fchain.ProcessFilter(req, resp)
+	})
+	ws.Route(ws.POST("/batch").To(s.Batch))
+	ws.Route(ws.GET("/echo/{id}").To(func(req *restful.Request, resp *restful.Response) {
+		resp.WriteEntity(metadata.NewSuccessResp(map[string]string{
+			"id":   req.PathParameter("id"),
+			"user": req.Request.Header.Get(common.BKHTTPHeaderUser),
+		}))
+	}))
+	ws.Route(ws.POST("/echo/{id}").To(func(req *restful.Request, resp *restful.Response) {
+		body := make(map[string]interface{})
+		if err := json.NewDecoder(req.Request.Body).Decode(&body); err != nil {
+			resp.WriteHeaderAndJson(http.StatusBadRequest, metadata.BaseResp{Code: common.CCErrCommJSONUnmarshalFailed}, restful.MIME_JSON)
+			return
+		}
+		resp.WriteEntity(metadata.NewSuccessResp(body))
+	}))
+	ws.Route(ws.GET("/forbidden").To(func(req *restful.Request, resp *restful.Response) {
+		resp.WriteEntity(metadata.NewSuccessResp(nil))
+	}))
+	ws.Route(ws.GET("/broken").To(func(req *restful.Request, resp *restful.Response) {
+		resp.WriteErrorString(http.StatusInternalServerError, "internal error")
+	}))
+
+	container := restful.NewContainer()
+	container.Add(ws)
+	s.handler = container
+	return s
+}
+
+func doBatch(t *testing.T, s *service, apiKey string, body interface{}) (int, []metadata.BatchSubResponse) {
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, batchPath, bytes.NewReader(data))
+	req.Header.Set("Content-Type", restful.MIME_JSON)
+	req.Header.Set(common.BKHTTPHeaderUser, "admin")
+	if apiKey != "" {
+		req.Header.Set(common.BKHTTPAPIKey, apiKey)
+	}
+	recorder := httptest.NewRecorder()
+	s.handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		return recorder.Code, nil
+	}
+
+	result := metadata.BatchResult{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	require.True(t, result.Result)
+	return recorder.Code, result.Data
+}
+
+func TestBatch(t *testing.T) {
+	s := newBatchTestService()
+
+	tests := []struct {
+		name     string
+		apiKey   string
+		requests []metadata.BatchSubRequest
+		// status the expected status of each sub request
+		status []int
+		check  func(t *testing.T, results []metadata.BatchSubResponse)
+	}{
+		{
+			name:   "dispatch in order with the header of batch request",
+			apiKey: "valid",
+			requests: []metadata.BatchSubRequest{
+				{Method: "get", Path: rootPath + "/echo/1"},
+				{Method: http.MethodPost, Path: rootPath + "/echo/2", Body: json.RawMessage(`{"name":"host"}`)},
+				{Method: http.MethodGet, Path: rootPath + "/echo/3"},
+			},
+			status: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+			check: func(t *testing.T, results []metadata.BatchSubResponse) {
+				echo := struct {
+					Data map[string]string `json:"data"`
+				}{}
+				require.NoError(t, json.Unmarshal(results[0].Body, &echo))
+				require.Equal(t, map[string]string{"id": "1", "user": "admin"}, echo.Data)
+				require.NoError(t, json.Unmarshal(results[1].Body, &echo))
+				require.Equal(t, "host", echo.Data["name"])
+			},
+		},
+		{
+			name:   "each sub request is authorized on it's own",
+			apiKey: "valid",
+			requests: []metadata.BatchSubRequest{
+				{Method: http.MethodGet, Path: rootPath + "/echo/1"},
+				{Method: http.MethodGet, Path: rootPath + "/forbidden"},
+			},
+			status: []int{http.StatusOK, http.StatusForbidden},
+		},
+		{
+			name:   "the api key of batch request is checked in the sub requests",
+			apiKey: "invalid",
+			requests: []metadata.BatchSubRequest{
+				{Method: http.MethodGet, Path: rootPath + "/echo/1"},
+			},
+			status: []int{http.StatusForbidden},
+		},
+		{
+			name:   "partial failure returns the result of every sub request",
+			apiKey: "valid",
+			requests: []metadata.BatchSubRequest{
+				{Method: http.MethodGet, Path: rootPath + "/broken"},
+				{Method: http.MethodPost, Path: rootPath + "/echo/1", Body: json.RawMessage(`"not object"`)},
+				{Method: http.MethodGet, Path: rootPath + "/not_exist"},
+				{Method: http.MethodGet, Path: rootPath + "/echo/4"},
+			},
+			status: []int{http.StatusInternalServerError, http.StatusBadRequest, http.StatusNotFound, http.StatusOK},
+			check: func(t *testing.T, results []metadata.BatchSubResponse) {
+				// the body which is not json is returned as a json string
+				var msg string
+				require.NoError(t, json.Unmarshal(results[0].Body, &msg))
+				require.Equal(t, "internal error", msg)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, results := doBatch(t, s, test.apiKey, metadata.BatchRequest{Concurrency: 2, Requests: test.requests})
+			require.Equal(t, http.StatusOK, code)
+			require.Len(t, results, len(test.requests))
+			for idx, result := range results {
+				require.Equal(t, idx, result.Index)
+				require.Equal(t, test.status[idx], result.Status, "sub request %d", idx)
+			}
+			if test.check != nil {
+				test.check(t, results)
+			}
+		})
+	}
+}
+
+func TestBatchInvalidRequest(t *testing.T) {
+	s := newBatchTestService()
+
+	tests := []struct {
+		name     string
+		requests []metadata.BatchSubRequest
+	}{
+		{name: "empty requests"},
+		{name: "invalid method", requests: []metadata.BatchSubRequest{{Method: http.MethodPatch, Path: rootPath + "/echo/1"}}},
+		{name: "path out of api", requests: []metadata.BatchSubRequest{{Method: http.MethodGet, Path: "/healthz"}}},
+		{name: "nested batch", requests: []metadata.BatchSubRequest{{Method: http.MethodPost, Path: batchPath}}},
+		{name: "too many requests", requests: make([]metadata.BatchSubRequest, batchMaxRequests+1)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, _ := doBatch(t, s, "valid", metadata.BatchRequest{Requests: test.requests})
+			require.Equal(t, http.StatusBadRequest, code)
+		})
+	}
+}
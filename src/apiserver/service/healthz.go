@@ -19,6 +19,7 @@ import (
 	"configcenter/src/common/errors"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/metric"
+	"configcenter/src/common/openapi"
 	"configcenter/src/common/rdapi"
 	"configcenter/src/common/types"
 )
@@ -91,6 +92,7 @@ func (s *service) RootWebService() *restful.WebService {
 
 	ws.Route(ws.GET("/healthz").To(s.healthz))
 	ws.Route(ws.GET("/version").To(s.Version))
+	ws.Route(ws.GET(openapi.DocumentPath).To(s.OpenAPIDocument))
 
 	return ws
 }
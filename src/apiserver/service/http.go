@@ -16,11 +16,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/tracing"
 
 	"github.com/emicklei/go-restful"
 )
@@ -63,8 +65,13 @@ func (s *service) Do(req *restful.Request, resp *restful.Response) {
 		}
 	}
 
+	span := tracing.StartClientSpan(req.Request.Context(), req.Request.Header, "proxy "+req.Request.Method+" "+req.Request.URL.Path)
+	span.SetAttribute("http.method", req.Request.Method)
+	span.SetAttribute("http.url", url)
+	tracing.InjectHeader(span, proxyReq.Header)
 	response, err := s.client.Do(proxyReq)
 	if err != nil {
+		span.Finish(err)
 		blog.Errorf("*failed do request[%s url: %s] , err: %v", req.Request.Method, url, err)
 
 		if err := resp.WriteError(http.StatusInternalServerError, &metadata.RespError{
@@ -76,6 +83,8 @@ func (s *service) Do(req *restful.Request, resp *restful.Response) {
 		}
 		return
 	}
+	span.SetAttribute("http.status_code", strconv.Itoa(response.StatusCode))
+	span.End()
 	blog.V(5).Infof("success [%s] do request[%s url: %s]  ", response.Status, req.Request.Method, url)
 
 	defer response.Body.Close()
@@ -13,6 +13,8 @@
 package service
 
 import (
+	"net/http"
+
 	"configcenter/src/apimachinery/discovery"
 	"configcenter/src/apiserver/core"
 	compatiblev2 "configcenter/src/apiserver/core/compatiblev2/service"
@@ -20,6 +22,7 @@ import (
 	"configcenter/src/auth/authcenter"
 	"configcenter/src/common/backbone"
 	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
 	"configcenter/src/common/rdapi"
 
 	"github.com/emicklei/go-restful"
@@ -29,12 +32,16 @@ import (
 type Service interface {
 	WebServices(auth authcenter.AuthConfig) []*restful.WebService
 	SetConfig(enableAuth bool, engine *backbone.Engine, httpClient HTTPClient, discovery discovery.DiscoveryInterface, authorize auth.Authorize)
+	SetAPIKeyConfig(conf APIKeyConfig)
+	// SetHandler set the handler which serve all the apis, it's used to dispatch the sub requests of batch request
+	SetHandler(handler http.Handler)
 }
 
 // NewService create a new service instance
 func NewService() Service {
 	return &service{
-		core: core.New(nil, compatiblev2.New(nil)),
+		core:    core.New(nil, compatiblev2.New(nil)),
+		apiKeys: newAPIKeyManager(APIKeyConfig{DefaultQPS: defaultAPIKeyQPS, DefaultBurst: defaultAPIKeyBurst}),
 	}
 }
 
@@ -45,6 +52,10 @@ type service struct {
 	core       core.Core
 	discovery  discovery.DiscoveryInterface
 	authorizer auth.Authorizer
+	apiKeys    *apiKeyManager
+	// webServices the web services of apiserver itself, used to generate the api document
+	webServices []*restful.WebService
+	handler     http.Handler
 }
 
 func (s *service) SetConfig(enableAuth bool, engine *backbone.Engine, httpClient HTTPClient, discovery discovery.DiscoveryInterface, authorize auth.Authorize) {
@@ -56,6 +67,14 @@ func (s *service) SetConfig(enableAuth bool, engine *backbone.Engine, httpClient
 	s.authorizer = authorize
 }
 
+func (s *service) SetAPIKeyConfig(conf APIKeyConfig) {
+	s.apiKeys = newAPIKeyManager(conf)
+}
+
+func (s *service) SetHandler(handler http.Handler) {
+	s.handler = handler
+}
+
 func (s *service) WebServices(auth authcenter.AuthConfig) []*restful.WebService {
 	getErrFun := func() errors.CCErrorIf {
 		return s.engine.CCErr
@@ -64,7 +83,7 @@ func (s *service) WebServices(auth authcenter.AuthConfig) []*restful.WebService
 	ws := &restful.WebService{}
 	ws.Path(rootPath)
 	ws.Filter(s.engine.Metric().RestfulMiddleWare)
-	ws.Filter(rdapi.AllGlobalFilter(getErrFun))
+	s.addGlobalFilters(ws, getErrFun)
 	ws.Produces(restful.MIME_JSON)
 	if s.authorizer.Enabled() == true {
 		ws.Filter(s.authFilter(getErrFun))
@@ -74,6 +93,12 @@ func (s *service) WebServices(auth authcenter.AuthConfig) []*restful.WebService
 	ws.Route(ws.GET("/auth/admin_entrance").To(s.GetAdminEntrance))
 	ws.Route(ws.POST("/auth/skip_url").To(s.GetUserNoAuthSkipURL))
 	ws.Route(ws.POST("/auth/convert").To(s.GetCmdbConvertResources))
+	ws.Route(ws.POST("/apikey/create").To(s.CreateAPIKey).
+		Reads(metadata.CreateAPIKeyRequest{}).Writes(metadata.CreateAPIKeyResult{}))
+	ws.Route(ws.POST("/apikey/search").To(s.SearchAPIKey).
+		Reads(metadata.SearchAPIKeyRequest{}).Writes(metadata.SearchAPIKeyResult{}))
+	ws.Route(ws.DELETE("/apikey/{id}").To(s.DeleteAPIKey))
+	ws.Route(ws.POST("/batch").To(s.Batch).Reads(metadata.BatchRequest{}).Writes(metadata.BatchResult{}))
 	ws.Route(ws.GET("{.*}").Filter(s.URLFilterChan).To(s.Get))
 	ws.Route(ws.POST("{.*}").Filter(s.URLFilterChan).To(s.Post))
 	ws.Route(ws.PUT("{.*}").Filter(s.URLFilterChan).To(s.Put))
@@ -83,5 +108,15 @@ func (s *service) WebServices(auth authcenter.AuthConfig) []*restful.WebService
 	allWebServices = append(allWebServices, ws)
 	allWebServices = append(allWebServices, s.RootWebService())
 	allWebServices = append(allWebServices, s.core.CompatibleV2Operation().WebService())
+	s.webServices = allWebServices
 	return allWebServices
 }
+
+// addGlobalFilters add the filters which check the api key and the user of the requests, the api key
+// filter must run before the user check, for the request with api key only gets the user and the
+// supplier account from the api key.
+func (s *service) addGlobalFilters(ws *restful.WebService, errFunc func() errors.CCErrorIf) {
+	ws.Filter(rdapi.HTTPRequestIDFilter(errFunc))
+	ws.Filter(s.apiKeyFilter(errFunc))
+	ws.Filter(rdapi.HTTPAuthFilter(errFunc))
+}
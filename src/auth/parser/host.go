@@ -31,12 +31,13 @@ func (ps *parseStream) hostRelated() *parseStream {
 }
 
 var (
-	createUserAPIPattern     = "/api/v3/userapi"
-	updateUserAPIRegexp      = regexp.MustCompile(`^/api/v3/userapi/[0-9]+/[^\s/]+/?$`)
-	deleteUserAPIRegexp      = regexp.MustCompile(`^/api/v3/userapi/[0-9]+/[^\s/]+/?$`)
-	findUserAPIRegexp        = regexp.MustCompile(`^/api/v3/userapi/search/[0-9]+/?$`)
-	findUserAPIDetailsRegexp = regexp.MustCompile(`^/api/v3/userapi/detail/[0-9]+/[^\s/]+/?$`)
-	findWithUserAPIRegexp    = regexp.MustCompile(`^/api/v3/userapi/data/[0-9]+/[^\s/]+/[0-9]+/[0-9]+/?$`)
+	createUserAPIPattern       = "/api/v3/userapi"
+	updateUserAPIRegexp        = regexp.MustCompile(`^/api/v3/userapi/[0-9]+/[^\s/]+/?$`)
+	deleteUserAPIRegexp        = regexp.MustCompile(`^/api/v3/userapi/[0-9]+/[^\s/]+/?$`)
+	findUserAPIRegexp          = regexp.MustCompile(`^/api/v3/userapi/search/[0-9]+/?$`)
+	findUserAPIDetailsRegexp   = regexp.MustCompile(`^/api/v3/userapi/detail/[0-9]+/[^\s/]+/?$`)
+	findWithUserAPIRegexp      = regexp.MustCompile(`^/api/v3/userapi/data/[0-9]+/[^\s/]+/[0-9]+/[0-9]+/?$`)
+	findDynamicGroupDataRegexp = regexp.MustCompile(`^/api/v3/userapi/data/[0-9]+/[^\s/]+/?$`)
 )
 
 func (ps *parseStream) parseBusinessID() (int64, error) {
@@ -199,6 +200,32 @@ func (ps *parseStream) userAPI() *parseStream {
 		return ps
 	}
 
+	// get data of the dynamic group with selected fields and paging.
+	if ps.hitRegexp(findDynamicGroupDataRegexp, http.MethodPost) {
+		if len(ps.RequestCtx.Elements) != 6 {
+			ps.err = errors.New("find dynamic group data, but got invalid uri")
+			return ps
+		}
+
+		bizID, err := strconv.ParseInt(ps.RequestCtx.Elements[4], 10, 64)
+		if err != nil {
+			ps.err = fmt.Errorf("find dynamic group data failed, err: %v", err)
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			meta.ResourceAttribute{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:   meta.DynamicGrouping,
+					Action: meta.Execute,
+					Name:   ps.RequestCtx.Elements[5],
+				},
+			},
+		}
+		return ps
+	}
+
 	return ps
 }
 
@@ -272,6 +299,8 @@ const (
 	findHostsWithConditionPattern     = "/api/v3/hosts/search"
 	findBizHostsWithoutAppPattern     = "/api/v3/hosts/list_hosts_without_app"
 	findHostsDetailsPattern           = "/api/v3/hosts/search/asstdetail"
+	findHostsStreamPattern            = "/api/v3/hosts/search/stream"
+	findHostsStatisticsPattern        = "/api/v3/hosts/statistics"
 	updateHostInfoBatchPattern        = "/api/v3/hosts/batch"
 	findHostsWithModulesPattern       = "/api/v3/hosts/findmany/modulehost"
 )
@@ -574,7 +603,8 @@ func (ps *parseStream) host() *parseStream {
 		return ps
 	}
 
-	if ps.hitPattern(findHostsDetailsPattern, http.MethodPost) {
+	if ps.hitPattern(findHostsDetailsPattern, http.MethodPost) || ps.hitPattern(findHostsStreamPattern, http.MethodPost) ||
+		ps.hitPattern(findHostsStatisticsPattern, http.MethodPost) {
 		bizID, err := ps.parseBusinessID()
 		if err != nil {
 			ps.err = err
@@ -24,6 +24,8 @@ import (
 	"configcenter/src/common/blog"
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
+
+	"github.com/tidwall/gjson"
 )
 
 func (ps *parseStream) topology() *parseStream {
@@ -44,10 +46,17 @@ func (ps *parseStream) topology() *parseStream {
 		ObjectModule().
 		ObjectSet().
 		objectUnique().
+		objectSchema().
+		objectLifecycle().
+		fieldSource().
+		modelBundle().
+		recycleBin().
+		apiKey().
 		audit().
 		instanceAudit().
 		privilege().
-		fullTextSearch()
+		fullTextSearch().
+		graphQL()
 
 	return ps
 }
@@ -578,8 +587,9 @@ func (ps *parseStream) objectAssociation() *parseStream {
 }
 
 const (
-	findObjectInstanceAssociationPattern   = "/api/v3/inst/association/action/search"
-	createObjectInstanceAssociationPattern = "/api/v3/inst/association/action/create"
+	findObjectInstanceAssociationPattern     = "/api/v3/inst/association/action/search"
+	findObjectInstanceAssociationPagePattern = "/api/v3/inst/association/action/search/page"
+	createObjectInstanceAssociationPattern   = "/api/v3/inst/association/action/create"
 )
 
 var (
@@ -592,7 +602,8 @@ func (ps *parseStream) objectInstanceAssociation() *parseStream {
 	}
 
 	// find object instance's association operation.
-	if ps.RequestCtx.URI == findObjectInstanceAssociationPattern && ps.RequestCtx.Method == http.MethodPost {
+	if (ps.RequestCtx.URI == findObjectInstanceAssociationPattern || ps.RequestCtx.URI == findObjectInstanceAssociationPagePattern) &&
+		ps.RequestCtx.Method == http.MethodPost {
 		ps.Attribute.Resources = []meta.ResourceAttribute{
 			{
 				Basic: meta.Basic{
@@ -661,6 +672,8 @@ var (
 	findBusinessInstanceTopologyRegexp  = regexp.MustCompile(`^/api/v3/topo/inst/[^\s/]+/[0-9]+/?$`)
 	findObjectInstancesRegexp           = regexp.MustCompile(`^/api/v3/inst/search/owner/[^\s/]+/object/[^\s/]+/?$`)
 	findObjectInstancesDetailRegexp     = regexp.MustCompile(`^/api/v3/inst/search/owner/[^\s/]+/object/[^\s/]+/detail/?$`)
+	findObjectInstancesStatisticsRegexp = regexp.MustCompile(`^/api/v3/inst/search/owner/[^\s/]+/object/[^\s/]+/statistics/?$`)
+	findObjectInstancesStreamRegexp     = regexp.MustCompile(`^/api/v3/inst/search/owner/[^\s/]+/object/[^\s/]+/stream/?$`)
 )
 
 func (ps *parseStream) objectInstance() *parseStream {
@@ -993,7 +1006,9 @@ func (ps *parseStream) objectInstance() *parseStream {
 	}
 
 	// find object/s instance list details operation.
-	if ps.hitRegexp(findObjectInstancesDetailRegexp, http.MethodPost) {
+	if ps.hitRegexp(findObjectInstancesDetailRegexp, http.MethodPost) ||
+		ps.hitRegexp(findObjectInstancesStatisticsRegexp, http.MethodPost) ||
+		ps.hitRegexp(findObjectInstancesStreamRegexp, http.MethodPost) {
 		// TODO: parse these query condition
 		models, err := ps.getModel(mapstr.MapStr{common.BKObjIDField: ps.RequestCtx.Elements[7]})
 		if err != nil {
@@ -1970,6 +1985,473 @@ func (ps *parseStream) objectUnique() *parseStream {
 	return ps
 }
 
+var (
+	findObjectSchemaVersionsRegexp    = regexp.MustCompile(`^/api/v3/object/[^\s/]+/schema/versions/action/search$`)
+	findObjectSchemaVersionRegexp     = regexp.MustCompile(`^/api/v3/object/[^\s/]+/schema/version/[0-9]+$`)
+	diffObjectSchemaVersionRegexp     = regexp.MustCompile(`^/api/v3/object/[^\s/]+/schema/diff/[0-9]+/[0-9]+$`)
+	rollbackObjectSchemaVersionRegexp = regexp.MustCompile(`^/api/v3/object/[^\s/]+/schema/version/[0-9]+/action/rollback$`)
+)
+
+func (ps *parseStream) objectSchema() *parseStream {
+	if ps.shouldReturn() {
+		return ps
+	}
+
+	// find object schema versions operation.
+	if ps.hitRegexp(findObjectSchemaVersionsRegexp, http.MethodPost) ||
+		ps.hitRegexp(findObjectSchemaVersionRegexp, http.MethodGet) ||
+		ps.hitRegexp(diffObjectSchemaVersionRegexp, http.MethodGet) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.Model,
+					Action: meta.FindMany,
+				},
+			},
+		}
+		return ps
+	}
+
+	// rollback object schema operation, which is an update of the model.
+	if ps.hitRegexp(rollbackObjectSchemaVersionRegexp, http.MethodPost) {
+		models, err := ps.getModel(mapstr.MapStr{common.BKObjIDField: ps.RequestCtx.Elements[3]})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+		if len(models) == 0 {
+			ps.err = fmt.Errorf("rollback object schema, but got invalid object %s", ps.RequestCtx.Elements[3])
+			return ps
+		}
+
+		for _, model := range models {
+			bizID, err := metadata.BizIDFromMetadata(model.Metadata)
+			if err != nil {
+				ps.err = err
+				return ps
+			}
+			ps.Attribute.Resources = append(ps.Attribute.Resources, meta.ResourceAttribute{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:       meta.Model,
+					Action:     meta.Update,
+					InstanceID: model.ID,
+				},
+			})
+		}
+		return ps
+	}
+
+	return ps
+}
+
+var (
+	objectLifecycleRegexp      = regexp.MustCompile(`^/api/v3/object/[^\s/]+/lifecycle/?$`)
+	findLifecycleHistoryRegexp = regexp.MustCompile(`^/api/v3/object/[^\s/]+/lifecycle/history/action/search/?$`)
+)
+
+func (ps *parseStream) objectLifecycle() *parseStream {
+	if ps.shouldReturn() {
+		return ps
+	}
+
+	// find the lifecycle of the object and the state transitions of its instances.
+	if ps.hitRegexp(objectLifecycleRegexp, http.MethodGet) || ps.hitRegexp(findLifecycleHistoryRegexp, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.Model,
+					Action: meta.FindMany,
+				},
+			},
+		}
+		return ps
+	}
+
+	// set or delete the lifecycle of the object, which is an update of the model.
+	if ps.hitRegexp(objectLifecycleRegexp, http.MethodPut) || ps.hitRegexp(objectLifecycleRegexp, http.MethodDelete) {
+		models, err := ps.getModel(mapstr.MapStr{common.BKObjIDField: ps.RequestCtx.Elements[3]})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+		if len(models) == 0 {
+			ps.err = fmt.Errorf("update object lifecycle, but got invalid object %s", ps.RequestCtx.Elements[3])
+			return ps
+		}
+
+		for _, model := range models {
+			bizID, err := metadata.BizIDFromMetadata(model.Metadata)
+			if err != nil {
+				ps.err = err
+				return ps
+			}
+			ps.Attribute.Resources = append(ps.Attribute.Resources, meta.ResourceAttribute{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:       meta.Model,
+					Action:     meta.Update,
+					InstanceID: model.ID,
+				},
+			})
+		}
+		return ps
+	}
+
+	return ps
+}
+
+var (
+	fieldSourcePriorityRegexp       = regexp.MustCompile(`^/api/v3/object/[^\s/]+/field_source/priority/?$`)
+	deleteFieldSourcePriorityRegexp = regexp.MustCompile(`^/api/v3/object/[^\s/]+/field_source/priority/[^\s/]+/?$`)
+	findInstFieldSourceRegexp       = regexp.MustCompile(`^/api/v3/inst/[^\s/]+/[0-9]+/field_source/?$`)
+)
+
+func (ps *parseStream) fieldSource() *parseStream {
+	if ps.shouldReturn() {
+		return ps
+	}
+
+	// find the source priorities of the object fields.
+	if ps.hitRegexp(fieldSourcePriorityRegexp, http.MethodGet) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.Model,
+					Action: meta.FindMany,
+				},
+			},
+		}
+		return ps
+	}
+
+	// set or delete the source priority of the object field, which is an update of the model.
+	if ps.hitRegexp(fieldSourcePriorityRegexp, http.MethodPut) || ps.hitRegexp(deleteFieldSourcePriorityRegexp, http.MethodDelete) {
+		models, err := ps.getModel(mapstr.MapStr{common.BKObjIDField: ps.RequestCtx.Elements[3]})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+		if len(models) == 0 {
+			ps.err = fmt.Errorf("update field source priority, but got invalid object %s", ps.RequestCtx.Elements[3])
+			return ps
+		}
+
+		for _, model := range models {
+			bizID, err := metadata.BizIDFromMetadata(model.Metadata)
+			if err != nil {
+				ps.err = err
+				return ps
+			}
+			ps.Attribute.Resources = append(ps.Attribute.Resources, meta.ResourceAttribute{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:       meta.Model,
+					Action:     meta.Update,
+					InstanceID: model.ID,
+				},
+			})
+		}
+		return ps
+	}
+
+	// find the sources which last set the fields of the instance.
+	if ps.hitRegexp(findInstFieldSourceRegexp, http.MethodGet) {
+		instID, err := strconv.ParseInt(ps.RequestCtx.Elements[4], 10, 64)
+		if err != nil {
+			ps.err = fmt.Errorf("find instance field source, but got invalid instance id %s", ps.RequestCtx.Elements[4])
+			return ps
+		}
+		bizID, err := metadata.BizIDFromMetadata(ps.RequestCtx.Metadata)
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:       meta.ModelInstance,
+					Action:     meta.Find,
+					InstanceID: instID,
+				},
+				Layers: []meta.Item{
+					{
+						Type: meta.Model,
+						Name: ps.RequestCtx.Elements[3],
+					},
+				},
+			},
+		}
+		return ps
+	}
+
+	return ps
+}
+
+const (
+	exportModelBundlePattern = "/api/v3/model/bundle/action/export"
+	planModelBundlePattern   = "/api/v3/model/bundle/action/plan"
+	applyModelBundlePattern  = "/api/v3/model/bundle/action/apply"
+)
+
+func (ps *parseStream) modelBundle() *parseStream {
+	if ps.shouldReturn() {
+		return ps
+	}
+
+	// export and plan the model bundle, which only read the models.
+	if ps.hitPattern(exportModelBundlePattern, http.MethodPost) || ps.hitPattern(planModelBundlePattern, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.Model,
+					Action: meta.FindMany,
+				},
+			},
+		}
+		return ps
+	}
+
+	// apply the model bundle, which may create classifications and models, and update the models.
+	if ps.hitPattern(applyModelBundlePattern, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.ModelClassification,
+					Action: meta.Create,
+				},
+			},
+			{
+				Basic: meta.Basic{
+					Type:   meta.Model,
+					Action: meta.Create,
+				},
+			},
+			{
+				Basic: meta.Basic{
+					Type:   meta.Model,
+					Action: meta.UpdateMany,
+				},
+			},
+		}
+		return ps
+	}
+
+	return ps
+}
+
+const (
+	searchRecycleItemsPattern = "/api/v3/recycle/items/action/search"
+	purgeRecycleItemsPattern  = "/api/v3/recycle/items/action/purge"
+)
+
+var restoreRecycleItemRegexp = regexp.MustCompile(`^/api/v3/recycle/item/[0-9]+/action/restore/?$`)
+
+func (ps *parseStream) recycleBin() *parseStream {
+	if ps.shouldReturn() {
+		return ps
+	}
+
+	// search the deleted records in the recycle bin, the records of a model are authorized as the instances
+	// of the model, while searching the records of all the models needs the permission of the system.
+	if ps.hitPattern(searchRecycleItemsPattern, http.MethodPost) {
+		kind := gjson.GetBytes(ps.RequestCtx.Body, "condition.kind").String()
+		objID := gjson.GetBytes(ps.RequestCtx.Body, "condition."+common.BKObjIDField).String()
+		switch {
+		case kind == metadata.RecycleKindHost || (kind == "" && objID == common.BKInnerObjIDHost):
+			ps.Attribute.Resources = []meta.ResourceAttribute{
+				{
+					Basic: meta.Basic{
+						Type:   meta.HostInstance,
+						Action: meta.FindMany,
+					},
+				},
+			}
+		case kind == metadata.RecycleKindInstance && objID != "":
+			ps.Attribute.Resources = []meta.ResourceAttribute{
+				{
+					Basic: meta.Basic{
+						Type:   meta.ModelInstance,
+						Action: meta.FindMany,
+					},
+					Layers: []meta.Item{
+						{
+							Type: meta.Model,
+							Name: objID,
+						},
+					},
+				},
+			}
+		case kind == metadata.RecycleKindModel:
+			ps.Attribute.Resources = []meta.ResourceAttribute{
+				{
+					Basic: meta.Basic{
+						Type:   meta.Model,
+						Action: meta.FindMany,
+					},
+				},
+			}
+		default:
+			ps.Attribute.Resources = []meta.ResourceAttribute{
+				{
+					Basic: meta.Basic{
+						Type:   meta.SystemBase,
+						Action: meta.Find,
+					},
+				},
+			}
+		}
+		return ps
+	}
+
+	// restore a deleted record, which creates the record again.
+	if ps.hitRegexp(restoreRecycleItemRegexp, http.MethodPost) {
+		if len(ps.RequestCtx.Elements) != 7 {
+			ps.err = errors.New("restore recycle item, but got invalid url")
+			return ps
+		}
+		id, err := strconv.ParseInt(ps.RequestCtx.Elements[4], 10, 64)
+		if err != nil {
+			ps.err = fmt.Errorf("restore recycle item, but got invalid id %s", ps.RequestCtx.Elements[4])
+			return ps
+		}
+		ps.Attribute.Resources, ps.err = ps.recycleItemResources([]int64{id}, meta.Create)
+		return ps
+	}
+
+	// delete the records in the recycle bin permanently.
+	if ps.hitPattern(purgeRecycleItemsPattern, http.MethodPost) {
+		ids := make([]int64, 0)
+		for _, id := range gjson.GetBytes(ps.RequestCtx.Body, "ids").Array() {
+			ids = append(ids, id.Int())
+		}
+		if len(ids) == 0 {
+			ps.err = errors.New("purge recycle items, but got no ids")
+			return ps
+		}
+		ps.Attribute.Resources, ps.err = ps.recycleItemResources(ids, meta.Delete)
+		return ps
+	}
+
+	return ps
+}
+
+// recycleItemResources returns the resources of the recycled records, which are the original instances,
+// hosts and models, so that a record is operated only by the ones who could operate the original one.
+func (ps *parseStream) recycleItemResources(ids []int64, action meta.Action) ([]meta.ResourceAttribute, error) {
+	items, err := ps.getRecycleItems(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]meta.ResourceAttribute, 0, len(items))
+	for _, item := range items {
+		switch item.Kind {
+		case metadata.RecycleKindInstance:
+			bizID, err := metadata.ParseBizIDFromData(item.Data)
+			if err != nil {
+				bizID = 0
+			}
+			resources = append(resources, meta.ResourceAttribute{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:       meta.ModelInstance,
+					Action:     action,
+					InstanceID: item.InstID,
+				},
+				Layers: []meta.Item{
+					{
+						Type: meta.Model,
+						Name: item.ObjectID,
+					},
+				},
+			})
+		case metadata.RecycleKindHost:
+			var bizID int64
+			if len(item.HostRelations) > 0 {
+				bizID, _ = item.HostRelations[0].Int64(common.BKAppIDField)
+			}
+			resources = append(resources, meta.ResourceAttribute{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:       meta.HostInstance,
+					Action:     action,
+					InstanceID: item.InstID,
+				},
+			})
+		case metadata.RecycleKindModel:
+			resources = append(resources, meta.ResourceAttribute{
+				Basic: meta.Basic{
+					Type:       meta.Model,
+					Action:     action,
+					InstanceID: item.InstID,
+				},
+			})
+		default:
+			return nil, fmt.Errorf("recycle item %d has unknown kind %s", item.ID, item.Kind)
+		}
+	}
+	return resources, nil
+}
+
+const (
+	createAPIKeyPattern = "/api/v3/apikey/create"
+	searchAPIKeyPattern = "/api/v3/apikey/search"
+)
+
+var deleteAPIKeyRegexp = regexp.MustCompile(`^/api/v3/apikey/[0-9]+/?$`)
+
+// apiKey the api keys are managed by the user self, the ownership is checked by apiserver,
+// here is the permission to manage api keys at all.
+func (ps *parseStream) apiKey() *parseStream {
+	if ps.shouldReturn() {
+		return ps
+	}
+
+	// issue a api key.
+	if ps.hitPattern(createAPIKeyPattern, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.SystemBase,
+					Action: meta.Create,
+				},
+			},
+		}
+		return ps
+	}
+
+	// search the api keys.
+	if ps.hitPattern(searchAPIKeyPattern, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.SystemBase,
+					Action: meta.Find,
+				},
+			},
+		}
+		return ps
+	}
+
+	// revoke a api key.
+	if ps.hitRegexp(deleteAPIKeyRegexp, http.MethodDelete) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.SystemBase,
+					Action: meta.Delete,
+				},
+			},
+		}
+		return ps
+	}
+
+	return ps
+}
+
 var (
 	searchAuditlog               = `/api/v3/audit/search`
 	searchInstanceAuditlogRegexp = regexp.MustCompile(`^/api/v3/object/[^\s/]+/audit/search/?$`)
@@ -2065,3 +2547,28 @@ func (ps *parseStream) fullTextSearch() *parseStream {
 
 	return ps
 }
+
+const (
+	graphQLQueryPattern  = "/api/v3/topo/graphql"
+	graphQLSchemaPattern = "/api/v3/topo/graphql/schema"
+)
+
+// graphQL the instances are authorized when they are resolved in the query
+func (ps *parseStream) graphQL() *parseStream {
+	if ps.shouldReturn() {
+		return ps
+	}
+
+	if ps.hitPattern(graphQLQueryPattern, http.MethodPost) || ps.hitPattern(graphQLSchemaPattern, http.MethodGet) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Action: meta.SkipAction,
+				},
+			},
+		}
+		return ps
+	}
+
+	return ps
+}
@@ -143,3 +143,23 @@ func (ps *parseStream) getInstAssociation(cond mapstr.MapStr) (metadata.InstAsst
 
 	return asst.Data.Info[0], nil
 }
+
+func (ps *parseStream) getRecycleItems(ids []int64) ([]metadata.RecycleItem, error) {
+	cond := mapstr.MapStr{common.BKFieldID: mapstr.MapStr{common.BKDBIN: ids}}
+	fields := []string{common.BKFieldID, "kind", common.BKObjIDField, common.BKInstIDField, "data.metadata", "host_relations"}
+	rsp, err := ps.engine.CoreAPI.CoreService().RecycleBin().SearchRecycleItems(context.Background(), ps.RequestCtx.Header,
+		&metadata.QueryCondition{Condition: cond, Fields: fields})
+	if err != nil {
+		return nil, err
+	}
+
+	if !rsp.Result {
+		return nil, errors.New(rsp.Code, rsp.ErrMsg)
+	}
+
+	if len(rsp.Data.Info) != len(ids) {
+		return nil, fmt.Errorf("recycle items %v not found", ids)
+	}
+
+	return rsp.Data.Info, nil
+}
@@ -16,6 +16,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
@@ -29,6 +30,8 @@ import (
 	"configcenter/src/common/errors"
 	"configcenter/src/common/language"
 	"configcenter/src/common/metrics"
+	"configcenter/src/common/registerdiscover"
+	"configcenter/src/common/tracing"
 	"configcenter/src/common/types"
 )
 
@@ -71,6 +74,30 @@ func newSvcManagerClient(ctx context.Context, svcManagerAddr string) (*zk.ZkClie
 	return nil, err
 }
 
+// newRegDiscover connect to the register-discover service selected by the scheme of the regdiscv address,
+// the zookeeper client is nil if the service is not zookeeper.
+func newRegDiscover(ctx context.Context, regdiscv string) (*zk.ZkClient, *registerdiscover.RegDiscover, error) {
+	scheme, addr := registerdiscover.ParseAddress(regdiscv)
+	switch scheme {
+	case registerdiscover.SchemeZookeeper:
+		client, err := newSvcManagerClient(ctx, addr)
+		if err != nil {
+			return nil, nil, err
+		}
+		return client, registerdiscover.NewRegDiscoverEx(client), nil
+	case registerdiscover.SchemeFile:
+		rdServer, err := registerdiscover.NewFileRegDiscv(ctx, addr)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, registerdiscover.NewRegDiscoverWithServer(rdServer), nil
+	case registerdiscover.SchemeDNS:
+		return nil, registerdiscover.NewRegDiscoverWithServer(registerdiscover.NewDNSRegDiscv(ctx, addr)), nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported regdiscv scheme %s", scheme)
+	}
+}
+
 func newConfig(ctx context.Context, srvInfo *types.ServerInfo, discovery discovery.DiscoveryInterface, apiMachineryConfig *util.APIMachineryConfig) (*Config, error) {
 
 	machinery, err := apimachinery.NewApiMachinery(apiMachineryConfig, discovery)
@@ -92,6 +119,10 @@ func validateParameter(input *BackboneParameter) error {
 	if input.Regdiscv == "" {
 		return fmt.Errorf("regdiscv can not be emtpy")
 	}
+	// the config center depends on zookeeper, the config must be loaded from file without it
+	if scheme, _ := registerdiscover.ParseAddress(input.Regdiscv); scheme != registerdiscover.SchemeZookeeper && input.ConfigPath == "" {
+		return fmt.Errorf("config path can not be empty when regdiscv is not zookeeper")
+	}
 	if input.SrvInfo.IP == "" {
 		return fmt.Errorf("addrport ip can not be emtpy")
 	}
@@ -114,15 +145,15 @@ func NewBackbone(ctx context.Context, input *BackboneParameter) (*Engine, error)
 	metricService := metrics.NewService(metrics.Config{ProcessName: common.GetIdentification(), ProcessInstance: input.SrvInfo.Instance()})
 
 	common.SetServerInfo(input.SrvInfo)
-	client, err := newSvcManagerClient(ctx, input.Regdiscv)
+	client, regDiscover, err := newRegDiscover(ctx, input.Regdiscv)
 	if err != nil {
 		return nil, fmt.Errorf("connect regdiscv [%s] failed: %v", input.Regdiscv, err)
 	}
-	serviceDiscovery, err := discovery.NewServiceDiscovery(client)
+	serviceDiscovery, err := discovery.NewServiceDiscoveryWithRegDiscover(regDiscover)
 	if err != nil {
 		return nil, fmt.Errorf("connect regdiscv [%s] failed: %v", input.Regdiscv, err)
 	}
-	disc, err := NewServiceRegister(client)
+	disc, err := NewServiceRegisterWithRegDiscover(regDiscover)
 	if err != nil {
 		return nil, fmt.Errorf("new service discover failed, err:%v", err)
 	}
@@ -148,12 +179,19 @@ func NewBackbone(ctx context.Context, input *BackboneParameter) (*Engine, error)
 	engine.metric = metricService
 
 	handler := &cc.CCHandler{
-		OnProcessUpdate:  input.ConfigUpdate,
+		OnProcessUpdate:  engine.onProcessUpdate(input.ConfigUpdate),
 		OnLanguageUpdate: engine.onLanguageUpdate,
 		OnErrorUpdate:    engine.onErrorUpdate,
 	}
 
-	err = cc.NewConfigCenter(ctx, client, common.GetIdentification(), input.ConfigPath, handler)
+	// the config path can be a directory of the config files, which are reloaded once they are changed
+	if info, statErr := os.Stat(input.ConfigPath); input.ConfigPath != "" && statErr == nil && info.IsDir() {
+		err = cc.NewConfigCenterWithDir(ctx, common.GetIdentification(), input.ConfigPath, handler)
+	} else if client != nil {
+		err = cc.NewConfigCenter(ctx, client, common.GetIdentification(), input.ConfigPath, handler)
+	} else {
+		err = cc.New(ctx, common.GetIdentification(), input.ConfigPath, nil, handler)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("new config center failed, err: %v", err)
 	}
@@ -219,6 +257,7 @@ func (e *Engine) ApiMachineryConfig() *util.APIMachineryConfig {
 	return e.apiMachineryConfig
 }
 
+// ServiceManageClient returns the zookeeper client, it's nil if regdiscv is not zookeeper
 func (e *Engine) ServiceManageClient() *zk.ZkClient {
 	return e.client
 }
@@ -227,6 +266,19 @@ func (e *Engine) Metric() *metrics.Service {
 	return e.metric
 }
 
+// onProcessUpdate setup the tracing with the process config before handled by the service
+func (e *Engine) onProcessUpdate(handler cc.ProcHandlerFunc) cc.ProcHandlerFunc {
+	return func(previous, current cc.ProcessConfig) {
+		conf, err := tracing.ParseConfig(common.GetIdentification(), current.ConfigMap)
+		if err != nil {
+			blog.Errorf("parse tracing config failed, err: %v", err)
+		} else if err := tracing.Init(conf); err != nil {
+			blog.Errorf("init tracing failed, err: %v", err)
+		}
+		handler(previous, current)
+	}
+}
+
 func (e *Engine) onLanguageUpdate(previous, current map[string]language.LanguageMap) {
 	e.Lock()
 	defer e.Unlock()
@@ -35,6 +35,15 @@ func NewConfigCenter(ctx context.Context, client *zk.ZkClient, procName string,
 	return New(ctx, procName, confPath, disc, handler)
 }
 
+// NewConfigCenterWithDir load the configs from the directory, and reload them when the files are changed
+func NewConfigCenterWithDir(ctx context.Context, procName string, confDir string, handler *CCHandler) error {
+	disc := crd.NewFileRegDiscover(ctx, confDir)
+	if err := disc.Ping(); err != nil {
+		return err
+	}
+	return New(ctx, procName, "", disc, handler)
+}
+
 func New(ctx context.Context, procName string, confPath string, disc crd.ConfRegDiscvIf, handler *CCHandler) error {
 	confC = &CC{
 		ctx:           ctx,
@@ -99,16 +108,18 @@ func (c *CC) run() error {
 	}
 
 	go func() {
-		select {
-		case pEvent := <-procEvent:
-			c.onProcChange(pEvent)
-		case eEvent := <-errEvent:
-			c.onErrorChange(eEvent)
-		case langEvent := <-langEvent:
-			c.onLanguageChange(langEvent)
-		case <-c.ctx.Done():
-			blog.Warnf("config center event watch stopped because of context done.")
-			return
+		for {
+			select {
+			case pEvent := <-procEvent:
+				c.onProcChange(pEvent)
+			case eEvent := <-errEvent:
+				c.onErrorChange(eEvent)
+			case langEvent := <-langEvent:
+				c.onLanguageChange(langEvent)
+			case <-c.ctx.Done():
+				blog.Warnf("config center event watch stopped because of context done.")
+				return
+			}
 		}
 	}()
 	return nil
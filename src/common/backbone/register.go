@@ -34,6 +34,11 @@ func NewServiceRegister(client *zk.ZkClient) (ServiceRegisterInterface, error) {
 	return s, nil
 }
 
+// NewServiceRegisterWithRegDiscover register the service to any register-discover service
+func NewServiceRegisterWithRegDiscover(rd *registerdiscover.RegDiscover) (ServiceRegisterInterface, error) {
+	return &serviceRegister{client: rd}, nil
+}
+
 type serviceRegister struct {
 	client *registerdiscover.RegDiscover
 }
@@ -0,0 +1,186 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package confregdiscover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/language"
+	"configcenter/src/common/types"
+)
+
+// the sub directories of the error code and language resources in the config directory
+const (
+	FileErrorsDir   = "errors"
+	FileLanguageDir = "language"
+)
+
+// fileWatchInterval the interval to check whether the config is changed
+var fileWatchInterval = 5 * time.Second
+
+// FileRegDiscover read the configs from the local directory, which has the same layout as the configures
+// written into zookeeper by adminserver:
+//
+//	<dir>/<module>.conf  the process config of the module, e.g. coreservice.conf
+//	<dir>/errors/        the error code resource
+//	<dir>/language/      the language resource
+//
+// the config is discovered again when the files are changed.
+type FileRegDiscover struct {
+	ctx context.Context
+	dir string
+
+	// cache the loaded resources, they are loaded again only if the files are changed
+	sync.Mutex
+	cache map[string]fileResource
+}
+
+type fileResource struct {
+	fingerprint string
+	data        string
+}
+
+// NewFileRegDiscover create a object of FileRegDiscover
+func NewFileRegDiscover(ctx context.Context, dir string) *FileRegDiscover {
+	return &FileRegDiscover{
+		ctx:   ctx,
+		dir:   dir,
+		cache: make(map[string]fileResource),
+	}
+}
+
+// Ping check the config directory exists
+func (fileRD *FileRegDiscover) Ping() error {
+	info, err := os.Stat(fileRD.dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not directory", fileRD.dir)
+	}
+	return nil
+}
+
+// Write save the process config into the file, the error code and language resources can not be written
+func (fileRD *FileRegDiscover) Write(key string, data []byte) error {
+	if !strings.HasPrefix(key, types.CC_SERVCONF_BASEPATH+"/") {
+		return fmt.Errorf("config %s can not be written into file", key)
+	}
+	return ioutil.WriteFile(fileRD.confFile(key), data, 0644)
+}
+
+// Read the config data of the key from the files
+func (fileRD *FileRegDiscover) Read(key string) (string, error) {
+	var dir string
+	switch {
+	case key == types.CC_SERVERROR_BASEPATH:
+		dir = filepath.Join(fileRD.dir, FileErrorsDir)
+	case key == types.CC_SERVLANG_BASEPATH:
+		dir = filepath.Join(fileRD.dir, FileLanguageDir)
+	case strings.HasPrefix(key, types.CC_SERVCONF_BASEPATH+"/"):
+		data, err := ioutil.ReadFile(fileRD.confFile(key))
+		return string(data), err
+	default:
+		return "", fmt.Errorf("unknown config %s", key)
+	}
+
+	fingerprint, err := dirFingerprint(dir)
+	if err != nil {
+		return "", err
+	}
+	fileRD.Lock()
+	defer fileRD.Unlock()
+	if cached, ok := fileRD.cache[key]; ok && cached.fingerprint == fingerprint {
+		return cached.data, nil
+	}
+
+	var resource interface{}
+	if key == types.CC_SERVERROR_BASEPATH {
+		resource, err = errors.LoadErrorResourceFromDir(dir)
+	} else {
+		resource, err = language.LoadLanguageResourceFromDir(dir)
+	}
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return "", err
+	}
+	fileRD.cache[key] = fileResource{fingerprint: fingerprint, data: string(data)}
+	return string(data), nil
+}
+
+// dirFingerprint returns the fingerprint of the files in the directory, it's changed once any file is changed
+func dirFingerprint(dir string) (string, error) {
+	fingerprint := make([]string, 0)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		fingerprint = append(fingerprint, fmt.Sprintf("%s:%d:%d", path, info.Size(), info.ModTime().UnixNano()))
+		return nil
+	})
+	return strings.Join(fingerprint, ","), err
+}
+
+func (fileRD *FileRegDiscover) confFile(key string) string {
+	return filepath.Join(fileRD.dir, path.Base(key)+".conf")
+}
+
+// Discover send the config data at first, and send it again once it's changed
+func (fileRD *FileRegDiscover) Discover(key string) (<-chan *DiscoverEvent, error) {
+	env := make(chan *DiscoverEvent, 1)
+	go fileRD.loopDiscover(key, env)
+	return env, nil
+}
+
+func (fileRD *FileRegDiscover) loopDiscover(key string, env chan *DiscoverEvent) {
+	var last *string
+	ticker := time.NewTicker(fileWatchInterval)
+	defer ticker.Stop()
+	for {
+		data, err := fileRD.Read(key)
+		if err != nil {
+			blog.Errorf("read config %s from directory %s failed, err: %v", key, fileRD.dir, err)
+		} else if last == nil || *last != data {
+			if last != nil {
+				blog.Infof("config %s in directory %s is changed", key, fileRD.dir)
+			}
+			last = &data
+			select {
+			case env <- &DiscoverEvent{Key: key, Data: []byte(data)}:
+			case <-fileRD.ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-fileRD.ctx.Done():
+			blog.Infof("discover config %s done", key)
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package confregdiscover
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"configcenter/src/common/types"
+)
+
+func TestFileRegDiscover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "confregdiscover")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	confFile := filepath.Join(dir, "coreservice.conf")
+	if err := ioutil.WriteFile(confFile, []byte("[mongodb]\nhost=127.0.0.1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fileWatchInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rd := NewFileRegDiscover(ctx, dir)
+	if err := rd.Ping(); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	key := types.CC_SERVCONF_BASEPATH + "/coreservice"
+	events, err := rd.Discover(key)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if event := <-events; string(event.Data) != "[mongodb]\nhost=127.0.0.1\n" {
+		t.Errorf("Discover() data = %s", event.Data)
+	}
+
+	if err := rd.Write(key, []byte("[mongodb]\nhost=127.0.0.2\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	select {
+	case event := <-events:
+		if string(event.Data) != "[mongodb]\nhost=127.0.0.2\n" {
+			t.Errorf("Discover() data after changed = %s", event.Data)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Discover() is not notified after the file is changed")
+	}
+
+	if err := rd.Write(types.CC_SERVERROR_BASEPATH, []byte("{}")); err == nil {
+		t.Errorf("Write() error code resource should fail")
+	}
+}
@@ -169,6 +169,12 @@ const (
 	// BKDBNot the db opeartor
 	BKDBNot = "$not"
 
+	// BKDBNOR the db operator
+	BKDBNOR = "$nor"
+
+	// BKDBElemMatch the db operator
+	BKDBElemMatch = "$elemMatch"
+
 	// BKDBCount the db opeartor
 	BKDBCount = "$count"
 
@@ -181,6 +187,30 @@ const (
 	// BKDBSum the db opeartor
 	BKDBSum = "$sum"
 
+	// BKDBAvg the db operator
+	BKDBAvg = "$avg"
+
+	// BKDBMin the db operator
+	BKDBMin = "$min"
+
+	// BKDBMax the db operator
+	BKDBMax = "$max"
+
+	// BKDBFirst the db operator
+	BKDBFirst = "$first"
+
+	// BKDBLookUp the db operator
+	BKDBLookUp = "$lookup"
+
+	// BKDBUnwind the db operator
+	BKDBUnwind = "$unwind"
+
+	// BKDBSort the db operator
+	BKDBSort = "$sort"
+
+	// BKDBLimit the db operator
+	BKDBLimit = "$limit"
+
 	// BKDBPush the db opeartor
 	BKDBPush = "$push"
 
@@ -236,6 +266,12 @@ const (
 	// BKHostOuterIPField the host outerip field
 	BKHostOuterIPField = "bk_host_outerip"
 
+	// BKHostInnerIPKeyField the ordered keys of the host innerip, which is used to search host by ip range
+	BKHostInnerIPKeyField = "bk_host_innerip_key"
+
+	// BKHostOuterIPKeyField the ordered keys of the host outerip, which is used to search host by ip range
+	BKHostOuterIPKeyField = "bk_host_outerip_key"
+
 	// TimeTransferModel the time transferModel field
 	TimeTransferModel = "2006-01-02 15:04:05"
 
@@ -857,6 +893,24 @@ const (
 	BKHTTPCCRequestTime     = "Cc_Request_Time"
 	BKHTTPCCTransactionID   = "Cc_Txn_Id"
 	BKHTTPCCTxnTMServerAddr = "Cc_Txn_Tm_addr-Ip"
+
+	// BKHTTPAPIKey the api key which issued to a application by apiserver
+	BKHTTPAPIKey = "BK_API_Key"
+	// BKHTTPAppCode the application code which the api key belongs to
+	BKHTTPAppCode = "BK_App_Code"
+	// BKHTTPDataSource the source which writes the instance fields, such as the collectors
+	BKHTTPDataSource = "Cc_Data_Source"
+)
+
+// the sources which write the instance fields, the writers set it to the BKHTTPDataSource header
+const (
+	// FieldSourceUser the manual edits, which is the default source
+	FieldSourceUser       = "user"
+	FieldSourceExcel      = "excel"
+	FieldSourceHostSnap   = "hostsnap"
+	FieldSourceCloudSync  = "cloud_sync"
+	FieldSourceDiscover   = "discover"
+	FieldSourceNetcollect = "netcollect"
 )
 
 type CCContextKey string
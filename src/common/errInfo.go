@@ -210,6 +210,11 @@ const (
 	CCErrAPIGetAuthorizedAppListFromAuthFailed = 1100001
 	CCErrAPIGetUserResourceAuthStatusFailed    = 1100002
 	CCErrAPINoObjectInstancesIsFound           = 1100003
+	CCErrAPIKeyInvalid                         = 1100004
+	CCErrAPIKeyRateLimited                     = 1100005
+	CCErrAPIKeyScopeForbidden                  = 1100006
+	CCErrAPIKeyRequired                        = 1100007
+	CCErrAPIKeyManageForbidden                 = 1100008
 
 	// toposerver 1101XXX
 	// CCErrTopoInstCreateFailed unable to create the instance
@@ -674,6 +679,12 @@ const (
 	CCErrCollectNetHistorySearchFail           = 1112016
 	CCErrCollectNetDeviceUpdateFail            = 1112017
 	CCErrCollectNetPropertyUpdateFail          = 1112018
+	CCErrCollectNetPolicySearchFail            = 1112019
+	CCErrCollectNetPolicyUpdateFail            = 1112020
+	CCErrCollectNetPolicyDeleteFail            = 1112021
+	CCErrCollectDiscoverSchemaSearchFail       = 1112022
+	CCErrCollectDiscoverSchemaUpdateFail       = 1112023
+	CCErrCollectDiscoverSchemaDeleteFail       = 1112024
 
 	// coreservice 1113xxx
 
@@ -731,6 +742,12 @@ const (
 	CCErrCoreServiceModelHasInstanceErr = 1113030
 	// CCErrCoreServiceModelHasAssociationErr 模型与其他模型有关联关系
 	CCErrCoreServiceModelHasAssociationErr = 1113031
+	// CCErrCoreServiceLifecycleStateInvalid [%s] 不是生命周期中的状态
+	CCErrCoreServiceLifecycleStateInvalid = 1113032
+	// CCErrCoreServiceLifecycleTransitionNotAllowed 不允许从状态[%s]变更为[%s]
+	CCErrCoreServiceLifecycleTransitionNotAllowed = 1113033
+	// CCErrCoreServiceLifecycleOperationBlocked 实例处于[%s]状态，不允许执行[%s]操作
+	CCErrCoreServiceLifecycleOperationBlocked = 1113034
 
 	// synchronize data core service  11139xx
 	CCErrCoreServiceSyncError = 1113900
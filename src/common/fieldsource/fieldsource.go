@@ -0,0 +1,183 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package fieldsource keeps the provenance of the instance fields, and enforces the source priorities of the
+// fields on the writes, so that the values corrected by hand are not overwritten by the collectors.
+package fieldsource
+
+import (
+	"context"
+	"sort"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/storage/dal"
+)
+
+// fieldSourcesField the field of the provenance doc keeping the sources of the instance fields
+const fieldSourcesField = "fields"
+
+// FindPriorities returns the source priorities of the fields of the model, keyed by the property id
+func FindPriorities(ctx context.Context, db dal.RDB, ownerID, objID string) (map[string]metadata.FieldSourcePriority, error) {
+	cond := util.SetQueryOwner(mapstr.MapStr{common.BKObjIDField: objID}, ownerID)
+	priorities := make([]metadata.FieldSourcePriority, 0)
+	if err := db.Table(common.BKTableNameFieldSourcePriority).Find(cond).All(ctx, &priorities); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]metadata.FieldSourcePriority, len(priorities))
+	for _, priority := range priorities {
+		result[priority.PropertyID] = priority
+	}
+	return result, nil
+}
+
+// Find returns the sources of the fields of the instance, the fields are empty if no source is recorded
+func Find(ctx context.Context, db dal.RDB, objID string, instID int64) (*metadata.InstFieldSource, error) {
+	cond := mapstr.MapStr{
+		common.BKObjIDField:  objID,
+		common.BKInstIDField: instID,
+	}
+	sources := make([]metadata.InstFieldSource, 0)
+	if err := db.Table(common.BKTableNameInstFieldSource).Find(cond).Limit(1).All(ctx, &sources); err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 || sources[0].Fields == nil {
+		return &metadata.InstFieldSource{ObjectID: objID, InstID: instID, Fields: map[string]metadata.FieldSource{}}, nil
+	}
+	return &sources[0], nil
+}
+
+// FindMany returns the sources of the fields of the instances, keyed by the instance id
+func FindMany(ctx context.Context, db dal.RDB, objID string, instIDs []int64) (map[int64]metadata.InstFieldSource, error) {
+	cond := mapstr.MapStr{
+		common.BKObjIDField:  objID,
+		common.BKInstIDField: mapstr.MapStr{common.BKDBIN: instIDs},
+	}
+	sources := make([]metadata.InstFieldSource, 0)
+	if err := db.Table(common.BKTableNameInstFieldSource).Find(cond).All(ctx, &sources); err != nil {
+		return nil, err
+	}
+	result := make(map[int64]metadata.InstFieldSource, len(sources))
+	for _, source := range sources {
+		result[source.InstID] = source
+	}
+	return result, nil
+}
+
+// FilterInst returns the fields of the data the source is allowed to overwrite on the instance, and the rejected
+// fields, the sources of the instance are read only when some fields of the data have priorities
+func FilterInst(ctx context.Context, db dal.RDB, priorities map[string]metadata.FieldSourcePriority, objID string,
+	instID int64, source string, data mapstr.MapStr) (mapstr.MapStr, []string, error) {
+
+	prioritized := false
+	for field := range data {
+		if _, ok := priorities[field]; ok {
+			prioritized = true
+			break
+		}
+	}
+	if !prioritized {
+		return data, nil, nil
+	}
+
+	sources, err := Find(ctx, db, objID, instID)
+	if err != nil {
+		return nil, nil, err
+	}
+	allowed, rejected := Filter(priorities, sources, source, data)
+	return allowed, rejected, nil
+}
+
+// Filter returns the fields of the data the source is allowed to overwrite, and the rejected fields,
+// the data itself is returned as the allowed fields if no field is rejected.
+func Filter(priorities map[string]metadata.FieldSourcePriority, sources *metadata.InstFieldSource, source string,
+	data mapstr.MapStr) (mapstr.MapStr, []string) {
+
+	allowed := mapstr.New()
+	rejected := make([]string, 0)
+	for field, value := range data {
+		if priority, ok := priorities[field]; ok && !priority.CanOverwrite(sources.Fields[field].Source, source) {
+			rejected = append(rejected, field)
+			continue
+		}
+		allowed[field] = value
+	}
+	if len(rejected) == 0 {
+		return data, rejected
+	}
+	sort.Strings(rejected)
+	return allowed, rejected
+}
+
+// Record records the source as the last writer of the fields of the instances
+func Record(ctx context.Context, db dal.RDB, ownerID, objID string, instIDs []int64, source, modifier string, data mapstr.MapStr) error {
+	now := metadata.Now()
+	fields := make(map[string]metadata.FieldSource, len(data))
+	for field := range data {
+		if isInternalField(objID, field) {
+			continue
+		}
+		fields[field] = metadata.FieldSource{Source: source, Modifier: modifier, LastTime: now}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	for _, instID := range instIDs {
+		if err := Set(ctx, db, ownerID, objID, instID, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set sets the sources of the fields of the instance in a single write, the sources of the other fields are kept
+func Set(ctx context.Context, db dal.RDB, ownerID, objID string, instID int64, fields map[string]metadata.FieldSource) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	filter := mapstr.MapStr{
+		common.BKObjIDField:  objID,
+		common.BKInstIDField: instID,
+	}
+	doc := mapstr.MapStr{
+		common.BKObjIDField:   objID,
+		common.BKInstIDField:  instID,
+		common.BKOwnerIDField: ownerID,
+	}
+	for field, fieldSource := range fields {
+		doc[fieldSourcesField+"."+field] = fieldSource
+	}
+	return db.Table(common.BKTableNameInstFieldSource).Upsert(ctx, filter, doc)
+}
+
+// Clear removes the field sources of the deleted instances
+func Clear(ctx context.Context, db dal.RDB, objID string, instIDs []int64) error {
+	cond := mapstr.MapStr{
+		common.BKObjIDField:  objID,
+		common.BKInstIDField: mapstr.MapStr{common.BKDBIN: instIDs},
+	}
+	return db.Table(common.BKTableNameInstFieldSource).Delete(ctx, cond)
+}
+
+// isInternalField returns whether the field is maintained by cmdb itself rather than written by a source
+func isInternalField(objID, field string) bool {
+	switch field {
+	case common.BKOwnerIDField, common.CreateTimeField, common.LastTimeField, metadata.BKMetadata,
+		common.GetInstIDField(objID), common.BKObjIDField, "_id":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fieldsource
+
+import (
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	priorities := map[string]metadata.FieldSourcePriority{
+		"bk_state": {ObjectID: "bk_switch", PropertyID: "bk_state", Sources: []string{common.FieldSourceUser, common.FieldSourceNetcollect}},
+	}
+	setByUser := &metadata.InstFieldSource{Fields: map[string]metadata.FieldSource{"bk_state": {Source: common.FieldSourceUser}}}
+
+	tests := []struct {
+		name         string
+		sources      *metadata.InstFieldSource
+		source       string
+		wantAllowed  mapstr.MapStr
+		wantRejected []string
+	}{
+		{
+			name:         "not recorded",
+			sources:      &metadata.InstFieldSource{},
+			source:       common.FieldSourceNetcollect,
+			wantAllowed:  mapstr.MapStr{"bk_state": "online", "bk_inst_name": "switch-01"},
+			wantRejected: []string{},
+		},
+		{
+			name:         "higher priority",
+			sources:      setByUser,
+			source:       common.FieldSourceUser,
+			wantAllowed:  mapstr.MapStr{"bk_state": "online", "bk_inst_name": "switch-01"},
+			wantRejected: []string{},
+		},
+		{
+			name:         "lower priority",
+			sources:      setByUser,
+			source:       common.FieldSourceNetcollect,
+			wantAllowed:  mapstr.MapStr{"bk_inst_name": "switch-01"},
+			wantRejected: []string{"bk_state"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := mapstr.MapStr{"bk_state": "online", "bk_inst_name": "switch-01"}
+			allowed, rejected := Filter(priorities, tt.sources, tt.source, data)
+			require.Equal(t, tt.wantAllowed, allowed)
+			require.Equal(t, tt.wantRejected, rejected)
+
+			// the data itself is returned if nothing is rejected, so that the changes made by the validator are kept
+			allowed["bk_comment"] = "checked"
+			require.Equal(t, len(rejected) == 0, data.Exists("bk_comment"))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package graphql implement a read only GraphQL query engine, it supports queries with aliases,
+// arguments, variables, fragments and the @skip/@include directives. mutation, subscription and
+// introspection are not supported, use the SDL of the schema to know what can be queried.
+package graphql
+
+// Document the parsed GraphQL document
+type Document struct {
+	Operations []*OperationDefinition
+	Fragments  map[string]*FragmentDefinition
+}
+
+// OperationDefinition a query, mutation or subscription operation
+type OperationDefinition struct {
+	Operation    string
+	Name         string
+	Variables    []*VariableDefinition
+	SelectionSet []Selection
+}
+
+// VariableDefinition the variable declared by operation
+type VariableDefinition struct {
+	Name         string
+	Type         string
+	NonNull      bool
+	DefaultValue Value
+}
+
+// Selection is one of *Field, *FragmentSpread and *InlineFragment
+type Selection interface{}
+
+// Field a field selection
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	Directives   []*Directive
+	SelectionSet []Selection
+}
+
+// ResponseKey the key of the field in the response
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Argument the argument of field or directive
+type Argument struct {
+	Name  string
+	Value Value
+}
+
+// Directive like @skip(if: true)
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+}
+
+// FragmentSpread like ...fragmentName
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+}
+
+// InlineFragment like ... on Type { fields }
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+}
+
+// FragmentDefinition like fragment name on Type { fields }
+type FragmentDefinition struct {
+	Name          string
+	TypeCondition string
+	SelectionSet  []Selection
+}
+
+// Value is one of *Variable, EnumValue, string, int64, float64, bool, nil, []Value and map[string]Value
+type Value interface{}
+
+// Variable reference to a variable in value, like $name
+type Variable struct {
+	Name string
+}
+
+// EnumValue a enum value in the query
+type EnumValue string
@@ -0,0 +1,696 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// ExecuteParams the parameters to execute a query
+type ExecuteParams struct {
+	Context       context.Context
+	Schema        *Schema
+	Query         string
+	OperationName string
+	Variables     map[string]interface{}
+	// RootValue the source of the fields of query type
+	RootValue interface{}
+	// MaxDepth the max depth of the nested fields of the query, 0 means no limit
+	MaxDepth int
+}
+
+// Result the result of the query
+type Result struct {
+	Data   interface{} `json:"data"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error a error occurred when parse or execute the query
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Execute parse and execute the query
+func Execute(p ExecuteParams) *Result {
+	doc, err := Parse(p.Query)
+	if err != nil {
+		return &Result{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	op, err := selectOperation(doc, p.OperationName)
+	if err != nil {
+		return &Result{Errors: []*Error{{Message: err.Error()}}}
+	}
+	if op.Operation != "query" {
+		return &Result{Errors: []*Error{{Message: fmt.Sprintf("%s is not supported, only query is supported", op.Operation)}}}
+	}
+
+	if p.MaxDepth > 0 {
+		depth, err := selectionDepth(op.SelectionSet, doc.Fragments, make(map[string]bool))
+		if err != nil {
+			return &Result{Errors: []*Error{{Message: err.Error()}}}
+		}
+		if depth > p.MaxDepth {
+			return &Result{Errors: []*Error{{Message: fmt.Sprintf("query depth %d exceeds the max depth %d", depth, p.MaxDepth)}}}
+		}
+	}
+
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	e := &executor{
+		ctx:       ctx,
+		fragments: doc.Fragments,
+		variables: make(map[string]interface{}),
+		errors:    make([]*Error, 0),
+	}
+	for _, def := range op.Variables {
+		value, exist := p.Variables[def.Name]
+		if !exist {
+			if def.DefaultValue != nil {
+				if value, err = e.valueOf(def.DefaultValue); err != nil {
+					return &Result{Errors: []*Error{{Message: err.Error()}}}
+				}
+			} else if def.NonNull {
+				return &Result{Errors: []*Error{{Message: fmt.Sprintf("variable $%s of type %s! is required", def.Name, def.Type)}}}
+			}
+		}
+		e.variables[def.Name] = value
+	}
+
+	data := e.executeObjects(p.Schema.Query, []interface{}{p.RootValue}, op.SelectionSet, nil)
+	result := &Result{Data: data[0]}
+	if len(e.errors) > 0 {
+		result.Errors = e.errors
+	}
+	return result
+}
+
+func selectOperation(doc *Document, name string) (*OperationDefinition, error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, fmt.Errorf("operation name is required when the query contains multiple operations")
+		}
+		return doc.Operations[0], nil
+	}
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("operation %s is not found", name)
+}
+
+// selectionDepth get the max depth of the nested fields, the fragments are expanded, and the fragments which
+// spread themselves are rejected.
+func selectionDepth(selections []Selection, fragments map[string]*FragmentDefinition, spreading map[string]bool) (int, error) {
+	max := 0
+	for _, selection := range selections {
+		depth := 0
+		var err error
+		switch s := selection.(type) {
+		case *Field:
+			if depth, err = selectionDepth(s.SelectionSet, fragments, spreading); err != nil {
+				return 0, err
+			}
+			depth++
+
+		case *InlineFragment:
+			if depth, err = selectionDepth(s.SelectionSet, fragments, spreading); err != nil {
+				return 0, err
+			}
+
+		case *FragmentSpread:
+			if spreading[s.Name] {
+				return 0, fmt.Errorf("fragment %s cannot spread itself", s.Name)
+			}
+			fragment, exist := fragments[s.Name]
+			if !exist {
+				return 0, fmt.Errorf("unknown fragment %s", s.Name)
+			}
+			spreading[s.Name] = true
+			depth, err = selectionDepth(fragment.SelectionSet, fragments, spreading)
+			delete(spreading, s.Name)
+			if err != nil {
+				return 0, err
+			}
+		}
+		if depth > max {
+			max = depth
+		}
+	}
+	return max, nil
+}
+
+// resultMap keep the fields in the order of the query
+type resultMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newResultMap() *resultMap {
+	return &resultMap{values: make(map[string]interface{})}
+}
+
+func (m *resultMap) set(key string, value interface{}) {
+	if _, exist := m.values[key]; !exist {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// MarshalJSON marshal the map with the keys in order
+func (m *resultMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for idx, key := range m.keys {
+		if idx > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type collectedField struct {
+	key    string
+	fields []*Field
+}
+
+type executor struct {
+	ctx       context.Context
+	fragments map[string]*FragmentDefinition
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+func (e *executor) addError(path []interface{}, format string, args ...interface{}) {
+	e.errors = append(e.errors, &Error{Message: fmt.Sprintf(format, args...), Path: append([]interface{}{}, path...)})
+}
+
+// executeObjects execute the selections on all the sources of the same object type at once, so that
+// the batch resolver can resolve the field of all the sources in one call.
+func (e *executor) executeObjects(object *Object, sources []interface{}, selections []Selection, path []interface{}) []*resultMap {
+	results := make([]*resultMap, len(sources))
+	for idx := range results {
+		results[idx] = newResultMap()
+	}
+
+	collected := make([]*collectedField, 0)
+	if err := e.collectFields(object, selections, &collected, make(map[string]bool)); err != nil {
+		e.addError(path, "%v", err)
+		return results
+	}
+
+	for _, cf := range collected {
+		fieldPath := append(append([]interface{}{}, path...), cf.key)
+		name := cf.fields[0].Name
+		if name == "__typename" {
+			for idx := range results {
+				results[idx].set(cf.key, object.Name)
+			}
+			continue
+		}
+
+		def := object.Field(name)
+		if def == nil {
+			e.addError(fieldPath, "cannot query field %s on type %s", name, object.Name)
+			continue
+		}
+
+		args, err := e.coerceArguments(def, cf.fields[0].Arguments)
+		if err != nil {
+			e.addError(fieldPath, "%v", err)
+			for idx := range results {
+				results[idx].set(cf.key, nil)
+			}
+			continue
+		}
+
+		values := e.resolveField(def, sources, args, fieldPath)
+		subSelections := make([]Selection, 0)
+		for _, field := range cf.fields {
+			subSelections = append(subSelections, field.SelectionSet...)
+		}
+		completed := e.completeValues(def.Type, values, subSelections, fieldPath)
+		for idx := range results {
+			results[idx].set(cf.key, completed[idx])
+		}
+	}
+	return results
+}
+
+func (e *executor) resolveField(def *FieldDefinition, sources []interface{}, args map[string]interface{}, path []interface{}) []interface{} {
+	if def.BatchResolve != nil {
+		values, err := def.BatchResolve(BatchResolveParams{Context: e.ctx, Sources: sources, Args: args})
+		if err == nil && len(values) != len(sources) {
+			err = fmt.Errorf("resolve field %s got %d values, but there are %d sources", def.Name, len(values), len(sources))
+		}
+		if err != nil {
+			e.addError(path, "%v", err)
+			return make([]interface{}, len(sources))
+		}
+		return values
+	}
+
+	values := make([]interface{}, len(sources))
+	for idx, source := range sources {
+		if def.Resolve == nil {
+			values[idx] = defaultResolve(source, def.Name)
+			continue
+		}
+		value, err := def.Resolve(ResolveParams{Context: e.ctx, Source: source, Args: args})
+		if err != nil {
+			e.addError(path, "%v", err)
+			continue
+		}
+		values[idx] = value
+	}
+	return values
+}
+
+// defaultResolve get the value with the field name in the map source
+func defaultResolve(source interface{}, name string) interface{} {
+	if source == nil {
+		return nil
+	}
+	v := reflect.ValueOf(source)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+	value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+	if !value.IsValid() {
+		return nil
+	}
+	return value.Interface()
+}
+
+func (e *executor) completeValues(t *Type, values []interface{}, selections []Selection, path []interface{}) []interface{} {
+	completed := make([]interface{}, len(values))
+	if t.NonNull {
+		for _, value := range values {
+			if isNil(value) {
+				e.addError(path, "cannot return null for non-nullable field")
+				break
+			}
+		}
+	}
+
+	switch t.Kind {
+	case ScalarKind:
+		for idx, value := range values {
+			if isNil(value) {
+				continue
+			}
+			var err error
+			if completed[idx], err = serializeScalar(t.Name, value); err != nil {
+				e.addError(path, "%v", err)
+			}
+		}
+
+	case ObjectKind:
+		if len(selections) == 0 {
+			e.addError(path, "field of type %s must have a selection of subfields", t.Name)
+			return completed
+		}
+		sources := make([]interface{}, 0, len(values))
+		indexes := make([]int, 0, len(values))
+		for idx, value := range values {
+			if !isNil(value) {
+				sources = append(sources, value)
+				indexes = append(indexes, idx)
+			}
+		}
+		if len(sources) == 0 {
+			return completed
+		}
+		results := e.executeObjects(t.Object, sources, selections, path)
+		for i, idx := range indexes {
+			completed[idx] = results[i]
+		}
+
+	case ListKind:
+		// flatten all the lists, so that the items of all the lists are completed at once
+		items := make([]interface{}, 0)
+		bounds := make([][2]int, len(values))
+		for idx, value := range values {
+			if isNil(value) {
+				bounds[idx] = [2]int{-1, -1}
+				continue
+			}
+			v := reflect.ValueOf(value)
+			if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+				e.addError(path, "expect a list, but got %T", value)
+				bounds[idx] = [2]int{-1, -1}
+				continue
+			}
+			start := len(items)
+			for i := 0; i < v.Len(); i++ {
+				items = append(items, v.Index(i).Interface())
+			}
+			bounds[idx] = [2]int{start, len(items)}
+		}
+		completedItems := e.completeValues(t.OfType, items, selections, path)
+		for idx, bound := range bounds {
+			if bound[0] < 0 {
+				continue
+			}
+			completed[idx] = completedItems[bound[0]:bound[1]]
+		}
+	}
+	return completed
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// collectFields merge the fields with the same response key, and expand the fragments
+func (e *executor) collectFields(object *Object, selections []Selection, collected *[]*collectedField, visited map[string]bool) error {
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *Field:
+			include, err := e.shouldInclude(s.Directives)
+			if err != nil {
+				return err
+			}
+			if !include {
+				continue
+			}
+			key := s.ResponseKey()
+			merged := false
+			for _, cf := range *collected {
+				if cf.key == key {
+					if cf.fields[0].Name != s.Name {
+						return fmt.Errorf("fields %s and %s conflict because they have the same response key %s", cf.fields[0].Name, s.Name, key)
+					}
+					cf.fields = append(cf.fields, s)
+					merged = true
+					break
+				}
+			}
+			if !merged {
+				*collected = append(*collected, &collectedField{key: key, fields: []*Field{s}})
+			}
+
+		case *FragmentSpread:
+			include, err := e.shouldInclude(s.Directives)
+			if err != nil {
+				return err
+			}
+			if !include || visited[s.Name] {
+				continue
+			}
+			visited[s.Name] = true
+			fragment, exist := e.fragments[s.Name]
+			if !exist {
+				return fmt.Errorf("unknown fragment %s", s.Name)
+			}
+			if fragment.TypeCondition != object.Name {
+				continue
+			}
+			if err := e.collectFields(object, fragment.SelectionSet, collected, visited); err != nil {
+				return err
+			}
+
+		case *InlineFragment:
+			include, err := e.shouldInclude(s.Directives)
+			if err != nil {
+				return err
+			}
+			if !include || (s.TypeCondition != "" && s.TypeCondition != object.Name) {
+				continue
+			}
+			if err := e.collectFields(object, s.SelectionSet, collected, visited); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *executor) shouldInclude(directives []*Directive) (bool, error) {
+	for _, directive := range directives {
+		if directive.Name != "skip" && directive.Name != "include" {
+			continue
+		}
+		var condition interface{}
+		for _, arg := range directive.Arguments {
+			if arg.Name == "if" {
+				var err error
+				if condition, err = e.valueOf(arg.Value); err != nil {
+					return false, err
+				}
+			}
+		}
+		value, ok := condition.(bool)
+		if !ok {
+			return false, fmt.Errorf("argument if of directive @%s must be a boolean", directive.Name)
+		}
+		if (directive.Name == "skip" && value) || (directive.Name == "include" && !value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// valueOf convert the ast value to go value, the variables are replaced with the value of them
+func (e *executor) valueOf(value Value) (interface{}, error) {
+	switch v := value.(type) {
+	case *Variable:
+		variable, exist := e.variables[v.Name]
+		if !exist {
+			return nil, fmt.Errorf("variable $%s is not defined", v.Name)
+		}
+		return variable, nil
+	case EnumValue:
+		return string(v), nil
+	case []Value:
+		list := make([]interface{}, len(v))
+		for idx, item := range v {
+			var err error
+			if list[idx], err = e.valueOf(item); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case map[string]Value:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			var err error
+			if object[key], err = e.valueOf(item); err != nil {
+				return nil, err
+			}
+		}
+		return object, nil
+	default:
+		return v, nil
+	}
+}
+
+func (e *executor) coerceArguments(def *FieldDefinition, arguments []*Argument) (map[string]interface{}, error) {
+	args := make(map[string]interface{})
+	for _, arg := range arguments {
+		argDef := (*ArgumentDefinition)(nil)
+		for _, item := range def.Args {
+			if item.Name == arg.Name {
+				argDef = item
+				break
+			}
+		}
+		if argDef == nil {
+			return nil, fmt.Errorf("unknown argument %s on field %s", arg.Name, def.Name)
+		}
+		value, err := e.valueOf(arg.Value)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = coerceInput(argDef.Type, value); err != nil {
+			return nil, fmt.Errorf("argument %s of field %s is invalid, %v", arg.Name, def.Name, err)
+		}
+		args[arg.Name] = value
+	}
+
+	for _, argDef := range def.Args {
+		if _, exist := args[argDef.Name]; exist {
+			continue
+		}
+		if argDef.DefaultValue != nil {
+			args[argDef.Name] = argDef.DefaultValue
+			continue
+		}
+		if argDef.Type.NonNull {
+			return nil, fmt.Errorf("argument %s of type %s is required on field %s", argDef.Name, argDef.Type, def.Name)
+		}
+	}
+	return args, nil
+}
+
+// coerceInput check and convert the input value to the type
+func coerceInput(t *Type, value interface{}) (interface{}, error) {
+	if isNil(value) {
+		if t.NonNull {
+			return nil, fmt.Errorf("expect %s, but got null", t)
+		}
+		return nil, nil
+	}
+
+	switch t.Kind {
+	case ListKind:
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			// a single value is accepted as a list with one item
+			item, err := coerceInput(t.OfType, value)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{item}, nil
+		}
+		list := make([]interface{}, v.Len())
+		for idx := range list {
+			var err error
+			if list[idx], err = coerceInput(t.OfType, v.Index(idx).Interface()); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case ScalarKind:
+		switch t.Name {
+		case Int:
+			if number, ok := toFloat(value); ok && number == math.Trunc(number) {
+				return int64(number), nil
+			}
+		case Float:
+			if number, ok := toFloat(value); ok {
+				return number, nil
+			}
+		case String:
+			if str, ok := value.(string); ok {
+				return str, nil
+			}
+		case Boolean:
+			if b, ok := value.(bool); ok {
+				return b, nil
+			}
+		case ID:
+			switch value.(type) {
+			case string, int, int64, float64, json.Number:
+				return fmt.Sprint(value), nil
+			}
+		default:
+			return value, nil
+		}
+		return nil, fmt.Errorf("expect %s, but got %v", t, value)
+	default:
+		return nil, fmt.Errorf("%s can not be used as input", t)
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	if number, ok := value.(json.Number); ok {
+		f, err := number.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// serializeScalar convert the resolved value to the scalar type
+func serializeScalar(name string, value interface{}) (interface{}, error) {
+	switch name {
+	case Int:
+		if number, ok := toFloat(value); ok {
+			return int64(number), nil
+		}
+		if str, ok := value.(string); ok {
+			if number, err := strconv.ParseInt(str, 10, 64); err == nil {
+				return number, nil
+			}
+		}
+		return nil, fmt.Errorf("can not convert %v to Int", value)
+	case Float:
+		if number, ok := toFloat(value); ok {
+			return number, nil
+		}
+		if str, ok := value.(string); ok {
+			if number, err := strconv.ParseFloat(str, 64); err == nil {
+				return number, nil
+			}
+		}
+		return nil, fmt.Errorf("can not convert %v to Float", value)
+	case Boolean:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("can not convert %v to Boolean", value)
+	case String, ID:
+		if str, ok := value.(string); ok {
+			return str, nil
+		}
+		if _, ok := toFloat(value); ok {
+			return fmt.Sprint(value), nil
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSchema(batchCalls *int) *Schema {
+	host := &Object{Name: "host"}
+	module := &Object{Name: "module"}
+	host.AddField(&FieldDefinition{Name: "bk_host_id", Type: NonNullOf(ScalarType(Int))})
+	host.AddField(&FieldDefinition{Name: "bk_host_innerip", Type: ScalarType(String)})
+	host.AddField(&FieldDefinition{
+		Name: "modules",
+		Type: ListOf(ObjectType(module)),
+		BatchResolve: func(p BatchResolveParams) ([]interface{}, error) {
+			*batchCalls++
+			values := make([]interface{}, len(p.Sources))
+			for idx, source := range p.Sources {
+				id := source.(map[string]interface{})["bk_host_id"].(int)
+				values[idx] = []map[string]interface{}{{"bk_module_name": "module" + string(rune('0'+id))}}
+			}
+			return values, nil
+		},
+	})
+	module.AddField(&FieldDefinition{Name: "bk_module_name", Type: ScalarType(String)})
+
+	query := &Object{Name: "Query"}
+	query.AddField(&FieldDefinition{
+		Name: "host",
+		Type: ListOf(ObjectType(host)),
+		Args: []*ArgumentDefinition{
+			{Name: "limit", Type: ScalarType(Int), DefaultValue: int64(10)},
+		},
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			hosts := []map[string]interface{}{
+				{"bk_host_id": 1, "bk_host_innerip": "127.0.0.1"},
+				{"bk_host_id": 2, "bk_host_innerip": "127.0.0.2"},
+				{"bk_host_id": 3, "bk_host_innerip": "127.0.0.3"},
+			}
+			limit := int(p.Args["limit"].(int64))
+			if limit < len(hosts) {
+				hosts = hosts[:limit]
+			}
+			return hosts, nil
+		},
+	})
+	return &Schema{Query: query}
+}
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(`
+		# comment
+		query hosts($limit: Int = 2) {
+			h: host(limit: $limit) { ...hostFields modules @include(if: true) { bk_module_name } }
+		}
+		fragment hostFields on host { bk_host_id, bk_host_innerip }
+	`)
+	require.NoError(t, err)
+	require.Len(t, doc.Operations, 1)
+	require.Equal(t, "hosts", doc.Operations[0].Name)
+	require.Len(t, doc.Operations[0].Variables, 1)
+	require.Contains(t, doc.Fragments, "hostFields")
+
+	field := doc.Operations[0].SelectionSet[0].(*Field)
+	require.Equal(t, "h", field.ResponseKey())
+	require.Equal(t, &Variable{Name: "limit"}, field.Arguments[0].Value)
+
+	_, err = Parse(`{ host(limit: ) { bk_host_id } }`)
+	require.Error(t, err)
+}
+
+func TestExecute(t *testing.T) {
+	batchCalls := 0
+	schema := newTestSchema(&batchCalls)
+
+	result := Execute(ExecuteParams{
+		Schema:    schema,
+		Query:     `query q($limit: Int) { host(limit: $limit) { __typename id: bk_host_id ... on host { modules { bk_module_name } } } }`,
+		Variables: map[string]interface{}{"limit": float64(2)},
+	})
+	require.Empty(t, result.Errors)
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"data":{"host":[
+		{"__typename":"host","id":1,"modules":[{"bk_module_name":"module1"}]},
+		{"__typename":"host","id":2,"modules":[{"bk_module_name":"module2"}]}
+	]}}`, string(data))
+	// the modules of all the hosts are resolved at once
+	require.Equal(t, 1, batchCalls)
+
+	result = Execute(ExecuteParams{Schema: schema, Query: `{ host { bk_host_innerip @skip(if: true) bk_host_id } }`})
+	require.Empty(t, result.Errors)
+	data, err = json.Marshal(result.Data)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"host":[{"bk_host_id":1},{"bk_host_id":2},{"bk_host_id":3}]}`, string(data))
+
+	result = Execute(ExecuteParams{Schema: schema, Query: `{ host { unknown } }`})
+	require.Len(t, result.Errors, 1)
+	require.Equal(t, []interface{}{"host", "unknown"}, result.Errors[0].Path)
+
+	result = Execute(ExecuteParams{Schema: schema, Query: `mutation { host { bk_host_id } }`})
+	require.Len(t, result.Errors, 1)
+}
+
+func TestExecuteMaxDepth(t *testing.T) {
+	schema := newTestSchema(new(int))
+	query := `{ host { ...hostModules } } fragment hostModules on host { modules { bk_module_name } }`
+
+	result := Execute(ExecuteParams{Schema: schema, Query: query, MaxDepth: 3})
+	require.Empty(t, result.Errors)
+
+	result = Execute(ExecuteParams{Schema: schema, Query: query, MaxDepth: 2})
+	require.Len(t, result.Errors, 1)
+	require.Nil(t, result.Data)
+
+	result = Execute(ExecuteParams{Schema: schema, Query: `{ host { ...loop } } fragment loop on host { ...loop }`, MaxDepth: 3})
+	require.Len(t, result.Errors, 1)
+}
+
+func TestSchemaString(t *testing.T) {
+	sdl := newTestSchema(new(int)).String()
+	require.Contains(t, sdl, "type Query {")
+	require.Contains(t, sdl, "host(limit: Int = 10): [host]")
+	require.Contains(t, sdl, "bk_host_id: Int!")
+	require.Contains(t, sdl, "type module {")
+}
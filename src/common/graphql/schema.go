@@ -0,0 +1,271 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// the builtin scalar type names
+const (
+	String  = "String"
+	Int     = "Int"
+	Float   = "Float"
+	Boolean = "Boolean"
+	ID      = "ID"
+	// JSON any json value, like the condition of a query
+	JSON = "JSON"
+)
+
+var builtinScalars = map[string]bool{String: true, Int: true, Float: true, Boolean: true, ID: true}
+
+// Kind the kind of type
+type Kind int
+
+const (
+	ScalarKind Kind = iota
+	ObjectKind
+	ListKind
+)
+
+// Type the type of field or argument
+type Type struct {
+	Kind    Kind
+	Name    string
+	Object  *Object
+	OfType  *Type
+	NonNull bool
+}
+
+// ScalarType a scalar type like String
+func ScalarType(name string) *Type {
+	return &Type{Kind: ScalarKind, Name: name}
+}
+
+// ObjectType the type of a object
+func ObjectType(object *Object) *Type {
+	return &Type{Kind: ObjectKind, Name: object.Name, Object: object}
+}
+
+// ListOf the list of the type
+func ListOf(t *Type) *Type {
+	return &Type{Kind: ListKind, OfType: t}
+}
+
+// NonNullOf the not null type of the type
+func NonNullOf(t *Type) *Type {
+	nonNull := *t
+	nonNull.NonNull = true
+	return &nonNull
+}
+
+func (t *Type) String() string {
+	name := t.Name
+	if t.Kind == ListKind {
+		name = "[" + t.OfType.String() + "]"
+	}
+	if t.NonNull {
+		name += "!"
+	}
+	return name
+}
+
+// ResolveParams the parameters to resolve a field of a source
+type ResolveParams struct {
+	Context context.Context
+	// Source the value of the parent object
+	Source interface{}
+	Args   map[string]interface{}
+}
+
+// BatchResolveParams the parameters to resolve a field of all the sources at once
+type BatchResolveParams struct {
+	Context context.Context
+	Sources []interface{}
+	Args    map[string]interface{}
+}
+
+// ResolveFunc resolve the field value of a source
+type ResolveFunc func(p ResolveParams) (interface{}, error)
+
+// BatchResolveFunc resolve the field values of all the sources, the values must be in the same order with the sources.
+type BatchResolveFunc func(p BatchResolveParams) ([]interface{}, error)
+
+// FieldDefinition the field of a object
+type FieldDefinition struct {
+	Name        string
+	Description string
+	Type        *Type
+	Args        []*ArgumentDefinition
+	// Resolve resolve the field, the value with the same name in the source map is used if both Resolve
+	// and BatchResolve is nil.
+	Resolve ResolveFunc
+	// BatchResolve resolve the field of all the objects in the same level at once, which is used to avoid
+	// querying the data of each object one by one.
+	BatchResolve BatchResolveFunc
+}
+
+// ArgumentDefinition the argument of a field
+type ArgumentDefinition struct {
+	Name         string
+	Description  string
+	Type         *Type
+	DefaultValue interface{}
+}
+
+// Object a object type
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*FieldDefinition
+}
+
+// AddField add a field to the object
+func (o *Object) AddField(field *FieldDefinition) {
+	o.Fields = append(o.Fields, field)
+}
+
+// Field get the field with the name
+func (o *Object) Field(name string) *FieldDefinition {
+	for _, field := range o.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// Schema the GraphQL schema, only query is supported
+type Schema struct {
+	Query *Object
+}
+
+// ValidName judge if the name is a valid GraphQL name
+func ValidName(name string) bool {
+	if name == "" || !isNameStart(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if !isNameContinue(name[i]) {
+			return false
+		}
+	}
+	return !strings.HasPrefix(name, "__")
+}
+
+// Objects get all the object types which can be reached from the query
+func (s *Schema) Objects() []*Object {
+	objects := make(map[string]*Object)
+	var walk func(o *Object)
+	walk = func(o *Object) {
+		if _, exist := objects[o.Name]; exist {
+			return
+		}
+		objects[o.Name] = o
+		for _, field := range o.Fields {
+			t := field.Type
+			for t.Kind == ListKind {
+				t = t.OfType
+			}
+			if t.Kind == ObjectKind {
+				walk(t.Object)
+			}
+		}
+	}
+	walk(s.Query)
+
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]*Object, 0, len(names))
+	for _, name := range names {
+		result = append(result, objects[name])
+	}
+	return result
+}
+
+// String print the schema in the schema definition language
+func (s *Schema) String() string {
+	var buf strings.Builder
+	scalars := make(map[string]bool)
+	objects := s.Objects()
+	collect := func(t *Type) {
+		for t.Kind == ListKind {
+			t = t.OfType
+		}
+		if t.Kind == ScalarKind && !builtinScalars[t.Name] {
+			scalars[t.Name] = true
+		}
+	}
+	for _, o := range objects {
+		for _, field := range o.Fields {
+			collect(field.Type)
+			for _, arg := range field.Args {
+				collect(arg.Type)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(scalars))
+	for name := range scalars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&buf, "scalar %s\n\n", name)
+	}
+
+	fmt.Fprintf(&buf, "schema {\n  query: %s\n}\n", s.Query.Name)
+	for _, o := range objects {
+		buf.WriteString("\n")
+		writeDescription(&buf, "", o.Description)
+		fmt.Fprintf(&buf, "type %s {\n", o.Name)
+		for _, field := range o.Fields {
+			writeDescription(&buf, "  ", field.Description)
+			fmt.Fprintf(&buf, "  %s", field.Name)
+			if len(field.Args) > 0 {
+				args := make([]string, 0, len(field.Args))
+				for _, arg := range field.Args {
+					def := fmt.Sprintf("%s: %s", arg.Name, arg.Type)
+					if arg.DefaultValue != nil {
+						def += fmt.Sprintf(" = %v", formatValue(arg.DefaultValue))
+					}
+					args = append(args, def)
+				}
+				fmt.Fprintf(&buf, "(%s)", strings.Join(args, ", "))
+			}
+			fmt.Fprintf(&buf, ": %s\n", field.Type)
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.String()
+}
+
+func writeDescription(buf *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	fmt.Fprintf(buf, "%s%q\n", indent, description)
+}
+
+func formatValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return fmt.Sprintf("%q", str)
+	}
+	return fmt.Sprintf("%v", value)
+}
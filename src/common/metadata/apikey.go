@@ -0,0 +1,104 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"time"
+)
+
+// APIKeyScope limit what the api key can do
+type APIKeyScope struct {
+	// ReadOnly only allow the request which find resources
+	ReadOnly bool `json:"read_only" bson:"read_only"`
+	// BizIDs the business the api key can access, empty means no limit
+	BizIDs []int64 `json:"bk_biz_ids" bson:"bk_biz_ids"`
+}
+
+// AllowBiz judge the business can be accessed in this scope, 0 means not belong to any business,
+// which is denied when the scope is limited to some business.
+func (s APIKeyScope) AllowBiz(bizID int64) bool {
+	if len(s.BizIDs) == 0 {
+		return true
+	}
+	for _, id := range s.BizIDs {
+		if id == bizID {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey the api key bind to a application and a user
+type APIKey struct {
+	ID      int64  `json:"id" bson:"id"`
+	AppCode string `json:"bk_app_code" bson:"bk_app_code"`
+	User    string `json:"bk_username" bson:"bk_username"`
+	// KeyHash the sha256 hash of the api key, the api key self is never stored
+	KeyHash string `json:"key_hash,omitempty" bson:"key_hash"`
+	// KeyPrefix the first characters of api key, help user to recognise the api key
+	KeyPrefix string      `json:"key_prefix" bson:"key_prefix"`
+	Scope     APIKeyScope `json:"scope" bson:"scope"`
+	// QPS and Burst limit the request rate of this api key, 0 means use the apiserver default value
+	QPS        int64      `json:"qps" bson:"qps"`
+	Burst      int64      `json:"burst" bson:"burst"`
+	ExpireTime *time.Time `json:"expire_time,omitempty" bson:"expire_time"`
+	OwnerID    string     `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	CreateTime time.Time  `json:"create_time" bson:"create_time"`
+}
+
+// IsExpired judge the api key is expired or not
+func (k APIKey) IsExpired(now time.Time) bool {
+	return k.ExpireTime != nil && !k.ExpireTime.IsZero() && now.After(*k.ExpireTime)
+}
+
+// CreateAPIKeyRequest issue a api key http request parameter
+type CreateAPIKeyRequest struct {
+	AppCode    string      `json:"bk_app_code"`
+	Scope      APIKeyScope `json:"scope"`
+	QPS        int64       `json:"qps"`
+	Burst      int64       `json:"burst"`
+	ExpireTime *time.Time  `json:"expire_time"`
+}
+
+// CreateAPIKeyResult issue a api key result, the api key only can be get in this result
+type CreateAPIKeyResult struct {
+	BaseResp `json:",inline"`
+	Data     struct {
+		APIKey `json:",inline"`
+		Key    string `json:"key"`
+	} `json:"data"`
+}
+
+// SearchAPIKeyRequest search api key http request parameter
+type SearchAPIKeyRequest struct {
+	ID      int64    `json:"id"`
+	AppCode string   `json:"bk_app_code"`
+	User    string   `json:"bk_username"`
+	KeyHash string   `json:"key_hash"`
+	Page    BasePage `json:"page"`
+}
+
+// SearchAPIKeyResult search api key result
+type SearchAPIKeyResult struct {
+	BaseResp `json:",inline"`
+	Data     struct {
+		Count uint64   `json:"count"`
+		Info  []APIKey `json:"info"`
+	} `json:"data"`
+}
+
+// APIKeyResult a api key result
+type APIKeyResult struct {
+	BaseResp `json:",inline"`
+	Data     APIKey `json:"data"`
+}
@@ -119,6 +119,19 @@ type SearchAssociationInstResult struct {
 	Data     []*InstAsst `json:"data"`
 }
 
+// SearchAssociationInstPageRequest search the inst associations by page, use the next_cursor
+// of the result as the cursor of the page to iterate all the associations.
+type SearchAssociationInstPageRequest struct {
+	Condition mapstr.MapStr `json:"condition"`
+	Page      BasePage      `json:"page"`
+}
+
+type SearchAssociationInstPageResult struct {
+	Count      uint64     `json:"count"`
+	Info       []InstAsst `json:"info"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
 type CreateAssociationInstRequest struct {
 	ObjectAsstID string `field:"bk_obj_asst_id" json:"bk_obj_asst_id,omitempty" bson:"bk_obj_asst_id,omitempty"`
 	InstID       int64  `field:"bk_inst_id" json:"bk_inst_id,omitempty" bson:"bk_inst_id,omitempty"`
@@ -36,6 +36,8 @@ const (
 	AttributeFieldPropertyType    = "bk_property_type"
 	AttributeFieldOption          = "option"
 	AttributeFieldDescription     = "description"
+	AttributeFieldDefault         = "default"
+	AttributeFieldRequiredRules   = "required_rules"
 	AttributeFieldCreator         = "creator"
 	AttributeFieldCreateTime      = "create_time"
 	AttributeFieldLastTime        = "last_time"
@@ -64,6 +66,10 @@ type Attribute struct {
 	PropertyType      string      `field:"bk_property_type" json:"bk_property_type" bson:"bk_property_type"`
 	Option            interface{} `field:"option" json:"option" bson:"option"`
 	Description       string      `field:"description" json:"description" bson:"description"`
+	// Default the value filled when the field is absent on creating instance
+	Default interface{} `field:"default" json:"default" bson:"default"`
+	// RequiredRules the rules of the conditional required, it's a list of RequiredRule, use ParseRequiredRules to parse it
+	RequiredRules interface{} `field:"required_rules" json:"required_rules" bson:"required_rules"`
 
 	Creator    string `field:"creator" json:"creator" bson:"creator"`
 	CreateTime *Time  `json:"create_time" bson:"create_time"`
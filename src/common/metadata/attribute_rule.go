@@ -0,0 +1,137 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/util"
+)
+
+// RequiredRule the attribute is required when the field of the instance is set,
+// or equals to one of the values if the values are not empty.
+type RequiredRule struct {
+	Field  string        `json:"field" bson:"field"`
+	Values []interface{} `json:"values,omitempty" bson:"values,omitempty"`
+}
+
+// Hit returns whether the attribute is required by the rule with the instance data
+func (r RequiredRule) Hit(data mapstr.MapStr) bool {
+	val, exist := data[r.Field]
+	if !exist || IsEmptyAttributeValue(val) {
+		return false
+	}
+	if len(r.Values) == 0 {
+		return true
+	}
+	for _, item := range r.Values {
+		if fmt.Sprint(item) == fmt.Sprint(val) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRequiredRules parse the required rules of the attribute
+func ParseRequiredRules(val interface{}) ([]RequiredRule, error) {
+	rules := make([]RequiredRule, 0)
+	if val == nil {
+		return rules, nil
+	}
+	if data, ok := val.([]RequiredRule); ok {
+		return data, nil
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.Field == "" {
+			return nil, fmt.Errorf("the field of the required rule can not be empty")
+		}
+	}
+	return rules, nil
+}
+
+// IsEmptyAttributeValue the value is treated as not set
+func IsEmptyAttributeValue(val interface{}) bool {
+	return val == nil || val == ""
+}
+
+// ValidateDefault check whether the default value matches the type of the attribute
+func (attr Attribute) ValidateDefault() error {
+	if attr.Default == nil {
+		return nil
+	}
+
+	switch attr.PropertyType {
+	case common.FieldTypeSingleChar, common.FieldTypeLongChar, common.FieldTypeDate, common.FieldTypeTime,
+		common.FieldTypeTimeZone, common.FieldTypeUser:
+		if _, ok := attr.Default.(string); !ok {
+			return fmt.Errorf("default value of %s must be string", attr.PropertyType)
+		}
+	case common.FieldTypeInt:
+		val, ok := numberValue(attr.Default)
+		if !ok || val != math.Trunc(val) {
+			return fmt.Errorf("default value of %s must be integer", attr.PropertyType)
+		}
+	case common.FieldTypeFloat:
+		if _, ok := numberValue(attr.Default); !ok {
+			return fmt.Errorf("default value of %s must be number", attr.PropertyType)
+		}
+	case common.FieldTypeBool:
+		if _, ok := attr.Default.(bool); !ok {
+			return fmt.Errorf("default value of %s must be bool", attr.PropertyType)
+		}
+	case common.FieldTypeEnum:
+		id, ok := attr.Default.(string)
+		if !ok {
+			return fmt.Errorf("default value of %s must be the id of the enum option", attr.PropertyType)
+		}
+		options := make([]struct {
+			ID string `json:"id"`
+		}, 0)
+		data, err := json.Marshal(attr.Option)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &options); err != nil {
+			return fmt.Errorf("invalid enum option, err: %v", err)
+		}
+		for _, option := range options {
+			if option.ID == id {
+				return nil
+			}
+		}
+		return fmt.Errorf("default value %s is not an option of the enum", id)
+	default:
+		return fmt.Errorf("default value is not supported by %s", attr.PropertyType)
+	}
+	return nil
+}
+
+// numberValue returns the value of the number, number in string is not accepted
+func numberValue(val interface{}) (float64, bool) {
+	if _, ok := val.(string); ok {
+		return 0, false
+	}
+	number, err := util.GetFloat64ByInterface(val)
+	return number, err == nil
+}
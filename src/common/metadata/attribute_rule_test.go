@@ -0,0 +1,70 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+)
+
+func TestRequiredRuleHit(t *testing.T) {
+	rules, err := ParseRequiredRules([]interface{}{
+		map[string]interface{}{"field": "bk_isp_name"},
+		map[string]interface{}{"field": "bk_os_type", "values": []interface{}{"1"}},
+	})
+	if err != nil {
+		t.Fatalf("ParseRequiredRules() failed, err: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("ParseRequiredRules() got %d rules, want 2", len(rules))
+	}
+
+	data := mapstr.MapStr{"bk_isp_name": "", "bk_os_type": "2"}
+	if rules[0].Hit(data) || rules[1].Hit(data) {
+		t.Errorf("Hit() with empty or mismatched value should be false")
+	}
+	data = mapstr.MapStr{"bk_isp_name": "1", "bk_os_type": "1"}
+	if !rules[0].Hit(data) || !rules[1].Hit(data) {
+		t.Errorf("Hit() with matched value should be true")
+	}
+
+	if _, err := ParseRequiredRules([]interface{}{map[string]interface{}{"values": []interface{}{1}}}); err == nil {
+		t.Errorf("ParseRequiredRules() without field should fail")
+	}
+}
+
+func TestAttributeValidateDefault(t *testing.T) {
+	option := []interface{}{map[string]interface{}{"id": "1", "name": "linux"}}
+	cases := []struct {
+		attr  Attribute
+		valid bool
+	}{
+		{Attribute{PropertyType: common.FieldTypeSingleChar, Default: "abc"}, true},
+		{Attribute{PropertyType: common.FieldTypeSingleChar, Default: 1}, false},
+		{Attribute{PropertyType: common.FieldTypeInt, Default: float64(3)}, true},
+		{Attribute{PropertyType: common.FieldTypeInt, Default: 3.5}, false},
+		{Attribute{PropertyType: common.FieldTypeInt, Default: "3"}, false},
+		{Attribute{PropertyType: common.FieldTypeFloat, Default: 3.5}, true},
+		{Attribute{PropertyType: common.FieldTypeBool, Default: true}, true},
+		{Attribute{PropertyType: common.FieldTypeEnum, Option: option, Default: "1"}, true},
+		{Attribute{PropertyType: common.FieldTypeEnum, Option: option, Default: "2"}, false},
+		{Attribute{PropertyType: common.FieldTypeForeignKey, Default: 1}, false},
+	}
+	for idx, c := range cases {
+		if err := c.attr.ValidateDefault(); (err == nil) != c.valid {
+			t.Errorf("case %d: ValidateDefault() err: %v, want valid: %v", idx, err, c.valid)
+		}
+	}
+}
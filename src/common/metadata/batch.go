@@ -0,0 +1,44 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+)
+
+// BatchRequest the batch request of apiserver, the sub requests are dispatched with the concurrency limit.
+type BatchRequest struct {
+	// Concurrency how many sub requests can be dispatched at the same time, 0 means use the default value.
+	Concurrency int               `json:"concurrency"`
+	Requests    []BatchSubRequest `json:"requests"`
+}
+
+// BatchSubRequest a sub request of the batch request, the path is the apiserver path, like /api/v3/hosts/search
+type BatchSubRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// BatchSubResponse the response of a sub request, the responses are in the same order with the sub requests.
+type BatchSubResponse struct {
+	Index  int             `json:"index"`
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// BatchResult the result of a batch request
+type BatchResult struct {
+	BaseResp `json:",inline"`
+	Data     []BatchSubResponse `json:"data"`
+}
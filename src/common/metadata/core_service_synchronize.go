@@ -16,6 +16,7 @@ import (
 	"crypto/md5"
 	"encoding/base64"
 	"fmt"
+	"time"
 
 	"configcenter/src/common/mapstr"
 )
@@ -63,6 +64,8 @@ type SynchronizeDataInfo struct {
 	Count           int64  `json:"count"`
 	Version         int64  `json:"version"`
 	SynchronizeFlag string `json:"synchronize_flag"`
+	// ConflictPolicy how to handle the field which both changed in source and target
+	ConflictPolicy SynchronizeConflictPolicy `json:"conflict_policy"`
 }
 
 // SynchronizeParameter synchronize instance data http request parameter
@@ -80,6 +83,9 @@ type SynchronizeParameter struct {
 	InfoArray       []*SynchronizeItem `json:"instance_info_array"`
 	Version         int64              `json:"version"`
 	SynchronizeFlag string             `json:"synchronize_flag"`
+	// ConflictPolicy how to handle the field which both changed in source and target,
+	// only used by instance data, default SynchronizeConflictPolicySource
+	ConflictPolicy SynchronizeConflictPolicy `json:"conflict_policy"`
 }
 
 // SynchronizeItem synchronize data information
@@ -156,3 +162,99 @@ type SetIdenifierFlag struct {
 	// 3:删除, 删除同步标志
 	OperateType SynchronizeOperateType `json:"op_type"`
 }
+
+// SynchronizeConflictPolicy the way to handle a field which both changed
+// in the source cmdb and the target cmdb since last synchronize
+type SynchronizeConflictPolicy string
+
+const (
+	// SynchronizeConflictPolicySource the source value overwrite the target value
+	SynchronizeConflictPolicySource SynchronizeConflictPolicy = "source"
+	// SynchronizeConflictPolicyTarget keep the target value
+	SynchronizeConflictPolicyTarget SynchronizeConflictPolicy = "target"
+	// SynchronizeConflictPolicyManual keep the target value, wait for user resolve it
+	SynchronizeConflictPolicyManual SynchronizeConflictPolicy = "manual"
+)
+
+// Validate judge the policy is supported, empty policy means SynchronizeConflictPolicySource
+func (p SynchronizeConflictPolicy) Validate() bool {
+	switch p {
+	case "", SynchronizeConflictPolicySource, SynchronizeConflictPolicyTarget, SynchronizeConflictPolicyManual:
+		return true
+	}
+	return false
+}
+
+// SynchronizeConflictStatus synchronize conflict handle status
+type SynchronizeConflictStatus string
+
+const (
+	// SynchronizeConflictStatusPending conflict wait for user resolve
+	SynchronizeConflictStatusPending SynchronizeConflictStatus = "pending"
+	// SynchronizeConflictStatusResolved conflict already resolved
+	SynchronizeConflictStatusResolved SynchronizeConflictStatus = "resolved"
+)
+
+// SynchronizeConflictAction the action to resolve conflict
+type SynchronizeConflictAction string
+
+const (
+	// SynchronizeConflictActionUseSource write the source value to target instance
+	SynchronizeConflictActionUseSource SynchronizeConflictAction = "use_source"
+	// SynchronizeConflictActionUseTarget keep the target instance value
+	SynchronizeConflictActionUseTarget SynchronizeConflictAction = "use_target"
+)
+
+// SynchronizeInstSnapshot the source instance value of last synchronize,
+// it is the base to judge whether source or target has been changed.
+type SynchronizeInstSnapshot struct {
+	SynchronizeFlag string        `json:"synchronize_flag" bson:"synchronize_flag"`
+	DataClassify    string        `json:"data_classify" bson:"data_classify"`
+	InstID          int64         `json:"bk_inst_id" bson:"bk_inst_id"`
+	Version         int64         `json:"version" bson:"version"`
+	Info            mapstr.MapStr `json:"info" bson:"info"`
+	LastTime        time.Time     `json:"last_time" bson:"last_time"`
+}
+
+// SynchronizeConflict a field of instance both changed in source and target
+type SynchronizeConflict struct {
+	ID              int64                     `json:"id" bson:"id"`
+	SynchronizeFlag string                    `json:"synchronize_flag" bson:"synchronize_flag"`
+	DataClassify    string                    `json:"data_classify" bson:"data_classify"`
+	InstID          int64                     `json:"bk_inst_id" bson:"bk_inst_id"`
+	Field           string                    `json:"field" bson:"field"`
+	BaseValue       interface{}               `json:"base_value" bson:"base_value"`
+	SourceValue     interface{}               `json:"source_value" bson:"source_value"`
+	TargetValue     interface{}               `json:"target_value" bson:"target_value"`
+	Policy          SynchronizeConflictPolicy `json:"policy" bson:"policy"`
+	Status          SynchronizeConflictStatus `json:"status" bson:"status"`
+	Action          SynchronizeConflictAction `json:"action" bson:"action"`
+	Version         int64                     `json:"version" bson:"version"`
+	Resolver        string                    `json:"resolver" bson:"resolver"`
+	CreateTime      time.Time                 `json:"create_time" bson:"create_time"`
+	LastTime        time.Time                 `json:"last_time" bson:"last_time"`
+}
+
+// SynchronizeConflictSearchParameter search synchronize conflict http request parameter
+type SynchronizeConflictSearchParameter struct {
+	SynchronizeFlag string                    `json:"synchronize_flag"`
+	DataClassify    string                    `json:"data_classify"`
+	InstID          int64                     `json:"bk_inst_id"`
+	Status          SynchronizeConflictStatus `json:"status"`
+	Page            BasePage                  `json:"page"`
+}
+
+// SynchronizeConflictResolveParameter resolve synchronize conflict http request parameter
+type SynchronizeConflictResolveParameter struct {
+	IDs    []int64                   `json:"ids"`
+	Action SynchronizeConflictAction `json:"action"`
+}
+
+// SynchronizeConflictResult search synchronize conflict result
+type SynchronizeConflictResult struct {
+	BaseResp `json:",inline"`
+	Data     struct {
+		Count uint64                `json:"count"`
+		Info  []SynchronizeConflict `json:"info"`
+	} `json:"data"`
+}
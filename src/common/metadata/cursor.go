@@ -0,0 +1,102 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"configcenter/src/common"
+)
+
+// StreamPageSize the page size used to read the records of the NDJSON stream
+const StreamPageSize = 500
+
+// Cursor the position of the keyset paging, the records are sorted by the id field ascending,
+// and the next page starts after the last id of the previous page.
+type Cursor struct {
+	Field  string `json:"field"`
+	LastID int64  `json:"last_id"`
+}
+
+// Encode returns the opaque token of the cursor
+func (c Cursor) Encode() string {
+	js, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(js)
+}
+
+// ParseCursor parse the opaque token to cursor
+func ParseCursor(token string) (*Cursor, error) {
+	js, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	cursor := new(Cursor)
+	if err := json.Unmarshal(js, cursor); err != nil {
+		return nil, err
+	}
+	if cursor.Field == "" {
+		return nil, errors.New("cursor field is empty")
+	}
+	return cursor, nil
+}
+
+// ApplyCursor add the condition of the records after the cursor to the condition and sort the page by the id field,
+// the condition is returned unchanged if the page has no cursor.
+func (page *BasePage) ApplyCursor(idField string, cond map[string]interface{}) (map[string]interface{}, error) {
+	if page.Cursor == "" {
+		return cond, nil
+	}
+	cursor, err := ParseCursor(page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if cursor.Field != idField {
+		return nil, errors.New("cursor does not belong to the records")
+	}
+	page.Sort = idField
+	page.Start = 0
+
+	after := map[string]interface{}{idField: map[string]interface{}{common.BKDBGT: cursor.LastID}}
+	if len(cond) == 0 {
+		return after, nil
+	}
+	if _, exists := cond[idField]; exists {
+		return map[string]interface{}{common.BKDBAND: []map[string]interface{}{cond, after}}, nil
+	}
+	result := make(map[string]interface{}, len(cond)+1)
+	for key, value := range cond {
+		result[key] = value
+	}
+	result[idField] = after[idField]
+	return result, nil
+}
+
+// NextCursor returns the cursor of the page after the record with the last id, it's empty if the page
+// is not sorted by the id field or there is no more record.
+func (page BasePage) NextCursor(idField string, count int, lastID int64) string {
+	if page.Sort != idField || page.Limit <= 0 || page.Limit == common.BKNoLimit || count < page.Limit {
+		return ""
+	}
+	return Cursor{Field: idField, LastID: lastID}.Encode()
+}
+
+// ContentTypeNDJSON the content type of the stream which contains one json record per line
+const ContentTypeNDJSON = "application/x-ndjson"
+
+// StreamError the last line of the NDJSON stream if the stream is broken by error
+type StreamError struct {
+	Code    int    `json:"bk_error_code"`
+	Message string `json:"bk_error_msg"`
+}
@@ -0,0 +1,55 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBasePageCursor(t *testing.T) {
+	page := BasePage{Limit: 2, Sort: "bk_host_id"}
+	token := page.NextCursor("bk_host_id", 2, 9)
+	if token == "" {
+		t.Fatalf("NextCursor() of the full page is empty")
+	}
+	if got := page.NextCursor("bk_host_id", 1, 9); got != "" {
+		t.Errorf("NextCursor() of the last page = %s, want empty", got)
+	}
+	if got := (BasePage{Limit: 2}).NextCursor("bk_host_id", 2, 9); got != "" {
+		t.Errorf("NextCursor() of the page not sorted by id = %s, want empty", got)
+	}
+
+	next := BasePage{Limit: 2, Start: 10, Cursor: token}
+	cond, err := next.ApplyCursor("bk_host_id", map[string]interface{}{"bk_os_type": "1"})
+	if err != nil {
+		t.Fatalf("ApplyCursor() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"bk_os_type": "1",
+		"bk_host_id": map[string]interface{}{"$gt": int64(9)},
+	}
+	if !reflect.DeepEqual(cond, want) {
+		t.Errorf("ApplyCursor() = %v, want %v", cond, want)
+	}
+	if next.Sort != "bk_host_id" || next.Start != 0 {
+		t.Errorf("ApplyCursor() page = %+v, want sorted by id from start", next)
+	}
+
+	if _, err := next.ApplyCursor("bk_inst_id", nil); err == nil {
+		t.Errorf("ApplyCursor() with the cursor of other id field should fail")
+	}
+	if _, err := (&BasePage{Cursor: "invalid"}).ApplyCursor("bk_host_id", nil); err == nil {
+		t.Errorf("ApplyCursor() with invalid cursor should fail")
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/util"
+)
+
+// DiscoverSchema maps the payload reported by a discovery source to the models declaratively,
+// so that a new discovery script only needs to register its schema instead of customized code.
+// a message is analyzed by the schema when its data.meta.source is the registered source,
+// and the paths of the mappings are evaluated against its data.data payload.
+type DiscoverSchema struct {
+	Source      string            `json:"source" bson:"source"`
+	Description string            `json:"description" bson:"description"`
+	Mappings    []DiscoverMapping `json:"mappings" bson:"mappings"`
+	OwnerID     string            `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	Modifier    string            `json:"modifier" bson:"modifier"`
+	LastTime    Time              `json:"last_time" bson:"last_time"`
+}
+
+// DiscoverMapping maps the items in the payload to the instances of a model, the mappings are
+// applied in order, so that an item can be associated to the instances of the previous mappings.
+type DiscoverMapping struct {
+	ObjectID string `json:"bk_obj_id" bson:"bk_obj_id"`
+	// Items is the path of the item or the item array in the payload, the payload itself is the item when it is empty
+	Items string `json:"items" bson:"items"`
+	// Attributes maps the properties to the paths of the values in an item,
+	// the paths start with "$." are evaluated against the payload instead of the item
+	Attributes map[string]string `json:"attributes" bson:"attributes"`
+	// IdentityKeys are the properties to match the existing instance of an item
+	IdentityKeys []string                     `json:"identity_keys" bson:"identity_keys"`
+	Associations []DiscoverAssociationMapping `json:"associations" bson:"associations"`
+
+	// FullReport means the items are all the instances in the scope of the report,
+	// the instances in the scope but disappear from the report are handled by OnMissing
+	FullReport bool `json:"full_report" bson:"full_report"`
+	// ScopeKeys are the properties shared by all the instances in the scope, e.g. the ip of the reporting host,
+	// so that their paths must be evaluated against the payload
+	ScopeKeys []string `json:"scope_keys" bson:"scope_keys"`
+	OnMissing string   `json:"on_missing" bson:"on_missing"`
+}
+
+// DiscoverAssociationMapping associates the instance of an item to another instance,
+// the instance of the item is the source of the association.
+type DiscoverAssociationMapping struct {
+	ObjectAsstID string `json:"bk_obj_asst_id" bson:"bk_obj_asst_id"`
+	AsstObjectID string `json:"bk_asst_obj_id" bson:"bk_asst_obj_id"`
+	// AsstKeys maps the identity properties of the associated instance to the paths of the values in an item
+	AsstKeys map[string]string `json:"asst_keys" bson:"asst_keys"`
+}
+
+// the semantics of the instances disappear from a full report
+const (
+	DiscoverOnMissingKeep   = "keep"
+	DiscoverOnMissingDelete = "delete"
+)
+
+// DiscoverRootPathPrefix is the prefix of the paths evaluated against the payload
+const DiscoverRootPathPrefix = "$."
+
+// Validate checks the schema before it is registered
+func (s *DiscoverSchema) Validate() error {
+	if s.Source == "" {
+		return fmt.Errorf("source is required")
+	}
+	if len(s.Mappings) == 0 {
+		return fmt.Errorf("mappings are required")
+	}
+	for index, mapping := range s.Mappings {
+		if err := mapping.Validate(); err != nil {
+			return fmt.Errorf("mapping %d: %v", index, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the mapping
+func (m *DiscoverMapping) Validate() error {
+	if m.ObjectID == "" {
+		return fmt.Errorf("%s is required", common.BKObjIDField)
+	}
+	// the inner models have their own ways to be collected
+	if common.IsInnerModel(m.ObjectID) {
+		return fmt.Errorf("inner model %s can not be discovered", m.ObjectID)
+	}
+	if len(m.Attributes) == 0 {
+		return fmt.Errorf("attributes are required")
+	}
+	if len(m.IdentityKeys) == 0 {
+		return fmt.Errorf("identity keys are required")
+	}
+	for _, key := range m.IdentityKeys {
+		if _, ok := m.Attributes[key]; !ok {
+			return fmt.Errorf("identity key %s is not mapped", key)
+		}
+	}
+
+	for _, asst := range m.Associations {
+		if asst.ObjectAsstID == "" || asst.AsstObjectID == "" {
+			return fmt.Errorf("%s and %s of association are required", common.AssociationObjAsstIDField, common.BKAsstObjIDField)
+		}
+		if len(asst.AsstKeys) == 0 {
+			return fmt.Errorf("association %s requires asst keys", asst.ObjectAsstID)
+		}
+	}
+
+	switch m.OnMissing {
+	case "", DiscoverOnMissingKeep:
+	case DiscoverOnMissingDelete:
+		if !m.FullReport {
+			return fmt.Errorf("only the missing instances of a full report can be deleted")
+		}
+		// all the instances of the model would be deleted without the scope
+		if len(m.ScopeKeys) == 0 {
+			return fmt.Errorf("scope keys are required to delete the missing instances")
+		}
+	default:
+		return fmt.Errorf("unknown on_missing %s", m.OnMissing)
+	}
+	for _, key := range m.ScopeKeys {
+		if !strings.HasPrefix(m.Attributes[key], DiscoverRootPathPrefix) {
+			return fmt.Errorf("scope key %s must be mapped from the payload", key)
+		}
+		if util.InStrArr(m.IdentityKeys, key) && len(m.IdentityKeys) == 1 {
+			return fmt.Errorf("scope key %s can not be the only identity key", key)
+		}
+	}
+	return nil
+}
+
+type ParamSearchDiscoverSchema struct {
+	Sources []string `json:"sources"`
+}
+
+type ParamDeleteDiscoverSchema struct {
+	Sources []string `json:"sources"`
+}
+
+type RspDiscoverSchema struct {
+	Count uint64           `json:"count"`
+	Info  []DiscoverSchema `json:"info"`
+}
@@ -0,0 +1,94 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/querybuilder"
+)
+
+// ParseDynamicGroupCondition parse the condition of the dynamic group whose target is a model, the info of
+// the user custom query is the json of the query builder rules then.
+func ParseDynamicGroupCondition(info string) (*querybuilder.QueryFilter, error) {
+	rule, key, err := querybuilder.ParseRuleFromBytes([]byte(info))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: info.%s, err: %v", key, err)
+	}
+	filter := &querybuilder.QueryFilter{Rule: rule}
+	if key, err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid key: info.%s, err: %v", key, err)
+	}
+	return filter, nil
+}
+
+// DynamicGroupDataRequest the request to get the instances of a dynamic group
+type DynamicGroupDataRequest struct {
+	Fields []string `json:"fields"`
+	Page   BasePage `json:"page"`
+}
+
+// ListDynamicGroupInstances the option to search the instances of the model matched the dynamic group
+type ListDynamicGroupInstances struct {
+	BizID     int64                     `json:"bk_biz_id"`
+	ObjID     string                    `json:"bk_obj_id"`
+	Condition *querybuilder.QueryFilter `json:"condition"`
+	Fields    []string                  `json:"fields"`
+	Page      BasePage                  `json:"page"`
+}
+
+// Validate validate the option
+func (option ListDynamicGroupInstances) Validate() (string, error) {
+	if option.BizID <= 0 {
+		return common.BKAppIDField, fmt.Errorf("business id is required")
+	}
+	if len(option.ObjID) == 0 || option.ObjID == common.BKInnerObjIDPlat {
+		return common.BKObjIDField, fmt.Errorf("model %s can not be the target of dynamic group", option.ObjID)
+	}
+	if option.Condition == nil || option.Condition.Rule == nil {
+		return "condition", fmt.Errorf("condition is required")
+	}
+	if key, err := option.Condition.Validate(); err != nil {
+		return "condition." + key, err
+	}
+	if key, err := option.Page.Validate(); err != nil {
+		return "page." + key, err
+	}
+	return "", nil
+}
+
+// DynamicGroupInstances the instances matched the dynamic group
+type DynamicGroupInstances struct {
+	Count int             `json:"count"`
+	Info  []mapstr.MapStr `json:"info"`
+}
+
+// ListDynamicGroupInstancesResult the result of searching the instances of the dynamic group
+type ListDynamicGroupInstancesResult struct {
+	BaseResp `json:",inline"`
+	Data     DynamicGroupInstances `json:"data"`
+}
+
+// DynamicGroupMember the instance matched the materialized dynamic group
+type DynamicGroupMember struct {
+	GroupID    string    `json:"group_id" bson:"group_id"`
+	GroupName  string    `json:"group_name" bson:"group_name"`
+	BizID      int64     `json:"bk_biz_id" bson:"bk_biz_id"`
+	ObjID      string    `json:"bk_obj_id" bson:"bk_obj_id"`
+	InstID     int64     `json:"bk_inst_id" bson:"bk_inst_id"`
+	OwnerID    string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	CreateTime time.Time `json:"create_time" bson:"create_time"`
+}
@@ -0,0 +1,68 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDynamicGroupCondition(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    string
+		wantErr bool
+	}{
+		{"combined", `{"condition":"AND","rules":[{"field":"env","operator":"equal","value":"prod"}]}`, false},
+		{"atom", `{"field":"env","operator":"equal","value":"prod"}`, true},
+		{"operator", `{"condition":"AND","rules":[{"field":"env","operator":"unknown","value":"prod"}]}`, true},
+		{"json", `{"condition":`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDynamicGroupCondition(tt.info)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDynamicGroupCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListDynamicGroupInstances(t *testing.T) {
+	condition, err := ParseDynamicGroupCondition(`{"condition":"OR","rules":[{"field":"bk_module_name","operator":"contains","value":"db"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	option := ListDynamicGroupInstances{BizID: 2, ObjID: "module", Condition: condition, Page: BasePage{Limit: 10}}
+	if key, err := option.Validate(); err != nil {
+		t.Fatalf("Validate() key = %s, error = %v", key, err)
+	}
+
+	// the condition must be kept after transferred to the core service
+	data, err := json.Marshal(option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := ListDynamicGroupInstances{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := decoded.Validate(); err != nil {
+		t.Fatalf("Validate() key = %s, error = %v", key, err)
+	}
+
+	option.ObjID = "plat"
+	if _, err := option.Validate(); err == nil {
+		t.Errorf("Validate() plat should be invalid")
+	}
+}
@@ -173,6 +173,10 @@ const (
 const (
 	EventObjTypeProcModule     = "processmodule"
 	EventObjTypeModuleTransfer = "moduletransfer"
+
+	// the instances join or leave the materialized dynamic group
+	EventObjTypeGroupMemberAdded   = "group_member_added"
+	EventObjTypeGroupMemberRemoved = "group_member_removed"
 )
 
 // ConfirmMode define
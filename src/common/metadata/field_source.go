@@ -0,0 +1,105 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+)
+
+// FieldSourcePriority declares which sources are allowed to overwrite a field of the model instances.
+// a field set by a source can only be overwritten by the sources with the same or higher priority,
+// e.g. [user, cloud_sync, hostsnap] locks the manual edits against the collectors, and lets the
+// cloud sync beats the hostsnap. the fields without priority are always overwritten by the last writer.
+type FieldSourcePriority struct {
+	ObjectID   string `json:"bk_obj_id" bson:"bk_obj_id"`
+	PropertyID string `json:"bk_property_id" bson:"bk_property_id"`
+	// Sources are ordered by priority, the first one is the highest, the sources not listed are the lowest
+	Sources  []string `json:"sources" bson:"sources"`
+	OwnerID  string   `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	LastTime Time     `json:"last_time" bson:"last_time"`
+}
+
+// Validate checks the priority before it is saved
+func (p *FieldSourcePriority) Validate() error {
+	if p.ObjectID == "" {
+		return fmt.Errorf("bk_obj_id is required")
+	}
+	if p.PropertyID == "" {
+		return fmt.Errorf("bk_property_id is required")
+	}
+	if len(p.Sources) == 0 {
+		return fmt.Errorf("sources are required")
+	}
+	exists := make(map[string]bool)
+	for _, source := range p.Sources {
+		if source == "" {
+			return fmt.Errorf("source can not be empty")
+		}
+		if exists[source] {
+			return fmt.Errorf("source %s is duplicated", source)
+		}
+		exists[source] = true
+	}
+	return nil
+}
+
+// Rank returns the rank of the source, the smaller the higher priority
+func (p *FieldSourcePriority) Rank(source string) int {
+	for index, s := range p.Sources {
+		if s == source {
+			return index
+		}
+	}
+	return len(p.Sources)
+}
+
+// CanOverwrite returns whether the field set by the current source can be overwritten by the incoming source,
+// the current source is empty if the source of the field is not recorded yet.
+func (p *FieldSourcePriority) CanOverwrite(current, incoming string) bool {
+	if current == "" || current == incoming {
+		return true
+	}
+	return p.Rank(incoming) <= p.Rank(current)
+}
+
+// InstFieldSource records the sources which last set the fields of the instance
+type InstFieldSource struct {
+	ObjectID string `json:"bk_obj_id" bson:"bk_obj_id"`
+	InstID   int64  `json:"bk_inst_id" bson:"bk_inst_id"`
+	// Fields are keyed by the property id, the fields never set by a source are absent
+	Fields  map[string]FieldSource `json:"fields" bson:"fields"`
+	OwnerID string                 `json:"bk_supplier_account" bson:"bk_supplier_account"`
+}
+
+// FieldSource records the source which last set the field, and when
+type FieldSource struct {
+	Source   string `json:"source" bson:"source"`
+	Modifier string `json:"modifier" bson:"modifier"`
+	LastTime Time   `json:"last_time" bson:"last_time"`
+}
+
+// RejectedFields the fields of the instance the data source is not allowed to overwrite
+type RejectedFields struct {
+	InstID int64    `json:"bk_inst_id"`
+	Fields []string `json:"fields"`
+}
+
+type FieldSourcePriorityResult struct {
+	BaseResp `json:",inline"`
+	Data     []FieldSourcePriority `json:"data"`
+}
+
+type InstFieldSourceResult struct {
+	BaseResp `json:",inline"`
+	Data     InstFieldSource `json:"data"`
+}
@@ -0,0 +1,70 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"testing"
+)
+
+func TestFieldSourcePriorityValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority FieldSourcePriority
+		wantErr  bool
+	}{
+		{"valid", FieldSourcePriority{ObjectID: "host", PropertyID: "bk_host_outerip", Sources: []string{"user", "cloud_sync"}}, false},
+		{"without model", FieldSourcePriority{PropertyID: "bk_host_outerip", Sources: []string{"user"}}, true},
+		{"without property", FieldSourcePriority{ObjectID: "host", Sources: []string{"user"}}, true},
+		{"without sources", FieldSourcePriority{ObjectID: "host", PropertyID: "bk_host_outerip"}, true},
+		{"empty source", FieldSourcePriority{ObjectID: "host", PropertyID: "bk_host_outerip", Sources: []string{""}}, true},
+		{"duplicated source", FieldSourcePriority{ObjectID: "host", PropertyID: "bk_host_outerip", Sources: []string{"user", "user"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.priority.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFieldSourcePriorityCanOverwrite(t *testing.T) {
+	priority := FieldSourcePriority{
+		ObjectID:   "host",
+		PropertyID: "bk_host_outerip",
+		Sources:    []string{"user", "cloud_sync", "hostsnap"},
+	}
+	tests := []struct {
+		name     string
+		current  string
+		incoming string
+		want     bool
+	}{
+		{"not recorded", "", "hostsnap", true},
+		{"same source", "hostsnap", "hostsnap", true},
+		{"higher priority", "hostsnap", "cloud_sync", true},
+		{"lower priority", "cloud_sync", "hostsnap", false},
+		{"locked by user", "user", "cloud_sync", false},
+		{"unlisted over listed", "hostsnap", "discover", false},
+		{"listed over unlisted", "discover", "hostsnap", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := priority.CanOverwrite(tt.current, tt.incoming); got != tt.want {
+				t.Errorf("CanOverwrite(%s, %s) = %v, want %v", tt.current, tt.incoming, got, tt.want)
+			}
+		})
+	}
+}
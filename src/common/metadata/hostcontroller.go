@@ -140,14 +140,16 @@ type ModuleHostConfigParams struct {
 }
 
 type UserConfig struct {
-	Info       string    `json:"info" bson:"info"`
-	Name       string    `json:"name" bson:"name"`
-	ID         string    `json:"id" bson:"id"`
-	CreateTime time.Time `json:"create_time" bson:"create_time"`
-	UpdateTime time.Time `json:"last_time" bson:"last_time"`
-	AppID      int64     `json:"bk_biz_id" bson:"bk_biz_id"`
-	CreateUser string    `json:"create_user" bson:"create_user"`
-	ModifyUser string    `json:"modify_user" bson:"modify_user"`
+	Info        string    `json:"info" bson:"info"`
+	Name        string    `json:"name" bson:"name"`
+	ObjID       string    `json:"bk_obj_id" bson:"bk_obj_id"`
+	Materialize bool      `json:"materialize" bson:"materialize"`
+	ID          string    `json:"id" bson:"id"`
+	CreateTime  time.Time `json:"create_time" bson:"create_time"`
+	UpdateTime  time.Time `json:"last_time" bson:"last_time"`
+	AppID       int64     `json:"bk_biz_id" bson:"bk_biz_id"`
+	CreateUser  string    `json:"create_user" bson:"create_user"`
+	ModifyUser  string    `json:"modify_user" bson:"modify_user"`
 }
 
 type UserConfigResult struct {
@@ -193,22 +195,26 @@ type GetUserConfigDetailResult struct {
 }
 
 type UserConfigMeta struct {
-	AppID      int64     `json:"bk_biz_id,omitempty" bson:"bk_biz_id,omitempty"`
-	Info       string    `json:"info,omitempty" bson:"info,omitempty"`
-	Name       string    `json:"name,omitempty" bson:"name,omitempty"`
-	ID         string    `json:"id,omitempty" bson:"id,omitempty"`
-	CreateTime time.Time `json:"create_time" bson:"create_time,omitempty"`
-	CreateUser string    `json:"create_user" bson:"create_user,omitempty"`
-	ModifyUser string    `json:"modify_user" bson:"modify_user,omitempty"`
-	UpdateTime time.Time `json:"last_time" bson:"last_time,omitempty"`
-	OwnerID    string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	AppID       int64     `json:"bk_biz_id,omitempty" bson:"bk_biz_id,omitempty"`
+	Info        string    `json:"info,omitempty" bson:"info,omitempty"`
+	Name        string    `json:"name,omitempty" bson:"name,omitempty"`
+	ObjID       string    `json:"bk_obj_id,omitempty" bson:"bk_obj_id,omitempty"`
+	Materialize *bool     `json:"materialize,omitempty" bson:"materialize,omitempty"`
+	ID          string    `json:"id,omitempty" bson:"id,omitempty"`
+	CreateTime  time.Time `json:"create_time" bson:"create_time,omitempty"`
+	CreateUser  string    `json:"create_user" bson:"create_user,omitempty"`
+	ModifyUser  string    `json:"modify_user" bson:"modify_user,omitempty"`
+	UpdateTime  time.Time `json:"last_time" bson:"last_time,omitempty"`
+	OwnerID     string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
 }
 
 type AddConfigQuery struct {
-	AppID      int64  `json:"bk_biz_id,omitempty"`
-	Info       string `json:"info,omitempty"`
-	Name       string `json:"name,omitempty"`
-	CreateUser string `json:"create_user,omitempty"`
+	AppID       int64  `json:"bk_biz_id,omitempty"`
+	Info        string `json:"info,omitempty"`
+	Name        string `json:"name,omitempty"`
+	ObjID       string `json:"bk_obj_id,omitempty"`
+	Materialize bool   `json:"materialize,omitempty"`
+	CreateUser  string `json:"create_user,omitempty"`
 }
 
 type CloudTaskSearch struct {
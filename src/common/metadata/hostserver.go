@@ -107,6 +107,9 @@ type HostCommonSearch struct {
 	Condition []SearchCondition `json:"condition"`
 	Page      BasePage          `json:"page"`
 	Pattern   string            `json:"pattern,omitempty"`
+	// Expression the AND/OR/NOT expression tree, the field of rule is like bk_obj_id.bk_property_id, which can be
+	// the attribute of host, mainline object or the associated instance, it's combined with the condition by AND.
+	Expression *querybuilder.QueryFilter `json:"expression,omitempty"`
 }
 
 type HostModuleFind struct {
@@ -162,6 +165,8 @@ func (option ListHosts) GetHostPropertyFilter(ctx context.Context) (map[string]i
 
 // ip search info
 type IPInfo struct {
+	// Data the ips, and the cidr like 10.12.0.0/20 or fe80::/10, the range like 10.0.0.1-10.0.0.9 and the ipv4
+	// wildcard like 10.12.*.* which are matched by the numeric range of ip
 	Data  []string `json:"data"`
 	Exact int64    `json:"exact"`
 	Flag  string   `json:"flag"`
@@ -175,8 +180,9 @@ type SearchCondition struct {
 }
 
 type SearchHost struct {
-	Count int             `json:"count"`
-	Info  []mapstr.MapStr `json:"info"`
+	Count      int             `json:"count"`
+	Info       []mapstr.MapStr `json:"info"`
+	NextCursor string          `json:"next_cursor,omitempty"`
 }
 
 type ListHostResult struct {
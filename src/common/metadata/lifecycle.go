@@ -0,0 +1,177 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/util"
+)
+
+const (
+	// LifecycleOperationUpdate update the instance without changing its state
+	LifecycleOperationUpdate = "update"
+	// LifecycleOperationDelete delete the instance
+	LifecycleOperationDelete = "delete"
+	// LifecycleOperationTransfer transfer the host to other modules
+	LifecycleOperationTransfer = "transfer"
+)
+
+var lifecycleOperations = []string{LifecycleOperationUpdate, LifecycleOperationDelete, LifecycleOperationTransfer}
+
+// LifecycleState a state of the lifecycle
+type LifecycleState struct {
+	ID   string `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+	// RequiredFields the fields must be set when the instance is in this state
+	RequiredFields []string `json:"required_fields" bson:"required_fields"`
+	// BlockedOperations the operations not allowed when the instance is in this state
+	BlockedOperations []string `json:"blocked_operations" bson:"blocked_operations"`
+}
+
+// LifecycleTransition the allowed change of the state
+type LifecycleTransition struct {
+	From string `json:"from" bson:"from"`
+	To   string `json:"to" bson:"to"`
+}
+
+// ObjectLifecycle the lifecycle of the instances of the model, the state is kept in the attribute PropertyID
+type ObjectLifecycle struct {
+	ObjectID     string                `json:"bk_obj_id" bson:"bk_obj_id"`
+	PropertyID   string                `json:"bk_property_id" bson:"bk_property_id"`
+	InitialState string                `json:"initial_state" bson:"initial_state"`
+	States       []LifecycleState      `json:"states" bson:"states"`
+	Transitions  []LifecycleTransition `json:"transitions" bson:"transitions"`
+	OwnerID      string                `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	Modifier     string                `json:"modifier" bson:"modifier"`
+	LastTime     Time                  `json:"last_time" bson:"last_time"`
+}
+
+// Validate check whether the states and the transitions of the lifecycle are consistent
+func (l ObjectLifecycle) Validate() error {
+	if l.PropertyID == "" {
+		return fmt.Errorf("the attribute keeps the state can not be empty")
+	}
+	if len(l.States) == 0 {
+		return fmt.Errorf("the states can not be empty")
+	}
+	states := make(map[string]bool)
+	for _, state := range l.States {
+		if state.ID == "" {
+			return fmt.Errorf("the id of the state can not be empty")
+		}
+		if states[state.ID] {
+			return fmt.Errorf("the state %s is duplicated", state.ID)
+		}
+		states[state.ID] = true
+		for _, op := range state.BlockedOperations {
+			if !util.InStrArr(lifecycleOperations, op) {
+				return fmt.Errorf("the operation %s blocked by the state %s is not supported", op, state.ID)
+			}
+		}
+	}
+	if !states[l.InitialState] {
+		return fmt.Errorf("the initial state %s is not a state of the lifecycle", l.InitialState)
+	}
+	for _, transition := range l.Transitions {
+		if !states[transition.From] || !states[transition.To] {
+			return fmt.Errorf("the transition from %s to %s refers to unknown state", transition.From, transition.To)
+		}
+	}
+	return nil
+}
+
+// State returns the state with the id
+func (l ObjectLifecycle) State(id string) (LifecycleState, bool) {
+	for _, state := range l.States {
+		if state.ID == id {
+			return state, true
+		}
+	}
+	return LifecycleState{}, false
+}
+
+// CurrentState returns the state kept in the instance, the instances created before the lifecycle
+// is declared are treated as in the initial state
+func (l ObjectLifecycle) CurrentState(data mapstr.MapStr) string {
+	val, exist := data[l.PropertyID]
+	if !exist || IsEmptyAttributeValue(val) {
+		return l.InitialState
+	}
+	return fmt.Sprint(val)
+}
+
+// CanTransit returns whether the state can be changed from one to another
+func (l ObjectLifecycle) CanTransit(from, to string) bool {
+	if from == to {
+		return true
+	}
+	for _, transition := range l.Transitions {
+		if transition.From == from && transition.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBlocked returns whether the operation is not allowed in the state
+func (l ObjectLifecycle) IsBlocked(state, operation string) bool {
+	s, ok := l.State(state)
+	if !ok {
+		return false
+	}
+	return util.InStrArr(s.BlockedOperations, operation)
+}
+
+// MissingFields returns the fields required by the state but not set in the instance
+func (l ObjectLifecycle) MissingFields(state string, data mapstr.MapStr) []string {
+	missing := make([]string, 0)
+	s, ok := l.State(state)
+	if !ok {
+		return missing
+	}
+	for _, field := range s.RequiredFields {
+		if IsEmptyAttributeValue(data[field]) {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
+// LifecycleHistory a state transition of the instance
+type LifecycleHistory struct {
+	ID         int64  `json:"id" bson:"id"`
+	ObjectID   string `json:"bk_obj_id" bson:"bk_obj_id"`
+	InstID     int64  `json:"bk_inst_id" bson:"bk_inst_id"`
+	From       string `json:"from" bson:"from"`
+	To         string `json:"to" bson:"to"`
+	Operator   string `json:"operator" bson:"operator"`
+	OwnerID    string `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	CreateTime Time   `json:"create_time" bson:"create_time"`
+}
+
+type LifecycleHistoryList struct {
+	Count int64              `json:"count"`
+	Info  []LifecycleHistory `json:"info"`
+}
+
+type ObjectLifecycleResult struct {
+	BaseResp `json:",inline"`
+	Data     *ObjectLifecycle `json:"data"`
+}
+
+type SearchLifecycleHistoryResult struct {
+	BaseResp `json:",inline"`
+	Data     LifecycleHistoryList `json:"data"`
+}
@@ -0,0 +1,40 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"testing"
+)
+
+func TestObjectLifecycleValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		lifecycle ObjectLifecycle
+		wantErr   bool
+	}{
+		{"valid", ObjectLifecycle{PropertyID: "s", InitialState: "a", States: []LifecycleState{{ID: "a"}, {ID: "b", BlockedOperations: []string{LifecycleOperationDelete}}}, Transitions: []LifecycleTransition{{From: "a", To: "b"}}}, false},
+		{"without property", ObjectLifecycle{InitialState: "a", States: []LifecycleState{{ID: "a"}}}, true},
+		{"undeclared initial state", ObjectLifecycle{PropertyID: "s", InitialState: "b", States: []LifecycleState{{ID: "a"}}}, true},
+		{"duplicated state", ObjectLifecycle{PropertyID: "s", InitialState: "a", States: []LifecycleState{{ID: "a"}, {ID: "a"}}}, true},
+		{"unknown operation", ObjectLifecycle{PropertyID: "s", InitialState: "a", States: []LifecycleState{{ID: "a", BlockedOperations: []string{"x"}}}}, true},
+		{"undeclared transition state", ObjectLifecycle{PropertyID: "s", InitialState: "a", States: []LifecycleState{{ID: "a"}}, Transitions: []LifecycleTransition{{From: "a", To: "b"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.lifecycle.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,404 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ModelBundleVersion the version of the bundle format
+const ModelBundleVersion = 1
+
+const (
+	// ModelBundleFormatJSON the bundle is encoded as json
+	ModelBundleFormatJSON = "json"
+	// ModelBundleFormatYAML the bundle is encoded as yaml
+	ModelBundleFormatYAML = "yaml"
+)
+
+// the kinds of the items in the bundle
+const (
+	ModelBundleKindClassification  = "classification"
+	ModelBundleKindObject          = "object"
+	ModelBundleKindGroup           = "attribute_group"
+	ModelBundleKindAttribute       = "attribute"
+	ModelBundleKindUnique          = "unique"
+	ModelBundleKindAssociationKind = "association_kind"
+	ModelBundleKindAssociation     = "association"
+)
+
+const (
+	// ModelBundleActionCreate the item does not exist and will be created
+	ModelBundleActionCreate = "create"
+	// ModelBundleActionUpdate the item exists and the fields in changes will be updated
+	ModelBundleActionUpdate = "update"
+	// ModelBundleActionUnchanged the item exists and is the same as the one in the bundle
+	ModelBundleActionUnchanged = "unchanged"
+)
+
+// ModelBundle the portable definition of models, which can be exported from one cmdb and imported into another.
+// the ids, owners and the business scoped items are not included, the items are identified by their string ids.
+type ModelBundle struct {
+	Version          int                 `json:"version"`
+	Classifications  []Classification    `json:"classifications"`
+	Objects          []ModelBundleObject `json:"objects"`
+	AssociationKinds []AssociationKind   `json:"association_kinds"`
+	Associations     []Association       `json:"associations"`
+}
+
+// ModelBundleObject a model with its attribute groups, attributes and unique rules
+type ModelBundleObject struct {
+	Object     Object         `json:"object"`
+	Groups     []Group        `json:"groups"`
+	Attributes []Attribute    `json:"attributes"`
+	Uniques    []SchemaUnique `json:"uniques"`
+}
+
+// ExportModelBundleOption the option to export the model bundle
+type ExportModelBundleOption struct {
+	// ObjectIDs the models to export, all the models are exported if it's empty
+	ObjectIDs []string `json:"bk_obj_ids"`
+}
+
+// ModelBundlePlanItem what will be done to one item of the bundle
+type ModelBundlePlanItem struct {
+	Kind    string              `json:"kind"`
+	Key     string              `json:"key"`
+	Action  string              `json:"action"`
+	Changes []SchemaFieldChange `json:"changes,omitempty"`
+}
+
+// ModelBundlePlan what will be done when the bundle is applied
+type ModelBundlePlan struct {
+	Items          []ModelBundlePlanItem `json:"items"`
+	CreateCount    int                   `json:"create_count"`
+	UpdateCount    int                   `json:"update_count"`
+	UnchangedCount int                   `json:"unchanged_count"`
+}
+
+// ModelBundleApplied the plan applied and the resources created by it
+type ModelBundleApplied struct {
+	ModelBundlePlan `json:",inline"`
+	Created         ModelBundleCreated `json:"created"`
+}
+
+// ModelBundleCreated the resources created by applying the bundle, which are to be registered to iam
+type ModelBundleCreated struct {
+	Classifications    []Classification `json:"classifications"`
+	Objects            []Object         `json:"objects"`
+	Groups             []Group          `json:"groups"`
+	Attributes         []Attribute      `json:"attributes"`
+	UniqueIDs          []int64          `json:"unique_ids"`
+	AssociationKindIDs []int64          `json:"association_kind_ids"`
+}
+
+type ExportModelBundleResult struct {
+	BaseResp `json:",inline"`
+	Data     ModelBundle `json:"data"`
+}
+
+type ModelBundlePlanResult struct {
+	BaseResp `json:",inline"`
+	Data     ModelBundlePlan `json:"data"`
+}
+
+type ModelBundleApplyResult struct {
+	BaseResp `json:",inline"`
+	Data     ModelBundleApplied `json:"data"`
+}
+
+// bundleIgnoreFields the fields which are not portable between two cmdb
+var bundleIgnoreFields = map[string]bool{
+	"bk_supplier_account": true,
+	"metadata":            true,
+}
+
+// ObjectKey the key of the item which belongs to a model
+func (b ModelBundle) ObjectKey(objID, key string) string {
+	return objID + "/" + key
+}
+
+// Validate check the bundle is complete by itself
+func (b ModelBundle) Validate() error {
+	if b.Version != ModelBundleVersion {
+		return fmt.Errorf("unsupported bundle version %d", b.Version)
+	}
+
+	classifications := make(map[string]bool, len(b.Classifications))
+	for _, cls := range b.Classifications {
+		if cls.ClassificationID == "" || classifications[cls.ClassificationID] {
+			return fmt.Errorf("classification id [%s] is empty or duplicated", cls.ClassificationID)
+		}
+		classifications[cls.ClassificationID] = true
+	}
+
+	objects := make(map[string]bool, len(b.Objects))
+	for _, obj := range b.Objects {
+		objID := obj.Object.ObjectID
+		if objID == "" || objects[objID] {
+			return fmt.Errorf("object id [%s] is empty or duplicated", objID)
+		}
+		objects[objID] = true
+
+		groups := make(map[string]bool, len(obj.Groups))
+		for _, grp := range obj.Groups {
+			if grp.GroupID == "" || groups[grp.GroupID] || grp.ObjectID != objID {
+				return fmt.Errorf("group [%s] of object [%s] is invalid or duplicated", grp.GroupID, objID)
+			}
+			groups[grp.GroupID] = true
+		}
+
+		attrs := make(map[string]bool, len(obj.Attributes))
+		for _, attr := range obj.Attributes {
+			if attr.PropertyID == "" || attrs[attr.PropertyID] || attr.ObjectID != objID {
+				return fmt.Errorf("attribute [%s] of object [%s] is invalid or duplicated", attr.PropertyID, objID)
+			}
+			attrs[attr.PropertyID] = true
+		}
+
+		uniques := make(map[string]bool, len(obj.Uniques))
+		mustCheck := 0
+		for _, unique := range obj.Uniques {
+			if len(unique.Properties) == 0 || uniques[unique.key()] {
+				return fmt.Errorf("unique [%s] of object [%s] is empty or duplicated", unique.key(), objID)
+			}
+			uniques[unique.key()] = true
+			for _, propertyID := range unique.Properties {
+				if !attrs[propertyID] {
+					return fmt.Errorf("unique [%s] of object [%s] refers to unknown attribute [%s]",
+						unique.key(), objID, propertyID)
+				}
+			}
+			if unique.MustCheck {
+				mustCheck++
+			}
+		}
+		if mustCheck > 1 {
+			return fmt.Errorf("object [%s] has more than one must check unique", objID)
+		}
+	}
+
+	kinds := make(map[string]bool, len(b.AssociationKinds))
+	for _, kind := range b.AssociationKinds {
+		if kind.AssociationKindID == "" || kinds[kind.AssociationKindID] {
+			return fmt.Errorf("association kind [%s] is empty or duplicated", kind.AssociationKindID)
+		}
+		kinds[kind.AssociationKindID] = true
+	}
+
+	assts := make(map[string]bool, len(b.Associations))
+	for _, asst := range b.Associations {
+		if asst.AssociationName == "" || assts[asst.AssociationName] {
+			return fmt.Errorf("association [%s] is empty or duplicated", asst.AssociationName)
+		}
+		assts[asst.AssociationName] = true
+	}
+
+	return nil
+}
+
+// Normalize sort the uniques' properties, so that they can be compared with each other
+func (b *ModelBundle) Normalize() {
+	for i := range b.Objects {
+		for j := range b.Objects[i].Uniques {
+			sort.Strings(b.Objects[i].Uniques[j].Properties)
+		}
+	}
+}
+
+// PlanModelBundle compare the incoming bundle with the current one exported from the target cmdb,
+// the items which are only in current are left untouched, so they are not part of the plan.
+func PlanModelBundle(current, incoming ModelBundle) ModelBundlePlan {
+	plan := ModelBundlePlan{Items: make([]ModelBundlePlanItem, 0)}
+	add := func(kind, key string, exist bool, from, to interface{}) {
+		item := ModelBundlePlanItem{Kind: kind, Key: key, Action: ModelBundleActionCreate}
+		if exist {
+			item.Changes = diffBundleFields(from, to)
+			item.Action = ModelBundleActionUnchanged
+			if len(item.Changes) > 0 {
+				item.Action = ModelBundleActionUpdate
+			}
+		}
+		switch item.Action {
+		case ModelBundleActionCreate:
+			plan.CreateCount++
+		case ModelBundleActionUpdate:
+			plan.UpdateCount++
+		default:
+			plan.UnchangedCount++
+		}
+		plan.Items = append(plan.Items, item)
+	}
+
+	classifications := make(map[string]Classification, len(current.Classifications))
+	for _, cls := range current.Classifications {
+		classifications[cls.ClassificationID] = cls
+	}
+	for _, cls := range incoming.Classifications {
+		origin, exist := classifications[cls.ClassificationID]
+		add(ModelBundleKindClassification, cls.ClassificationID, exist, origin, cls)
+	}
+
+	objects := make(map[string]ModelBundleObject, len(current.Objects))
+	for _, obj := range current.Objects {
+		objects[obj.Object.ObjectID] = obj
+	}
+	for _, obj := range incoming.Objects {
+		objID := obj.Object.ObjectID
+		origin, exist := objects[objID]
+		add(ModelBundleKindObject, objID, exist, origin.Object, obj.Object)
+
+		groups := make(map[string]Group, len(origin.Groups))
+		for _, grp := range origin.Groups {
+			groups[grp.GroupID] = grp
+		}
+		for _, grp := range obj.Groups {
+			originGrp, exist := groups[grp.GroupID]
+			add(ModelBundleKindGroup, incoming.ObjectKey(objID, grp.GroupID), exist, originGrp, grp)
+		}
+
+		attrs := make(map[string]Attribute, len(origin.Attributes))
+		for _, attr := range origin.Attributes {
+			attrs[attr.PropertyID] = attr
+		}
+		for _, attr := range obj.Attributes {
+			originAttr, exist := attrs[attr.PropertyID]
+			add(ModelBundleKindAttribute, incoming.ObjectKey(objID, attr.PropertyID), exist, originAttr, attr)
+		}
+
+		uniques := make(map[string]SchemaUnique, len(origin.Uniques))
+		for _, unique := range origin.Uniques {
+			uniques[unique.key()] = unique
+		}
+		incomingUniques := make(map[string]bool, len(obj.Uniques))
+		mustCheck := ""
+		for _, unique := range obj.Uniques {
+			incomingUniques[unique.key()] = true
+			if unique.MustCheck {
+				mustCheck = unique.key()
+			}
+			originUnique, exist := uniques[unique.key()]
+			add(ModelBundleKindUnique, incoming.ObjectKey(objID, unique.key()), exist, originUnique, unique)
+		}
+		// there is only one must check unique of a model, so the current one is replaced.
+		for _, unique := range origin.Uniques {
+			if mustCheck == "" || !unique.MustCheck || unique.key() == mustCheck || incomingUniques[unique.key()] {
+				continue
+			}
+			replaced := unique
+			replaced.MustCheck = false
+			add(ModelBundleKindUnique, incoming.ObjectKey(objID, unique.key()), true, unique, replaced)
+		}
+	}
+
+	kinds := make(map[string]AssociationKind, len(current.AssociationKinds))
+	for _, kind := range current.AssociationKinds {
+		kinds[kind.AssociationKindID] = kind
+	}
+	for _, kind := range incoming.AssociationKinds {
+		origin, exist := kinds[kind.AssociationKindID]
+		add(ModelBundleKindAssociationKind, kind.AssociationKindID, exist, origin, kind)
+	}
+
+	assts := make(map[string]Association, len(current.Associations))
+	for _, asst := range current.Associations {
+		assts[asst.AssociationName] = asst
+	}
+	for _, asst := range incoming.Associations {
+		origin, exist := assts[asst.AssociationName]
+		add(ModelBundleKindAssociation, asst.AssociationName, exist, origin, asst)
+	}
+
+	return plan
+}
+
+// diffBundleFields compare the items regardless of the fields that are not portable
+func diffBundleFields(from, to interface{}) []SchemaFieldChange {
+	changes := make([]SchemaFieldChange, 0)
+	for _, change := range diffSchemaFields(from, to) {
+		if !bundleIgnoreFields[change.Field] {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// EncodeModelBundle encode the bundle with the format, which is json or yaml
+func EncodeModelBundle(bundle ModelBundle, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(bundle, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case ModelBundleFormatJSON, "":
+		return data, nil
+	case ModelBundleFormatYAML:
+		// the yaml is converted from the json, so that the keys are the same as the json tags.
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(value)
+	default:
+		return nil, fmt.Errorf("unsupported bundle format %s", format)
+	}
+}
+
+// DecodeModelBundle decode the bundle with the format, which is json or yaml
+func DecodeModelBundle(data []byte, format string) (ModelBundle, error) {
+	bundle := ModelBundle{}
+	switch format {
+	case ModelBundleFormatJSON, "":
+	case ModelBundleFormatYAML:
+		var value interface{}
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return bundle, err
+		}
+		converted, err := json.Marshal(convertYAMLValue(value))
+		if err != nil {
+			return bundle, err
+		}
+		data = converted
+	default:
+		return bundle, fmt.Errorf("unsupported bundle format %s", format)
+	}
+
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return bundle, err
+	}
+	bundle.Normalize()
+	return bundle, nil
+}
+
+// convertYAMLValue convert the map[interface{}]interface{} decoded by yaml into the one can be encoded into json
+func convertYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = convertYAMLValue(item)
+		}
+		return result
+	case []interface{}:
+		for i := range v {
+			v[i] = convertYAMLValue(v[i])
+		}
+		return v
+	default:
+		return value
+	}
+}
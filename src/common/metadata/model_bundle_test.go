@@ -0,0 +1,99 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"testing"
+)
+
+func TestPlanModelBundle(t *testing.T) {
+	current := ModelBundle{
+		Version:         ModelBundleVersion,
+		Classifications: []Classification{{ID: 1, ClassificationID: "bk_network", ClassificationName: "Network"}},
+		Objects: []ModelBundleObject{{
+			Object:     Object{ID: 1, ObjectID: "switch", ObjectName: "Switch", ObjCls: "bk_network", OwnerID: "0"},
+			Groups:     []Group{{ID: 1, ObjectID: "switch", GroupID: "default", GroupName: "Default"}},
+			Attributes: []Attribute{{ID: 1, ObjectID: "switch", PropertyID: "bk_inst_name", PropertyName: "Name"}},
+			Uniques:    []SchemaUnique{{MustCheck: true, Properties: []string{"bk_inst_name"}}},
+		}},
+	}
+	incoming := ModelBundle{
+		Version:         ModelBundleVersion,
+		Classifications: []Classification{{ClassificationID: "bk_network", ClassificationName: "Network"}},
+		Objects: []ModelBundleObject{{
+			Object: Object{ObjectID: "switch", ObjectName: "Switch", ObjCls: "bk_network"},
+			Groups: []Group{{ObjectID: "switch", GroupID: "default", GroupName: "Basic"}},
+			Attributes: []Attribute{
+				{ObjectID: "switch", PropertyID: "bk_inst_name", PropertyName: "Name"},
+				{ObjectID: "switch", PropertyID: "bk_sn", PropertyName: "SN"},
+			},
+			Uniques: []SchemaUnique{{MustCheck: true, Properties: []string{"bk_sn"}}},
+		}},
+	}
+	if err := incoming.Validate(); err != nil {
+		t.Fatalf("validate bundle failed, err: %v", err)
+	}
+
+	plan := PlanModelBundle(current, incoming)
+	actions := make(map[string]string)
+	for _, item := range plan.Items {
+		actions[item.Kind+":"+item.Key] = item.Action
+	}
+	expects := map[string]string{
+		"classification:bk_network":      ModelBundleActionUnchanged,
+		"object:switch":                  ModelBundleActionUnchanged,
+		"attribute_group:switch/default": ModelBundleActionUpdate,
+		"attribute:switch/bk_inst_name":  ModelBundleActionUnchanged,
+		"attribute:switch/bk_sn":         ModelBundleActionCreate,
+		"unique:switch/bk_sn":            ModelBundleActionCreate,
+		"unique:switch/bk_inst_name":     ModelBundleActionUpdate,
+	}
+	if len(actions) != len(expects) {
+		t.Errorf("plan items = %+v, want %d items", plan.Items, len(expects))
+	}
+	for key, action := range expects {
+		if actions[key] != action {
+			t.Errorf("the action of %s = %s, want %s", key, actions[key], action)
+		}
+	}
+	if plan.CreateCount != 2 || plan.UpdateCount != 2 || plan.UnchangedCount != 3 {
+		t.Errorf("plan counts = %d/%d/%d, want 2/2/3", plan.CreateCount, plan.UpdateCount, plan.UnchangedCount)
+	}
+}
+
+func TestEncodeModelBundle(t *testing.T) {
+	bundle := ModelBundle{
+		Version: ModelBundleVersion,
+		Objects: []ModelBundleObject{{
+			Object: Object{ObjectID: "switch", ObjectName: "Switch"},
+			Attributes: []Attribute{{ObjectID: "switch", PropertyID: "bk_port", PropertyIndex: 2,
+				Option: map[string]interface{}{"min": "1", "max": "65535"}}},
+			Uniques: []SchemaUnique{{MustCheck: true, Properties: []string{"bk_port"}}},
+		}},
+	}
+
+	for _, format := range []string{ModelBundleFormatJSON, ModelBundleFormatYAML} {
+		data, err := EncodeModelBundle(bundle, format)
+		if err != nil {
+			t.Fatalf("encode %s bundle failed, err: %v", format, err)
+		}
+		decoded, err := DecodeModelBundle(data, format)
+		if err != nil {
+			t.Fatalf("decode %s bundle failed, err: %v", format, err)
+		}
+		plan := PlanModelBundle(bundle, decoded)
+		if plan.CreateCount != 0 || plan.UpdateCount != 0 {
+			t.Errorf("the %s bundle is changed after decoding, plan: %+v", format, plan)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// ModelSchemaActionChange the version is created by a structural change of the model
+	ModelSchemaActionChange = "change"
+	// ModelSchemaActionRollback the version is created by rolling back to a former version
+	ModelSchemaActionRollback = "rollback"
+	// ModelSchemaActionInit the version is the snapshot of the model taken when the versioning is enabled
+	ModelSchemaActionInit = "init"
+)
+
+// ModelSchema the structure of a model, including the object, attributes, groups and uniques
+type ModelSchema struct {
+	Object     Object         `json:"object" bson:"object"`
+	Attributes []Attribute    `json:"attributes" bson:"attributes"`
+	Groups     []Group        `json:"groups" bson:"groups"`
+	Uniques    []ObjectUnique `json:"uniques" bson:"uniques"`
+}
+
+// ModelSchemaVersion the snapshot of the model schema, the version increases on every structural change
+type ModelSchemaVersion struct {
+	ObjectID        string      `json:"bk_obj_id" bson:"bk_obj_id"`
+	Version         int64       `json:"version" bson:"version"`
+	Action          string      `json:"action" bson:"action"`
+	RollbackVersion int64       `json:"rollback_version,omitempty" bson:"rollback_version,omitempty"`
+	Operator        string      `json:"operator" bson:"operator"`
+	OwnerID         string      `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	CreateTime      Time        `json:"create_time" bson:"create_time"`
+	Schema          ModelSchema `json:"schema" bson:"schema"`
+}
+
+// QueryModelSchemaVersionResult the model schema versions query result
+type QueryModelSchemaVersionResult struct {
+	Count uint64               `json:"count"`
+	Info  []ModelSchemaVersion `json:"info"`
+}
+
+// ReadModelSchemaVersionResult the model schema versions query response
+type ReadModelSchemaVersionResult struct {
+	BaseResp `json:",inline"`
+	Data     QueryModelSchemaVersionResult `json:"data"`
+}
+
+// ModelSchemaVersionResult the single model schema version response
+type ModelSchemaVersionResult struct {
+	BaseResp `json:",inline"`
+	Data     ModelSchemaVersion `json:"data"`
+}
+
+// ModelSchemaDiffResult the model schema diff response
+type ModelSchemaDiffResult struct {
+	BaseResp `json:",inline"`
+	Data     ModelSchemaDiff `json:"data"`
+}
+
+// SchemaFieldChange the value of the field is changed from From to To
+type SchemaFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// SchemaItemChange the changed fields of an attribute or a group identified by the key
+type SchemaItemChange struct {
+	Key     string              `json:"key"`
+	Changes []SchemaFieldChange `json:"changes"`
+}
+
+// SchemaUnique the unique rule described by the property ids, the key ids is meaningless across versions
+// because a removed attribute gets a new id when it is created again.
+type SchemaUnique struct {
+	MustCheck  bool     `json:"must_check"`
+	Properties []string `json:"properties"`
+}
+
+// ModelSchemaDiff the difference between two model schema versions
+type ModelSchemaDiff struct {
+	ObjectID          string              `json:"bk_obj_id"`
+	FromVersion       int64               `json:"from_version"`
+	ToVersion         int64               `json:"to_version"`
+	Object            []SchemaFieldChange `json:"object"`
+	AddedAttributes   []Attribute         `json:"added_attributes"`
+	RemovedAttributes []Attribute         `json:"removed_attributes"`
+	ChangedAttributes []SchemaItemChange  `json:"changed_attributes"`
+	AddedGroups       []Group             `json:"added_groups"`
+	RemovedGroups     []Group             `json:"removed_groups"`
+	ChangedGroups     []SchemaItemChange  `json:"changed_groups"`
+	AddedUniques      []SchemaUnique      `json:"added_uniques"`
+	RemovedUniques    []SchemaUnique      `json:"removed_uniques"`
+}
+
+// IsEmpty returns whether the two schemas are structurally the same
+func (d ModelSchemaDiff) IsEmpty() bool {
+	return len(d.Object) == 0 &&
+		len(d.AddedAttributes) == 0 && len(d.RemovedAttributes) == 0 && len(d.ChangedAttributes) == 0 &&
+		len(d.AddedGroups) == 0 && len(d.RemovedGroups) == 0 && len(d.ChangedGroups) == 0 &&
+		len(d.AddedUniques) == 0 && len(d.RemovedUniques) == 0
+}
+
+// schemaIgnoreFields the fields changed without a structural change
+var schemaIgnoreFields = map[string]bool{
+	"id":                     true,
+	"creator":                true,
+	"modifier":               true,
+	"create_time":            true,
+	"last_time":              true,
+	"position":               true,
+	"bk_property_group_name": true,
+}
+
+// AttributeKey the key to identify the attribute across versions
+func (s ModelSchema) AttributeKey(attr Attribute) string {
+	if _, bizID := attr.Metadata.Label.Get(LabelBusinessID); bizID != "" {
+		return attr.PropertyID + "@" + bizID
+	}
+	return attr.PropertyID
+}
+
+// GroupKey the key to identify the group across versions
+func (s ModelSchema) GroupKey(grp Group) string {
+	if _, bizID := grp.Metadata.Label.Get(LabelBusinessID); bizID != "" {
+		return grp.GroupID + "@" + bizID
+	}
+	return grp.GroupID
+}
+
+// SchemaUnique convert the unique into the one described by the property ids
+func (s ModelSchema) SchemaUnique(unique ObjectUnique) SchemaUnique {
+	propertyIDs := make(map[uint64]string, len(s.Attributes))
+	for _, attr := range s.Attributes {
+		propertyIDs[uint64(attr.ID)] = attr.PropertyID
+	}
+	result := SchemaUnique{MustCheck: unique.MustCheck, Properties: make([]string, 0, len(unique.Keys))}
+	for _, key := range unique.Keys {
+		result.Properties = append(result.Properties, propertyIDs[key.ID])
+	}
+	sort.Strings(result.Properties)
+	return result
+}
+
+func (u SchemaUnique) key() string {
+	return strings.Join(u.Properties, ",")
+}
+
+// DiffModelSchema compare the schema from with the schema to
+func DiffModelSchema(from, to ModelSchema) ModelSchemaDiff {
+	diff := ModelSchemaDiff{
+		ObjectID:          to.Object.ObjectID,
+		Object:            diffSchemaFields(from.Object, to.Object),
+		AddedAttributes:   make([]Attribute, 0),
+		RemovedAttributes: make([]Attribute, 0),
+		ChangedAttributes: make([]SchemaItemChange, 0),
+		AddedGroups:       make([]Group, 0),
+		RemovedGroups:     make([]Group, 0),
+		ChangedGroups:     make([]SchemaItemChange, 0),
+		AddedUniques:      make([]SchemaUnique, 0),
+		RemovedUniques:    make([]SchemaUnique, 0),
+	}
+
+	fromAttrs := make(map[string]Attribute, len(from.Attributes))
+	for _, attr := range from.Attributes {
+		fromAttrs[from.AttributeKey(attr)] = attr
+	}
+	toAttrs := make(map[string]bool, len(to.Attributes))
+	for _, attr := range to.Attributes {
+		key := to.AttributeKey(attr)
+		toAttrs[key] = true
+		origin, exist := fromAttrs[key]
+		if !exist {
+			diff.AddedAttributes = append(diff.AddedAttributes, attr)
+			continue
+		}
+		if changes := diffSchemaFields(origin, attr); len(changes) > 0 {
+			diff.ChangedAttributes = append(diff.ChangedAttributes, SchemaItemChange{Key: key, Changes: changes})
+		}
+	}
+	for _, attr := range from.Attributes {
+		if !toAttrs[from.AttributeKey(attr)] {
+			diff.RemovedAttributes = append(diff.RemovedAttributes, attr)
+		}
+	}
+
+	fromGroups := make(map[string]Group, len(from.Groups))
+	for _, grp := range from.Groups {
+		fromGroups[from.GroupKey(grp)] = grp
+	}
+	toGroups := make(map[string]bool, len(to.Groups))
+	for _, grp := range to.Groups {
+		key := to.GroupKey(grp)
+		toGroups[key] = true
+		origin, exist := fromGroups[key]
+		if !exist {
+			diff.AddedGroups = append(diff.AddedGroups, grp)
+			continue
+		}
+		if changes := diffSchemaFields(origin, grp); len(changes) > 0 {
+			diff.ChangedGroups = append(diff.ChangedGroups, SchemaItemChange{Key: key, Changes: changes})
+		}
+	}
+	for _, grp := range from.Groups {
+		if !toGroups[from.GroupKey(grp)] {
+			diff.RemovedGroups = append(diff.RemovedGroups, grp)
+		}
+	}
+
+	fromUniques := make(map[string]SchemaUnique, len(from.Uniques))
+	for _, unique := range from.Uniques {
+		item := from.SchemaUnique(unique)
+		fromUniques[item.key()] = item
+	}
+	toUniques := make(map[string]SchemaUnique, len(to.Uniques))
+	for _, unique := range to.Uniques {
+		item := to.SchemaUnique(unique)
+		toUniques[item.key()] = item
+		if origin, exist := fromUniques[item.key()]; !exist || origin.MustCheck != item.MustCheck {
+			diff.AddedUniques = append(diff.AddedUniques, item)
+		}
+	}
+	for key, item := range fromUniques {
+		if target, exist := toUniques[key]; !exist || target.MustCheck != item.MustCheck {
+			diff.RemovedUniques = append(diff.RemovedUniques, item)
+		}
+	}
+	sort.Slice(diff.RemovedUniques, func(i, j int) bool {
+		return diff.RemovedUniques[i].key() < diff.RemovedUniques[j].key()
+	})
+
+	return diff
+}
+
+// diffSchemaFields compare the json representation of the two items, so that the values decoded from
+// the database and the ones from the request can be compared with each other.
+func diffSchemaFields(from, to interface{}) []SchemaFieldChange {
+	fromFields, toFields := schemaFields(from), schemaFields(to)
+	keys := make([]string, 0, len(toFields))
+	for key := range toFields {
+		keys = append(keys, key)
+	}
+	for key := range fromFields {
+		if _, exist := toFields[key]; !exist {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := make([]SchemaFieldChange, 0)
+	for _, key := range keys {
+		if schemaIgnoreFields[key] {
+			continue
+		}
+		if !reflect.DeepEqual(fromFields[key], toFields[key]) {
+			changes = append(changes, SchemaFieldChange{Field: key, From: fromFields[key], To: toFields[key]})
+		}
+	}
+	return changes
+}
+
+func schemaFields(item interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
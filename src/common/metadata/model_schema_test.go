@@ -0,0 +1,67 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"testing"
+)
+
+func TestDiffModelSchema(t *testing.T) {
+	from := ModelSchema{
+		Object: Object{ID: 1, ObjectID: "host", ObjectName: "host"},
+		Attributes: []Attribute{
+			{ID: 1, ObjectID: "host", PropertyID: "bk_os_type", PropertyType: "enum",
+				Option: []interface{}{map[string]interface{}{"id": "1"}, map[string]interface{}{"id": "2"}}},
+			{ID: 2, ObjectID: "host", PropertyID: "bk_host_innerip"},
+			{ID: 3, ObjectID: "host", PropertyID: "bk_asset_id"},
+		},
+		Groups:  []Group{{ID: 1, GroupID: "default", GroupName: "Default"}},
+		Uniques: []ObjectUnique{{ID: 1, MustCheck: true, Keys: []UniqueKey{{Kind: UniqueKeyKindProperty, ID: 2}}}},
+	}
+	to := ModelSchema{
+		Object: Object{ID: 1, ObjectID: "host", ObjectName: "Host", LastTime: &Time{}},
+		Attributes: []Attribute{
+			{ID: 1, ObjectID: "host", PropertyID: "bk_os_type", PropertyType: "enum",
+				Option: []interface{}{map[string]interface{}{"id": "1"}}},
+			{ID: 12, ObjectID: "host", PropertyID: "bk_host_innerip"},
+			{ID: 4, ObjectID: "host", PropertyID: "bk_sn"},
+		},
+		Groups:  []Group{{ID: 1, GroupID: "default", GroupName: "Default"}},
+		Uniques: []ObjectUnique{{ID: 2, MustCheck: true, Keys: []UniqueKey{{Kind: UniqueKeyKindProperty, ID: 12}}}},
+	}
+
+	diff := DiffModelSchema(from, to)
+	if len(diff.Object) != 1 || diff.Object[0].Field != "bk_obj_name" {
+		t.Errorf("object changes = %+v, want bk_obj_name only", diff.Object)
+	}
+	if len(diff.AddedAttributes) != 1 || diff.AddedAttributes[0].PropertyID != "bk_sn" {
+		t.Errorf("added attributes = %+v, want bk_sn", diff.AddedAttributes)
+	}
+	if len(diff.RemovedAttributes) != 1 || diff.RemovedAttributes[0].PropertyID != "bk_asset_id" {
+		t.Errorf("removed attributes = %+v, want bk_asset_id", diff.RemovedAttributes)
+	}
+	if len(diff.ChangedAttributes) != 1 || diff.ChangedAttributes[0].Key != "bk_os_type" ||
+		diff.ChangedAttributes[0].Changes[0].Field != "option" {
+		t.Errorf("changed attributes = %+v, want the option of bk_os_type", diff.ChangedAttributes)
+	}
+	if len(diff.AddedUniques) != 0 || len(diff.RemovedUniques) != 0 {
+		t.Errorf("the unique on the recreated attribute should not be changed, diff: %+v", diff)
+	}
+	if len(diff.AddedGroups) != 0 || len(diff.RemovedGroups) != 0 || len(diff.ChangedGroups) != 0 {
+		t.Errorf("groups should not be changed, diff: %+v", diff)
+	}
+
+	if !DiffModelSchema(to, to).IsEmpty() {
+		t.Errorf("diff of the same schema should be empty")
+	}
+}
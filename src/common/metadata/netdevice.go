@@ -13,7 +13,13 @@
 package metadata
 
 import (
+	"fmt"
+	"reflect"
+	"regexp"
 	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/util"
 )
 
 type NetcollectDevice struct {
@@ -96,8 +102,51 @@ type NetcollectConfig struct {
 	ScanRange []string `json:"scan_range" bson:"scan_range"`
 	Period    string   `json:"period" bson:"period"`
 	Community string   `json:"community" bson:"community"`
+	// Snmp is only used by the built-in snmp poller, netdevicebeat polls by snmp v2c with the community
+	Snmp NetcollectSnmpConfig `json:"snmp" bson:"snmp"`
+}
+
+// NetcollectSnmpConfig the snmp parameters of the built-in snmp poller
+type NetcollectSnmpConfig struct {
+	// Version is v2c or v3, default v2c
+	Version string `json:"version" bson:"version"`
+	Port    int    `json:"port" bson:"port"`
+	// Timeout is the timeout of each request in seconds
+	Timeout int `json:"timeout" bson:"timeout"`
+	Retries int `json:"retries" bson:"retries"`
+
+	// the fields below are the snmp v3 user based security model parameters
+	UserName      string `json:"user_name" bson:"user_name"`
+	SecurityLevel string `json:"security_level" bson:"security_level"`
+	AuthProtocol  string `json:"auth_protocol" bson:"auth_protocol"`
+	AuthPassword  string `json:"auth_password" bson:"auth_password"`
+	PrivProtocol  string `json:"priv_protocol" bson:"priv_protocol"`
+	PrivPassword  string `json:"priv_password" bson:"priv_password"`
+}
+
+// HideSecrets blanks the snmp v3 passwords, the passwords are never returned to the users or logged
+func (c *NetcollectConfig) HideSecrets() {
+	c.Snmp.AuthPassword = ""
+	c.Snmp.PrivPassword = ""
 }
 
+// KeepSecrets keeps the snmp v3 passwords of the saved config which are not set, as the users
+// never get the passwords, they update the config without them.
+func (c *NetcollectConfig) KeepSecrets(saved NetcollectConfig) {
+	if c.Snmp.AuthPassword == "" {
+		c.Snmp.AuthPassword = saved.Snmp.AuthPassword
+	}
+	if c.Snmp.PrivPassword == "" {
+		c.Snmp.PrivPassword = saved.Snmp.PrivPassword
+	}
+}
+
+// the snmp versions of the built-in snmp poller
+const (
+	NetcollectSnmpV2c = "v2c"
+	NetcollectSnmpV3  = "v3"
+)
+
 type ParamSearchNetcollectReport struct {
 	Action    string   `json:"action"`
 	ObjectID  string   `json:"bk_object_id"`
@@ -144,11 +193,187 @@ type NetcollectReport struct {
 	LastTime     Time                          `json:"last_time" bson:"last_time"`
 	Attributes   []NetcollectReportAttribute   `json:"attributes" bson:"attributes"`
 	Associations []NetcollectReportAssociation `json:"associations" bson:"associations"`
+	// Neighbors are the lldp/cdp neighbors, which are proposed as connect associations when the report arrives
+	Neighbors []NetcollectNeighbor `json:"neighbors,omitempty" bson:"neighbors"`
 }
 
+// NetcollectNeighbor is a device directly linked to the reported device, learned from lldp or cdp
+type NetcollectNeighbor struct {
+	Protocol  string `json:"protocol" bson:"protocol"`
+	LocalPort string `json:"local_port" bson:"local_port"`
+	// ChassisID is usually the mac address of the neighbor
+	ChassisID string `json:"chassis_id" bson:"chassis_id"`
+	SysName   string `json:"sys_name" bson:"sys_name"`
+	PortID    string `json:"port_id" bson:"port_id"`
+	// Address is the management ip address of the neighbor
+	Address string `json:"address" bson:"address"`
+}
+
+// Link returns the description of the physical link to the neighbor
+func (n *NetcollectNeighbor) Link() string {
+	return fmt.Sprintf("%s: %s <-> %s %s", n.Protocol, n.LocalPort, n.SysName, n.PortID)
+}
+
+// the neighbor discovery protocols
+const (
+	NetcollectNeighborLLDP = "lldp"
+	NetcollectNeighborCDP  = "cdp"
+)
+
 type NetcollectHistory struct {
 	NetcollectReport `json:",inline" bson:",inline"`
 	Success          bool `json:"success" bson:"success"`
+	// AutoConfirmed is true when the report is confirmed by the confirm policy instead of a user
+	AutoConfirmed bool   `json:"auto_confirmed" bson:"auto_confirmed"`
+	Remark        string `json:"remark" bson:"remark"`
+}
+
+// NetcollectConfirmPolicy decides which changes in the collected reports of a model are confirmed
+// automatically when the reports arrive, the others are still left to the manual review.
+// the deletions and the changes of the instance key are always reviewed manually.
+type NetcollectConfirmPolicy struct {
+	ObjectID string `json:"bk_obj_id" bson:"bk_obj_id"`
+	// AutoApplyFields are the properties whose changes are applied automatically, "*" means all the properties
+	AutoApplyFields []string `json:"auto_apply_fields" bson:"auto_apply_fields"`
+	// ManualFields are always reviewed manually, even if they are matched by AutoApplyFields
+	ManualFields []string `json:"manual_fields" bson:"manual_fields"`
+	// AutoCreate creates the instances not exist yet when all the CreateConditions are matched
+	AutoCreate       bool                        `json:"auto_create" bson:"auto_create"`
+	CreateConditions []NetcollectPolicyCondition `json:"create_conditions" bson:"create_conditions"`
+	// AutoAssociate creates the reported associations and the proposed neighbor associations automatically
+	AutoAssociate bool   `json:"auto_associate" bson:"auto_associate"`
+	OwnerID       string `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	Modifier      string `json:"modifier" bson:"modifier"`
+	LastTime      Time   `json:"last_time" bson:"last_time"`
+}
+
+// NetcollectPolicyCondition is matched against the value of a reported attribute,
+// the values are compared as strings, because the collectors report all the values as strings.
+type NetcollectPolicyCondition struct {
+	PropertyID string      `json:"bk_property_id" bson:"bk_property_id"`
+	Operator   string      `json:"operator" bson:"operator"`
+	Value      interface{} `json:"value" bson:"value"`
+}
+
+// the fields and operators of the confirm policies
+const (
+	NetcollectPolicyAllFields = "*"
+
+	NetcollectPolicyOperatorEqual    = "equal"
+	NetcollectPolicyOperatorNotEqual = "not_equal"
+	NetcollectPolicyOperatorIn       = "in"
+	NetcollectPolicyOperatorRegex    = "regex"
+	NetcollectPolicyOperatorExist    = "exist"
+)
+
+// Validate checks the policy before it is saved
+func (p *NetcollectConfirmPolicy) Validate() error {
+	if p.ObjectID == "" {
+		return fmt.Errorf("%s is required", common.BKObjIDField)
+	}
+	for _, cond := range p.CreateConditions {
+		if cond.PropertyID == "" {
+			return fmt.Errorf("create condition %s is required", common.BKPropertyIDField)
+		}
+		switch cond.Operator {
+		case NetcollectPolicyOperatorEqual, NetcollectPolicyOperatorNotEqual, NetcollectPolicyOperatorExist:
+		case NetcollectPolicyOperatorIn:
+			if _, ok := policyValues(cond.Value); !ok {
+				return fmt.Errorf("create condition %s requires an array value", cond.PropertyID)
+			}
+		case NetcollectPolicyOperatorRegex:
+			if _, err := regexp.Compile(fmt.Sprint(cond.Value)); err != nil {
+				return fmt.Errorf("create condition %s has invalid regex: %v", cond.PropertyID, err)
+			}
+		default:
+			return fmt.Errorf("create condition %s has unknown operator %s", cond.PropertyID, cond.Operator)
+		}
+	}
+	return nil
+}
+
+// AutoApply returns whether the changes of the property are applied automatically
+func (p *NetcollectConfirmPolicy) AutoApply(propertyID string) bool {
+	if util.InStrArr(p.ManualFields, propertyID) {
+		return false
+	}
+	return util.InStrArr(p.AutoApplyFields, NetcollectPolicyAllFields) || util.InStrArr(p.AutoApplyFields, propertyID)
+}
+
+// CanCreate returns whether a new instance with the reported attributes is created automatically
+func (p *NetcollectConfirmPolicy) CanCreate(attrs []NetcollectReportAttribute) bool {
+	if !p.AutoCreate {
+		return false
+	}
+	for _, cond := range p.CreateConditions {
+		var value interface{}
+		exist := false
+		for _, attr := range attrs {
+			if attr.PropertyID == cond.PropertyID {
+				value, exist = attr.CurValue, true
+				break
+			}
+		}
+		if !cond.Match(value, exist) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match returns whether the reported value matches the condition
+func (c *NetcollectPolicyCondition) Match(value interface{}, exist bool) bool {
+	if c.Operator == NetcollectPolicyOperatorExist {
+		return exist
+	}
+	if !exist {
+		return c.Operator == NetcollectPolicyOperatorNotEqual
+	}
+
+	str := fmt.Sprint(value)
+	switch c.Operator {
+	case NetcollectPolicyOperatorEqual:
+		return str == fmt.Sprint(c.Value)
+	case NetcollectPolicyOperatorNotEqual:
+		return str != fmt.Sprint(c.Value)
+	case NetcollectPolicyOperatorIn:
+		values, _ := policyValues(c.Value)
+		for _, v := range values {
+			if str == fmt.Sprint(v) {
+				return true
+			}
+		}
+		return false
+	case NetcollectPolicyOperatorRegex:
+		matched, err := regexp.MatchString(fmt.Sprint(c.Value), str)
+		return err == nil && matched
+	}
+	return false
+}
+
+func policyValues(value interface{}) ([]interface{}, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	values := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		values = append(values, v.Index(i).Interface())
+	}
+	return values, true
+}
+
+type ParamSearchNetcollectPolicy struct {
+	ObjectIDs []string `json:"bk_obj_ids"`
+}
+
+type ParamDeleteNetcollectPolicy struct {
+	ObjectIDs []string `json:"bk_obj_ids"`
+}
+
+type RspNetcollectPolicy struct {
+	Count uint64                    `json:"count"`
+	Info  []NetcollectConfirmPolicy `json:"info"`
 }
 
 type NetcollectReportAttribute struct {
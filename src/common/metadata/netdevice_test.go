@@ -0,0 +1,132 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"testing"
+)
+
+var switchPolicy = NetcollectConfirmPolicy{
+	ObjectID:        "bk_switch",
+	AutoApplyFields: []string{NetcollectPolicyAllFields},
+	ManualFields:    []string{"bk_admin_ip"},
+	AutoCreate:      true,
+	CreateConditions: []NetcollectPolicyCondition{
+		{PropertyID: "bk_vendor", Operator: NetcollectPolicyOperatorIn, Value: []interface{}{"huawei", "cisco"}},
+		{PropertyID: "bk_sn", Operator: NetcollectPolicyOperatorRegex, Value: "^[0-9A-Z]+$"},
+		{PropertyID: "bk_model", Operator: NetcollectPolicyOperatorExist},
+	},
+}
+
+func TestNetcollectConfirmPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition NetcollectPolicyCondition
+		wantErr   bool
+	}{
+		{"equal", NetcollectPolicyCondition{PropertyID: "bk_sn", Operator: NetcollectPolicyOperatorEqual, Value: "x"}, false},
+		{"without property", NetcollectPolicyCondition{Operator: NetcollectPolicyOperatorExist}, true},
+		{"in without array", NetcollectPolicyCondition{PropertyID: "bk_vendor", Operator: NetcollectPolicyOperatorIn, Value: "huawei"}, true},
+		{"invalid regex", NetcollectPolicyCondition{PropertyID: "bk_sn", Operator: NetcollectPolicyOperatorRegex, Value: "["}, true},
+		{"unknown operator", NetcollectPolicyCondition{PropertyID: "bk_sn", Operator: "like"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := switchPolicy
+			policy.CreateConditions = append(append([]NetcollectPolicyCondition{}, switchPolicy.CreateConditions...), tt.condition)
+			if err := policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNetcollectConfirmPolicyAutoApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		autoFields []string
+		propertyID string
+		want       bool
+	}{
+		{"all fields", []string{NetcollectPolicyAllFields}, "bk_os_version", true},
+		{"manual field", []string{NetcollectPolicyAllFields}, "bk_admin_ip", false},
+		{"listed field", []string{"bk_os_version"}, "bk_os_version", true},
+		{"unlisted field", []string{"bk_os_version"}, "bk_model", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := switchPolicy
+			policy.AutoApplyFields = tt.autoFields
+			if got := policy.AutoApply(tt.propertyID); got != tt.want {
+				t.Errorf("AutoApply(%s) = %v, want %v", tt.propertyID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNetcollectConfirmPolicyCanCreate(t *testing.T) {
+	tests := []struct {
+		name       string
+		autoCreate bool
+		attrs      []NetcollectReportAttribute
+		want       bool
+	}{
+		{"matched", true, []NetcollectReportAttribute{{PropertyID: "bk_vendor", CurValue: "cisco"}, {PropertyID: "bk_sn", CurValue: "FOC1234X0AB"}, {PropertyID: "bk_model", CurValue: "C2960"}}, true},
+		{"auto create disabled", false, []NetcollectReportAttribute{{PropertyID: "bk_vendor", CurValue: "cisco"}, {PropertyID: "bk_sn", CurValue: "FOC1234X0AB"}, {PropertyID: "bk_model", CurValue: "C2960"}}, false},
+		{"unmatched vendor", true, []NetcollectReportAttribute{{PropertyID: "bk_vendor", CurValue: "h3c"}, {PropertyID: "bk_sn", CurValue: "FOC1234X0AB"}, {PropertyID: "bk_model", CurValue: "C2960"}}, false},
+		{"unmatched regex", true, []NetcollectReportAttribute{{PropertyID: "bk_vendor", CurValue: "cisco"}, {PropertyID: "bk_sn", CurValue: "foc-1"}, {PropertyID: "bk_model", CurValue: "C2960"}}, false},
+		{"missing attribute", true, []NetcollectReportAttribute{{PropertyID: "bk_sn", CurValue: "FOC1234X0AB"}, {PropertyID: "bk_model", CurValue: "C2960"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := switchPolicy
+			policy.AutoCreate = tt.autoCreate
+			if got := policy.CanCreate(tt.attrs); got != tt.want {
+				t.Errorf("CanCreate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNetcollectConfigKeepSecrets(t *testing.T) {
+	saved := NetcollectConfig{Snmp: NetcollectSnmpConfig{UserName: "admin", AuthPassword: "auth-saved", PrivPassword: "priv-saved"}}
+	tests := []struct {
+		name     string
+		auth     string
+		priv     string
+		wantAuth string
+		wantPriv string
+	}{
+		{"passwords not set", "", "", "auth-saved", "priv-saved"},
+		{"auth password changed", "auth-new", "", "auth-new", "priv-saved"},
+		{"both changed", "auth-new", "priv-new", "auth-new", "priv-new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NetcollectConfig{Snmp: NetcollectSnmpConfig{UserName: "admin", AuthPassword: tt.auth, PrivPassword: tt.priv}}
+			config.KeepSecrets(saved)
+			if config.Snmp.AuthPassword != tt.wantAuth || config.Snmp.PrivPassword != tt.wantPriv {
+				t.Errorf("KeepSecrets() got %s/%s, want %s/%s", config.Snmp.AuthPassword, config.Snmp.PrivPassword, tt.wantAuth, tt.wantPriv)
+			}
+
+			config.HideSecrets()
+			if config.Snmp.AuthPassword != "" || config.Snmp.PrivPassword != "" || config.Snmp.UserName != "admin" {
+				t.Errorf("HideSecrets() got %+v, want the passwords blanked only", config.Snmp)
+			}
+		})
+	}
+}
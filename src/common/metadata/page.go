@@ -23,6 +23,7 @@ const (
 	PageName         = "page"
 	PageSort         = "sort"
 	PageStart        = "start"
+	PageCursor       = "cursor"
 	DBFields         = "fields"
 	DBQueryCondition = "condition"
 )
@@ -32,6 +33,9 @@ type BasePage struct {
 	Sort  string `json:"sort,omitempty"`
 	Limit int    `json:"limit,omitempty"`
 	Start int    `json:"start"`
+	// Cursor the next_cursor returned by the previous page, the records after it are returned sorted by the id
+	// field, and the start is ignored.
+	Cursor string `json:"cursor,omitempty"`
 }
 
 func (page BasePage) Validate() (string, error) {
@@ -65,6 +69,9 @@ func ParsePage(origin interface{}) BasePage {
 	if start, ok := page["start"]; ok {
 		result.Start, _ = strconv.Atoi(fmt.Sprint(start))
 	}
+	if cursor, ok := page[PageCursor]; ok && cursor != nil {
+		result.Cursor = fmt.Sprint(cursor)
+	}
 	if limit, ok := page["limit"]; ok {
 		result.Limit, _ = strconv.Atoi(fmt.Sprint(limit))
 		if result.Limit <= 0 {
@@ -0,0 +1,92 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"configcenter/src/common/mapstr"
+)
+
+// DefaultRecycleRetentionDays the days the deleted records are kept in the recycle bin by default
+const DefaultRecycleRetentionDays = 30
+
+const (
+	// RecycleKindInstance the recycled record is an instance of a common model
+	RecycleKindInstance = "instance"
+	// RecycleKindHost the recycled record is a host
+	RecycleKindHost = "host"
+	// RecycleKindModel the recycled record is a model
+	RecycleKindModel = "model"
+)
+
+// RecycleItem a deleted record with the related records deleted along with it
+type RecycleItem struct {
+	ID   int64  `json:"id" bson:"id"`
+	Kind string `json:"kind" bson:"kind"`
+	// ObjectID the model of the record
+	ObjectID string `json:"bk_obj_id" bson:"bk_obj_id"`
+	// InstID the id of the instance, the host or the model
+	InstID   int64  `json:"bk_inst_id" bson:"bk_inst_id"`
+	InstName string `json:"bk_inst_name" bson:"bk_inst_name"`
+	// Data the deleted instance or host
+	Data mapstr.MapStr `json:"data,omitempty" bson:"data,omitempty"`
+	// Associations the instance associations deleted with the instance
+	Associations []mapstr.MapStr `json:"associations,omitempty" bson:"associations,omitempty"`
+	// HostRelations the host module relations deleted with the host
+	HostRelations []mapstr.MapStr `json:"host_relations,omitempty" bson:"host_relations,omitempty"`
+	// FieldSources the sources which last set the fields of the deleted instance or host
+	FieldSources map[string]FieldSource `json:"field_sources,omitempty" bson:"field_sources,omitempty"`
+	// Schema the deleted model with its attributes, groups and uniques
+	Schema     *ModelSchema `json:"schema,omitempty" bson:"schema,omitempty"`
+	OwnerID    string       `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	Operator   string       `json:"operator" bson:"operator"`
+	DeleteTime Time         `json:"delete_time" bson:"delete_time"`
+}
+
+// RecycleInstancesOption the instances to move into the recycle bin before they are deleted
+type RecycleInstancesOption struct {
+	InstIDs []int64 `json:"bk_inst_ids"`
+}
+
+// PurgeRecycleItemsOption the recycled records to delete permanently
+type PurgeRecycleItemsOption struct {
+	IDs []int64 `json:"ids"`
+}
+
+// RestoreRecycleItemResult the result of restoring a recycled record
+type RestoreRecycleItemResult struct {
+	Item RecycleItem `json:"item"`
+	// RestoredAssociations the associations or host module relations restored
+	RestoredAssociations int `json:"restored_associations"`
+	// SkippedAssociations the associations or host module relations skipped because their peers are gone
+	SkippedAssociations int `json:"skipped_associations"`
+}
+
+type RecycleItemList struct {
+	Count int64         `json:"count"`
+	Info  []RecycleItem `json:"info"`
+}
+
+type SearchRecycleItemResult struct {
+	BaseResp `json:",inline"`
+	Data     RecycleItemList `json:"data"`
+}
+
+type RecycleInstancesResult struct {
+	BaseResp `json:",inline"`
+	Data     []int64 `json:"data"`
+}
+
+type RestoreRecycleItemResp struct {
+	BaseResp `json:",inline"`
+	Data     RestoreRecycleItemResult `json:"data"`
+}
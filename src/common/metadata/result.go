@@ -84,6 +84,8 @@ type CreatedCount struct {
 // UpdatedCount created count struct
 type UpdatedCount struct {
 	Count uint64 `json:"updated_count"`
+	// Rejected the fields the data source of the request is not allowed to overwrite
+	Rejected []RejectedFields `json:"rejected_fields,omitempty"`
 }
 
 // DeletedCount created count struct
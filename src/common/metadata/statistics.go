@@ -0,0 +1,143 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/querybuilder"
+)
+
+// the functions of the statistics metric
+const (
+	StatisticsFuncCount = "count"
+	StatisticsFuncSum   = "sum"
+	StatisticsFuncAvg   = "avg"
+	StatisticsFuncMin   = "min"
+	StatisticsFuncMax   = "max"
+)
+
+// the date buckets of the statistics group field
+const (
+	DateBucketDay   = "day"
+	DateBucketMonth = "month"
+	DateBucketYear  = "year"
+)
+
+const (
+	// StatisticsMaxGroupFields the max number of the group fields of statistics
+	StatisticsMaxGroupFields = 5
+	// StatisticsDefaultLimit the default max number of groups returned by statistics
+	StatisticsDefaultLimit = 1000
+	// StatisticsMaxLimit the max number of groups returned by statistics
+	StatisticsMaxLimit = 10000
+)
+
+// StatisticsGroupField the field to group the instances by
+type StatisticsGroupField struct {
+	Field string `json:"field"`
+	// DateBucket group the date or time attribute by day, month or year
+	DateBucket string `json:"date_bucket,omitempty"`
+}
+
+// StatisticsMetric the metric calculated for each group
+type StatisticsMetric struct {
+	Func string `json:"func"`
+	// Field the numeric attribute, it's not needed for count
+	Field string `json:"field,omitempty"`
+}
+
+// Name the name of the metric in the result, like count or sum_bk_cpu
+func (m StatisticsMetric) Name() string {
+	if m.Func == StatisticsFuncCount {
+		return StatisticsFuncCount
+	}
+	return m.Func + "_" + m.Field
+}
+
+// StatisticsOption the option of the instances statistics, the condition is the same as the instance search.
+// the mainline parents bk_biz_id, bk_set_id and bk_module_id can be used to group hosts.
+type StatisticsOption struct {
+	Condition mapstr.MapStr          `json:"condition"`
+	GroupBy   []StatisticsGroupField `json:"group_by"`
+	Metrics   []StatisticsMetric     `json:"metrics"`
+	Limit     int                    `json:"limit"`
+}
+
+// Validate validate the option
+func (o *StatisticsOption) Validate() (string, error) {
+	if len(o.GroupBy) > StatisticsMaxGroupFields {
+		return "group_by", fmt.Errorf("exceed max group fields %d", StatisticsMaxGroupFields)
+	}
+	for idx, group := range o.GroupBy {
+		if !querybuilder.ValidFieldPattern.MatchString(group.Field) {
+			return fmt.Sprintf("group_by[%d].field", idx), fmt.Errorf("invalid field %s", group.Field)
+		}
+		switch group.DateBucket {
+		case "", DateBucketDay, DateBucketMonth, DateBucketYear:
+		default:
+			return fmt.Sprintf("group_by[%d].date_bucket", idx), fmt.Errorf("invalid date bucket %s", group.DateBucket)
+		}
+	}
+
+	if len(o.Metrics) == 0 {
+		o.Metrics = []StatisticsMetric{{Func: StatisticsFuncCount}}
+	}
+	for idx, metric := range o.Metrics {
+		switch metric.Func {
+		case StatisticsFuncCount:
+			continue
+		case StatisticsFuncSum, StatisticsFuncAvg, StatisticsFuncMin, StatisticsFuncMax:
+		default:
+			return fmt.Sprintf("metrics[%d].func", idx), fmt.Errorf("invalid function %s", metric.Func)
+		}
+		if !querybuilder.ValidFieldPattern.MatchString(metric.Field) {
+			return fmt.Sprintf("metrics[%d].field", idx), fmt.Errorf("invalid field %s", metric.Field)
+		}
+	}
+
+	if o.Limit <= 0 {
+		o.Limit = StatisticsDefaultLimit
+	}
+	if o.Limit > StatisticsMaxLimit {
+		return "limit", fmt.Errorf("exceed max limit %d", StatisticsMaxLimit)
+	}
+	return "", nil
+}
+
+// IsHostMainlineField returns whether the field is the mainline parent of host, which is in the host relations
+func IsHostMainlineField(field string) bool {
+	return field == common.BKAppIDField || field == common.BKSetIDField || field == common.BKModuleIDField
+}
+
+// StatisticsGroup the group of the instances
+type StatisticsGroup struct {
+	// Key the values of the group fields
+	Key mapstr.MapStr `json:"key"`
+	// Metrics the metrics of the group, the key is the name of metric
+	Metrics mapstr.MapStr `json:"metrics"`
+}
+
+// StatisticsResult the groups of the instances statistics
+type StatisticsResult struct {
+	Count int               `json:"count"`
+	Info  []StatisticsGroup `json:"info"`
+}
+
+// StatisticsInstanceResult the result of the instances statistics
+type StatisticsInstanceResult struct {
+	BaseResp `json:",inline"`
+	Data     StatisticsResult `json:"data"`
+}
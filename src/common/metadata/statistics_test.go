@@ -0,0 +1,44 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatisticsOptionValidate(t *testing.T) {
+	option := &StatisticsOption{
+		GroupBy: []StatisticsGroupField{{Field: "bk_biz_id"}, {Field: "create_time", DateBucket: DateBucketMonth}},
+	}
+	key, err := option.Validate()
+	require.NoError(t, err, key)
+	require.Equal(t, []StatisticsMetric{{Func: StatisticsFuncCount}}, option.Metrics)
+	require.Equal(t, StatisticsDefaultLimit, option.Limit)
+
+	invalids := []*StatisticsOption{
+		{GroupBy: []StatisticsGroupField{{Field: "$where"}}},
+		{GroupBy: []StatisticsGroupField{{Field: "create_time", DateBucket: "week"}}},
+		{Metrics: []StatisticsMetric{{Func: StatisticsFuncSum}}},
+		{Metrics: []StatisticsMetric{{Func: "median", Field: "bk_cpu"}}},
+		{Limit: StatisticsMaxLimit + 1},
+	}
+	for idx, option := range invalids {
+		key, err := option.Validate()
+		require.Error(t, err, "case %d", idx)
+		require.NotEmpty(t, key, "case %d", idx)
+	}
+
+	require.Equal(t, "sum_bk_cpu", StatisticsMetric{Func: StatisticsFuncSum, Field: "bk_cpu"}.Name())
+}
@@ -0,0 +1,174 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"configcenter/src/common/metadata"
+
+	"github.com/emicklei/go-restful"
+)
+
+// pathParamRegexp match the path parameter like {bk_obj_id} or {name:*}
+var pathParamRegexp = regexp.MustCompile(`\{([^}:]*)(:[^}]*)?\}`)
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Build generate the document with the routes of the web services,
+// the routes with wildcard path like {.*} are ignored.
+func Build(info Info, services []*restful.WebService) *Document {
+	doc := NewDocument(info)
+	for _, ws := range services {
+		for _, route := range ws.Routes() {
+			if route.Path == DocumentPath {
+				continue
+			}
+			path, params, ok := parsePath(route.Path)
+			if !ok {
+				continue
+			}
+			doc.AddOperation(route.Method, path, doc.buildOperation(ws, route, params))
+		}
+	}
+	return doc
+}
+
+// parsePath convert the restful path to OpenAPI path, and get the path parameters of it
+func parsePath(restPath string) (string, []string, bool) {
+	params := make([]string, 0)
+	valid := true
+	path := pathParamRegexp.ReplaceAllStringFunc(restPath, func(s string) string {
+		name := pathParamRegexp.FindStringSubmatch(s)[1]
+		if !identifierRegexp.MatchString(name) {
+			valid = false
+			return s
+		}
+		params = append(params, name)
+		return "{" + name + "}"
+	})
+	return path, params, valid
+}
+
+func (d *Document) buildOperation(ws *restful.WebService, route restful.Route, params []string) *Operation {
+	op := &Operation{
+		OperationID: operationID(route.Method, route.Path),
+		Summary:     route.Doc,
+		Responses:   make(map[string]*Response),
+	}
+	if tag := routeTag(ws.RootPath(), route.Path); tag != "" {
+		op.Tags = []string{tag}
+	}
+
+	docs := make(map[string]*restful.Parameter)
+	for _, param := range route.ParameterDocs {
+		docs[param.Data().Name] = param
+	}
+	for _, name := range params {
+		param := Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}}
+		if paramDoc, exist := docs[name]; exist {
+			param.Description = paramDoc.Data().Description
+		}
+		op.Parameters = append(op.Parameters, param)
+	}
+
+	if route.ReadSample != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{restful.MIME_JSON: {Schema: d.SchemaOf(route.ReadSample)}},
+		}
+	} else if route.Method == http.MethodPost || route.Method == http.MethodPut {
+		op.RequestBody = &RequestBody{
+			Content: map[string]MediaType{restful.MIME_JSON: {Schema: &Schema{Type: "object"}}},
+		}
+	}
+
+	var response interface{} = metadata.Response{}
+	if route.WriteSample != nil {
+		response = route.WriteSample
+	}
+	op.Responses["200"] = &Response{
+		Description: "success",
+		Content:     map[string]MediaType{restful.MIME_JSON: {Schema: d.SchemaOf(response)}},
+	}
+	return op
+}
+
+// operationID generate a unique operation id with the method and path, like post_topo_v3_inst_search
+func operationID(method, path string) string {
+	fields := strings.FieldsFunc(path, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_')
+	})
+	return strings.ToLower(method) + "_" + strings.Join(fields, "_")
+}
+
+// routeTag use the first path element after the root path as the tag, so that the routes are grouped
+func routeTag(root, path string) string {
+	path = strings.TrimPrefix(path, strings.TrimSuffix(root, "/"))
+	for _, elem := range strings.Split(path, "/") {
+		if elem != "" && !strings.HasPrefix(elem, "{") {
+			return elem
+		}
+	}
+	return ""
+}
+
+func lowerMethod(method string) string {
+	return strings.ToLower(method)
+}
+
+// AddDocumentRoute add the route which serve the OpenAPI document of all the web services in
+// the container to the web service, the document is generated when it's requested at the first time.
+func AddDocumentRoute(ws *restful.WebService, info Info, container *restful.Container) {
+	var once sync.Once
+	var doc *Document
+	ws.Route(ws.GET(DocumentPath).To(func(req *restful.Request, resp *restful.Response) {
+		once.Do(func() {
+			doc = Build(info, container.RegisteredWebServices())
+		})
+		resp.WriteAsJson(doc)
+	}))
+}
+
+// RouteSchema the request and response sample of the route, which the operation schemas are generated from
+type RouteSchema struct {
+	Reads  interface{}
+	Writes interface{}
+}
+
+// RouteSchemas the schemas of the routes keyed by the method and path, for the services whose routes
+// are registered with the generic handlers and can not declare the samples in the route self.
+type RouteSchemas map[string]RouteSchema
+
+// Add add the request and response sample of the route, the nil sample is not documented
+func (r RouteSchemas) Add(method, path string, reads, writes interface{}) {
+	r[method+" "+path] = RouteSchema{Reads: reads, Writes: writes}
+}
+
+// Apply set the samples of the route to the route builder, the route without schema is unchanged
+func (r RouteSchemas) Apply(builder *restful.RouteBuilder, method, path string) *restful.RouteBuilder {
+	schema, exist := r[method+" "+path]
+	if !exist {
+		return builder
+	}
+	if schema.Reads != nil {
+		builder.Reads(schema.Reads)
+	}
+	if schema.Writes != nil {
+		builder.Writes(schema.Writes)
+	}
+	return builder
+}
@@ -0,0 +1,134 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package openapi generate the OpenAPI 3 document from the routes registered in the restful web services.
+package openapi
+
+// Version the OpenAPI specification version of the generated document
+const Version = "3.0.2"
+
+// DocumentPath the path which each service serve it's OpenAPI document
+const DocumentPath = "/openapi/v3"
+
+// Document the OpenAPI document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info the metadata of the api
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem the operations on a path, the key is the lower case http method
+type PathItem map[string]*Operation
+
+// Operation a single api operation on a path
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter a path, query or header parameter of operation
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// RequestBody the request body of operation
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response the response of operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType the schema of a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components hold the reusable schemas, the key is the name of the struct, like metadata.APIKey
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema the json schema of the data, a empty schema means any value
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// NewDocument create a empty document
+func NewDocument(info Info) *Document {
+	return &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Paths:      make(map[string]*PathItem),
+		Components: Components{Schemas: make(map[string]*Schema)},
+	}
+}
+
+// AddOperation add a operation of the http method on the path
+func (d *Document) AddOperation(method, path string, op *Operation) {
+	item, exist := d.Paths[path]
+	if !exist {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+	(*item)[lowerMethod(method)] = op
+}
+
+// Merge merge the paths and schemas of the documents into this document,
+// the path is converted with the convert function, and the path is dropped when it returns false.
+func (d *Document) Merge(doc *Document, convert func(path string) (string, bool)) {
+	if doc == nil {
+		return
+	}
+	for path, item := range doc.Paths {
+		if convert != nil {
+			var ok bool
+			if path, ok = convert(path); !ok {
+				continue
+			}
+		}
+		for method, op := range *item {
+			d.AddOperation(method, path, op)
+		}
+	}
+	for name, schema := range doc.Components.Schemas {
+		if _, exist := d.Components.Schemas[name]; !exist {
+			d.Components.Schemas[name] = schema
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"configcenter/src/common/metadata"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+)
+
+type testNode struct {
+	metadata.BaseResp `json:",inline"`
+	Name              string      `json:"name"`
+	Children          []*testNode `json:"children"`
+	CreateTime        time.Time   `json:"create_time"`
+	Ignored           string      `json:"-"`
+	private           string
+}
+
+func TestSchemaOf(t *testing.T) {
+	doc := NewDocument(Info{Title: "test", Version: "v3"})
+	schema := doc.SchemaOf(testNode{})
+	require.Equal(t, schemaRefPrefix+"openapi.testNode", schema.Ref)
+
+	node := doc.Components.Schemas["openapi.testNode"]
+	require.NotNil(t, node)
+	require.Equal(t, "boolean", node.Properties["result"].Type)
+	require.Equal(t, "array", node.Properties["children"].Type)
+	require.Equal(t, schemaRefPrefix+"openapi.testNode", node.Properties["children"].Items.Ref)
+	require.Equal(t, "date-time", node.Properties["create_time"].Format)
+	require.NotContains(t, node.Properties, "Ignored")
+	require.NotContains(t, node.Properties, "private")
+}
+
+func TestBuild(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/topo/v3")
+	noop := func(req *restful.Request, resp *restful.Response) {}
+	ws.Route(ws.POST("/inst/{bk_obj_id}/{id:*}").To(noop).Reads(testNode{}))
+	ws.Route(ws.GET("{.*}").To(noop))
+
+	doc := Build(Info{Title: "test", Version: "v3"}, []*restful.WebService{ws})
+	require.Len(t, doc.Paths, 1)
+	item := doc.Paths["/topo/v3/inst/{bk_obj_id}/{id}"]
+	require.NotNil(t, item)
+	op := (*item)["post"]
+	require.NotNil(t, op)
+	require.Equal(t, []string{"inst"}, op.Tags)
+	require.Len(t, op.Parameters, 2)
+	require.Equal(t, schemaRefPrefix+"openapi.testNode", op.RequestBody.Content[restful.MIME_JSON].Schema.Ref)
+	require.Equal(t, "post_topo_v3_inst_bk_obj_id_id", op.OperationID)
+
+	merged := NewDocument(Info{Title: "merged", Version: "v3"})
+	merged.Merge(doc, func(path string) (string, bool) {
+		return "/api/v3" + path[len("/topo/v3"):], true
+	})
+	require.NotNil(t, merged.Paths["/api/v3/inst/{bk_obj_id}/{id}"])
+	require.NotNil(t, (*merged.Paths["/api/v3/inst/{bk_obj_id}/{id}"])[lowerMethod(http.MethodPost)])
+}
+
+func TestRouteSchemas(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/topo/v3")
+	noop := func(req *restful.Request, resp *restful.Response) {}
+	schemas := RouteSchemas{}
+	schemas.Add(http.MethodPost, "/inst/{bk_obj_id}", testNode{}, testNode{})
+	ws.Route(schemas.Apply(ws.POST("/inst/{bk_obj_id}").To(noop), http.MethodPost, "/inst/{bk_obj_id}"))
+	ws.Route(schemas.Apply(ws.GET("/inst/{bk_obj_id}").To(noop), http.MethodGet, "/inst/{bk_obj_id}"))
+
+	doc := Build(Info{Title: "test", Version: "v3"}, []*restful.WebService{ws})
+	item := doc.Paths["/topo/v3/inst/{bk_obj_id}"]
+	require.NotNil(t, item)
+	post := (*item)["post"]
+	require.Equal(t, schemaRefPrefix+"openapi.testNode", post.RequestBody.Content[restful.MIME_JSON].Schema.Ref)
+	require.Equal(t, schemaRefPrefix+"openapi.testNode", post.Responses["200"].Content[restful.MIME_JSON].Schema.Ref)
+	// the route without schema is documented with the generic response
+	get := (*item)["get"]
+	require.Nil(t, get.RequestBody)
+	require.Equal(t, schemaRefPrefix+"metadata.Response", get.Responses["200"].Content[restful.MIME_JSON].Schema.Ref)
+}
@@ -105,9 +105,49 @@ func ParseHostParams(input []metadata.ConditionItem, output map[string]interface
 }
 
 func ParseHostIPParams(ipCond metadata.IPInfo, output map[string]interface{}) error {
-	ipArr := ipCond.Data
-	exact := ipCond.Exact
-	flag := ipCond.Flag
+	if 0 == len(ipCond.Data) {
+		return nil
+	}
+
+	// the cidr, ip range and wildcard are matched by the ordered ip keys, and or with the plain ips
+	ipArr := make([]string, 0)
+	rangeCond := make([]map[string]interface{}, 0)
+	for _, ip := range ipCond.Data {
+		if !util.IsIPRange(ip) {
+			ipArr = append(ipArr, ip)
+			continue
+		}
+		start, end, err := util.ParseIPRange(ip)
+		if err != nil {
+			return err
+		}
+		keyRange := map[string]interface{}{
+			common.BKDBElemMatch: map[string]interface{}{common.BKDBGTE: start, common.BKDBLTE: end},
+		}
+		if INNERONLY == ipCond.Flag || IOBOTH == ipCond.Flag {
+			rangeCond = append(rangeCond, map[string]interface{}{common.BKHostInnerIPKeyField: keyRange})
+		}
+		if OUTERONLY == ipCond.Flag || IOBOTH == ipCond.Flag {
+			rangeCond = append(rangeCond, map[string]interface{}{common.BKHostOuterIPKeyField: keyRange})
+		}
+	}
+	if 0 == len(rangeCond) {
+		return parseHostIPs(ipArr, ipCond.Exact, ipCond.Flag, output)
+	}
+
+	if 0 != len(ipArr) {
+		ipOutput := make(map[string]interface{})
+		if err := parseHostIPs(ipArr, ipCond.Exact, ipCond.Flag, ipOutput); err != nil {
+			return err
+		}
+		rangeCond = append(rangeCond, ipOutput)
+	}
+	andCond, _ := output[common.BKDBAND].([]map[string]interface{})
+	output[common.BKDBAND] = append(andCond, map[string]interface{}{common.BKDBOR: rangeCond})
+	return nil
+}
+
+func parseHostIPs(ipArr []string, exact int64, flag string, output map[string]interface{}) error {
 	if 0 == len(ipArr) {
 		return nil
 	}
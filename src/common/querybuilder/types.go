@@ -30,7 +30,7 @@ type Rule interface {
 type Condition string
 
 func (c Condition) Validate() error {
-	if c == ConditionAnd || c == ConditionOr {
+	if c == ConditionAnd || c == ConditionOr || c == ConditionNot {
 		return nil
 	}
 	return fmt.Errorf("unexpected condition: %s", c)
@@ -42,6 +42,8 @@ func (c Condition) ToMgo() (mgoOperator string, err error) {
 		return common.BKDBOR, nil
 	case ConditionAnd:
 		return common.BKDBAND, nil
+	case ConditionNot:
+		return common.BKDBNOR, nil
 	default:
 		return "", fmt.Errorf("unexpected operator %s", c)
 	}
@@ -50,6 +52,8 @@ func (c Condition) ToMgo() (mgoOperator string, err error) {
 var (
 	ConditionAnd = Condition("AND")
 	ConditionOr  = Condition("OR")
+	// ConditionNot negate the conjunction of the rules
+	ConditionNot = Condition("NOT")
 )
 
 // *************** define operator ************************
@@ -385,6 +389,9 @@ func (r CombinedRule) ToMgo() (mgoFilter map[string]interface{}, key string, err
 	if err != nil {
 		return nil, "condition", err
 	}
+	if r.Condition == ConditionNot {
+		filters = []map[string]interface{}{{common.BKDBAND: filters}}
+	}
 	mgoFilter = map[string]interface{}{
 		mgoOperator: filters,
 	}
@@ -334,6 +334,15 @@ func TestNormalCombinedRule(t *testing.T) {
 					Value:    1,
 				},
 			},
+		}, {
+			Condition: querybuilder.ConditionNot,
+			Rules: []querybuilder.Rule{
+				querybuilder.AtomRule{
+					Operator: querybuilder.OperatorEqual,
+					Field:    "field",
+					Value:    1,
+				},
+			},
 		},
 	}
 	for idx, rule := range rules {
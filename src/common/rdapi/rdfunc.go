@@ -18,12 +18,14 @@ import (
 	"io"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"strings"
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/errors"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/tracing"
 	"configcenter/src/common/util"
 
 	"github.com/emicklei/go-restful"
@@ -43,6 +45,18 @@ func checkHTTPAuth(req *restful.Request, defErr errors.DefaultCCErrorIf) (int, s
 }
 
 func AllGlobalFilter(errFunc func() errors.CCErrorIf) func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
+	authFilter := HTTPAuthFilter(errFunc)
+	return func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
+		generateHttpHeaderRID(req, resp)
+		defer startRequestSpan(req, resp)()
+		authFilter(req, resp, fchain)
+	}
+}
+
+// HTTPAuthFilter check the user and supplier account of the request, and recover the panic of the
+// following filters and handlers. it's used after HTTPRequestIDFilter when the headers are filled by
+// the other filters, AllGlobalFilter is HTTPRequestIDFilter and HTTPAuthFilter in one.
+func HTTPAuthFilter(errFunc func() errors.CCErrorIf) func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
 	return func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
 		defer func() {
 			if fetalErr := recover(); fetalErr != nil {
@@ -54,7 +68,6 @@ func AllGlobalFilter(errFunc func() errors.CCErrorIf) func(req *restful.Request,
 			}
 
 		}()
-		generateHttpHeaderRID(req, resp)
 
 		whiteListSuffix := strings.Split(common.URLFilterWhiteListSuffix, common.URLFilterWhiteListSepareteChar)
 		for _, url := range whiteListSuffix {
@@ -75,19 +88,14 @@ func AllGlobalFilter(errFunc func() errors.CCErrorIf) func(req *restful.Request,
 			return
 		}
 
-		if 1 < len(fchain.Filters) {
-			fchain.ProcessFilter(req, resp)
-			return
-		}
-
 		fchain.ProcessFilter(req, resp)
-		return
 	}
 }
 
 func HTTPRequestIDFilter(errFunc func() errors.CCErrorIf) func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
 	return func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
 		generateHttpHeaderRID(req, resp)
+		defer startRequestSpan(req, resp)()
 		if 1 < len(fchain.Filters) {
 			fchain.ProcessFilter(req, resp)
 			return
@@ -131,6 +139,21 @@ func generateHttpHeaderRID(req *restful.Request, resp *restful.Response) {
 	resp.Header().Set(common.BKHTTPCCRequestID, cid)
 }
 
+// startRequestSpan start the server span of the request, returns the function to end it
+func startRequestSpan(req *restful.Request, resp *restful.Response) func() {
+	span := tracing.StartRequestSpan(req.Request, req.Request.Method+" "+req.SelectedRoutePath())
+	if span == nil {
+		return func() {}
+	}
+	return func() {
+		span.SetAttribute("http.status_code", strconv.Itoa(resp.StatusCode()))
+		if resp.StatusCode() >= http.StatusInternalServerError {
+			span.SetError(fmt.Errorf("response status %d", resp.StatusCode()))
+		}
+		span.End()
+	}
+}
+
 func ServiceErrorHandler(err restful.ServiceError, req *restful.Request, resp *restful.Response) {
 	blog.Errorf("HTTP ERROR: %v, HTTP MESSAGE: %v, RequestURI: %s %s", err.Code, err.Message, req.Request.Method, req.Request.RequestURI)
 	ret := metadata.BaseResp{
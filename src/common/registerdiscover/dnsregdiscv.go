@@ -0,0 +1,161 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registerdiscover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/types"
+)
+
+// dnsLookupInterval the interval to lookup the SRV records of the services
+var dnsLookupInterval = 10 * time.Second
+
+// DNSRegDiscv discover the services by the DNS SRV records, the endpoints of the service are
+// the records of _<service>._tcp.<domain>, e.g. _coreservice._tcp.cmdb.svc.cluster.local.
+// the records are managed by the DNS server, so register is ignored.
+type DNSRegDiscv struct {
+	ctx      context.Context
+	domain   string
+	resolver dnsResolver
+
+	sync.RWMutex
+	lastErr error
+}
+
+// dnsResolver lookup the SRV records and the addresses of their targets, it's net.DefaultResolver except in tests
+type dnsResolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// NewDNSRegDiscv create a object of DNSRegDiscv
+func NewDNSRegDiscv(ctx context.Context, domain string) *DNSRegDiscv {
+	return &DNSRegDiscv{
+		ctx:      ctx,
+		domain:   strings.TrimSuffix(domain, "/"),
+		resolver: net.DefaultResolver,
+	}
+}
+
+// Ping returns the error of the last lookup
+func (rd *DNSRegDiscv) Ping() error {
+	rd.RLock()
+	defer rd.RUnlock()
+	return rd.lastErr
+}
+
+// RegisterAndWatch the SRV records are managed by the DNS server, so it does nothing
+func (rd *DNSRegDiscv) RegisterAndWatch(key string, data []byte) error {
+	blog.Infof("the services are discovered from DNS domain %s, skip register %s", rd.domain, key)
+	return nil
+}
+
+// GetServNodes get the server nodes of the service, they are the addresses of the targets of the SRV records
+func (rd *DNSRegDiscv) GetServNodes(key string) ([]string, error) {
+	event := rd.lookup(key)
+	return event.Nodes, event.Err
+}
+
+// Discover lookup the SRV records of the service periodically, and notify when they are changed
+func (rd *DNSRegDiscv) Discover(key string) (<-chan *DiscoverEvent, error) {
+	env := make(chan *DiscoverEvent, 1)
+	go rd.loopDiscover(key, env)
+	return env, nil
+}
+
+func (rd *DNSRegDiscv) loopDiscover(key string, env chan *DiscoverEvent) {
+	var last []string
+	ticker := time.NewTicker(dnsLookupInterval)
+	defer ticker.Stop()
+	for {
+		event := rd.lookup(key)
+		if event.Err != nil {
+			blog.Errorf("lookup the SRV records of %s failed, err: %v", key, event.Err)
+		} else if last == nil || !reflect.DeepEqual(last, event.Server) {
+			last = event.Server
+			notifyDiscoverEvent(env, event)
+		}
+
+		select {
+		case <-rd.ctx.Done():
+			blog.Infof("discover %s from DNS done", key)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (rd *DNSRegDiscv) lookup(key string) *DiscoverEvent {
+	event := &DiscoverEvent{Key: key, Server: make([]string, 0)}
+	_, records, err := rd.resolver.LookupSRV(rd.ctx, path.Base(key), "tcp", rd.domain)
+	// no such host means there is no endpoint of the service now
+	if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+		err = nil
+	}
+
+	rd.Lock()
+	rd.lastErr = err
+	rd.Unlock()
+
+	if err != nil {
+		event.Err = err
+		return event
+	}
+
+	// the targets are resolved to the addresses, which are the same as the addresses the processes registered
+	// with, so that the process can find out whether it's the master by the first server.
+	servers := make([]types.ServerInfo, 0, len(records))
+	for _, record := range records {
+		target := strings.TrimSuffix(record.Target, ".")
+		addrs, err := rd.resolver.LookupIPAddr(rd.ctx, target)
+		if err != nil {
+			blog.Errorf("resolve the target %s of the SRV records of %s failed, skip it, err: %v", target, key, err)
+			continue
+		}
+		for _, addr := range addrs {
+			servers = append(servers, types.ServerInfo{IP: addr.IP.String(), Port: uint(record.Port), Scheme: "http"})
+		}
+	}
+
+	// the records are sorted by priority and randomized by weight, sort them to get the stable endpoints
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].IP != servers[j].IP {
+			return servers[i].IP < servers[j].IP
+		}
+		return servers[i].Port < servers[j].Port
+	})
+	for idx, server := range servers {
+		if idx > 0 && server.IP == servers[idx-1].IP && server.Port == servers[idx-1].Port {
+			continue
+		}
+		js, err := json.Marshal(server)
+		if err != nil {
+			event.Err = err
+			return event
+		}
+		event.Server = append(event.Server, string(js))
+		event.Nodes = append(event.Nodes, fmt.Sprintf("%s:%d", server.IP, server.Port))
+	}
+	return event
+}
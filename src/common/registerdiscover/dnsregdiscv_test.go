@@ -0,0 +1,73 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registerdiscover
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+type fakeResolver struct {
+	records []*net.SRV
+	addrs   map[string][]string
+}
+
+func (r *fakeResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	return "", r.records, nil
+}
+
+func (r *fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r.addrs[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	addrs := make([]net.IPAddr, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func TestDNSRegDiscvLookup(t *testing.T) {
+	rd := NewDNSRegDiscv(context.Background(), "cmdb.svc.cluster.local")
+	rd.resolver = &fakeResolver{
+		records: []*net.SRV{
+			{Target: "coreservice-1.cmdb.svc.cluster.local.", Port: 50009},
+			{Target: "coreservice-0.cmdb.svc.cluster.local.", Port: 50009},
+			{Target: "coreservice-gone.cmdb.svc.cluster.local.", Port: 50009},
+			{Target: "coreservice-alias.cmdb.svc.cluster.local.", Port: 50009},
+		},
+		addrs: map[string][]string{
+			"coreservice-0.cmdb.svc.cluster.local":     {"10.0.0.2"},
+			"coreservice-1.cmdb.svc.cluster.local":     {"10.0.0.1"},
+			"coreservice-alias.cmdb.svc.cluster.local": {"10.0.0.1"},
+		},
+	}
+
+	event := rd.lookup("/cc/services/endpoints/coreservice")
+	if event.Err != nil {
+		t.Fatalf("lookup() error = %v", event.Err)
+	}
+	// the targets are resolved, sorted by the addresses and deduplicated, the unresolved target is skipped
+	wantNodes := []string{"10.0.0.1:50009", "10.0.0.2:50009"}
+	if !reflect.DeepEqual(event.Nodes, wantNodes) {
+		t.Errorf("lookup() nodes = %v, want %v", event.Nodes, wantNodes)
+	}
+	wantServer := `{"ip":"10.0.0.1","port":50009,"hostname":"","scheme":"http","version":"","pid":0}`
+	if len(event.Server) != 2 || event.Server[0] != wantServer {
+		t.Errorf("lookup() servers = %v, want the first %s", event.Server, wantServer)
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registerdiscover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sync"
+	"time"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/types"
+
+	"gopkg.in/yaml.v2"
+)
+
+// fileReloadInterval the interval to check whether the endpoints file is changed
+var fileReloadInterval = 5 * time.Second
+
+// FileEndpoint the endpoint of the service listed in the file
+type FileEndpoint struct {
+	IP     string `yaml:"ip"`
+	Port   uint   `yaml:"port"`
+	Scheme string `yaml:"scheme"`
+}
+
+// FileRegDiscv discover the services from a static file, which lists the endpoints of each service like:
+//
+//	coreservice:
+//	  - ip: 127.0.0.1
+//	    port: 50009
+//	    scheme: http
+//
+// the file is reloaded when it's changed. the services can not register themselves, so register is ignored.
+type FileRegDiscv struct {
+	ctx  context.Context
+	file string
+
+	sync.RWMutex
+	modTime   time.Time
+	endpoints map[string][]FileEndpoint
+	watchers  map[string][]chan *DiscoverEvent
+}
+
+// NewFileRegDiscv create a object of FileRegDiscv
+func NewFileRegDiscv(ctx context.Context, file string) (*FileRegDiscv, error) {
+	rd := &FileRegDiscv{
+		ctx:      ctx,
+		file:     file,
+		watchers: make(map[string][]chan *DiscoverEvent),
+	}
+	if _, err := rd.reload(); err != nil {
+		return nil, err
+	}
+
+	go rd.loopReload()
+	return rd, nil
+}
+
+// reload read the file if it's changed since last load, returns whether the endpoints are changed
+func (rd *FileRegDiscv) reload() (bool, error) {
+	info, err := os.Stat(rd.file)
+	if err != nil {
+		return false, err
+	}
+	rd.RLock()
+	modTime := rd.modTime
+	rd.RUnlock()
+	if info.ModTime().Equal(modTime) {
+		return false, nil
+	}
+
+	content, err := ioutil.ReadFile(rd.file)
+	if err != nil {
+		return false, err
+	}
+	endpoints := make(map[string][]FileEndpoint)
+	if err := yaml.Unmarshal(content, &endpoints); err != nil {
+		return false, fmt.Errorf("parse endpoints file %s failed, err: %v", rd.file, err)
+	}
+
+	rd.Lock()
+	defer rd.Unlock()
+	rd.modTime = info.ModTime()
+	if reflect.DeepEqual(rd.endpoints, endpoints) {
+		return false, nil
+	}
+	rd.endpoints = endpoints
+	return true, nil
+}
+
+func (rd *FileRegDiscv) loopReload() {
+	ticker := time.NewTicker(fileReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-rd.ctx.Done():
+			blog.Infof("stop reloading endpoints file %s", rd.file)
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := rd.reload()
+		if err != nil {
+			blog.Errorf("reload endpoints file %s failed, err: %v", rd.file, err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		blog.Infof("endpoints file %s is changed, notify the discoverers", rd.file)
+
+		rd.RLock()
+		for key, chans := range rd.watchers {
+			for _, ch := range chans {
+				notifyDiscoverEvent(ch, rd.getEvent(key))
+			}
+		}
+		rd.RUnlock()
+	}
+}
+
+// getEvent must be called with the lock held
+func (rd *FileRegDiscv) getEvent(key string) *DiscoverEvent {
+	event := &DiscoverEvent{Key: key}
+	for idx, endpoint := range rd.endpoints[path.Base(key)] {
+		server := types.ServerInfo{IP: endpoint.IP, Port: endpoint.Port, Scheme: endpoint.Scheme}
+		js, err := json.Marshal(server)
+		if err != nil {
+			event.Err = err
+			return event
+		}
+		event.Server = append(event.Server, string(js))
+		event.Nodes = append(event.Nodes, fmt.Sprintf("%d", idx))
+	}
+	return event
+}
+
+// Ping check the endpoints file exists
+func (rd *FileRegDiscv) Ping() error {
+	_, err := os.Stat(rd.file)
+	return err
+}
+
+// RegisterAndWatch the endpoints are listed in the file, so it does nothing
+func (rd *FileRegDiscv) RegisterAndWatch(key string, data []byte) error {
+	blog.Infof("the services are discovered from file %s, skip register %s", rd.file, key)
+	return nil
+}
+
+// GetServNodes get the server nodes of the service, they are the indexes of the endpoints in the file
+func (rd *FileRegDiscv) GetServNodes(key string) ([]string, error) {
+	rd.RLock()
+	defer rd.RUnlock()
+	event := rd.getEvent(key)
+	return event.Nodes, event.Err
+}
+
+// Discover returns the endpoints of the service, and notify again when the file is changed
+func (rd *FileRegDiscv) Discover(key string) (<-chan *DiscoverEvent, error) {
+	env := make(chan *DiscoverEvent, 1)
+
+	rd.Lock()
+	defer rd.Unlock()
+	rd.watchers[key] = append(rd.watchers[key], env)
+	env <- rd.getEvent(key)
+	return env, nil
+}
+
+// notifyDiscoverEvent send the event to the channel, the pending event is replaced since it's out of date
+func notifyDiscoverEvent(ch chan *DiscoverEvent, event *DiscoverEvent) {
+	select {
+	case ch <- event:
+	default:
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- event
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registerdiscover
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		scheme  string
+		addr    string
+	}{
+		{"127.0.0.1:2181,127.0.0.2:2181", SchemeZookeeper, "127.0.0.1:2181,127.0.0.2:2181"},
+		{"zk://127.0.0.1:2181", SchemeZookeeper, "127.0.0.1:2181"},
+		{"file:///data/cmdb/services.yaml", SchemeFile, "/data/cmdb/services.yaml"},
+		{"dns://cmdb.svc.cluster.local", SchemeDNS, "cmdb.svc.cluster.local"},
+	}
+	for _, tt := range tests {
+		scheme, addr := ParseAddress(tt.address)
+		if scheme != tt.scheme || addr != tt.addr {
+			t.Errorf("ParseAddress(%s) = %s, %s, want %s, %s", tt.address, scheme, addr, tt.scheme, tt.addr)
+		}
+	}
+}
+
+func TestFileRegDiscv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "regdiscv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "services.yaml")
+	if err := ioutil.WriteFile(file, []byte("coreservice:\n  - ip: 127.0.0.1\n    port: 50009\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fileReloadInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rd, err := NewFileRegDiscv(ctx, file)
+	if err != nil {
+		t.Fatalf("NewFileRegDiscv() error = %v", err)
+	}
+	events, err := rd.Discover("/cc/services/endpoints/coreservice")
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	event := <-events
+	if len(event.Server) != 1 || event.Server[0] != `{"ip":"127.0.0.1","port":50009,"hostname":"","scheme":"","version":"","pid":0}` {
+		t.Errorf("Discover() servers = %v", event.Server)
+	}
+
+	content := []byte("coreservice:\n  - ip: 127.0.0.1\n    port: 50009\n  - ip: 127.0.0.2\n    port: 50009\n")
+	if err := ioutil.WriteFile(file, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// make sure the modify time is changed
+	if err := os.Chtimes(file, time.Now(), time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event = <-events:
+		if len(event.Server) != 2 {
+			t.Errorf("Discover() servers after reload = %v", event.Server)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Discover() is not notified after the file is changed")
+	}
+}
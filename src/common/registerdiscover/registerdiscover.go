@@ -13,11 +13,29 @@
 package registerdiscover
 
 import (
+	"strings"
 	"time"
 
 	"configcenter/src/common/backbone/service_mange/zk"
 )
 
+// the schemes of the regdiscv address, the address without scheme is zookeeper's
+const (
+	SchemeZookeeper = "zk"
+	SchemeFile      = "file"
+	SchemeDNS       = "dns"
+)
+
+// ParseAddress split the regdiscv address like zk://127.0.0.1:2181, file:///data/cmdb/services.yaml
+// or dns://cmdb.svc.cluster.local to the scheme and the address of the register-discover service
+func ParseAddress(address string) (scheme, addr string) {
+	idx := strings.Index(address, "://")
+	if idx < 0 {
+		return SchemeZookeeper, address
+	}
+	return address[:idx], address[idx+len("://"):]
+}
+
 // DiscoverEvent if servers chenged, will create a discover event
 type DiscoverEvent struct { //
 	Err    error
@@ -53,6 +71,13 @@ func NewRegDiscoverEx(client *zk.ZkClient) *RegDiscover {
 	return regDiscv
 }
 
+// NewRegDiscoverWithServer create a object of RegDiscover with the register-discover service
+func NewRegDiscoverWithServer(server RegDiscvServer) *RegDiscover {
+	return &RegDiscover{
+		rdServer: server,
+	}
+}
+
 // RegisterAndWatchService register service info into register-discover platform
 // and then watch the service info, if not exist, then register again
 // key is the index of registered service
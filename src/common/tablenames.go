@@ -87,6 +87,17 @@ const (
 	BKTableNameNetcollectReport  = "cc_NetcollectReport"
 	BKTableNameNetcollectHistory = "cc_NetcollectHistory"
 
+	// BKTableNameNetcollectConfirmPolicy the table name of the policies to confirm the collected reports automatically
+	BKTableNameNetcollectConfirmPolicy = "cc_NetcollectConfirmPolicy"
+
+	// BKTableNameDiscoverSchema the table name of the payload schemas of the discovery sources
+	BKTableNameDiscoverSchema = "cc_DiscoverSchema"
+
+	// BKTableNameFieldSourcePriority the table name of the source priorities of the model fields
+	BKTableNameFieldSourcePriority = "cc_FieldSourcePriority"
+	// BKTableNameInstFieldSource the table name of the sources which last set the instance fields
+	BKTableNameInstFieldSource = "cc_InstFieldSource"
+
 	BKTableNameHostLock = "cc_HostLock"
 
 	// Cloud sync tables
@@ -101,6 +112,26 @@ const (
 	BKTableNameServiceInstance         = "cc_ServiceInstance"
 	BKTableNameProcessTemplate         = "cc_ProcessTemplate"
 	BKTableNameProcessInstanceRelation = "cc_ProcessInstanceRelation"
+
+	// synchronize tables
+	BKTableNameSynchronizeSnapshot = "cc_SynchronizeSnapshot"
+	BKTableNameSynchronizeConflict = "cc_SynchronizeConflict"
+
+	BKTableNameAPIKey = "cc_APIKey"
+
+	BKTableNameDynamicGroupMember = "cc_DynamicGroupMember"
+
+	// BKTableNameObjSchemaVersion the table name of the model schema versions
+	BKTableNameObjSchemaVersion = "cc_ObjSchemaVersion"
+
+	// BKTableNameRecycleBin the table name of the deleted records kept for restoring
+	BKTableNameRecycleBin = "cc_RecycleBin"
+
+	// BKTableNameObjLifecycle the table name of the lifecycle of the models
+	BKTableNameObjLifecycle = "cc_ObjLifecycle"
+
+	// BKTableNameLifecycleHistory the table name of the lifecycle state transitions of the instances
+	BKTableNameLifecycleHistory = "cc_LifecycleHistory"
 )
 
 // AllTables alltables
@@ -154,6 +185,18 @@ var AllTables = []string{
 	BKTableNameServiceInstance,
 	BKTableNameProcessTemplate,
 	BKTableNameProcessInstanceRelation,
+	BKTableNameSynchronizeSnapshot,
+	BKTableNameSynchronizeConflict,
+	BKTableNameAPIKey,
+	BKTableNameDynamicGroupMember,
+	BKTableNameObjSchemaVersion,
+	BKTableNameRecycleBin,
+	BKTableNameObjLifecycle,
+	BKTableNameLifecycleHistory,
+	BKTableNameNetcollectConfirmPolicy,
+	BKTableNameDiscoverSchema,
+	BKTableNameFieldSourcePriority,
+	BKTableNameInstFieldSource,
 }
 
 // GetInstTableName returns inst data table name
@@ -0,0 +1,271 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"configcenter/src/common/blog"
+)
+
+const (
+	batchSize     = 512
+	queueSize     = 4096
+	flushInterval = 5 * time.Second
+)
+
+// Exporter send the ended spans to the storage
+type Exporter interface {
+	Export(spans []*Span) error
+	Close() error
+}
+
+// batchProcessor buffer the ended spans and export them in batches, the spans are dropped
+// if the queue is full, tracing should never block the requests.
+type batchProcessor struct {
+	exporter Exporter
+	queue    chan *Span
+	done     chan struct{}
+	once     sync.Once
+	wg       sync.WaitGroup
+	dropped  int64
+}
+
+func newBatchProcessor(exporter Exporter) *batchProcessor {
+	p := &batchProcessor{
+		exporter: exporter,
+		queue:    make(chan *Span, queueSize),
+		done:     make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go p.run()
+	return p
+}
+
+func (p *batchProcessor) add(span *Span) {
+	select {
+	case p.queue <- span:
+	default:
+		atomic.AddInt64(&p.dropped, 1)
+	}
+}
+
+func (p *batchProcessor) run() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := p.exporter.Export(batch); err != nil {
+			blog.Errorf("export %d spans failed, err: %v", len(batch), err)
+		}
+		batch = make([]*Span, 0, batchSize)
+	}
+
+	for {
+		select {
+		case span := <-p.queue:
+			batch = append(batch, span)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+			if dropped := atomic.SwapInt64(&p.dropped, 0); dropped > 0 {
+				blog.Warnf("%d spans are dropped because the export queue is full", dropped)
+			}
+		case <-p.done:
+			for {
+				select {
+				case span := <-p.queue:
+					batch = append(batch, span)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// stop flush the buffered spans and close the exporter
+func (p *batchProcessor) stop() {
+	p.once.Do(func() {
+		close(p.done)
+		p.wg.Wait()
+		if err := p.exporter.Close(); err != nil {
+			blog.Errorf("close trace exporter failed, err: %v", err)
+		}
+	})
+}
+
+// FileExporter write the spans to a local file, one json encoded span per line
+type FileExporter struct {
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// NewFileExporter open the file in append mode, the directory is created if not exist
+func NewFileExporter(path string) (*FileExporter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("create trace file directory failed, err: %v", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open trace file %s failed, err: %v", path, err)
+	}
+	return &FileExporter{file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+// Export append the spans to the file
+func (e *FileExporter) Export(spans []*Span) error {
+	encoder := json.NewEncoder(e.writer)
+	for _, span := range spans {
+		if err := encoder.Encode(span); err != nil {
+			return err
+		}
+	}
+	return e.writer.Flush()
+}
+
+// Close close the file
+func (e *FileExporter) Close() error {
+	if err := e.writer.Flush(); err != nil {
+		e.file.Close()
+		return err
+	}
+	return e.file.Close()
+}
+
+// OTLPExporter send the spans to an OpenTelemetry collector with the OTLP/HTTP json protocol
+type OTLPExporter struct {
+	endpoint    string
+	serviceName string
+	client      *http.Client
+}
+
+// NewOTLPExporter the endpoint is the full traces url of the collector
+func NewOTLPExporter(endpoint, serviceName string) *OTLPExporter {
+	return &OTLPExporter{
+		endpoint:    endpoint,
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    StatusCode `json:"code"`
+	Message string     `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTraceRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+func newOTLPKeyValue(key, value string) otlpKeyValue {
+	kv := otlpKeyValue{Key: key}
+	kv.Value.StringValue = value
+	return kv
+}
+
+// Export post the spans to the collector
+func (e *OTLPExporter) Export(spans []*Span) error {
+	scope := otlpScopeSpans{Spans: make([]otlpSpan, 0, len(spans))}
+	scope.Scope.Name = "configcenter"
+	for _, span := range spans {
+		item := otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentSpanID,
+			Name:              span.Name,
+			Kind:              int(span.Kind),
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			Status:            otlpStatus{Code: span.Status, Message: span.StatusMessage},
+		}
+		for key, value := range span.Attributes {
+			item.Attributes = append(item.Attributes, newOTLPKeyValue(key, value))
+		}
+		scope.Spans = append(scope.Spans, item)
+	}
+	resource := otlpResourceSpans{ScopeSpans: []otlpScopeSpans{scope}}
+	resource.Resource.Attributes = []otlpKeyValue{newOTLPKeyValue("service.name", e.serviceName)}
+
+	body, err := json.Marshal(otlpTraceRequest{ResourceSpans: []otlpResourceSpans{resource}})
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		content, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("collector response status %s, body: %s", resp.Status, content)
+	}
+	return nil
+}
+
+// Close nothing to release
+func (e *OTLPExporter) Close() error {
+	return nil
+}
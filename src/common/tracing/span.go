@@ -0,0 +1,139 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// SpanKind the role of the span in the trace, the value is the same as the OTLP protocol
+type SpanKind int
+
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+func (k SpanKind) String() string {
+	switch k {
+	case KindServer:
+		return "server"
+	case KindClient:
+		return "client"
+	default:
+		return "internal"
+	}
+}
+
+// MarshalJSON marshal the span kind as readable string
+func (k SpanKind) MarshalJSON() ([]byte, error) {
+	return json.Marshal(k.String())
+}
+
+// StatusCode the status of the span, the value is the same as the OTLP protocol
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// Span a timed operation of the trace, all the methods can be called with a nil span,
+// which is returned when the tracing is disabled.
+type Span struct {
+	Service       string            `json:"service"`
+	Name          string            `json:"name"`
+	Kind          SpanKind          `json:"kind"`
+	TraceID       string            `json:"trace_id"`
+	SpanID        string            `json:"span_id"`
+	ParentSpanID  string            `json:"parent_span_id,omitempty"`
+	StartTime     time.Time         `json:"start_time"`
+	EndTime       time.Time         `json:"end_time"`
+	Duration      float64           `json:"duration_ms"`
+	Attributes    map[string]string `json:"attributes,omitempty"`
+	Status        StatusCode        `json:"status"`
+	StatusMessage string            `json:"status_message,omitempty"`
+
+	ctx       SpanContext
+	requestID string
+	lock      sync.Mutex
+	ended     bool
+}
+
+// Context returns the span context to propagate
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.ctx
+}
+
+// SetAttribute set the attribute of the span
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]string)
+	}
+	s.Attributes[key] = value
+}
+
+// SetError mark the span as failed
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Status = StatusError
+	s.StatusMessage = err.Error()
+}
+
+// Finish set the error if not nil and end the span
+func (s *Span) Finish(err error) {
+	s.SetError(err)
+	s.End()
+}
+
+// End end the span and send it to the exporter if it's sampled, a span can only be ended once
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	s.Duration = float64(s.EndTime.Sub(s.StartTime)) / float64(time.Millisecond)
+	if s.Status == StatusUnset {
+		s.Status = StatusOK
+	}
+	s.lock.Unlock()
+
+	if s.requestID != "" {
+		globalTracer.unbindRequest(s.requestID, s.ctx)
+	}
+	if s.ctx.Sampled {
+		globalTracer.export(s)
+	}
+}
@@ -0,0 +1,270 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+)
+
+// the keys of the process config to setup the tracing
+const (
+	ConfigExporter    = "trace.exporter"
+	ConfigFile        = "trace.file"
+	ConfigEndpoint    = "trace.endpoint"
+	ConfigSampleRatio = "trace.sample_ratio"
+)
+
+// the supported exporters, tracing is disabled if the exporter is not set
+const (
+	ExporterFile = "file"
+	ExporterOTLP = "otlp"
+)
+
+// Config the tracing config
+type Config struct {
+	ServiceName string
+	Exporter    string
+	// File the file path the spans written to with the file exporter
+	File string
+	// Endpoint the OTLP/HTTP traces url of the collector, like http://127.0.0.1:4318/v1/traces
+	Endpoint string
+	// SampleRatio the ratio of the new traces to be sampled, the sampled flag of the parent is always respected
+	SampleRatio float64
+}
+
+// ParseConfig parse the tracing config from the process config
+func ParseConfig(serviceName string, configMap map[string]string) (Config, error) {
+	conf := Config{
+		ServiceName: serviceName,
+		Exporter:    configMap[ConfigExporter],
+		File:        configMap[ConfigFile],
+		Endpoint:    configMap[ConfigEndpoint],
+		SampleRatio: 1,
+	}
+	if ratio, ok := configMap[ConfigSampleRatio]; ok && ratio != "" {
+		value, err := strconv.ParseFloat(ratio, 64)
+		if err != nil || value < 0 || value > 1 {
+			return conf, fmt.Errorf("invalid %s %s, must be a number between 0 and 1", ConfigSampleRatio, ratio)
+		}
+		conf.SampleRatio = value
+	}
+
+	switch conf.Exporter {
+	case "":
+	case ExporterFile:
+		if conf.File == "" {
+			conf.File = "./trace/" + serviceName + ".trace"
+		}
+	case ExporterOTLP:
+		if conf.Endpoint == "" {
+			return conf, fmt.Errorf("%s can not be empty with the otlp exporter", ConfigEndpoint)
+		}
+	default:
+		return conf, fmt.Errorf("unsupported %s %s", ConfigExporter, conf.Exporter)
+	}
+	return conf, nil
+}
+
+type tracer struct {
+	lock      sync.RWMutex
+	conf      Config
+	processor *batchProcessor
+
+	// requests the span of the requests in handling, keyed by the request id, so that the spans
+	// of the operations which only know the request id of the context can find their parent.
+	requests     map[string]SpanContext
+	requestsLock sync.RWMutex
+}
+
+var globalTracer = &tracer{requests: make(map[string]SpanContext)}
+
+// Init setup or reset the global tracer with the config, the previous exporter is flushed and closed
+func Init(conf Config) error {
+	globalTracer.lock.Lock()
+	defer globalTracer.lock.Unlock()
+
+	if conf == globalTracer.conf {
+		return nil
+	}
+
+	var exporter Exporter
+	var err error
+	switch conf.Exporter {
+	case "":
+	case ExporterFile:
+		exporter, err = NewFileExporter(conf.File)
+	case ExporterOTLP:
+		exporter = NewOTLPExporter(conf.Endpoint, conf.ServiceName)
+	default:
+		err = fmt.Errorf("unsupported exporter %s", conf.Exporter)
+	}
+	if err != nil {
+		return err
+	}
+
+	if globalTracer.processor != nil {
+		globalTracer.processor.stop()
+		globalTracer.processor = nil
+	}
+	if exporter != nil {
+		globalTracer.processor = newBatchProcessor(exporter)
+	}
+	globalTracer.conf = conf
+	blog.Infof("tracing config updated, exporter: %s, sample ratio: %v", conf.Exporter, conf.SampleRatio)
+	return nil
+}
+
+// Enabled returns whether the spans should be created
+func Enabled() bool {
+	globalTracer.lock.RLock()
+	defer globalTracer.lock.RUnlock()
+	return globalTracer.processor != nil
+}
+
+func (t *tracer) export(span *Span) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	if t.processor != nil {
+		t.processor.add(span)
+	}
+}
+
+func (t *tracer) newSpan(parent SpanContext, name string, kind SpanKind) *Span {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	if t.processor == nil {
+		return nil
+	}
+
+	span := &Span{
+		Service:   t.conf.ServiceName,
+		Name:      name,
+		Kind:      kind,
+		StartTime: time.Now(),
+	}
+	if parent.IsValid() {
+		span.ctx.TraceID = parent.TraceID
+		span.ctx.Sampled = parent.Sampled
+		span.ParentSpanID = parent.SpanID.String()
+	} else {
+		span.ctx.TraceID = newTraceID()
+		span.ctx.Sampled = t.conf.SampleRatio >= 1 || rand.Float64() < t.conf.SampleRatio
+	}
+	span.ctx.SpanID = newSpanID()
+	span.TraceID = span.ctx.TraceID.String()
+	span.SpanID = span.ctx.SpanID.String()
+	return span
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpan returns a new context that contains the span as the parent of the new spans
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, spanContextKey{}, span.Context())
+}
+
+// SpanContextFromContext returns the span context in the context, or the span context
+// of the request which has the same request id with the context.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if ctx == nil {
+		return SpanContext{}
+	}
+	if sc, ok := ctx.Value(spanContextKey{}).(SpanContext); ok {
+		return sc
+	}
+	if rid, ok := ctx.Value(common.ContextRequestIDField).(string); ok && rid != "" {
+		globalTracer.requestsLock.RLock()
+		defer globalTracer.requestsLock.RUnlock()
+		return globalTracer.requests[rid]
+	}
+	return SpanContext{}
+}
+
+// StartSpan start a span whose parent is found from the context, the returned context contains
+// the new span. the span is nil if the tracing is disabled.
+func StartSpan(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	span := globalTracer.newSpan(SpanContextFromContext(ctx), name, kind)
+	return ContextWithSpan(ctx, span), span
+}
+
+// StartSpanWithParent start a span with the specified parent, a new trace is started if the parent is invalid
+func StartSpanWithParent(parent SpanContext, name string, kind SpanKind) *Span {
+	return globalTracer.newSpan(parent, name, kind)
+}
+
+// StartRequestSpan start the server span of the http request, the parent is parsed from the traceparent header
+// of the request, which is then replaced with the server span, so that the spans of the downstream requests
+// which are sent with the request header are children of the server span. the span is bound to the request id
+// until it's ended.
+func StartRequestSpan(req *http.Request, name string) *Span {
+	parent, _ := ParseTraceParent(req.Header.Get(TraceParentHeader))
+	span := globalTracer.newSpan(parent, name, KindServer)
+	if span == nil {
+		return nil
+	}
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.target", req.URL.Path)
+	InjectHeader(span, req.Header)
+
+	rid := req.Header.Get(common.BKHTTPCCRequestID)
+	if rid != "" {
+		span.SetAttribute("cc.request_id", rid)
+		span.requestID = rid
+		globalTracer.requestsLock.Lock()
+		globalTracer.requests[rid] = span.Context()
+		globalTracer.requestsLock.Unlock()
+	}
+	return span
+}
+
+func (t *tracer) unbindRequest(rid string, sc SpanContext) {
+	t.requestsLock.Lock()
+	defer t.requestsLock.Unlock()
+	// the same request id may be handled by nested requests, only remove the binding of itself
+	if t.requests[rid] == sc {
+		delete(t.requests, rid)
+	}
+}
+
+// InjectHeader set the traceparent header with the span
+func InjectHeader(span *Span, header http.Header) {
+	if span == nil || header == nil {
+		return
+	}
+	header.Set(TraceParentHeader, span.Context().TraceParent())
+}
+
+// StartClientSpan start the span of an outgoing request, the parent is found from the context,
+// or parsed from the traceparent header the request is going to be sent with.
+func StartClientSpan(ctx context.Context, header http.Header, name string) *Span {
+	parent := SpanContextFromContext(ctx)
+	if !parent.IsValid() && header != nil {
+		parent, _ = ParseTraceParent(header.Get(TraceParentHeader))
+	}
+	return globalTracer.newSpan(parent, name, KindClient)
+}
@@ -0,0 +1,127 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"configcenter/src/common"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, err := ParseTraceParent(value)
+	if err != nil {
+		t.Fatalf("parse traceparent failed, err: %v", err)
+	}
+	if !sc.Sampled || sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID.String() != "00f067aa0ba902b7" {
+		t.Errorf("unexpected span context %+v", sc)
+	}
+	if sc.TraceParent() != value {
+		t.Errorf("format traceparent got %s, want %s", sc.TraceParent(), value)
+	}
+
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	}
+	for _, item := range invalid {
+		if _, err := ParseTraceParent(item); err == nil {
+			t.Errorf("traceparent %s should be invalid", item)
+		}
+	}
+}
+
+func TestSpanPropagation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "test.trace")
+	if err := Init(Config{ServiceName: "test", Exporter: ExporterFile, File: file, SampleRatio: 1}); err != nil {
+		t.Fatalf("init tracing failed, err: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1/api/v3/find", nil)
+	req.Header.Set(TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set(common.BKHTTPCCRequestID, "rid")
+	server := StartRequestSpan(req, "POST /api/v3/find")
+	if server.ParentSpanID != "00f067aa0ba902b7" || server.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("server span should continue the incoming trace, got %+v", server)
+	}
+	if req.Header.Get(TraceParentHeader) != server.Context().TraceParent() {
+		t.Errorf("traceparent header should be replaced by the server span")
+	}
+
+	// the db operation only knows the request id
+	ctx := context.WithValue(context.Background(), common.ContextRequestIDField, "rid")
+	_, db := StartSpan(ctx, "find", KindClient)
+	if db.ParentSpanID != server.SpanID {
+		t.Errorf("span with request id should be child of the request span, got parent %s", db.ParentSpanID)
+	}
+	db.End()
+
+	client := StartClientSpan(context.Background(), req.Header, "GET /next")
+	if client.ParentSpanID != server.SpanID {
+		t.Errorf("client span should be child of the header span, got parent %s", client.ParentSpanID)
+	}
+	client.End()
+	server.End()
+
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		t.Errorf("request id should be unbound after the request span ended")
+	}
+
+	// flush and close the exporter
+	if err := Init(Config{}); err != nil {
+		t.Fatal(err)
+	}
+	if Enabled() {
+		t.Errorf("tracing should be disabled without exporter")
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	count := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		span := make(map[string]interface{})
+		if err := json.Unmarshal(scanner.Bytes(), &span); err != nil {
+			t.Fatalf("unmarshal span failed, err: %v", err)
+		}
+		if span["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("unexpected trace id %v", span["trace_id"])
+		}
+		count++
+	}
+	if count != 3 {
+		t.Errorf("exported %d spans, want 3", count)
+	}
+}
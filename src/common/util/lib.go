@@ -50,6 +50,21 @@ func GetOwnerID(header http.Header) string {
 	return header.Get(common.BKHTTPOwnerID)
 }
 
+// GetDataSource returns the source which writes the instance fields, the manual edits by default
+func GetDataSource(header http.Header) string {
+	if source := header.Get(common.BKHTTPDataSource); source != "" {
+		return source
+	}
+	return common.FieldSourceUser
+}
+
+// FlushResponse send the written data of the stream response to client
+func FlushResponse(resp *restful.Response) {
+	if flusher, ok := resp.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 // set supplier id and account in head
 func SetOwnerIDAndAccount(req *restful.Request) {
 	owner := req.Request.Header.Get(common.BKHTTPOwner)
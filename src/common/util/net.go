@@ -14,10 +14,16 @@ package util
 
 import (
 	"bytes"
+	"encoding/hex"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
+
+	"configcenter/src/common"
 )
 
 // GetDailAddress returns the address for net.Dail
@@ -53,3 +59,101 @@ type closerWrapper struct {
 }
 
 func (c *closerWrapper) Close() error { return c.closeFunc() }
+
+// IPKey returns the fixed length key of the ip, the keys are ordered as the ips, so that the ip range can be
+// searched by the keys with index. the ipv4 address is converted to the ipv4-mapped ipv6 address.
+func IPKey(ip net.IP) string {
+	return hex.EncodeToString(ip.To16())
+}
+
+// SetHostIPKeys set the ordered keys of the host ips, so that the hosts can be searched by the ip range with index,
+// it must be called before the host data is written into the db. The keys are only written along with the ips,
+// the keys without the ips in the data are removed.
+func SetHostIPKeys(data map[string]interface{}) {
+	if ip, ok := data[common.BKHostInnerIPField]; ok {
+		data[common.BKHostInnerIPKeyField] = IPKeys(GetStrByInterface(ip))
+	} else {
+		delete(data, common.BKHostInnerIPKeyField)
+	}
+	if ip, ok := data[common.BKHostOuterIPField]; ok {
+		data[common.BKHostOuterIPKeyField] = IPKeys(GetStrByInterface(ip))
+	} else {
+		delete(data, common.BKHostOuterIPKeyField)
+	}
+}
+
+// IPKeys returns the keys of the ips split by comma, the invalid ips are ignored
+func IPKeys(ips string) []string {
+	keys := make([]string, 0)
+	for _, item := range strings.Split(ips, ",") {
+		ip := net.ParseIP(strings.TrimSpace(item))
+		if ip == nil {
+			continue
+		}
+		keys = append(keys, IPKey(ip))
+	}
+	return keys
+}
+
+// IsIPRange returns whether the value is a cidr, a start-end range or an ipv4 wildcard
+func IsIPRange(value string) bool {
+	return strings.ContainsAny(value, "/-*")
+}
+
+// ParseIPRange parse the cidr like 10.0.0.0/8 and fe80::/10, the range like 10.0.0.1-10.0.0.9 or the ipv4 wildcard
+// like 10.12.*.* to the keys of the first and the last ip
+func ParseIPRange(value string) (start, end string, err error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case strings.Contains(value, "/"):
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return "", "", err
+		}
+		last := make(net.IP, len(ipNet.IP))
+		for i := range ipNet.IP {
+			last[i] = ipNet.IP[i] | ^ipNet.Mask[i]
+		}
+		return IPKey(ipNet.IP), IPKey(last), nil
+
+	case strings.Contains(value, "-"):
+		parts := strings.SplitN(value, "-", 2)
+		first, last := net.ParseIP(strings.TrimSpace(parts[0])), net.ParseIP(strings.TrimSpace(parts[1]))
+		if first == nil || last == nil {
+			return "", "", fmt.Errorf("invalid ip range %s", value)
+		}
+		if (first.To4() == nil) != (last.To4() == nil) {
+			return "", "", fmt.Errorf("ip range %s mixed ipv4 and ipv6", value)
+		}
+		start, end = IPKey(first), IPKey(last)
+		if start > end {
+			return "", "", fmt.Errorf("the start of ip range %s is greater than the end", value)
+		}
+		return start, end, nil
+
+	case strings.Contains(value, "*"):
+		// only the trailing octets can be the wildcards, like 10.12.*.*, so that they are a continuous range
+		octets := strings.Split(value, ".")
+		wildcard := false
+		for _, octet := range octets {
+			if octet == "*" {
+				wildcard = true
+			} else if wildcard || strings.Contains(octet, "*") {
+				return "", "", fmt.Errorf("invalid ip wildcard %s, only the trailing octets can be *", value)
+			}
+		}
+		first := net.ParseIP(strings.Replace(value, "*", "0", -1))
+		last := net.ParseIP(strings.Replace(value, "*", "255", -1))
+		if first == nil || last == nil || first.To4() == nil || len(octets) != 4 {
+			return "", "", fmt.Errorf("invalid ip wildcard %s", value)
+		}
+		return IPKey(first), IPKey(last), nil
+
+	default:
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return "", "", fmt.Errorf("invalid ip %s", value)
+		}
+		return IPKey(ip), IPKey(ip), nil
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"net/http"
 	"testing"
 
+	"configcenter/src/common"
+
 	"github.com/emicklei/go-restful"
 	"github.com/stretchr/testify/require"
 )
@@ -65,3 +67,48 @@ func TestPeekRequest(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "", string(ncontent))
 }
+
+func TestParseIPRange(t *testing.T) {
+	tests := []struct {
+		value   string
+		inside  []string
+		outside []string
+		wantErr bool
+	}{
+		{"10.12.0.0/20", []string{"10.12.0.0", "10.12.15.255", "10.12.8.1"}, []string{"10.12.16.0", "10.11.255.255"}, false},
+		{"10.0.0.1-10.0.0.9", []string{"10.0.0.1", "10.0.0.9"}, []string{"10.0.0.10", "10.0.0.0"}, false},
+		{"10.12.*.*", []string{"10.12.0.0", "10.12.255.255"}, []string{"10.13.0.0"}, false},
+		{"fe80::/10", []string{"fe80::1", "febf::ffff"}, []string{"fec0::", "10.0.0.1"}, false},
+		{"10.0.0.9-10.0.0.1", nil, nil, true},
+		{"10.0.0.1-fe80::1", nil, nil, true},
+		{"10.0.0.0/33", nil, nil, true},
+		{"10.*.1.*", nil, nil, true},
+		{"10.1*.*.*", nil, nil, true},
+	}
+	for _, tt := range tests {
+		start, end, err := ParseIPRange(tt.value)
+		if tt.wantErr {
+			require.Error(t, err, tt.value)
+			continue
+		}
+		require.NoError(t, err, tt.value)
+		for _, ip := range tt.inside {
+			key := IPKeys(ip)[0]
+			require.True(t, key >= start && key <= end, "%s should be in %s", ip, tt.value)
+		}
+		for _, ip := range tt.outside {
+			key := IPKeys(ip)[0]
+			require.False(t, key >= start && key <= end, "%s should not be in %s", ip, tt.value)
+		}
+	}
+}
+
+func TestSetHostIPKeys(t *testing.T) {
+	data := map[string]interface{}{
+		common.BKHostInnerIPField:    "10.0.0.2,10.0.0.1",
+		common.BKHostOuterIPKeyField: []string{"stale"},
+	}
+	SetHostIPKeys(data)
+	require.Equal(t, IPKeys("10.0.0.2,10.0.0.1"), data[common.BKHostInnerIPKeyField])
+	require.NotContains(t, data, common.BKHostOuterIPKeyField)
+}
@@ -66,16 +66,20 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 	service.Config = *process.Config
 	process.Core = engine
 	process.Service = service
-	process.ConfigCenter = configures.NewConfCenter(ctx, engine.ServiceManageClient())
-
-	// adminserver conf not depend discovery
-	err = process.ConfigCenter.Start(
-		pconfig.ConfigMap["confs.dir"],
-		pconfig.ConfigMap["errors.res"],
-		pconfig.ConfigMap["language.res"],
-	)
-	if err != nil {
-		return err
+	// the configures are written to zookeeper for the other services, they load the configures
+	// from their own files if regdiscv is not zookeeper
+	if engine.ServiceManageClient() != nil {
+		process.ConfigCenter = configures.NewConfCenter(ctx, engine.ServiceManageClient())
+
+		// adminserver conf not depend discovery
+		err = process.ConfigCenter.Start(
+			pconfig.ConfigMap["confs.dir"],
+			pconfig.ConfigMap["errors.res"],
+			pconfig.ConfigMap["language.res"],
+		)
+		if err != nil {
+			return err
+		}
 	}
 
 	for {
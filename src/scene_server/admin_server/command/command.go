@@ -19,13 +19,17 @@ import (
 
 	"configcenter/src/common"
 	"configcenter/src/common/backbone/configcenter"
+	"configcenter/src/storage/dal"
 	"configcenter/src/storage/dal/mongo"
 	"configcenter/src/storage/dal/mongo/local"
 
 	"github.com/spf13/pflag"
 )
 
-const bkbizCmdName = "bkbiz"
+const (
+	bkbizCmdName   = "bkbiz"
+	bkmodelCmdName = "bkmodel"
+)
 
 const (
 	scopeAll = "all"
@@ -34,6 +38,9 @@ const (
 // Parse run app command
 func Parse(args []string) error {
 	ctx := context.Background()
+	if len(args) > 1 && args[1] == bkmodelCmdName {
+		return parseModelBundle(ctx, args)
+	}
 	if len(args) <= 1 || args[1] != bkbizCmdName {
 		return nil
 	}
@@ -64,17 +71,9 @@ func Parse(args []string) error {
 		return err
 	}
 
-	// read config
-	config, err := configcenter.ParseConfigWithFile(configPosition)
-	if nil != err {
-		return fmt.Errorf("parse config file error %s", err.Error())
-	}
-	mongoConfig := mongo.ParseConfigFromKV("mongodb", config.ConfigMap)
-
-	// connect to mongo db
-	db, err := local.NewMgo(mongoConfig.BuildURI(), 0)
+	db, err := connectDB(configPosition)
 	if err != nil {
-		return fmt.Errorf("connect mongo server failed %s", err.Error())
+		return err
 	}
 	opt := &option{
 		position: filePath,
@@ -121,3 +120,20 @@ func Parse(args []string) error {
 	os.Exit(0)
 	return nil
 }
+
+// connectDB connect to the mongo db configured in the config file
+func connectDB(configPosition string) (dal.RDB, error) {
+	// read config
+	config, err := configcenter.ParseConfigWithFile(configPosition)
+	if nil != err {
+		return nil, fmt.Errorf("parse config file error %s", err.Error())
+	}
+	mongoConfig := mongo.ParseConfigFromKV("mongodb", config.ConfigMap)
+
+	// connect to mongo db
+	db, err := local.NewMgo(mongoConfig.BuildURI(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("connect mongo server failed %s", err.Error())
+	}
+	return db, nil
+}
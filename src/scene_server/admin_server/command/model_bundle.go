@@ -0,0 +1,216 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/backbone/configcenter"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/language"
+	"configcenter/src/common/metadata"
+	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/source_controller/coreservice/core/model"
+	"configcenter/src/source_controller/coreservice/core/modelbundle"
+	"configcenter/src/storage/dal"
+
+	"github.com/spf13/pflag"
+)
+
+// modelBundleOption the option of the bkmodel command
+type modelBundleOption struct {
+	position  string
+	format    string
+	OwnerID   string
+	objectIDs []string
+	dryrun    bool
+	errors    map[string]errors.ErrorCode
+	languages map[string]language.LanguageMap
+}
+
+// modelBundleLanguage the language of the messages of the invalid attributes
+const modelBundleLanguage = "en"
+
+// parseModelBundle run the bkmodel command, which exports the model definitions into a bundle file,
+// or imports the bundle file into the cmdb.
+func parseModelBundle(ctx context.Context, args []string) error {
+	var (
+		exportFlag     bool
+		importFlag     bool
+		dryRunFlag     bool
+		filePath       string
+		configPosition string
+		format         string
+		objects        string
+	)
+
+	// set flags
+	cmdFlags := pflag.NewFlagSet(bkmodelCmdName, pflag.ExitOnError)
+	cmdFlags.BoolVar(&dryRunFlag, "dryrun", false, "dryrun flag, if this flag seted, we will just print the plan of the import but not execute to db")
+	cmdFlags.BoolVar(&exportFlag, "export", false, "export flag")
+	cmdFlags.BoolVar(&importFlag, "import", false, "import flag")
+	cmdFlags.StringVar(&filePath, "file", "", "export/import filepath")
+	cmdFlags.StringVar(&configPosition, "config", "conf/api.conf", "The config path. e.g conf/api.conf")
+	cmdFlags.StringVar(&format, "format", "", "the format of the file, could be [json] or [yaml], default by the file extension")
+	cmdFlags.StringVar(&objects, "objects", "", "the models to export separated by comma, default all")
+	err := cmdFlags.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+
+	if format == "" {
+		format = metadata.ModelBundleFormatJSON
+		if ext := strings.ToLower(filepath.Ext(filePath)); ext == ".yaml" || ext == ".yml" {
+			format = metadata.ModelBundleFormatYAML
+		}
+	}
+	opt := &modelBundleOption{
+		position: filePath,
+		format:   format,
+		OwnerID:  common.BKDefaultOwnerID,
+		dryrun:   dryRunFlag,
+	}
+	if objects != "" {
+		opt.objectIDs = strings.Split(objects, ",")
+	}
+
+	db, err := connectDB(configPosition)
+	if err != nil {
+		return err
+	}
+	if importFlag {
+		if err := loadModelBundleResources(configPosition, opt); err != nil {
+			return err
+		}
+	}
+
+	if exportFlag {
+		fmt.Printf("exporting models to %s in \033[34m%s\033[0m format\n", filePath, format)
+		if err := exportModelBundle(ctx, db, opt); err != nil {
+			fmt.Printf("export error: %s", err.Error())
+			os.Exit(2)
+		}
+		fmt.Printf("models have been export to %s\n", filePath)
+	} else if importFlag {
+		if dryRunFlag {
+			fmt.Printf("dryrun import models from %s\n", filePath)
+		} else {
+			fmt.Printf("importing models from %s\n", filePath)
+		}
+		if err := importModelBundle(ctx, db, opt); err != nil {
+			fmt.Printf("import error: %s", err.Error())
+			os.Exit(2)
+		}
+		if !dryRunFlag {
+			fmt.Printf("models have been import from %s\n", filePath)
+		}
+	} else {
+		fmt.Printf("invalide argument")
+	}
+
+	os.Exit(0)
+	return nil
+}
+
+func exportModelBundle(ctx context.Context, db dal.RDB, opt *modelBundleOption) error {
+	bundle, err := modelbundle.New(db, nil).Export(ctx, opt.OwnerID, opt.objectIDs)
+	if nil != err {
+		return err
+	}
+	data, err := metadata.EncodeModelBundle(bundle, opt.format)
+	if nil != err {
+		return err
+	}
+	return ioutil.WriteFile(opt.position, data, 0644)
+}
+
+func importModelBundle(ctx context.Context, db dal.RDB, opt *modelBundleOption) error {
+	data, err := ioutil.ReadFile(opt.position)
+	if nil != err {
+		return err
+	}
+	bundle, err := metadata.DecodeModelBundle(data, opt.format)
+	if nil != err {
+		return err
+	}
+
+	// the attributes are checked by the attribute manager of the coreservice, as the coreservice api does
+	params := core.ContextParams{
+		Context:         ctx,
+		SupplierAccount: opt.OwnerID,
+		User:            common.CCSystemOperatorUserName,
+		Error:           errors.NewFromCtx(opt.errors).CreateDefaultCCErrorIf(modelBundleLanguage),
+		Lang:            language.NewFromCtx(opt.languages).CreateDefaultCCLanguageIf(modelBundleLanguage),
+	}
+	attributes := model.New(db, nil)
+	manager := modelbundle.New(db, func(attribute metadata.Attribute, create bool) error {
+		return attributes.ValidateModelAttribute(params, attribute, create)
+	})
+	var plan metadata.ModelBundlePlan
+	if opt.dryrun {
+		plan, err = manager.Plan(ctx, opt.OwnerID, bundle)
+	} else {
+		var applied metadata.ModelBundleApplied
+		applied, err = manager.Apply(ctx, opt.OwnerID, common.CCSystemOperatorUserName, bundle)
+		plan = applied.ModelBundlePlan
+	}
+	if nil != err {
+		return err
+	}
+	if !opt.dryrun {
+		objIDs := make([]string, 0, len(bundle.Objects))
+		for _, obj := range bundle.Objects {
+			objIDs = append(objIDs, obj.Object.ObjectID)
+		}
+		attributes.SaveModelSchemaVersion(params, objIDs...)
+	}
+
+	for _, item := range plan.Items {
+		if item.Action == metadata.ModelBundleActionUnchanged {
+			continue
+		}
+		fmt.Printf("%-10s %-18s %s\n", item.Action, item.Kind, item.Key)
+		for _, change := range item.Changes {
+			fmt.Printf("%30s: %v => %v\n", change.Field, change.From, change.To)
+		}
+	}
+	fmt.Printf("%d to create, %d to update, %d unchanged\n", plan.CreateCount, plan.UpdateCount, plan.UnchangedCount)
+	return nil
+}
+
+// loadModelBundleResources load the error and language resources, which are used to explain the invalid attributes
+func loadModelBundleResources(configPosition string, opt *modelBundleOption) error {
+	config, err := configcenter.ParseConfigWithFile(configPosition)
+	if nil != err {
+		return fmt.Errorf("parse config file error %s", err.Error())
+	}
+
+	opt.errors, opt.languages = errors.EmptyErrorsSetting, language.EmptyLanguageSetting
+	if dir := config.ConfigMap["errors.res"]; dir != "" {
+		if opt.errors, err = errors.LoadErrorResourceFromDir(dir); nil != err {
+			return fmt.Errorf("load error resource error: %s", err.Error())
+		}
+	}
+	if dir := config.ConfigMap["language.res"]; dir != "" {
+		if opt.languages, err = language.LoadLanguageResourceFromDir(dir); nil != err {
+			return fmt.Errorf("load language resource error: %s", err.Error())
+		}
+	}
+	return nil
+}
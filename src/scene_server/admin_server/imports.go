@@ -49,4 +49,15 @@ import (
 	// v3.5.x
 	_ "configcenter/src/scene_server/admin_server/upgrader/x19.08.20.01"
 	_ "configcenter/src/scene_server/admin_server/upgrader/x19.08.26.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.09.02.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.09.03.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.09.04.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.09.05.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.09.06.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.09.07.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.09.08.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.09.09.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.09.10.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.09.11.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.09.12.01"
 )
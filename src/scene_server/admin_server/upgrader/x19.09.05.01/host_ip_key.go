@@ -0,0 +1,68 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package x19_09_05_01
+
+import (
+	"context"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/util"
+	"configcenter/src/scene_server/admin_server/upgrader"
+	"configcenter/src/storage/dal"
+)
+
+// addHostIPKeys fill the ordered keys of the host ips which are used to search hosts by ip range
+func addHostIPKeys(ctx context.Context, db dal.RDB, conf *upgrader.Config) error {
+	type Host struct {
+		HostID  int64  `bson:"bk_host_id"`
+		InnerIP string `bson:"bk_host_innerip"`
+		OuterIP string `bson:"bk_host_outerip"`
+	}
+
+	var start uint64
+	fields := []string{common.BKHostIDField, common.BKHostInnerIPField, common.BKHostOuterIPField}
+	for {
+		hosts := make([]Host, 0)
+		err := db.Table(common.BKTableNameBaseHost).Find(nil).Fields(fields...).Sort(common.BKHostIDField).Start(start).Limit(500).All(ctx, &hosts)
+		if err != nil {
+			return err
+		}
+		if len(hosts) == 0 {
+			break
+		}
+		start += uint64(len(hosts))
+
+		for _, host := range hosts {
+			cond := mapstr.MapStr{common.BKHostIDField: host.HostID}
+			data := mapstr.MapStr{
+				common.BKHostInnerIPKeyField: util.IPKeys(host.InnerIP),
+				common.BKHostOuterIPKeyField: util.IPKeys(host.OuterIP),
+			}
+			if err := db.Table(common.BKTableNameBaseHost).Update(ctx, cond, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	indexes := []dal.Index{
+		{Name: "idx_innerIPKey", Keys: map[string]int32{common.BKHostInnerIPKeyField: 1}, Background: true},
+		{Name: "idx_outerIPKey", Keys: map[string]int32{common.BKHostOuterIPKeyField: 1}, Background: true},
+	}
+	for _, index := range indexes {
+		if err := db.Table(common.BKTableNameBaseHost).CreateIndex(ctx, index); err != nil && !db.IsDuplicatedError(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package x19_09_06_01
+
+import (
+	"context"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/admin_server/upgrader"
+	"configcenter/src/storage/dal"
+)
+
+// snapshotModelSchemas record the first schema version of the existing models, so that the
+// models can be rolled back to the schema before their first change.
+func snapshotModelSchemas(ctx context.Context, db dal.RDB, conf *upgrader.Config) error {
+	objects := make([]metadata.Object, 0)
+	if err := db.Table(common.BKTableNameObjDes).Find(nil).All(ctx, &objects); err != nil {
+		return err
+	}
+
+	for _, obj := range objects {
+		cond := mapstr.MapStr{common.BKObjIDField: obj.ObjectID, common.BKOwnerIDField: obj.OwnerID}
+		cnt, err := db.Table(common.BKTableNameObjSchemaVersion).Find(cond).Count(ctx)
+		if err != nil {
+			return err
+		}
+		if cnt > 0 {
+			continue
+		}
+
+		schema := metadata.ModelSchema{Object: obj}
+		if err := db.Table(common.BKTableNameObjAttDes).Find(cond).Sort(common.BKFieldID).All(ctx, &schema.Attributes); err != nil {
+			return err
+		}
+		if err := db.Table(common.BKTableNamePropertyGroup).Find(cond).Sort(common.BKFieldID).All(ctx, &schema.Groups); err != nil {
+			return err
+		}
+		if err := db.Table(common.BKTableNameObjUnique).Find(cond).Sort(common.BKFieldID).All(ctx, &schema.Uniques); err != nil {
+			return err
+		}
+
+		version := metadata.ModelSchemaVersion{
+			ObjectID:   obj.ObjectID,
+			Version:    1,
+			Action:     metadata.ModelSchemaActionInit,
+			Operator:   conf.User,
+			OwnerID:    obj.OwnerID,
+			CreateTime: metadata.Now(),
+			Schema:     schema,
+		}
+		if err := db.Table(common.BKTableNameObjSchemaVersion).Insert(ctx, version); err != nil && !db.IsDuplicatedError(err) {
+			blog.Errorf("snapshot the schema of the model(%s) failed, err: %v", obj.ObjectID, err)
+			return err
+		}
+	}
+	return nil
+}
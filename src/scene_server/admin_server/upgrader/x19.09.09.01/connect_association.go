@@ -0,0 +1,60 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package x19_09_09_01
+
+import (
+	"context"
+	"fmt"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/admin_server/upgrader"
+	"configcenter/src/storage/dal"
+)
+
+// addNetDeviceConnectAssociation adds the connect associations between the network devices and to the hosts,
+// so that the physical links found by lldp/cdp can be confirmed as instance associations
+func addNetDeviceConnectAssociation(ctx context.Context, db dal.RDB, conf *upgrader.Config) error {
+	devices := []string{common.BKInnerObjIDSwitch, common.BKInnerObjIDRouter, common.BKInnerObjIDFirewall, common.BKInnerObjIDBlance}
+	asstObjIDs := append(devices, common.BKInnerObjIDHost)
+	falseVar := false
+
+	for _, objID := range devices {
+		for _, asstObjID := range asstObjIDs {
+			asst := metadata.Association{
+				OwnerID:         conf.OwnerID,
+				AsstKindID:      common.AssociationTypeConnect,
+				ObjectID:        objID,
+				AsstObjID:       asstObjID,
+				AssociationName: fmt.Sprintf("%s_%s_%s", objID, common.AssociationTypeConnect, asstObjID),
+				Mapping:         metadata.ManyToManyMapping,
+				OnDelete:        metadata.NoAction,
+				IsPre:           &falseVar,
+			}
+
+			// keep the association if the user has defined it already
+			cond := map[string]interface{}{common.AssociationObjAsstIDField: asst.AssociationName}
+			count, err := db.Table(common.BKTableNameObjAsst).Find(cond).Count(ctx)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+			if _, _, err = upgrader.Upsert(ctx, db, common.BKTableNameObjAsst, asst, "id", []string{common.AssociationObjAsstIDField}, []string{"id"}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
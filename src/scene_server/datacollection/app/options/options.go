@@ -36,8 +36,8 @@ func NewServerOption() *ServerOption {
 func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.ServConf.AddrPort, "addrport", "127.0.0.1:50006", "The ip address and port for the serve on")
 
-	fs.StringVar(&s.ServConf.RegDiscover, "regdiscv", "", "hosts of register and discover server. e.g: 127.0.0.1:2181")
-	fs.StringVar(&s.ServConf.ExConfig, "config", "", "The config path. e.g conf/api.conf")
+	fs.StringVar(&s.ServConf.RegDiscover, "regdiscv", "", "address of register and discover server. e.g: 127.0.0.1:2181, zk://127.0.0.1:2181, file:///data/cmdb/services.yaml or dns://cmdb.svc.cluster.local")
+	fs.StringVar(&s.ServConf.ExConfig, "config", "", "The config path. e.g conf/api.conf, or the directory of the config files which are reloaded once changed")
 }
 
 type Config struct {
@@ -46,9 +46,19 @@ type Config struct {
 	SnapRedis       SnapRedis
 	DiscoverRedis   SnapRedis
 	NetCollectRedis SnapRedis
+	SnmpPoller      SnmpPoller
 	Esb             esbutil.EsbConfig
 }
 
+// SnmpPoller the config of the built-in snmp poller, which polls the network devices without netdevicebeat
+type SnmpPoller struct {
+	Enable string
+	// Interval is the seconds between two checks of the collectors
+	Interval int
+	// Workers is the number of targets polled at the same time
+	Workers int
+}
+
 type SnapRedis struct {
 	redis.Config
 	Enable string
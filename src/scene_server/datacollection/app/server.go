@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"sync"
 	"time"
 
@@ -86,8 +87,9 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 		}
 
 		process.Service.Logics = logics.NewLogics(ctx, service.Engine, mgoCli, esb)
+		process.Service.Logics.SnmpPoller = process.Config.SnmpPoller.Enable == "true"
 
-		err = datacollection.NewDataCollection(ctx, process.Config, process.Core, engine.Metric().Registry()).Run()
+		err = datacollection.NewDataCollection(ctx, process.Config, process.Core, engine.Metric().Registry(), process.Service.Logics).Run()
 		if err != nil {
 			return fmt.Errorf("run datacollection routine failed %s", err.Error())
 		}
@@ -145,6 +147,11 @@ func (h *DCServer) onHostConfigUpdate(previous, current cc.ProcessConfig) {
 		h.Config.NetCollectRedis.Config = netCollectRedisConf
 		h.Config.SnapRedis.Enable = current.ConfigMap[netCollectPrefix+".enable"]
 
+		snmpPollerPrefix := "snmp-poller"
+		h.Config.SnmpPoller.Enable = current.ConfigMap[snmpPollerPrefix+".enable"]
+		h.Config.SnmpPoller.Interval, _ = strconv.Atoi(current.ConfigMap[snmpPollerPrefix+".interval"])
+		h.Config.SnmpPoller.Workers, _ = strconv.Atoi(current.ConfigMap[snmpPollerPrefix+".workers"])
+
 		esbPrefix := "esb"
 		h.Config.Esb.Addrs = current.ConfigMap[esbPrefix+".addr"]
 		h.Config.Esb.AppCode = current.ConfigMap[esbPrefix+".appCode"]
@@ -40,10 +40,12 @@ type DataCollection struct {
 	db       dal.RDB
 	ctx      context.Context
 	registry prometheus.Registerer
+	// confirmer confirms the net collect reports by the confirm policies
+	confirmer netcollect.Confirmer
 }
 
-func NewDataCollection(ctx context.Context, config *options.Config, backbone *backbone.Engine, registry prometheus.Registerer) *DataCollection {
-	return &DataCollection{ctx: ctx, Config: config, Engine: backbone, registry: registry}
+func NewDataCollection(ctx context.Context, config *options.Config, backbone *backbone.Engine, registry prometheus.Registerer, confirmer netcollect.Confirmer) *DataCollection {
+	return &DataCollection{ctx: ctx, Config: config, Engine: backbone, registry: registry, confirmer: confirmer}
 }
 
 func (d *DataCollection) Run() error {
@@ -105,7 +107,7 @@ func (d *DataCollection) Run() error {
 		}
 		blog.Infof("[data-collection][RUN]connected to discover-redis %+v", d.Config.DiscoverRedis.Config)
 		discoverChanName := d.getDiscoverChanName(defaultAppID)
-		middlewareCollector := middleware.NewDiscover(d.ctx, redisCli, d.Engine)
+		middlewareCollector := middleware.NewDiscover(d.ctx, redisCli, db, d.Engine)
 		middlewarePorter := BuildChanPorter("middleware", middlewareCollector, redisCli, disCli, discoverChanName, middleware.MockMessage, d.registry, d.Engine)
 		man.AddPorter(middlewarePorter)
 	}
@@ -119,11 +121,17 @@ func (d *DataCollection) Run() error {
 		}
 		blog.Infof("[data-collection][RUN]connected to netcollect-redis %+v", d.Config.NetCollectRedis.Config)
 		netDevChanName := d.getNetcollectChanName(defaultAppID)
-		netCollector := netcollect.NewNetCollect(d.ctx, db)
+		netCollector := netcollect.NewNetCollect(d.ctx, db, d.confirmer)
 		netCollectPorter := BuildChanPorter("netcollect", netCollector, redisCli, netCli, netDevChanName, netcollect.MockMessage, d.registry, d.Engine)
 		man.AddPorter(netCollectPorter)
 	}
 
+	if d.Config.SnmpPoller.Enable == "true" {
+		interval := time.Duration(d.Config.SnmpPoller.Interval) * time.Second
+		snmpPoller := netcollect.NewSnmpPoller(d.ctx, db, d.Engine, d.confirmer, interval, d.Config.SnmpPoller.Workers)
+		man.AddPorter(snmpPoller)
+	}
+
 	blog.Infof("data-collection started")
 	return nil
 }
@@ -22,6 +22,10 @@ import (
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/fieldsource"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
 	"configcenter/src/storage/dal"
 
 	"github.com/tidwall/gjson"
@@ -93,15 +97,82 @@ func (h *HostSnap) Analyze(mesg string) error {
 	}
 	setter := parseSetter(&val, innerip, outip)
 	if needToUpdate(setter, host) {
-		blog.Infof("[data-collection][hostsnap] update host by %v, to %v", condition, setter)
-		if err := h.db.Table(common.BKTableNameBaseHost).Update(h.ctx, condition, setter); err != nil {
-			return fmt.Errorf("update host error: %v", err)
+		allowed, err := h.filterBySource(host, setter)
+		if err != nil {
+			return fmt.Errorf("filter host fields by source priority error: %v", err)
+		}
+		if len(allowed) > 0 {
+			blog.Infof("[data-collection][hostsnap] update host by %v, to %v", condition, allowed)
+			update := allowed.Clone()
+			util.SetHostIPKeys(update)
+			if err := h.db.Table(common.BKTableNameBaseHost).Update(h.ctx, condition, update); err != nil {
+				return fmt.Errorf("update host error: %v", err)
+			}
+			h.recordSource(host, allowed)
 		}
+		// the rejected values are cached as well, so that they are not checked again until they change
 		copyVal(setter, host)
 	}
 	return nil
 }
 
+// filterBySource drop the changed fields which are set by the sources with higher priority than hostsnap
+func (h *HostSnap) filterBySource(host *HostInst, setter map[string]interface{}) (mapstr.MapStr, error) {
+	hostID, err := util.GetInt64ByInterface(host.get(common.BKHostIDField))
+	if err != nil {
+		return nil, err
+	}
+	priorities, err := fieldsource.FindPriorities(h.ctx, h.db, hostOwnerID(host), common.BKInnerObjIDHost)
+	if err != nil {
+		return nil, err
+	}
+	sources := &metadata.InstFieldSource{Fields: map[string]metadata.FieldSource{}}
+	if len(priorities) > 0 {
+		if sources, err = fieldsource.Find(h.ctx, h.db, common.BKInnerObjIDHost, hostID); err != nil {
+			return nil, err
+		}
+	}
+
+	allowed, rejected := filterChanged(host, setter, priorities, sources)
+	if len(rejected) > 0 {
+		blog.V(4).Infof("[data-collection][hostsnap] fields %v of host %d are set by the sources with higher priority, skip", rejected, hostID)
+	}
+	return allowed, nil
+}
+
+// filterChanged returns the changed fields hostsnap is allowed to overwrite, and the rejected ones
+func filterChanged(host *HostInst, setter map[string]interface{}, priorities map[string]metadata.FieldSourcePriority,
+	sources *metadata.InstFieldSource) (mapstr.MapStr, []string) {
+
+	changed := mapstr.New()
+	for k, v := range setter {
+		if host.get(k) != v {
+			changed[k] = v
+		}
+	}
+	return fieldsource.Filter(priorities, sources, common.FieldSourceHostSnap, changed)
+}
+
+// recordSource record hostsnap as the last writer of the updated fields
+func (h *HostSnap) recordSource(host *HostInst, data mapstr.MapStr) {
+	hostID, err := util.GetInt64ByInterface(host.get(common.BKHostIDField))
+	if err != nil {
+		return
+	}
+	err = fieldsource.Record(h.ctx, h.db, hostOwnerID(host), common.BKInnerObjIDHost, []int64{hostID},
+		common.FieldSourceHostSnap, common.CCSystemCollectorUserName, data)
+	if err != nil {
+		blog.Errorf("[data-collection][hostsnap] record the field source of host %d failed, err: %v", hostID, err)
+	}
+}
+
+func hostOwnerID(host *HostInst) string {
+	if ownerID, ok := host.get(common.BKOwnerIDField).(string); ok && ownerID != "" {
+		return ownerID
+	}
+	return common.BKDefaultOwnerID
+}
+
 func copyVal(a map[string]interface{}, b *HostInst) {
 	for k, v := range a {
 		b.set(k, v)
@@ -0,0 +1,99 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hostsnap
+
+import (
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterChanged(t *testing.T) {
+	priorities := map[string]metadata.FieldSourcePriority{
+		common.BKHostOuterIPField: {
+			ObjectID:   common.BKInnerObjIDHost,
+			PropertyID: common.BKHostOuterIPField,
+			Sources:    []string{common.FieldSourceUser, common.FieldSourceCloudSync, common.FieldSourceHostSnap},
+		},
+	}
+	setter := map[string]interface{}{
+		common.BKHostOuterIPField: "1.1.1.2",
+		common.BKHostNameField:    "host-02",
+		common.BKOSNameField:      "linux centos",
+	}
+
+	tests := []struct {
+		name         string
+		priorities   map[string]metadata.FieldSourcePriority
+		sources      map[string]string
+		wantAllowed  mapstr.MapStr
+		wantRejected []string
+	}{
+		{
+			name:         "without priorities",
+			sources:      map[string]string{common.BKHostOuterIPField: common.FieldSourceUser},
+			wantAllowed:  mapstr.MapStr{common.BKHostOuterIPField: "1.1.1.2", common.BKHostNameField: "host-02"},
+			wantRejected: []string{},
+		},
+		{
+			name:         "not recorded",
+			priorities:   priorities,
+			wantAllowed:  mapstr.MapStr{common.BKHostOuterIPField: "1.1.1.2", common.BKHostNameField: "host-02"},
+			wantRejected: []string{},
+		},
+		{
+			name:         "set by hostsnap",
+			priorities:   priorities,
+			sources:      map[string]string{common.BKHostOuterIPField: common.FieldSourceHostSnap},
+			wantAllowed:  mapstr.MapStr{common.BKHostOuterIPField: "1.1.1.2", common.BKHostNameField: "host-02"},
+			wantRejected: []string{},
+		},
+		{
+			name:         "set by cloud sync",
+			priorities:   priorities,
+			sources:      map[string]string{common.BKHostOuterIPField: common.FieldSourceCloudSync},
+			wantAllowed:  mapstr.MapStr{common.BKHostNameField: "host-02"},
+			wantRejected: []string{common.BKHostOuterIPField},
+		},
+		{
+			name:         "locked by user",
+			priorities:   priorities,
+			sources:      map[string]string{common.BKHostOuterIPField: common.FieldSourceUser, common.BKHostNameField: common.FieldSourceUser},
+			wantAllowed:  mapstr.MapStr{common.BKHostNameField: "host-02"},
+			wantRejected: []string{common.BKHostOuterIPField},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host := &HostInst{data: map[string]interface{}{
+				common.BKHostIDField:      int64(1),
+				common.BKHostOuterIPField: "1.1.1.1",
+				common.BKHostNameField:    "host-01",
+				common.BKOSNameField:      "linux centos",
+			}}
+			sources := &metadata.InstFieldSource{Fields: map[string]metadata.FieldSource{}}
+			for field, source := range tt.sources {
+				sources.Fields[field] = metadata.FieldSource{Source: source}
+			}
+
+			allowed, rejected := filterChanged(host, setter, tt.priorities, sources)
+			require.Equal(t, tt.wantAllowed, allowed)
+			require.Equal(t, tt.wantRejected, rejected)
+		})
+	}
+}
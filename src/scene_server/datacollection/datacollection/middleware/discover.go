@@ -19,6 +19,7 @@ import (
 
 	bkc "configcenter/src/common"
 	"configcenter/src/common/backbone"
+	"configcenter/src/storage/dal"
 
 	"gopkg.in/redis.v5"
 )
@@ -28,18 +29,21 @@ type Discover struct {
 	httpHeader http.Header
 
 	redisCli *redis.Client
+	db       dal.RDB
 	*backbone.Engine
 }
 
 var msgHandlerCnt = int64(0)
 
-func NewDiscover(ctx context.Context, redisCli *redis.Client, backbone *backbone.Engine) *Discover {
+func NewDiscover(ctx context.Context, redisCli *redis.Client, db dal.RDB, backbone *backbone.Engine) *Discover {
 	header := http.Header{}
 	header.Add(bkc.BKHTTPOwnerID, bkc.BKDefaultOwnerID)
 	header.Add(bkc.BKHTTPHeaderUser, bkc.CCSystemCollectorUserName)
+	header.Add(bkc.BKHTTPDataSource, bkc.FieldSourceDiscover)
 
 	discover := &Discover{
 		redisCli:   redisCli,
+		db:         db,
 		ctx:        ctx,
 		httpHeader: header,
 	}
@@ -48,6 +52,14 @@ func NewDiscover(ctx context.Context, redisCli *redis.Client, backbone *backbone
 }
 
 func (d *Discover) Analyze(msg string) error {
+	// the payload of a source with registered schema is mapped by the schema
+	if source := d.parseSource(msg); source != "" {
+		if err := d.AnalyzeBySchema(source, msg); err != nil {
+			return fmt.Errorf("analyze by schema err: %v, raw: %s", err, msg)
+		}
+		return nil
+	}
+
 	err := d.TryCreateModel(msg)
 	if err != nil {
 		return fmt.Errorf("create model err: %v, raw: %s", err, msg)
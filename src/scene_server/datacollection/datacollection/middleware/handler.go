@@ -665,6 +665,9 @@ func (d *Discover) UpdateOrCreateInst(msg string) error {
 		blog.Errorf("search model failed %s", resp.ErrMsg)
 		return fmt.Errorf("search model failed: %s", resp.ErrMsg)
 	}
+	for _, rejected := range resp.Data.Rejected {
+		blog.Infof("fields %v of %s inst %d are set by the sources with higher priority, skip", rejected.Fields, objID, rejected.InstID)
+	}
 	blog.Infof("update inst result: %v", resp)
 
 	d.TryUnsetRedis(instKeyStr)
@@ -0,0 +1,361 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	bkc "configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/tidwall/gjson"
+)
+
+// discoverItem is an item of the payload mapped to an instance
+type discoverItem struct {
+	data  mapstr.MapStr
+	assts []discoverAsst
+}
+
+// discoverAsst is an association of the item to the instance matched by the keys
+type discoverAsst struct {
+	mapping metadata.DiscoverAssociationMapping
+	keys    mapstr.MapStr
+}
+
+func (d *Discover) parseSource(msg string) string {
+	return gjson.Get(msg, "data.meta.source").String()
+}
+
+// GetSchema returns the registered schema of the source, nil if it is not registered
+func (d *Discover) GetSchema(ownerID, source string) (*metadata.DiscoverSchema, error) {
+	schema := new(metadata.DiscoverSchema)
+	filter := mapstr.MapStr{
+		"source":           source,
+		bkc.BKOwnerIDField: ownerID,
+	}
+	if err := d.db.Table(bkc.BKTableNameDiscoverSchema).Find(filter).One(d.ctx, schema); err != nil {
+		if d.db.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return schema, nil
+}
+
+// schemaClient reads and writes the instances mapped by the discover schemas
+type schemaClient interface {
+	GetSchema(ownerID, source string) (*metadata.DiscoverSchema, error)
+	findSchemaInsts(header http.Header, objID string, cond mapstr.MapStr) ([]mapstr.MapStr, error)
+	upsertSchemaInst(header http.Header, mapping metadata.DiscoverMapping, data mapstr.MapStr) (int64, error)
+	createSchemaAsst(header http.Header, objID string, instID int64, asst discoverAsst) error
+	deleteSchemaInst(header http.Header, ownerID, objID string, instID int64) error
+}
+
+// AnalyzeBySchema creates or updates the instances and the associations mapped by the registered schema of the source
+func (d *Discover) AnalyzeBySchema(source, msg string) error {
+	ownerID := d.parseOwnerId(msg)
+	header := util.CloneHeader(d.httpHeader)
+	header.Set(bkc.BKHTTPOwnerID, ownerID)
+	return analyzeBySchema(d, header, ownerID, source, msg)
+}
+
+func analyzeBySchema(client schemaClient, header http.Header, ownerID, source, msg string) error {
+	schema, err := client.GetSchema(ownerID, source)
+	if err != nil {
+		return fmt.Errorf("get schema of %s failed: %s", source, err)
+	}
+	if schema == nil {
+		return fmt.Errorf("schema of %s is not registered", source)
+	}
+
+	payload := gjson.Get(msg, "data.data")
+
+	var errs []string
+	for _, mapping := range schema.Mappings {
+		items, complete, err := extractItems(payload, mapping)
+		if err != nil {
+			// the report may be broken or truncated, nothing is known to be missing
+			blog.Errorf("extract %s items of %s failed: %s", mapping.ObjectID, source, err)
+			errs = append(errs, err.Error())
+			continue
+		}
+		found := make(map[int64]bool, len(items))
+		// the items without identity keys are not known to be found or not
+		failed := !complete
+		for _, item := range items {
+			instID, err := client.upsertSchemaInst(header, mapping, item.data)
+			if err != nil {
+				blog.Errorf("discover %s inst %v of %s failed: %s", mapping.ObjectID, item.data, source, err)
+				errs = append(errs, err.Error())
+				failed = true
+				continue
+			}
+			found[instID] = true
+
+			for _, asst := range item.assts {
+				if err := client.createSchemaAsst(header, mapping.ObjectID, instID, asst); err != nil {
+					blog.Errorf("discover association %s of %s inst %d failed: %s", asst.mapping.ObjectAsstID, mapping.ObjectID, instID, err)
+					errs = append(errs, err.Error())
+				}
+			}
+		}
+
+		// the missing instances are not reliable when some items are failed
+		if mapping.FullReport && mapping.OnMissing == metadata.DiscoverOnMissingDelete && !failed {
+			if err := deleteMissingInsts(client, header, ownerID, mapping, scopeValues(payload, mapping), found); err != nil {
+				blog.Errorf("delete missing %s insts of %s failed: %s", mapping.ObjectID, source, err)
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// extractItems maps the items of the payload to the instances of the mapping model, the items without
+// all the identity keys are skipped, and it returns false if any item is skipped, then the items are not
+// a full report even the mapping is. the items path must exist even there is no item.
+func extractItems(payload gjson.Result, mapping metadata.DiscoverMapping) ([]discoverItem, bool, error) {
+	results := []gjson.Result{payload}
+	if mapping.Items != "" {
+		result := payload.Get(mapping.Items)
+		if !result.Exists() {
+			return nil, false, fmt.Errorf("items %s of %s is not found", mapping.Items, mapping.ObjectID)
+		}
+		results = []gjson.Result{result}
+		if result.IsArray() {
+			results = result.Array()
+		}
+	}
+
+	items := make([]discoverItem, 0, len(results))
+	allComplete := true
+	for _, result := range results {
+		item := discoverItem{data: mapstr.New()}
+		for propertyID, path := range mapping.Attributes {
+			if value := lookupPath(payload, result, path); value.Exists() {
+				item.data[propertyID] = value.Value()
+			}
+		}
+
+		complete := true
+		for _, key := range mapping.IdentityKeys {
+			if _, ok := item.data[key]; !ok {
+				complete = false
+				break
+			}
+		}
+		if !complete {
+			blog.Warnf("skip %s item without identity keys %v: %s", mapping.ObjectID, mapping.IdentityKeys, result.Raw)
+			allComplete = false
+			continue
+		}
+
+		for _, asstMapping := range mapping.Associations {
+			asst := discoverAsst{mapping: asstMapping, keys: mapstr.New()}
+			for propertyID, path := range asstMapping.AsstKeys {
+				if value := lookupPath(payload, result, path); value.Exists() {
+					asst.keys[propertyID] = value.Value()
+				}
+			}
+			if len(asst.keys) == len(asstMapping.AsstKeys) {
+				item.assts = append(item.assts, asst)
+			}
+		}
+		items = append(items, item)
+	}
+	return items, allComplete, nil
+}
+
+// scopeValues returns the values of the scope keys, which are shared by all the items of the payload
+func scopeValues(payload gjson.Result, mapping metadata.DiscoverMapping) mapstr.MapStr {
+	scope := mapstr.New()
+	for _, key := range mapping.ScopeKeys {
+		if value := lookupPath(payload, payload, mapping.Attributes[key]); value.Exists() {
+			scope[key] = value.Value()
+		}
+	}
+	return scope
+}
+
+func lookupPath(payload, item gjson.Result, path string) gjson.Result {
+	if strings.HasPrefix(path, metadata.DiscoverRootPathPrefix) {
+		return payload.Get(strings.TrimPrefix(path, metadata.DiscoverRootPathPrefix))
+	}
+	return item.Get(path)
+}
+
+func (d *Discover) findSchemaInsts(header http.Header, objID string, cond mapstr.MapStr) ([]mapstr.MapStr, error) {
+	cond = cond.Clone()
+	if !bkc.IsInnerModel(objID) {
+		cond.Set(bkc.BKObjIDField, objID)
+	}
+	resp, err := d.CoreAPI.CoreService().Instance().ReadInstance(d.ctx, header, objID, &metadata.QueryCondition{Condition: cond})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Result {
+		return nil, errors.New(resp.ErrMsg)
+	}
+	return resp.Data.Info, nil
+}
+
+// upsertSchemaInst creates the instance of the item, or updates the instance matched by the identity keys
+func (d *Discover) upsertSchemaInst(header http.Header, mapping metadata.DiscoverMapping, data mapstr.MapStr) (int64, error) {
+	cond := mapstr.New()
+	for _, key := range mapping.IdentityKeys {
+		cond[key] = data[key]
+	}
+	insts, err := d.findSchemaInsts(header, mapping.ObjectID, cond)
+	if err != nil {
+		return 0, fmt.Errorf("search inst failed: %s", err)
+	}
+
+	if len(insts) == 0 {
+		resp, err := d.CoreAPI.CoreService().Instance().CreateInstance(d.ctx, header, mapping.ObjectID, &metadata.CreateModelInstance{Data: data})
+		if err != nil {
+			return 0, fmt.Errorf("create inst failed: %s", err)
+		}
+		if !resp.Result {
+			return 0, fmt.Errorf("create inst failed: %s", resp.ErrMsg)
+		}
+		blog.Infof("create %s inst result: %v", mapping.ObjectID, resp)
+		return int64(resp.Data.Created.ID), nil
+	}
+	if len(insts) > 1 {
+		return 0, fmt.Errorf("identity %v matches %d insts", cond, len(insts))
+	}
+
+	instID, err := insts[0].Int64(bkc.GetInstIDField(mapping.ObjectID))
+	if err != nil {
+		return 0, fmt.Errorf("get inst id failed: %s", err)
+	}
+	diff := mapstr.New()
+	for propertyID, value := range data {
+		if fmt.Sprint(insts[0][propertyID]) != fmt.Sprint(value) {
+			diff[propertyID] = value
+		}
+	}
+	if len(diff) == 0 {
+		return instID, nil
+	}
+
+	input := metadata.UpdateOption{
+		Data:      diff,
+		Condition: mapstr.MapStr{bkc.GetInstIDField(mapping.ObjectID): instID},
+	}
+	resp, err := d.CoreAPI.CoreService().Instance().UpdateInstance(d.ctx, header, mapping.ObjectID, &input)
+	if err != nil {
+		return 0, fmt.Errorf("update inst failed: %s", err)
+	}
+	if !resp.Result {
+		return 0, fmt.Errorf("update inst failed: %s", resp.ErrMsg)
+	}
+	for _, rejected := range resp.Data.Rejected {
+		blog.Infof("fields %v of %s inst %d are set by the sources with higher priority, skip", rejected.Fields, mapping.ObjectID, rejected.InstID)
+	}
+	blog.Infof("update %s inst %d result: %v", mapping.ObjectID, instID, resp)
+	return instID, nil
+}
+
+// createSchemaAsst associates the instance to the instance matched by the asst keys if they are not associated yet
+func (d *Discover) createSchemaAsst(header http.Header, objID string, instID int64, asst discoverAsst) error {
+	asstInsts, err := d.findSchemaInsts(header, asst.mapping.AsstObjectID, asst.keys)
+	if err != nil {
+		return fmt.Errorf("search asst inst failed: %s", err)
+	}
+	if len(asstInsts) != 1 {
+		return fmt.Errorf("asst keys %v matches %d %s insts", asst.keys, len(asstInsts), asst.mapping.AsstObjectID)
+	}
+	asstInstID, err := asstInsts[0].Int64(bkc.GetInstIDField(asst.mapping.AsstObjectID))
+	if err != nil {
+		return fmt.Errorf("get asst inst id failed: %s", err)
+	}
+
+	cond := mapstr.MapStr{
+		bkc.AssociationObjAsstIDField: asst.mapping.ObjectAsstID,
+		bkc.BKInstIDField:             instID,
+		bkc.BKAsstInstIDField:         asstInstID,
+	}
+	existResp, err := d.CoreAPI.CoreService().Association().ReadInstAssociation(d.ctx, header, &metadata.QueryCondition{Condition: cond})
+	if err != nil {
+		return fmt.Errorf("search inst association failed: %s", err)
+	}
+	if !existResp.Result {
+		return fmt.Errorf("search inst association failed: %s", existResp.ErrMsg)
+	}
+	if len(existResp.Data.Info) > 0 {
+		return nil
+	}
+
+	req := metadata.CreateAssociationInstRequest{
+		ObjectAsstID: asst.mapping.ObjectAsstID,
+		InstID:       instID,
+		AsstInstID:   asstInstID,
+	}
+	resp, err := d.CoreAPI.TopoServer().Association().CreateInst(d.ctx, header, &req)
+	if err != nil {
+		return fmt.Errorf("create inst association failed: %s", err)
+	}
+	if !resp.Result {
+		return fmt.Errorf("create inst association failed: %s", resp.ErrMsg)
+	}
+	return nil
+}
+
+// deleteMissingInsts deletes the instances in the scope of a full report but not reported
+func deleteMissingInsts(client schemaClient, header http.Header, ownerID string, mapping metadata.DiscoverMapping, scope mapstr.MapStr, found map[int64]bool) error {
+	if len(scope) != len(mapping.ScopeKeys) {
+		return fmt.Errorf("scope %v is incomplete, scope keys: %v", scope, mapping.ScopeKeys)
+	}
+	insts, err := client.findSchemaInsts(header, mapping.ObjectID, scope)
+	if err != nil {
+		return fmt.Errorf("search insts in scope failed: %s", err)
+	}
+
+	for _, inst := range insts {
+		instID, err := inst.Int64(bkc.GetInstIDField(mapping.ObjectID))
+		if err != nil {
+			return fmt.Errorf("get inst id failed: %s", err)
+		}
+		if found[instID] {
+			continue
+		}
+		if err := client.deleteSchemaInst(header, ownerID, mapping.ObjectID, instID); err != nil {
+			return err
+		}
+		blog.Infof("delete missing %s inst %d in scope %v", mapping.ObjectID, instID, scope)
+	}
+	return nil
+}
+
+func (d *Discover) deleteSchemaInst(header http.Header, ownerID, objID string, instID int64) error {
+	resp, err := d.CoreAPI.TopoServer().Instance().DeleteInst(d.ctx, ownerID, objID, instID, header)
+	if err != nil {
+		return fmt.Errorf("delete inst %d failed: %s", instID, err)
+	}
+	if !resp.Result {
+		return fmt.Errorf("delete inst %d failed: %s", instID, resp.ErrMsg)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+const schemaPayload = `{
+    "host": "192.168.0.1",
+    "cluster": {"name": "mysql-prod"},
+    "instances": [
+        {"port": 3306, "role": "master", "version": "5.7.26"},
+        {"port": 3307, "role": "slave"},
+        {"role": "unknown"}
+    ]
+}`
+
+func mysqlMapping() metadata.DiscoverMapping {
+	return metadata.DiscoverMapping{
+		ObjectID: "bk_mysql",
+		Items:    "instances",
+		Attributes: map[string]string{
+			"bk_inst_name": "role",
+			"bk_port":      "port",
+			"bk_version":   "version",
+			"bk_host_ip":   "$.host",
+		},
+		IdentityKeys: []string{"bk_host_ip", "bk_port"},
+		Associations: []metadata.DiscoverAssociationMapping{
+			{ObjectAsstID: "bk_mysql_belong_bk_mysql_cluster", AsstObjectID: "bk_mysql_cluster", AsstKeys: map[string]string{"bk_inst_name": "$.cluster.name"}},
+		},
+		FullReport: true,
+		ScopeKeys:  []string{"bk_host_ip"},
+		OnMissing:  metadata.DiscoverOnMissingDelete,
+	}
+}
+
+func TestExtractItems(t *testing.T) {
+	payload := gjson.Parse(schemaPayload)
+	tests := []struct {
+		name       string
+		items      string
+		attributes map[string]string
+		wantData   []mapstr.MapStr
+		wantAssts  []int
+		// wantComplete means no item is skipped
+		wantComplete bool
+		wantErr      bool
+	}{
+		{
+			name:  "items array",
+			items: "instances",
+			wantData: []mapstr.MapStr{
+				{"bk_inst_name": "master", "bk_port": float64(3306), "bk_version": "5.7.26", "bk_host_ip": "192.168.0.1"},
+				{"bk_inst_name": "slave", "bk_port": float64(3307), "bk_host_ip": "192.168.0.1"},
+			},
+			wantAssts: []int{1, 1},
+		},
+		{
+			name:       "payload as item",
+			items:      "",
+			attributes: map[string]string{"bk_port": "instances.0.port", "bk_inst_name": "instances.0.role", "bk_version": "instances.0.version"},
+			wantData: []mapstr.MapStr{
+				{"bk_inst_name": "master", "bk_port": float64(3306), "bk_version": "5.7.26", "bk_host_ip": "192.168.0.1"},
+			},
+			wantAssts:    []int{1},
+			wantComplete: true,
+		},
+		{
+			name:     "items path not exist",
+			items:    "clusters",
+			wantData: []mapstr.MapStr{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping := mysqlMapping()
+			mapping.Items = tt.items
+			for propertyID, path := range tt.attributes {
+				mapping.Attributes[propertyID] = path
+			}
+			require.NoError(t, mapping.Validate())
+
+			items, complete, err := extractItems(payload, mapping)
+			require.Equal(t, tt.wantErr, err != nil, "err: %v", err)
+			require.Equal(t, tt.wantComplete, complete)
+			data := make([]mapstr.MapStr, 0)
+			assts := make([]int, 0)
+			for _, item := range items {
+				data = append(data, item.data)
+				assts = append(assts, len(item.assts))
+			}
+			require.Equal(t, tt.wantData, data)
+			if tt.wantAssts != nil {
+				require.Equal(t, tt.wantAssts, assts)
+			}
+		})
+	}
+}
+
+type fakeSchemaClient struct {
+	schema   *metadata.DiscoverSchema
+	existing []mapstr.MapStr
+	failPort float64
+
+	deleted []int64
+}
+
+func (c *fakeSchemaClient) GetSchema(ownerID, source string) (*metadata.DiscoverSchema, error) {
+	return c.schema, nil
+}
+
+func (c *fakeSchemaClient) findSchemaInsts(header http.Header, objID string, cond mapstr.MapStr) ([]mapstr.MapStr, error) {
+	if cond["bk_host_ip"] != "192.168.0.1" {
+		return nil, fmt.Errorf("unexpected scope %v", cond)
+	}
+	return c.existing, nil
+}
+
+func (c *fakeSchemaClient) upsertSchemaInst(header http.Header, mapping metadata.DiscoverMapping, data mapstr.MapStr) (int64, error) {
+	port, _ := data["bk_port"].(float64)
+	if port == c.failPort {
+		return 0, errors.New("create inst failed")
+	}
+	return int64(port), nil
+}
+
+func (c *fakeSchemaClient) createSchemaAsst(header http.Header, objID string, instID int64, asst discoverAsst) error {
+	return nil
+}
+
+func (c *fakeSchemaClient) deleteSchemaInst(header http.Header, ownerID, objID string, instID int64) error {
+	c.deleted = append(c.deleted, instID)
+	return nil
+}
+
+func TestAnalyzeBySchema(t *testing.T) {
+	existing := []mapstr.MapStr{
+		{"bk_inst_id": int64(3306), "bk_host_ip": "192.168.0.1"},
+		{"bk_inst_id": int64(3307), "bk_host_ip": "192.168.0.1"},
+		{"bk_inst_id": int64(3308), "bk_host_ip": "192.168.0.1"},
+	}
+	full := mysqlMapping()
+	keep := mysqlMapping()
+	keep.OnMissing = metadata.DiscoverOnMissingKeep
+	partial := mysqlMapping()
+	partial.FullReport = false
+	partial.OnMissing = ""
+	scoped := mysqlMapping()
+	scoped.Attributes["bk_cluster"] = "$.cluster.name"
+	scoped.ScopeKeys = append(scoped.ScopeKeys, "bk_cluster")
+	// completePayload is schemaPayload without the item lacking identity keys
+	completePayload := `{"host": "192.168.0.1", "instances": [{"port": 3306}, {"port": 3307}]}`
+
+	tests := []struct {
+		name        string
+		mapping     *metadata.DiscoverMapping
+		payload     string
+		failPort    float64
+		wantErr     bool
+		wantDeleted []int64
+	}{
+		{name: "delete missing", mapping: &full, payload: completePayload, wantDeleted: []int64{3308}},
+		{name: "keep missing", mapping: &keep, payload: completePayload},
+		{name: "partial report", mapping: &partial, payload: completePayload},
+		{name: "item failed", mapping: &full, payload: completePayload, failPort: 3307, wantErr: true},
+		{name: "item without identity keys", mapping: &full, payload: schemaPayload},
+		{name: "scope incomplete", mapping: &scoped, payload: completePayload, wantErr: true},
+		{name: "empty full report", mapping: &full, payload: `{"host": "192.168.0.1", "instances": []}`, wantDeleted: []int64{3306, 3307, 3308}},
+		{name: "no items key", mapping: &full, payload: `{"host": "192.168.0.1"}`, wantErr: true},
+		{name: "null items", mapping: &full, payload: `{"host": "192.168.0.1", "instances": null}`},
+		{name: "schema not registered", payload: schemaPayload, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeSchemaClient{existing: existing, failPort: tt.failPort}
+			if tt.mapping != nil {
+				client.schema = &metadata.DiscoverSchema{Source: "mysql", Mappings: []metadata.DiscoverMapping{*tt.mapping}}
+			}
+			msg := fmt.Sprintf(`{"data": {"meta": {"source": "mysql"}, "data": %s}}`, tt.payload)
+			err := analyzeBySchema(client, http.Header{}, "0", "mysql", msg)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantDeleted, client.deleted)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package netcollect
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/datacollection/datacollection/snmp"
+)
+
+// the lldp and cdp table oids, LLDP-MIB and CISCO-CDP-MIB
+const (
+	oidLldpRemChassisIDSubtype = "1.0.8802.1.1.2.1.4.1.1.4"
+	oidLldpRemChassisID        = "1.0.8802.1.1.2.1.4.1.1.5"
+	oidLldpRemPortID           = "1.0.8802.1.1.2.1.4.1.1.7"
+	oidLldpRemSysName          = "1.0.8802.1.1.2.1.4.1.1.9"
+	oidLldpRemManAddrIfSubtype = "1.0.8802.1.1.2.1.4.2.1.3"
+	oidLldpLocPortDesc         = "1.0.8802.1.1.2.1.3.7.1.4"
+
+	oidCdpCacheAddressType = "1.3.6.1.4.1.9.9.23.1.2.1.1.3"
+	oidCdpCacheAddress     = "1.3.6.1.4.1.9.9.23.1.2.1.1.4"
+	oidCdpCacheDeviceID    = "1.3.6.1.4.1.9.9.23.1.2.1.1.6"
+	oidCdpCacheDevicePort  = "1.3.6.1.4.1.9.9.23.1.2.1.1.7"
+	oidIfDescr             = "1.3.6.1.2.1.2.2.1.2"
+
+	// lldpChassisIDSubtypeMac means the chassis id is a mac address
+	lldpChassisIDSubtypeMac = 4
+	// addressTypeIPv4 is the ipv4 address type of both lldp and cdp
+	addressTypeIPv4 = 1
+	// maxNeighborVars limits the rows walked from one table column
+	maxNeighborVars = 1024
+)
+
+// PollNeighbors walks the lldp and cdp neighbor tables of the device, a device usually supports one of them
+func PollNeighbors(client *snmp.Client) ([]metadata.NetcollectNeighbor, error) {
+	neighbors, err := pollLLDPNeighbors(client)
+	if err != nil {
+		return nil, fmt.Errorf("poll lldp neighbors failed: %v", err)
+	}
+	cdpNeighbors, err := pollCDPNeighbors(client)
+	if err != nil {
+		return nil, fmt.Errorf("poll cdp neighbors failed: %v", err)
+	}
+	return append(neighbors, cdpNeighbors...), nil
+}
+
+func pollLLDPNeighbors(client *snmp.Client) ([]metadata.NetcollectNeighbor, error) {
+	chassisIDs, indexes, err := walkColumn(client, oidLldpRemChassisID)
+	if err != nil || len(indexes) == 0 {
+		return nil, err
+	}
+	subtypes, _, err := walkColumn(client, oidLldpRemChassisIDSubtype)
+	if err != nil {
+		return nil, err
+	}
+	portIDs, _, err := walkColumn(client, oidLldpRemPortID)
+	if err != nil {
+		return nil, err
+	}
+	sysNames, _, err := walkColumn(client, oidLldpRemSysName)
+	if err != nil {
+		return nil, err
+	}
+	localPorts, _, err := walkColumn(client, oidLldpLocPortDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	// the management address is a part of the index: timeMark.localPortNum.remIndex.subtype.length.address
+	_, manIndexes, err := walkColumn(client, oidLldpRemManAddrIfSubtype)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make(map[string]string)
+	for _, index := range manIndexes {
+		parts := strings.Split(index, ".")
+		if len(parts) != 9 || parts[3] != fmt.Sprint(addressTypeIPv4) || parts[4] != "4" {
+			continue
+		}
+		remIndex := strings.Join(parts[:3], ".")
+		if _, exist := addresses[remIndex]; !exist {
+			addresses[remIndex] = strings.Join(parts[5:], ".")
+		}
+	}
+
+	neighbors := make([]metadata.NetcollectNeighbor, 0)
+	for _, index := range indexes {
+		parts := strings.Split(index, ".")
+		if len(parts) != 3 {
+			continue
+		}
+
+		neighbor := metadata.NetcollectNeighbor{
+			Protocol:  metadata.NetcollectNeighborLLDP,
+			ChassisID: variableString(chassisIDs[index]),
+			SysName:   variableString(sysNames[index]),
+			PortID:    variableString(portIDs[index]),
+			Address:   addresses[index],
+			LocalPort: variableString(localPorts[parts[1]]),
+		}
+		if raw, ok := chassisIDs[index].Value.([]byte); ok && subtypes[index].Value == int64(lldpChassisIDSubtypeMac) {
+			neighbor.ChassisID = hexString(raw)
+		}
+		if neighbor.LocalPort == "" {
+			neighbor.LocalPort = parts[1]
+		}
+		neighbors = append(neighbors, neighbor)
+	}
+	return neighbors, nil
+}
+
+func pollCDPNeighbors(client *snmp.Client) ([]metadata.NetcollectNeighbor, error) {
+	deviceIDs, indexes, err := walkColumn(client, oidCdpCacheDeviceID)
+	if err != nil || len(indexes) == 0 {
+		return nil, err
+	}
+	ports, _, err := walkColumn(client, oidCdpCacheDevicePort)
+	if err != nil {
+		return nil, err
+	}
+	addressTypes, _, err := walkColumn(client, oidCdpCacheAddressType)
+	if err != nil {
+		return nil, err
+	}
+	addresses, _, err := walkColumn(client, oidCdpCacheAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// the index is ifIndex.deviceIndex, the local port is the ifDescr of the ifIndex
+	ifIndexes := make([]string, 0)
+	exist := make(map[string]bool)
+	for _, index := range indexes {
+		ifIndex := strings.Split(index, ".")[0]
+		if !exist[ifIndex] {
+			exist[ifIndex] = true
+			ifIndexes = append(ifIndexes, oidIfDescr+"."+ifIndex)
+		}
+	}
+	ifDescrs := make(map[string]string)
+	vars, err := client.Get(ifIndexes)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range vars {
+		if !v.Exception() {
+			ifDescrs[strings.TrimPrefix(v.OID, oidIfDescr+".")] = variableString(v)
+		}
+	}
+
+	neighbors := make([]metadata.NetcollectNeighbor, 0)
+	for _, index := range indexes {
+		ifIndex := strings.Split(index, ".")[0]
+		neighbor := metadata.NetcollectNeighbor{
+			Protocol:  metadata.NetcollectNeighborCDP,
+			SysName:   variableString(deviceIDs[index]),
+			PortID:    variableString(ports[index]),
+			LocalPort: ifDescrs[ifIndex],
+		}
+		if raw, ok := addresses[index].Value.([]byte); ok && len(raw) == net.IPv4len && addressTypes[index].Value == int64(addressTypeIPv4) {
+			neighbor.Address = net.IP(raw).String()
+		}
+		if neighbor.LocalPort == "" {
+			neighbor.LocalPort = ifIndex
+		}
+		neighbors = append(neighbors, neighbor)
+	}
+	return neighbors, nil
+}
+
+// walkColumn walks a table column, returns the variables by the index after the column oid, and the indexes in order
+func walkColumn(client *snmp.Client, column string) (map[string]snmp.Variable, []string, error) {
+	vars, err := client.Walk(column, maxNeighborVars)
+	if err != nil {
+		return nil, nil, err
+	}
+	values := make(map[string]snmp.Variable, len(vars))
+	indexes := make([]string, 0, len(vars))
+	for _, v := range vars {
+		index := strings.TrimPrefix(v.OID, column+".")
+		values[index] = v
+		indexes = append(indexes, index)
+	}
+	return values, indexes, nil
+}
+
+func variableString(v snmp.Variable) string {
+	return fmt.Sprint(variableValue(v))
+}
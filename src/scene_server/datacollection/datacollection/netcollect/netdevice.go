@@ -24,17 +24,27 @@ import (
 	"configcenter/src/storage/dal"
 )
 
+// Confirmer confirms the reports by the confirm policies when they arrive
+type Confirmer interface {
+	// ProposeNeighborAssociations adds the connect associations to the neighbors of the report
+	ProposeNeighborAssociations(report *metadata.NetcollectReport) error
+	// AutoConfirmReport removes the confirmed changes from the report, pending is false when nothing is left
+	AutoConfirmReport(report *metadata.NetcollectReport) (pending bool, err error)
+}
+
 // NetCollect collect the net information
 type NetCollect struct {
-	ctx context.Context
-	db  dal.RDB
+	ctx       context.Context
+	db        dal.RDB
+	confirmer Confirmer
 }
 
-// NewNetCollect returns a new netcollector
-func NewNetCollect(ctx context.Context, db dal.RDB) *NetCollect {
+// NewNetCollect returns a new netcollector, the confirmer is optional
+func NewNetCollect(ctx context.Context, db dal.RDB, confirmer Confirmer) *NetCollect {
 	h := &NetCollect{
-		ctx: ctx,
-		db:  db,
+		ctx:       ctx,
+		db:        db,
+		confirmer: confirmer,
 	}
 	return h
 }
@@ -57,6 +67,21 @@ func (h *NetCollect) Analyze(raw string) error {
 }
 
 func (h *NetCollect) handleReport(report *metadata.NetcollectReport) (err error) {
+	if h.confirmer != nil {
+		// the neighbors are resolved once and the proposals are saved with the report,
+		// a failure should not hide the collected attributes
+		if err := h.confirmer.ProposeNeighborAssociations(report); err != nil {
+			blog.Warnf("[data-collection][netcollect] propose neighbor associations failed: %v", err)
+		}
+		pending, err := h.confirmer.AutoConfirmReport(report)
+		if err != nil {
+			blog.Warnf("[data-collection][netcollect] auto confirm report failed, left to manual review: %v", err)
+		}
+		if !pending {
+			return h.deleteReport(report)
+		}
+	}
+
 	// TODO compare 若有变化才插入
 	if err = h.upsertReport(report); err != nil {
 		blog.Errorf("[data-collection][netcollect] upsert association error: %v", err)
@@ -85,6 +110,16 @@ func (h *NetCollect) upsertReport(report *metadata.NetcollectReport) error {
 	return h.db.Table(common.BKTableNameNetcollectReport).Update(h.ctx, existFilter, report)
 }
 
+// deleteReport deletes the report confirmed before, which is replaced by the newly confirmed one
+func (h *NetCollect) deleteReport(report *metadata.NetcollectReport) error {
+	filter := map[string]interface{}{
+		common.BKCloudIDField: report.CloudID,
+		common.BKObjIDField:   report.ObjectID,
+		common.BKInstKeyField: report.InstKey,
+	}
+	return h.db.Table(common.BKTableNameNetcollectReport).Delete(h.ctx, filter)
+}
+
 // ReportMessage define a netcollect message
 type ReportMessage struct {
 	Timestamp time.Time                   `json:"timestamp"`
@@ -133,7 +168,17 @@ const MockMessage = `{
                     "bk_asst_obj_name": "主机",
                     "bk_asst_property_id": "bk_host_id"
 				}
-			]
+			],
+            "neighbors": [
+                {
+                    "protocol": "lldp",
+                    "local_port": "GigabitEthernet0/0/1",
+                    "chassis_id": "56:79:9a:00:00:01",
+                    "sys_name": "huawei 5789#56-79-9a-jj",
+                    "port_id": "GigabitEthernet0/0/24",
+                    "address": "192.168.1.2"
+                }
+            ]
         }
     ]
 }
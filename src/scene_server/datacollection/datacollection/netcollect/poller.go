@@ -0,0 +1,518 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package netcollect
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"configcenter/src/common"
+	"configcenter/src/common/backbone"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/scene_server/datacollection/datacollection/snmp"
+	"configcenter/src/storage/dal"
+)
+
+// the system group oids used to identify the device model, rfc3418
+const (
+	oidSysDescr    = "1.3.6.1.2.1.1.1.0"
+	oidSysObjectID = "1.3.6.1.2.1.1.2.0"
+	oidSysName     = "1.3.6.1.2.1.1.5.0"
+)
+
+const (
+	// maxScanTargets limits the targets of one collector, a /20 network at most
+	maxScanTargets = 4096
+	// defaultPollWorkers is the number of targets polled at the same time
+	defaultPollWorkers = 10
+)
+
+// SnmpPoller polls the network devices by snmp itself, and saves the results as net collect
+// reports, so that the network devices can be collected without netdevicebeat.
+type SnmpPoller struct {
+	ctx      context.Context
+	db       dal.RDB
+	engine   *backbone.Engine
+	collect  *NetCollect
+	interval time.Duration
+	workers  int
+
+	// polled records the last poll of every collector
+	polled map[string]pollRecord
+}
+
+type pollRecord struct {
+	config metadata.NetcollectConfig
+	ts     time.Time
+}
+
+// NewSnmpPoller returns a new snmp poller, which checks the collectors every interval
+func NewSnmpPoller(ctx context.Context, db dal.RDB, engine *backbone.Engine, confirmer Confirmer, interval time.Duration, workers int) *SnmpPoller {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if workers <= 0 {
+		workers = defaultPollWorkers
+	}
+	return &SnmpPoller{
+		ctx:      ctx,
+		db:       db,
+		engine:   engine,
+		collect:  NewNetCollect(ctx, db, confirmer),
+		interval: interval,
+		workers:  workers,
+		polled:   make(map[string]pollRecord),
+	}
+}
+
+// Name implements the Porter interface
+func (p *SnmpPoller) Name() string {
+	return "snmppoller"
+}
+
+// Mock polls the collector in the message immediately, the message is a json of metadata.Netcollector
+func (p *SnmpPoller) Mock(mesg string) error {
+	collector := metadata.Netcollector{}
+	if err := json.Unmarshal([]byte(mesg), &collector); err != nil {
+		return fmt.Errorf("unmarshal message error: %v, raw: %s", err, mesg)
+	}
+	_, err := p.pollCollector(&collector)
+	return err
+}
+
+// Run implements the Porter interface, only the master polls the devices
+func (p *SnmpPoller) Run() error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		if p.engine.ServiceManageInterface.IsMaster() {
+			p.schedule()
+		}
+		<-ticker.C
+	}
+}
+
+// schedule polls the collectors whose period is reached or whose config is changed
+func (p *SnmpPoller) schedule() {
+	collectors := make([]metadata.Netcollector, 0)
+	if err := p.db.Table(common.BKTableNameNetcollectConfig).Find(nil).All(p.ctx, &collectors); err != nil {
+		blog.Errorf("[data-collection][snmppoller] find collectors failed: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for index := range collectors {
+		collector := &collectors[index]
+		key := fmt.Sprintf("%d:%s", collector.CloudID, collector.InnerIP)
+		period := parsePeriod(collector.Config.Period)
+		record, ok := p.polled[key]
+		// an infinite period means polling only once for each config
+		if ok && reflect.DeepEqual(record.config, collector.Config) && (period <= 0 || now.Sub(record.ts) < period) {
+			continue
+		}
+		p.polled[key] = pollRecord{config: collector.Config, ts: now}
+
+		total, err := p.pollCollector(collector)
+		if err != nil {
+			blog.Errorf("[data-collection][snmppoller] poll collector %s failed: %v", key, err)
+		}
+		if err := p.saveCollectorStatus(collector, total, err); err != nil {
+			blog.Errorf("[data-collection][snmppoller] save collector %s status failed: %v", key, err)
+		}
+	}
+}
+
+func (p *SnmpPoller) saveCollectorStatus(collector *metadata.Netcollector, total int64, pollErr error) error {
+	status := metadata.CollectorReportStatusNormal
+	if pollErr != nil {
+		status = metadata.CollectorReportStatusAbnormal
+	}
+	filter := map[string]interface{}{
+		common.BKCloudIDField:     collector.CloudID,
+		common.BKHostInnerIPField: collector.InnerIP,
+	}
+	data := map[string]interface{}{
+		"report_total":         total,
+		"status.report_status": status,
+	}
+	return p.db.Table(common.BKTableNameNetcollectConfig).Update(p.ctx, filter, data)
+}
+
+// pollCollector polls all the targets in the scan range of the collector, returns the number of the reports
+func (p *SnmpPoller) pollCollector(collector *metadata.Netcollector) (int64, error) {
+	targets, err := ExpandScanRange(collector.Config.ScanRange)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := NewSnmpClient(&collector.Config, ""); err != nil {
+		return 0, err
+	}
+
+	devices := make([]metadata.NetcollectDevice, 0)
+	if err := p.db.Table(common.BKTableNameNetcollectDevice).Find(nil).All(p.ctx, &devices); err != nil {
+		return 0, err
+	}
+	properties := make([]metadata.NetcollectProperty, 0)
+	if err := p.db.Table(common.BKTableNameNetcollectProperty).Find(nil).All(p.ctx, &properties); err != nil {
+		return 0, err
+	}
+	deviceProperties := make(map[uint64][]metadata.NetcollectProperty)
+	for _, property := range properties {
+		deviceProperties[property.DeviceID] = append(deviceProperties[property.DeviceID], property)
+	}
+
+	var total int64
+	targetC := make(chan string)
+	wg := sync.WaitGroup{}
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range targetC {
+				report, err := PollTarget(collector, target, devices, deviceProperties)
+				if err != nil {
+					blog.V(4).Infof("[data-collection][snmppoller] poll %s failed: %v", target, err)
+					continue
+				}
+				if report == nil {
+					continue
+				}
+				if err := p.collect.handleReport(report); err != nil {
+					continue
+				}
+				atomic.AddInt64(&total, 1)
+			}
+		}()
+	}
+	for _, target := range targets {
+		targetC <- target
+	}
+	close(targetC)
+	wg.Wait()
+
+	blog.Infof("[data-collection][snmppoller] polled %d targets of collector %d:%s, got %d reports",
+		len(targets), collector.CloudID, collector.InnerIP, total)
+	return total, nil
+}
+
+// NewSnmpClient builds a snmp client of the target by the collector config
+func NewSnmpClient(config *metadata.NetcollectConfig, target string) (*snmp.Client, error) {
+	client := &snmp.Client{
+		Target:    target,
+		Port:      config.Snmp.Port,
+		Community: config.Community,
+		Timeout:   time.Duration(config.Snmp.Timeout) * time.Second,
+		Retries:   config.Snmp.Retries,
+	}
+	if config.Snmp.Retries == 0 {
+		client.Retries = snmp.DefaultRetries
+	}
+
+	switch config.Snmp.Version {
+	case "", metadata.NetcollectSnmpV2c:
+		client.Version = snmp.Version2c
+	case metadata.NetcollectSnmpV3:
+		client.Version = snmp.Version3
+		client.Security = &snmp.UsmSecurity{
+			UserName:       config.Snmp.UserName,
+			SecurityLevel:  snmp.SecurityLevel(config.Snmp.SecurityLevel),
+			AuthProtocol:   snmp.AuthProtocol(strings.ToUpper(config.Snmp.AuthProtocol)),
+			AuthPassphrase: config.Snmp.AuthPassword,
+			PrivProtocol:   snmp.PrivProtocol(strings.ToUpper(config.Snmp.PrivProtocol)),
+			PrivPassphrase: config.Snmp.PrivPassword,
+		}
+		if err := client.Security.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid snmp v3 config: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported snmp version %s", config.Snmp.Version)
+	}
+	return client, nil
+}
+
+// PollTarget identifies the device model of the target, and polls the properties of the model,
+// returns nil report if the target does not respond or matches no device model.
+func PollTarget(collector *metadata.Netcollector, target string, devices []metadata.NetcollectDevice,
+	deviceProperties map[uint64][]metadata.NetcollectProperty) (*metadata.NetcollectReport, error) {
+
+	client, err := NewSnmpClient(&collector.Config, target)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Connect(); err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	system, err := client.Get([]string{oidSysDescr, oidSysObjectID, oidSysName})
+	if err != nil {
+		if err == snmp.ErrTimeout {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sysDescr := fmt.Sprint(variableValue(system[0]))
+	sysName := fmt.Sprint(variableValue(system[2]))
+	device := matchDevice(sysDescr, devices)
+	if device == nil {
+		blog.V(4).Infof("[data-collection][snmppoller] %s matches no device, sysDescr: %s", target, sysDescr)
+		return nil, nil
+	}
+
+	getOIDs := make([]string, 0)
+	getProperties := make([]metadata.NetcollectProperty, 0)
+	nextOIDs := make([]string, 0)
+	nextProperties := make([]metadata.NetcollectProperty, 0)
+	for _, property := range deviceProperties[device.DeviceID] {
+		if property.OID == "" {
+			continue
+		}
+		if property.Action == common.SNMPActionGetNext {
+			nextOIDs = append(nextOIDs, snmp.NormalizeOID(property.OID))
+			nextProperties = append(nextProperties, property)
+		} else {
+			getOIDs = append(getOIDs, snmp.NormalizeOID(property.OID))
+			getProperties = append(getProperties, property)
+		}
+	}
+
+	attributes := make([]metadata.NetcollectReportAttribute, 0)
+	if len(getOIDs) > 0 {
+		vars, err := client.Get(getOIDs)
+		if err != nil {
+			return nil, err
+		}
+		for index, v := range vars {
+			if v.Exception() {
+				continue
+			}
+			attributes = append(attributes, metadata.NetcollectReportAttribute{
+				PropertyID: getProperties[index].PropertyID,
+				CurValue:   variableValue(v),
+			})
+		}
+	}
+	if len(nextOIDs) > 0 {
+		vars, err := client.GetNext(nextOIDs)
+		if err != nil {
+			return nil, err
+		}
+		for index, v := range vars {
+			// the successor out of the subtree means the table is empty
+			if v.Exception() || !strings.HasPrefix(v.OID, nextOIDs[index]+".") {
+				continue
+			}
+			attributes = append(attributes, metadata.NetcollectReportAttribute{
+				PropertyID: nextProperties[index].PropertyID,
+				CurValue:   variableValue(v),
+			})
+		}
+	}
+
+	neighbors, err := PollNeighbors(client)
+	if err != nil {
+		// the neighbors are optional, the attributes are still worth reporting
+		blog.Warnf("[data-collection][snmppoller] poll neighbors of %s failed: %v", target, err)
+	}
+
+	report := &metadata.NetcollectReport{
+		Action:       metadata.ReporctActionCreate,
+		CloudID:      collector.CloudID,
+		ObjectID:     device.ObjectID,
+		InnerIP:      target,
+		OwnerID:      device.OwnerID,
+		LastTime:     metadata.Now(),
+		Attributes:   attributes,
+		Associations: make([]metadata.NetcollectReportAssociation, 0),
+		Neighbors:    neighbors,
+	}
+
+	// the report is confirmed to the instance whose name is the inst key
+	keyField := common.GetInstNameField(device.ObjectID)
+	if common.GetObjByType(device.ObjectID) == common.BKInnerObjIDHost {
+		keyField = common.BKHostInnerIPField
+	}
+	for _, attribute := range attributes {
+		if attribute.PropertyID == keyField {
+			report.InstKey = fmt.Sprint(attribute.CurValue)
+		}
+	}
+	if report.InstKey == "" {
+		switch {
+		case keyField == common.BKHostInnerIPField || sysName == "":
+			report.InstKey = target
+		default:
+			report.InstKey = sysName
+		}
+		report.Attributes = append(report.Attributes, metadata.NetcollectReportAttribute{
+			PropertyID: keyField,
+			CurValue:   report.InstKey,
+		})
+	}
+	return report, nil
+}
+
+// matchDevice returns the device whose model appears in the sysDescr, the longest model wins,
+// and the one whose vendor appears as well is preferred.
+func matchDevice(sysDescr string, devices []metadata.NetcollectDevice) *metadata.NetcollectDevice {
+	descr := strings.ToLower(sysDescr)
+	var matched *metadata.NetcollectDevice
+	matchedScore := 0
+	for index := range devices {
+		device := &devices[index]
+		model := strings.ToLower(strings.TrimSpace(device.DeviceModel))
+		if model == "" || !strings.Contains(descr, model) {
+			continue
+		}
+		score := len(model) * 2
+		if vendor := strings.ToLower(strings.TrimSpace(device.BkVendor)); vendor != "" && strings.Contains(descr, vendor) {
+			score++
+		}
+		if score > matchedScore {
+			matched, matchedScore = device, score
+		}
+	}
+	return matched
+}
+
+// variableValue converts the snmp value to the value of a report attribute,
+// the octet strings are converted to text if printable, otherwise to hex like a mac address.
+func variableValue(v snmp.Variable) interface{} {
+	switch value := v.Value.(type) {
+	case []byte:
+		text := strings.TrimRight(string(value), "\x00")
+		if utf8.ValidString(text) && strings.IndexFunc(text, func(r rune) bool {
+			return !unicode.IsPrint(r) && !unicode.IsSpace(r)
+		}) < 0 {
+			return text
+		}
+		return hexString(value)
+	case uint64:
+		if value <= 1<<63-1 {
+			return int64(value)
+		}
+		return strconv.FormatUint(value, 10)
+	case nil:
+		return ""
+	}
+	return v.Value
+}
+
+// hexString formats the bytes like a mac address, 00:1b:21:3c:4d:5e
+func hexString(value []byte) string {
+	hex := make([]string, len(value))
+	for i, b := range value {
+		hex[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(hex, ":")
+}
+
+// parsePeriod converts the collect period like 30S, 5M, 1H, 1D to duration, 0 means infinite
+func parsePeriod(period string) time.Duration {
+	period, err := util.FormatPeriod(period)
+	if err != nil || period == common.Infinite {
+		return 0
+	}
+	num, err := strconv.Atoi(period[:len(period)-1])
+	if err != nil {
+		return 0
+	}
+	unit := map[byte]time.Duration{'S': time.Second, 'M': time.Minute, 'H': time.Hour, 'D': 24 * time.Hour}
+	return time.Duration(num) * unit[period[len(period)-1]]
+}
+
+// ExpandScanRange expands the scan range to ipv4 addresses, the range item is one
+// of single ip "10.0.0.1", cidr "10.0.0.0/24" and ip range "10.0.0.1-10.0.0.20".
+func ExpandScanRange(scanRange []string) ([]string, error) {
+	targets := make([]string, 0)
+	exist := make(map[uint32]bool)
+	add := func(ip uint32) error {
+		if exist[ip] {
+			return nil
+		}
+		if len(targets) >= maxScanTargets {
+			return fmt.Errorf("scan range has more than %d targets", maxScanTargets)
+		}
+		exist[ip] = true
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, ip)
+		targets = append(targets, net.IP(buf).String())
+		return nil
+	}
+
+	for _, item := range scanRange {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		var start, end uint32
+		switch {
+		case strings.Contains(item, "/"):
+			ip, ipNet, err := net.ParseCIDR(item)
+			if err != nil || ip.To4() == nil {
+				return nil, fmt.Errorf("invalid scan range %s", item)
+			}
+			ones, bits := ipNet.Mask.Size()
+			start = binary.BigEndian.Uint32(ipNet.IP.To4())
+			end = start | (1<<uint(bits-ones) - 1)
+			// skip the network and broadcast address
+			if bits-ones >= 2 {
+				start, end = start+1, end-1
+			}
+		case strings.Contains(item, "-"):
+			parts := strings.SplitN(item, "-", 2)
+			from, to := net.ParseIP(strings.TrimSpace(parts[0])).To4(), net.ParseIP(strings.TrimSpace(parts[1])).To4()
+			if from == nil || to == nil {
+				return nil, fmt.Errorf("invalid scan range %s", item)
+			}
+			start, end = binary.BigEndian.Uint32(from), binary.BigEndian.Uint32(to)
+		default:
+			ip := net.ParseIP(item).To4()
+			if ip == nil {
+				return nil, fmt.Errorf("invalid scan range %s", item)
+			}
+			start = binary.BigEndian.Uint32(ip)
+			end = start
+		}
+		if start > end {
+			return nil, fmt.Errorf("invalid scan range %s", item)
+		}
+		if end-start >= maxScanTargets {
+			return nil, fmt.Errorf("scan range %s has more than %d targets", item, maxScanTargets)
+		}
+
+		for ip := start; ; ip++ {
+			if err := add(ip); err != nil {
+				return nil, err
+			}
+			if ip == end {
+				break
+			}
+		}
+	}
+	return targets, nil
+}
@@ -0,0 +1,122 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package netcollect
+
+import (
+	"reflect"
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/datacollection/datacollection/snmp"
+)
+
+func TestExpandScanRange(t *testing.T) {
+	targets, err := ExpandScanRange([]string{"10.0.0.1", "10.0.0.0/30", " 10.0.0.2-10.0.0.4 "})
+	if err != nil {
+		t.Fatalf("ExpandScanRange() failed, err: %v", err)
+	}
+	if want := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}; !reflect.DeepEqual(targets, want) {
+		t.Errorf("ExpandScanRange() got %v, want %v", targets, want)
+	}
+
+	for _, scanRange := range []string{"10.0.0.0/8", "10.0.0.9-10.0.0.1", "10.0.0", "::1"} {
+		if _, err := ExpandScanRange([]string{scanRange}); err == nil {
+			t.Errorf("ExpandScanRange(%s) should fail", scanRange)
+		}
+	}
+}
+
+func TestPollTarget(t *testing.T) {
+	agent, err := snmp.NewAgent("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("NewAgent() failed, err: %v", err)
+	}
+	defer agent.Close()
+	agent.Set(
+		snmp.Variable{OID: oidSysDescr, Type: snmp.OctetString, Value: "Huawei Versatile Routing Platform Software, Quidway S5700-28C-EI"},
+		snmp.Variable{OID: oidSysName, Type: snmp.OctetString, Value: "core-switch-01"},
+		snmp.Variable{OID: "1.3.6.1.2.1.2.1.0", Type: snmp.Integer, Value: int64(28)},
+		snmp.Variable{OID: "1.3.6.1.2.1.2.2.1.6.1", Type: snmp.OctetString, Value: []byte{0x00, 0x1b, 0x21, 0x3c, 0x4d, 0x5e}},
+		// a lldp neighbor on local port 1, and a cdp neighbor on ifIndex 3
+		snmp.Variable{OID: oidLldpRemChassisIDSubtype + ".0.1.1", Type: snmp.Integer, Value: int64(lldpChassisIDSubtypeMac)},
+		snmp.Variable{OID: oidLldpRemChassisID + ".0.1.1", Type: snmp.OctetString, Value: []byte{0x00, 0x1b, 0x21, 0x3c, 0x4d, 0x60}},
+		snmp.Variable{OID: oidLldpRemPortID + ".0.1.1", Type: snmp.OctetString, Value: "GigabitEthernet0/0/24"},
+		snmp.Variable{OID: oidLldpRemSysName + ".0.1.1", Type: snmp.OctetString, Value: "access-switch-02"},
+		snmp.Variable{OID: oidLldpRemManAddrIfSubtype + ".0.1.1.1.4.10.0.0.2", Type: snmp.Integer, Value: int64(2)},
+		snmp.Variable{OID: oidLldpLocPortDesc + ".1", Type: snmp.OctetString, Value: "GigabitEthernet0/0/1"},
+		snmp.Variable{OID: oidCdpCacheAddressType + ".3.1", Type: snmp.Integer, Value: int64(addressTypeIPv4)},
+		snmp.Variable{OID: oidCdpCacheAddress + ".3.1", Type: snmp.OctetString, Value: []byte{10, 0, 0, 3}},
+		snmp.Variable{OID: oidCdpCacheDeviceID + ".3.1", Type: snmp.OctetString, Value: "router-01"},
+		snmp.Variable{OID: oidCdpCacheDevicePort + ".3.1", Type: snmp.OctetString, Value: "FastEthernet0/0"},
+		snmp.Variable{OID: oidIfDescr + ".3", Type: snmp.OctetString, Value: "GigabitEthernet0/0/3"},
+	)
+	security := snmp.UsmSecurity{UserName: "cmdb", SecurityLevel: snmp.AuthPriv, AuthProtocol: snmp.SHA,
+		AuthPassphrase: "authpassword", PrivProtocol: snmp.AES, PrivPassphrase: "privpassword"}
+	agent.Security = &security
+	go agent.Serve()
+
+	devices := []metadata.NetcollectDevice{
+		{DeviceID: 1, DeviceModel: "S5700", BkVendor: "huawei", ObjectID: "bk_switch"},
+		{DeviceID: 2, DeviceModel: "S5700-28C", ObjectID: "bk_switch"},
+		{DeviceID: 3, DeviceModel: "Catalyst", BkVendor: "cisco", ObjectID: "bk_switch"},
+	}
+	properties := map[uint64][]metadata.NetcollectProperty{
+		2: {
+			{PropertyID: "port_count", OID: ".1.3.6.1.2.1.2.1.0", Action: common.SNMPActionGet},
+			{PropertyID: "mac", OID: "1.3.6.1.2.1.2.2.1.6", Action: common.SNMPActionGetNext},
+			{PropertyID: "serial", OID: "1.3.6.1.4.1.2011.5.25.31.1.1.1.1.11.1", Action: common.SNMPActionGet},
+		},
+	}
+
+	for _, config := range []metadata.NetcollectConfig{
+		{Community: "public", Snmp: metadata.NetcollectSnmpConfig{Port: agent.Addr().Port}},
+		{Snmp: metadata.NetcollectSnmpConfig{Version: metadata.NetcollectSnmpV3, Port: agent.Addr().Port, UserName: "cmdb",
+			SecurityLevel: "authPriv", AuthProtocol: "sha", AuthPassword: "authpassword", PrivProtocol: "aes", PrivPassword: "privpassword"}},
+	} {
+		collector := &metadata.Netcollector{CloudID: 1, Config: config}
+		report, err := PollTarget(collector, "127.0.0.1", devices, properties)
+		if err != nil {
+			t.Fatalf("PollTarget() with snmp %s failed, err: %v", config.Snmp.Version, err)
+		}
+		if report == nil {
+			t.Fatalf("PollTarget() with snmp %s matches no device", config.Snmp.Version)
+		}
+		if report.ObjectID != "bk_switch" || report.InstKey != "core-switch-01" || report.CloudID != 1 || report.InnerIP != "127.0.0.1" {
+			t.Errorf("PollTarget() got report %+v", report)
+		}
+		want := []metadata.NetcollectReportAttribute{
+			{PropertyID: "port_count", CurValue: int64(28)},
+			{PropertyID: "mac", CurValue: "00:1b:21:3c:4d:5e"},
+			{PropertyID: common.BKInstNameField, CurValue: "core-switch-01"},
+		}
+		if !reflect.DeepEqual(report.Attributes, want) {
+			t.Errorf("PollTarget() got attributes %+v, want %+v", report.Attributes, want)
+		}
+		neighbors := []metadata.NetcollectNeighbor{
+			{Protocol: metadata.NetcollectNeighborLLDP, LocalPort: "GigabitEthernet0/0/1", ChassisID: "00:1b:21:3c:4d:60",
+				SysName: "access-switch-02", PortID: "GigabitEthernet0/0/24", Address: "10.0.0.2"},
+			{Protocol: metadata.NetcollectNeighborCDP, LocalPort: "GigabitEthernet0/0/3", SysName: "router-01",
+				PortID: "FastEthernet0/0", Address: "10.0.0.3"},
+		}
+		if !reflect.DeepEqual(report.Neighbors, neighbors) {
+			t.Errorf("PollTarget() got neighbors %+v, want %+v", report.Neighbors, neighbors)
+		}
+	}
+
+	collector := &metadata.Netcollector{Config: metadata.NetcollectConfig{Community: "public", Snmp: metadata.NetcollectSnmpConfig{Port: agent.Addr().Port}}}
+	report, err := PollTarget(collector, "127.0.0.1", devices[2:], properties)
+	if err != nil || report != nil {
+		t.Errorf("PollTarget() without matched device got %+v, err: %v", report, err)
+	}
+}
@@ -79,6 +79,19 @@ func BuildChanPorter(name string, analyzer Analyzer, redisCli, snapCli *redis.Cl
 		func() float64 { return float64(len(porter.slaveC)) },
 	))
 
+	registry.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: ns + "last_message_timestamp_seconds",
+			Help: "unix timestamp of the last received message.",
+		},
+		func() float64 {
+			if porter.lastMesgTs.IsZero() {
+				return 0
+			}
+			return float64(porter.lastMesgTs.Unix())
+		},
+	))
+
 	porter.analyseDuration = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Name: ns + "analyze_duration",
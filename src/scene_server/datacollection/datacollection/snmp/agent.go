@@ -0,0 +1,236 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snmp
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+const (
+	// maxMessageSize is the maximum payload of a udp packet
+	maxMessageSize = 65507
+	// usmStatsUnknownEngineIDs is the oid carried by the report pdu of the discovery, rfc3414 4
+	usmStatsUnknownEngineIDs = "1.3.6.1.6.3.15.1.1.4.0"
+)
+
+// Agent is a minimal snmp v2c/v3 agent answering get and getnext requests from a static mib by gosnmp.
+// It simulates a network device, so that the pollers can be tested without real devices.
+type Agent struct {
+	Community string
+	EngineID  []byte
+	// Security is the snmp v3 user, the snmp v3 requests are dropped if it is nil
+	Security *UsmSecurity
+	// MaxVariables truncates the answers to simulate a broken device, 0 means no limit
+	MaxVariables int
+
+	conn    *net.UDPConn
+	started time.Time
+
+	lock sync.RWMutex
+	mib  map[string]Variable
+	oids []string
+
+	// unknownEngineIDs counts the discovery requests
+	unknownEngineIDs uint32
+}
+
+// NewAgent listens on the udp address, use "127.0.0.1:0" to pick a free port
+func NewAgent(address string) (*Agent, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Agent{
+		Community: "public",
+		EngineID:  []byte("\x80\x00\x1f\x88\x04cmdb-snmp-agent"),
+		conn:      conn,
+		started:   time.Now(),
+		mib:       make(map[string]Variable),
+	}, nil
+}
+
+// Addr returns the address the agent listening on
+func (a *Agent) Addr() *net.UDPAddr {
+	return a.conn.LocalAddr().(*net.UDPAddr)
+}
+
+// Set adds or replaces the variables of the mib
+func (a *Agent) Set(vars ...Variable) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for _, v := range vars {
+		v.OID = NormalizeOID(v.OID)
+		if _, exist := a.mib[v.OID]; !exist {
+			a.oids = append(a.oids, v.OID)
+		}
+		a.mib[v.OID] = v
+	}
+	sort.Slice(a.oids, func(i, j int) bool {
+		return CompareOID(a.oids[i], a.oids[j]) < 0
+	})
+}
+
+// Serve answers the requests until the agent is closed
+func (a *Agent) Serve() error {
+	buf := make([]byte, maxMessageSize)
+	for {
+		n, addr, err := a.conn.ReadFromUDP(buf)
+		if err != nil {
+			return err
+		}
+		resp, err := a.handle(append([]byte{}, buf[:n]...))
+		if err != nil || resp == nil {
+			// a real device drops the requests it can not handle silently as well
+			continue
+		}
+		a.conn.WriteToUDP(resp, addr)
+	}
+}
+
+// Close stops the agent
+func (a *Agent) Close() error {
+	return a.conn.Close()
+}
+
+func (a *Agent) handle(packet []byte) (resp []byte, err error) {
+	// gosnmp panics on the messages it can not decrypt, such as the ones of an unknown privacy protocol
+	defer func() {
+		if r := recover(); r != nil {
+			resp, err = nil, fmt.Errorf("invalid request: %v", r)
+		}
+	}()
+
+	decoder := &gosnmp.GoSNMP{Version: Version2c}
+	if a.Security != nil {
+		decoder.Version = Version3
+		decoder.SecurityModel = gosnmp.UserSecurityModel
+		decoder.MsgFlags, decoder.SecurityParameters = a.Security.parameters()
+	}
+	// the trap decoder authenticates and decrypts the message by the parameters of the agent
+	req := decoder.UnmarshalTrap(packet, true)
+	if req == nil {
+		return nil, errors.New("invalid request")
+	}
+	if req.PDUType != gosnmp.GetRequest && req.PDUType != gosnmp.GetNextRequest {
+		return nil, errors.New("unsupported request")
+	}
+
+	response := &gosnmp.SnmpPacket{Version: req.Version, PDUType: gosnmp.GetResponse, RequestID: req.RequestID}
+	if req.Version == Version2c {
+		if req.Community != a.Community {
+			return nil, errors.New("wrong community")
+		}
+		response.Community = req.Community
+		if response.Variables, err = a.answer(req); err != nil {
+			return nil, err
+		}
+		return response.MarshalMsg()
+	}
+
+	usm, ok := req.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	if req.Version != Version3 || !ok {
+		return nil, errors.New("unsupported snmp version")
+	}
+	security := &gosnmp.UsmSecurityParameters{
+		AuthoritativeEngineID:    string(a.EngineID),
+		AuthoritativeEngineBoots: 1,
+		AuthoritativeEngineTime:  uint32(time.Since(a.started) / time.Second),
+	}
+	response.MsgID = req.MsgID
+	response.SecurityModel = gosnmp.UserSecurityModel
+	response.SecurityParameters = security
+	response.ContextEngineID = string(a.EngineID)
+
+	// report the engine to the discovery request, rfc3414 4
+	if usm.AuthoritativeEngineID != string(a.EngineID) {
+		response.PDUType = gosnmp.Report
+		response.Variables = []gosnmp.SnmpPDU{{Name: usmStatsUnknownEngineIDs, Type: Counter32,
+			Value: atomic.AddUint32(&a.unknownEngineIDs, 1)}}
+		return response.MarshalMsg()
+	}
+
+	flags, _ := a.Security.parameters()
+	if usm.UserName != a.Security.UserName || req.MsgFlags&gosnmp.AuthPriv != flags {
+		return nil, fmt.Errorf("unknown user %s of security flags %d", usm.UserName, req.MsgFlags)
+	}
+	response.MsgFlags = flags
+	security.UserName = usm.UserName
+	security.AuthenticationProtocol = usm.AuthenticationProtocol
+	security.PrivacyProtocol = usm.PrivacyProtocol
+	security.SecretKey = usm.SecretKey
+	security.PrivacyKey = usm.PrivacyKey
+	if flags == gosnmp.AuthPriv {
+		security.PrivacyParameters = make([]byte, 8)
+		if _, err := rand.Read(security.PrivacyParameters); err != nil {
+			return nil, err
+		}
+	}
+	if response.Variables, err = a.answer(req); err != nil {
+		return nil, err
+	}
+	return response.MarshalMsg()
+}
+
+func (a *Agent) answer(req *gosnmp.SnmpPacket) ([]gosnmp.SnmpPDU, error) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	vars := make([]Variable, 0, len(req.Variables))
+	for _, pdu := range req.Variables {
+		oid := NormalizeOID(pdu.Name)
+		if req.PDUType == gosnmp.GetRequest {
+			if value, ok := a.mib[oid]; ok {
+				vars = append(vars, value)
+			} else {
+				vars = append(vars, Variable{OID: oid, Type: NoSuchInstance})
+			}
+			continue
+		}
+
+		index := sort.Search(len(a.oids), func(i int) bool {
+			return CompareOID(a.oids[i], oid) > 0
+		})
+		if index < len(a.oids) {
+			vars = append(vars, a.mib[a.oids[index]])
+		} else {
+			vars = append(vars, Variable{OID: oid, Type: EndOfMibView})
+		}
+	}
+	if a.MaxVariables > 0 && len(vars) > a.MaxVariables {
+		vars = vars[:a.MaxVariables]
+	}
+
+	pdus := make([]gosnmp.SnmpPDU, 0, len(vars))
+	for index := range vars {
+		pdu, err := vars[index].pdu()
+		if err != nil {
+			return nil, err
+		}
+		pdus = append(pdus, pdu)
+	}
+	return pdus, nil
+}
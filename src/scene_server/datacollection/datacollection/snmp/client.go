@@ -0,0 +1,195 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snmp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// Version is the snmp protocol version on the wire
+type Version = gosnmp.SnmpVersion
+
+// the supported snmp versions
+const (
+	Version2c = gosnmp.Version2c
+	Version3  = gosnmp.Version3
+)
+
+// the default client settings, the same as the netdevicebeat defaults
+const (
+	DefaultPort    = 161
+	DefaultTimeout = 3 * time.Second
+	DefaultRetries = 1
+	DefaultMaxOids = 10
+)
+
+// ErrTimeout is returned when the target does not respond in time
+var ErrTimeout = errors.New("request timeout")
+
+// Client is a snmp v2c/v3 manager which sends get and getnext requests to one target by gosnmp.
+// It is not safe to share one client between goroutines.
+type Client struct {
+	Target    string
+	Port      int
+	Version   Version
+	Community string
+	// Security is required by snmp v3
+	Security *UsmSecurity
+	Timeout  time.Duration
+	Retries  int
+	// MaxOids is the maximum number of oids in one request, more oids are split into several requests
+	MaxOids int
+
+	conn *gosnmp.GoSNMP
+	lock sync.Mutex
+}
+
+// Connect opens the udp socket, the authoritative engine of snmp v3 is discovered by the first request
+func (c *Client) Connect() error {
+	if c.Port <= 0 {
+		c.Port = DefaultPort
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.Retries < 0 {
+		c.Retries = 0
+	}
+	if c.MaxOids <= 0 {
+		c.MaxOids = DefaultMaxOids
+	}
+
+	conn := &gosnmp.GoSNMP{
+		Target:    c.Target,
+		Port:      uint16(c.Port),
+		Transport: "udp",
+		Community: c.Community,
+		Version:   c.Version,
+		Timeout:   c.Timeout,
+		Retries:   c.Retries,
+		MaxOids:   c.MaxOids,
+	}
+	switch c.Version {
+	case Version2c:
+	case Version3:
+		if c.Security == nil {
+			return errors.New("security parameters are required by snmp v3")
+		}
+		if err := c.Security.Validate(); err != nil {
+			return err
+		}
+		conn.SecurityModel = gosnmp.UserSecurityModel
+		conn.MsgFlags, conn.SecurityParameters = c.Security.parameters()
+	default:
+		return fmt.Errorf("unsupported snmp version %d", c.Version)
+	}
+
+	if err := conn.Connect(); err != nil {
+		return err
+	}
+	c.conn = conn
+	return nil
+}
+
+// Close closes the udp socket
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Get fetches the values of the oids
+func (c *Client) Get(oids []string) ([]Variable, error) {
+	return c.request(gosnmp.GetRequest, oids)
+}
+
+// GetNext fetches the values of the lexicographic successors of the oids
+func (c *Client) GetNext(oids []string) ([]Variable, error) {
+	return c.request(gosnmp.GetNextRequest, oids)
+}
+
+// Walk fetches the variables in the subtree of the root oid one by one, returns at most max variables
+func (c *Client) Walk(root string, max int) ([]Variable, error) {
+	root = NormalizeOID(root)
+	result := make([]Variable, 0)
+	for oid := root; len(result) < max; {
+		vars, err := c.GetNext([]string{oid})
+		if err != nil {
+			return nil, err
+		}
+		if len(vars) == 0 {
+			break
+		}
+		v := vars[0]
+		if v.Exception() || !strings.HasPrefix(v.OID, root+".") || CompareOID(v.OID, oid) <= 0 {
+			break
+		}
+		result = append(result, v)
+		oid = v.OID
+	}
+	return result, nil
+}
+
+func (c *Client) request(pduType gosnmp.PDUType, oids []string) ([]Variable, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.conn == nil {
+		return nil, errors.New("client is not connected")
+	}
+
+	result := make([]Variable, 0, len(oids))
+	for start := 0; start < len(oids); start += c.MaxOids {
+		end := start + c.MaxOids
+		if end > len(oids) {
+			end = len(oids)
+		}
+
+		batch := make([]string, 0, end-start)
+		for _, oid := range oids[start:end] {
+			batch = append(batch, NormalizeOID(oid))
+		}
+		var resp *gosnmp.SnmpPacket
+		var err error
+		if pduType == gosnmp.GetNextRequest {
+			resp, err = c.conn.GetNext(batch)
+		} else {
+			resp, err = c.conn.Get(batch)
+		}
+		if err != nil {
+			// gosnmp reports the timeout by the error message only
+			if strings.Contains(err.Error(), "timeout") {
+				return nil, ErrTimeout
+			}
+			return nil, err
+		}
+		if resp.Error != gosnmp.NoError {
+			return nil, fmt.Errorf("snmp error status %d at index %d", resp.Error, resp.ErrorIndex)
+		}
+		if len(resp.Variables) != end-start {
+			return nil, fmt.Errorf("snmp response has %d variables, want %d", len(resp.Variables), end-start)
+		}
+		for _, pdu := range resp.Variables {
+			result = append(result, newVariable(pdu))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,46 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snmp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NormalizeOID trims the leading dot of the oid, so that ".1.3.6" and "1.3.6" are the same one
+func NormalizeOID(oid string) string {
+	return strings.TrimPrefix(strings.TrimSpace(oid), ".")
+}
+
+// CompareOID compares two oids arc by arc, returns -1, 0 or 1 like strings.Compare
+func CompareOID(a, b string) int {
+	x := strings.Split(NormalizeOID(a), ".")
+	y := strings.Split(NormalizeOID(b), ".")
+	for i := 0; i < len(x) && i < len(y); i++ {
+		m, _ := strconv.ParseUint(x[i], 10, 64)
+		n, _ := strconv.ParseUint(y[i], 10, 64)
+		if m != n {
+			if m < n {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(x) < len(y):
+		return -1
+	case len(x) > len(y):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,165 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snmp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompareOID(t *testing.T) {
+	if CompareOID("1.3.6.1.2", "1.3.6.1.10") >= 0 || CompareOID("1.3.6.1", "1.3.6.1.0") >= 0 {
+		t.Errorf("CompareOID() should compare arc by arc")
+	}
+	if CompareOID(".1.3.6.1.2.1", "1.3.6.1.2.1") != 0 {
+		t.Errorf("CompareOID() should ignore the leading dot")
+	}
+}
+
+func startAgent(t *testing.T, security *UsmSecurity) *Agent {
+	agent, err := NewAgent("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("NewAgent() failed, err: %v", err)
+	}
+	agent.Security = security
+	agent.Set(
+		Variable{OID: "1.3.6.1.2.1.1.1.0", Type: OctetString, Value: "Huawei Versatile Routing Platform S5700"},
+		Variable{OID: "1.3.6.1.2.1.1.3.0", Type: TimeTicks, Value: uint64(360000)},
+		Variable{OID: "1.3.6.1.2.1.1.5.0", Type: OctetString, Value: "core-switch-01"},
+		Variable{OID: "1.3.6.1.2.1.2.1.0", Type: Integer, Value: int64(48)},
+		Variable{OID: "1.3.6.1.2.1.4.20.1.1.10.0.0.1", Type: IPAddress, Value: "10.0.0.1"},
+		Variable{OID: "1.3.6.1.2.1.31.1.1.1.6.1", Type: Counter64, Value: uint64(1<<64 - 1)},
+	)
+	go agent.Serve()
+	return agent
+}
+
+func checkPoll(t *testing.T, client *Client) {
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect() failed, err: %v", err)
+	}
+	defer client.Close()
+
+	vars, err := client.Get([]string{".1.3.6.1.2.1.1.5.0", "1.3.6.1.2.1.2.1.0", "1.3.6.1.2.1.31.1.1.1.6.1", "1.3.6.1.2.1.1.9.0"})
+	if err != nil {
+		t.Fatalf("Get() failed, err: %v", err)
+	}
+	if len(vars) != 4 {
+		t.Fatalf("Get() got %d variables, want 4", len(vars))
+	}
+	if name, _ := vars[0].Value.([]byte); string(name) != "core-switch-01" {
+		t.Errorf("Get() sysName got %v", vars[0].Value)
+	}
+	if vars[1].Value != int64(48) || vars[2].Value != uint64(1<<64-1) {
+		t.Errorf("Get() got %v and %v", vars[1].Value, vars[2].Value)
+	}
+	if !vars[3].Exception() {
+		t.Errorf("Get() not exist oid got %v", vars[3])
+	}
+
+	vars, err = client.GetNext([]string{"1.3.6.1.2.1.4.20.1.1", "1.3.6.1.2.1.31.1.1.1.6.1"})
+	if err != nil {
+		t.Fatalf("GetNext() failed, err: %v", err)
+	}
+	if vars[0].OID != "1.3.6.1.2.1.4.20.1.1.10.0.0.1" || vars[0].Value != "10.0.0.1" {
+		t.Errorf("GetNext() got %+v", vars[0])
+	}
+	if vars[1].Type != EndOfMibView {
+		t.Errorf("GetNext() at the end of mib got %+v", vars[1])
+	}
+
+	vars, err = client.Walk("1.3.6.1.2.1.1", 10)
+	if err != nil {
+		t.Fatalf("Walk() failed, err: %v", err)
+	}
+	if len(vars) != 3 || vars[2].OID != "1.3.6.1.2.1.1.5.0" {
+		t.Errorf("Walk() got %+v", vars)
+	}
+}
+
+func TestClientV2c(t *testing.T) {
+	agent := startAgent(t, nil)
+	defer agent.Close()
+
+	checkPoll(t, &Client{Target: "127.0.0.1", Port: agent.Addr().Port, Version: Version2c, Community: "public", MaxOids: 2})
+
+	client := &Client{Target: "127.0.0.1", Port: agent.Addr().Port, Version: Version2c, Community: "private", Timeout: 200 * time.Millisecond}
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect() failed, err: %v", err)
+	}
+	defer client.Close()
+	if _, err := client.Get([]string{"1.3.6.1.2.1.1.5.0"}); err != ErrTimeout {
+		t.Errorf("Get() with wrong community got err: %v, want timeout", err)
+	}
+}
+
+func TestClientMissingVariables(t *testing.T) {
+	agent, err := NewAgent("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("NewAgent() failed, err: %v", err)
+	}
+	agent.MaxVariables = 1
+	agent.Set(Variable{OID: "1.3.6.1.2.1.1.5.0", Type: OctetString, Value: "core-switch-01"})
+	go agent.Serve()
+	defer agent.Close()
+
+	client := &Client{Target: "127.0.0.1", Port: agent.Addr().Port, Version: Version2c, Community: "public"}
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect() failed, err: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Get([]string{"1.3.6.1.2.1.1.5.0"}); err != nil {
+		t.Errorf("Get() failed, err: %v", err)
+	}
+	if vars, err := client.Get([]string{"1.3.6.1.2.1.1.5.0", "1.3.6.1.2.1.2.1.0"}); err == nil {
+		t.Errorf("Get() with missing variables got %+v, want error", vars)
+	}
+	if vars, err := client.GetNext([]string{"1.3.6.1.2.1.1", "1.3.6.1.2.1.2"}); err == nil {
+		t.Errorf("GetNext() with missing variables got %+v, want error", vars)
+	}
+}
+
+func TestClientV3(t *testing.T) {
+	users := []UsmSecurity{
+		{UserName: "noauth", SecurityLevel: NoAuthNoPriv},
+		{UserName: "md5", SecurityLevel: AuthNoPriv, AuthProtocol: MD5, AuthPassphrase: "md5password"},
+		{UserName: "md5des", SecurityLevel: AuthPriv, AuthProtocol: MD5, AuthPassphrase: "md5password", PrivProtocol: DES, PrivPassphrase: "despassword"},
+		{UserName: "shaaes", SecurityLevel: AuthPriv, AuthProtocol: SHA, AuthPassphrase: "shapassword", PrivProtocol: AES, PrivPassphrase: "aespassword"},
+	}
+	for index := range users {
+		agent := startAgent(t, &users[index])
+		security := users[index]
+		checkPoll(t, &Client{Target: "127.0.0.1", Port: agent.Addr().Port, Version: Version3, Security: &security})
+		agent.Close()
+	}
+
+	agent := startAgent(t, &users[3])
+	defer agent.Close()
+	for _, wrong := range []UsmSecurity{
+		{UserName: "shaaes", SecurityLevel: AuthPriv, AuthProtocol: SHA, AuthPassphrase: "wrongpassword", PrivProtocol: AES, PrivPassphrase: "aespassword"},
+		{UserName: "shaaes", SecurityLevel: AuthPriv, AuthProtocol: SHA, AuthPassphrase: "shapassword", PrivProtocol: AES, PrivPassphrase: "wrongpassword"},
+		{UserName: "shaaes", SecurityLevel: AuthPriv, AuthProtocol: SHA, AuthPassphrase: "shapassword", PrivProtocol: DES, PrivPassphrase: "aespassword"},
+		{UserName: "shaaes", SecurityLevel: AuthNoPriv, AuthProtocol: SHA, AuthPassphrase: "shapassword"},
+	} {
+		security := wrong
+		client := &Client{Target: "127.0.0.1", Port: agent.Addr().Port, Version: Version3, Security: &security, Timeout: 200 * time.Millisecond}
+		if err := client.Connect(); err != nil {
+			t.Fatalf("Connect() failed, err: %v", err)
+		}
+		if vars, err := client.Get([]string{"1.3.6.1.2.1.1.5.0"}); err == nil {
+			t.Errorf("Get() with wrong security %+v got %+v, want error", wrong, vars)
+		}
+		client.Close()
+	}
+}
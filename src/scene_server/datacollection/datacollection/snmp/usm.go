@@ -0,0 +1,117 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snmp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// SecurityLevel is the snmp v3 security level
+type SecurityLevel string
+
+// the snmp v3 security levels
+const (
+	NoAuthNoPriv SecurityLevel = "noAuthNoPriv"
+	AuthNoPriv   SecurityLevel = "authNoPriv"
+	AuthPriv     SecurityLevel = "authPriv"
+)
+
+// AuthProtocol is the snmp v3 authentication protocol
+type AuthProtocol string
+
+// the supported authentication protocols
+const (
+	MD5 AuthProtocol = "MD5"
+	SHA AuthProtocol = "SHA"
+)
+
+// PrivProtocol is the snmp v3 privacy protocol
+type PrivProtocol string
+
+// the supported privacy protocols
+const (
+	DES PrivProtocol = "DES"
+	AES PrivProtocol = "AES"
+)
+
+// minPassphraseLength is the minimum passphrase length required by rfc3414
+const minPassphraseLength = 8
+
+// UsmSecurity is the user based security model parameters of snmp v3
+type UsmSecurity struct {
+	UserName       string
+	SecurityLevel  SecurityLevel
+	AuthProtocol   AuthProtocol
+	AuthPassphrase string
+	PrivProtocol   PrivProtocol
+	PrivPassphrase string
+}
+
+// Validate checks whether the parameters are enough for the security level
+func (u *UsmSecurity) Validate() error {
+	if u.UserName == "" {
+		return errors.New("user name is empty")
+	}
+	switch u.SecurityLevel {
+	case NoAuthNoPriv:
+		return nil
+	case AuthNoPriv, AuthPriv:
+	default:
+		return fmt.Errorf("unsupported security level %s", u.SecurityLevel)
+	}
+
+	if u.AuthProtocol != MD5 && u.AuthProtocol != SHA {
+		return fmt.Errorf("unsupported authentication protocol %s", u.AuthProtocol)
+	}
+	if len(u.AuthPassphrase) < minPassphraseLength {
+		return fmt.Errorf("authentication passphrase should be at least %d characters", minPassphraseLength)
+	}
+	if u.SecurityLevel == AuthNoPriv {
+		return nil
+	}
+
+	if u.PrivProtocol != DES && u.PrivProtocol != AES {
+		return fmt.Errorf("unsupported privacy protocol %s", u.PrivProtocol)
+	}
+	if len(u.PrivPassphrase) < minPassphraseLength {
+		return fmt.Errorf("privacy passphrase should be at least %d characters", minPassphraseLength)
+	}
+	return nil
+}
+
+// parameters returns the message flags and the user security model parameters used by gosnmp
+func (u *UsmSecurity) parameters() (gosnmp.SnmpV3MsgFlags, *gosnmp.UsmSecurityParameters) {
+	params := &gosnmp.UsmSecurityParameters{UserName: u.UserName}
+	if u.SecurityLevel == NoAuthNoPriv {
+		return gosnmp.NoAuthNoPriv, params
+	}
+
+	params.AuthenticationProtocol = gosnmp.MD5
+	if u.AuthProtocol == SHA {
+		params.AuthenticationProtocol = gosnmp.SHA
+	}
+	params.AuthenticationPassphrase = u.AuthPassphrase
+	if u.SecurityLevel == AuthNoPriv {
+		return gosnmp.AuthNoPriv, params
+	}
+
+	params.PrivacyProtocol = gosnmp.DES
+	if u.PrivProtocol == AES {
+		params.PrivacyProtocol = gosnmp.AES
+	}
+	params.PrivacyPassphrase = u.PrivPassphrase
+	return gosnmp.AuthPriv, params
+}
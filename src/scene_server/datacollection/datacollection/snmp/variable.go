@@ -0,0 +1,120 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snmp
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// Asn1BER is the BER tag of a snmp value
+type Asn1BER = gosnmp.Asn1BER
+
+// the BER tags of the snmp values
+const (
+	Integer          = gosnmp.Integer
+	OctetString      = gosnmp.OctetString
+	Null             = gosnmp.Null
+	ObjectIdentifier = gosnmp.ObjectIdentifier
+	IPAddress        = gosnmp.IPAddress
+	Counter32        = gosnmp.Counter32
+	Gauge32          = gosnmp.Gauge32
+	TimeTicks        = gosnmp.TimeTicks
+	Opaque           = gosnmp.Opaque
+	Counter64        = gosnmp.Counter64
+	NoSuchObject     = gosnmp.NoSuchObject
+	NoSuchInstance   = gosnmp.NoSuchInstance
+	EndOfMibView     = gosnmp.EndOfMibView
+)
+
+// Variable is a snmp variable binding, the type of Value depends on Type:
+// Integer is int64, Counter32, Gauge32, TimeTicks and Counter64 are uint64,
+// OctetString and Opaque are []byte, ObjectIdentifier and IPAddress are string,
+// and the others are nil.
+type Variable struct {
+	OID   string
+	Type  Asn1BER
+	Value interface{}
+}
+
+// Exception returns true if the variable tells the oid does not exist rather than carrying a value
+func (v *Variable) Exception() bool {
+	return v.Type == NoSuchObject || v.Type == NoSuchInstance || v.Type == EndOfMibView
+}
+
+// newVariable converts the variable decoded by gosnmp, whose numbers are of the go types of their sizes
+func newVariable(pdu gosnmp.SnmpPDU) Variable {
+	v := Variable{OID: NormalizeOID(pdu.Name), Type: pdu.Type, Value: pdu.Value}
+	switch value := pdu.Value.(type) {
+	case int:
+		v.Value = int64(value)
+	case uint:
+		v.Value = uint64(value)
+	case uint32:
+		v.Value = uint64(value)
+	case string:
+		if pdu.Type == ObjectIdentifier {
+			v.Value = NormalizeOID(value)
+		}
+	}
+	return v
+}
+
+// pdu converts the variable to the go types gosnmp encodes
+func (v *Variable) pdu() (gosnmp.SnmpPDU, error) {
+	pdu := gosnmp.SnmpPDU{Name: NormalizeOID(v.OID), Type: v.Type, Value: v.Value}
+	switch v.Type {
+	case Integer:
+		i, ok := toInt64(v.Value)
+		if !ok || i < math.MinInt32 || i > math.MaxInt32 {
+			return pdu, fmt.Errorf("invalid integer value %v of %s", v.Value, v.OID)
+		}
+		pdu.Value = int(i)
+	case Counter32, Gauge32, TimeTicks, Counter64:
+		u, ok := v.Value.(uint64)
+		if !ok {
+			i, isInt := toInt64(v.Value)
+			if !isInt || i < 0 {
+				return pdu, fmt.Errorf("invalid unsigned value %v of %s", v.Value, v.OID)
+			}
+			u = uint64(i)
+		}
+		if v.Type == Counter64 {
+			pdu.Value = u
+			break
+		}
+		if u > math.MaxUint32 {
+			return pdu, fmt.Errorf("invalid unsigned value %v of %s", v.Value, v.OID)
+		}
+		pdu.Value = uint32(u)
+	}
+	return pdu, nil
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch i := v.(type) {
+	case int:
+		return int64(i), true
+	case int32:
+		return int64(i), true
+	case int64:
+		return i, true
+	case uint32:
+		return int64(i), true
+	case uint:
+		return int64(i), true
+	}
+	return 0, false
+}
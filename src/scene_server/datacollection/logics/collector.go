@@ -15,6 +15,7 @@ package logics
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -24,6 +25,7 @@ import (
 	"configcenter/src/common/blog"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/util"
+	"configcenter/src/scene_server/datacollection/datacollection/netcollect"
 	"configcenter/src/thirdpartyclient/esbserver/nodeman"
 
 	"gopkg.in/yaml.v2"
@@ -33,6 +35,10 @@ import (
 const Netdevicebeat = "netdevicebeat"
 
 func (lgc *Logics) SearchCollector(header http.Header, cond metadata.ParamNetcollectorSearch) (int64, []metadata.Netcollector, error) {
+	if lgc.SnmpPoller {
+		return lgc.searchPollerCollector(header)
+	}
+
 	collectors := make([]metadata.Netcollector, 0)
 
 	// fetch package info
@@ -124,6 +130,7 @@ func (lgc *Logics) SearchCollector(header http.Header, cond metadata.ParamNetcol
 			blog.Warnf("[NetDevice][SearchCollector] get collector config for %s failed", key)
 		}
 		collector.Config = existsOne.Config
+		collector.Config.HideSecrets()
 		collector.ReportTotal = existsOne.ReportTotal
 		collector.TaskID = existsOne.TaskID
 		collector.DeployTime = existsOne.DeployTime
@@ -154,6 +161,44 @@ func (lgc *Logics) SearchCollector(header http.Header, cond metadata.ParamNetcol
 	return int64(len(collectors)), collectors, nil
 }
 
+// searchPollerCollector returns the collectors polled by the built-in snmp poller, which are all from our db
+func (lgc *Logics) searchPollerCollector(header http.Header) (int64, []metadata.Netcollector, error) {
+	collectors := make([]metadata.Netcollector, 0)
+	if err := lgc.db.Table(common.BKTableNameNetcollectConfig).Find(nil).All(lgc.ctx, &collectors); err != nil {
+		blog.Errorf("[NetDevice][SearchCollector] get collector config failed: %v", err)
+		return 0, nil, err
+	}
+
+	cloudIDs := make([]int64, 0)
+	for _, collector := range collectors {
+		cloudIDs = append(cloudIDs, collector.CloudID)
+	}
+	cloudCond := map[string]interface{}{
+		common.BKCloudIDField: map[string]interface{}{
+			common.BKDBIN: cloudIDs,
+		},
+	}
+	cloudMap, err := lgc.findInstMap(header, common.BKInnerObjIDPlat, &metadata.QueryCondition{Condition: cloudCond})
+	if err != nil {
+		blog.Errorf("[NetDevice][SearchCollector] find clouds by %+v failed: %v", cloudCond, err)
+		return 0, nil, err
+	}
+
+	for index := range collectors {
+		collector := &collectors[index]
+		collector.Config.HideSecrets()
+		if cloudInst, ok := cloudMap[collector.CloudID]; ok {
+			collector.CloudName, _ = cloudInst.String(common.BKCloudNameField)
+		}
+		collector.Status.CollectorStatus = metadata.CollectorStatusNormal
+		collector.Status.ConfigStatus = metadata.CollectorConfigStatusNormal
+		if collector.Status.ReportStatus == "" {
+			collector.Status.ReportStatus = metadata.CollectorReportStatusAbnormal
+		}
+	}
+	return int64(len(collectors)), collectors, nil
+}
+
 func (lgc *Logics) queryCollectTask(header http.Header, bizID int64, taskID int64) (string, error) {
 	resp, err := lgc.ESB.NodemanSrv().SearchTask(context.Background(), header, bizID, taskID)
 	if err != nil {
@@ -161,7 +206,7 @@ func (lgc *Logics) queryCollectTask(header http.Header, bizID int64, taskID int6
 	}
 	if !resp.Result {
 		blog.Errorf("[NetDevice][queryNodemanTask] failed: %+v", resp.Message)
-		return metadata.CollectorConfigStatusPending, fmt.Errorf(resp.Message)
+		return metadata.CollectorConfigStatusPending, errors.New(resp.Message)
 	}
 
 	for _, host := range resp.Data.Hosts {
@@ -203,15 +248,25 @@ func (lgc *Logics) UpdateCollector(header http.Header, config metadata.Netcollec
 		common.BKHostInnerIPField: config.InnerIP,
 	}
 
-	count, err := lgc.db.Table(common.BKTableNameNetcollectConfig).Find(filter).Count(lgc.ctx)
+	collectorMap, err := lgc.findCollectorMap(filter)
 	if err != nil {
-		blog.Errorf("[UpdateCollector] count by %+v error: %v", filter, err)
+		blog.Errorf("[UpdateCollector] get collector config by %+v error: %v", filter, err)
 		return err
 	}
-	if count > 0 {
+	saved, exist := collectorMap[collectorMapKey(config.CloudID, config.InnerIP)]
+	if exist {
+		config.Config.KeepSecrets(saved.Config)
+	}
+
+	if _, err := netcollect.NewSnmpClient(&config.Config, ""); err != nil {
+		blog.Errorf("[UpdateCollector] invalid snmp config of %s: %v", config.InnerIP, err)
+		return err
+	}
+
+	if exist {
 		err = lgc.db.Table(common.BKTableNameNetcollectConfig).Update(lgc.ctx, filter, config)
 		if err != nil {
-			blog.Errorf("[UpdateCollector] UpdateByCondition by %+v to %+v error: %v", filter, config, err)
+			blog.Errorf("[UpdateCollector] UpdateByCondition by %+v error: %v", filter, err)
 			return err
 		}
 		return lgc.DiscoverNetDevice(header, []metadata.Netcollector{config})
@@ -219,7 +274,7 @@ func (lgc *Logics) UpdateCollector(header http.Header, config metadata.Netcollec
 
 	err = lgc.db.Table(common.BKTableNameNetcollectConfig).Insert(lgc.ctx, config)
 	if err != nil {
-		blog.Errorf("[UpdateCollector] Insert %+v error: %v", config, err)
+		blog.Errorf("[UpdateCollector] Insert collector config of %s error: %v", config.InnerIP, err)
 		return err
 	}
 
@@ -227,6 +282,16 @@ func (lgc *Logics) UpdateCollector(header http.Header, config metadata.Netcollec
 }
 
 func (lgc *Logics) DiscoverNetDevice(header http.Header, configs []metadata.Netcollector) error {
+	if lgc.SnmpPoller {
+		// the built-in snmp poller picks up the changed configs itself, nothing to deploy
+		for index := range configs {
+			if err := lgc.saveCollectTask(&configs[index], 0, metadata.CollectorConfigStatusNormal); err != nil {
+				blog.Errorf("[NetDevice][DiscoverNetDevice] saveCollectTask %s failed, %v", configs[index].InnerIP, err)
+				return err
+			}
+		}
+		return nil
+	}
 
 	// fetch global_params
 	pkgResp, err := lgc.ESB.NodemanSrv().SearchPackage(context.Background(), header, Netdevicebeat)
@@ -364,7 +429,7 @@ func (lgc *Logics) buildUpgradePluginRequest(collector *metadata.Netcollector, u
 func (lgc *Logics) buildNetdevicebeatConfigFile(collector *metadata.Netcollector) ([]byte, error) {
 	customs, err := lgc.findCustom()
 	if err != nil {
-		blog.Errorf("[NetDevice][buildNetdevicebeatConfigFile] findCustom for %+v failed: %v", collector, err)
+		blog.Errorf("[NetDevice][buildNetdevicebeatConfigFile] findCustom for %s failed: %v", collector.InnerIP, err)
 		return []byte(""), err
 	}
 
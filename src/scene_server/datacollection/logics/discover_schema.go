@@ -0,0 +1,122 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"fmt"
+	"net/http"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+func (lgc *Logics) SearchDiscoverSchema(header http.Header, param metadata.ParamSearchDiscoverSchema) ([]metadata.DiscoverSchema, error) {
+	rid := util.GetHTTPCCRequestID(header)
+	cond := mapstr.MapStr{common.BKOwnerIDField: util.GetOwnerID(header)}
+	if len(param.Sources) > 0 {
+		cond.Set("source", mapstr.MapStr{common.BKDBIN: param.Sources})
+	}
+
+	schemas := make([]metadata.DiscoverSchema, 0)
+	if err := lgc.db.Table(common.BKTableNameDiscoverSchema).Find(cond).All(lgc.ctx, &schemas); err != nil {
+		blog.Errorf("[DiscoverSchema][SearchDiscoverSchema] search schemas by %+v failed, err: %v, rid: %s", cond, err, rid)
+		return nil, err
+	}
+	return schemas, nil
+}
+
+// UpdateDiscoverSchema registers the schema of the source, the models and the properties mapped must exist
+func (lgc *Logics) UpdateDiscoverSchema(header http.Header, schema metadata.DiscoverSchema) error {
+	rid := util.GetHTTPCCRequestID(header)
+	if err := schema.Validate(); err != nil {
+		blog.Errorf("[DiscoverSchema][UpdateDiscoverSchema] invalid schema %+v, err: %v, rid: %s", schema, err, rid)
+		return err
+	}
+
+	objIDs := make([]string, 0)
+	for _, mapping := range schema.Mappings {
+		objIDs = append(objIDs, mapping.ObjectID)
+		for _, asst := range mapping.Associations {
+			objIDs = append(objIDs, asst.AsstObjectID)
+		}
+	}
+	objMap, err := lgc.findObjectMap(header, objIDs...)
+	if err != nil {
+		blog.Errorf("[DiscoverSchema][UpdateDiscoverSchema] find objects %v failed, err: %v, rid: %s", objIDs, err, rid)
+		return err
+	}
+	attrsMap, err := lgc.findAttrsMap(header, objIDs...)
+	if err != nil {
+		blog.Errorf("[DiscoverSchema][UpdateDiscoverSchema] find attributes of %v failed, err: %v, rid: %s", objIDs, err, rid)
+		return err
+	}
+	for _, mapping := range schema.Mappings {
+		if _, ok := objMap[mapping.ObjectID]; !ok {
+			return fmt.Errorf("object %s not exist", mapping.ObjectID)
+		}
+		for propertyID := range mapping.Attributes {
+			if _, ok := attrsMap[attrMapKey(mapping.ObjectID, propertyID)]; !ok {
+				return fmt.Errorf("property %s of %s not exist", propertyID, mapping.ObjectID)
+			}
+		}
+		for _, asst := range mapping.Associations {
+			if _, ok := objMap[asst.AsstObjectID]; !ok {
+				return fmt.Errorf("object %s not exist", asst.AsstObjectID)
+			}
+			for propertyID := range asst.AsstKeys {
+				if _, ok := attrsMap[attrMapKey(asst.AsstObjectID, propertyID)]; !ok {
+					return fmt.Errorf("property %s of %s not exist", propertyID, asst.AsstObjectID)
+				}
+			}
+		}
+	}
+
+	schema.OwnerID = util.GetOwnerID(header)
+	schema.Modifier = util.GetUser(header)
+	schema.LastTime = metadata.Now()
+	filter := mapstr.MapStr{
+		common.BKOwnerIDField: schema.OwnerID,
+		"source":              schema.Source,
+	}
+	count, err := lgc.db.Table(common.BKTableNameDiscoverSchema).Find(filter).Count(lgc.ctx)
+	if err != nil {
+		blog.Errorf("[DiscoverSchema][UpdateDiscoverSchema] count schema by %+v failed, err: %v, rid: %s", filter, err, rid)
+		return err
+	}
+	if count <= 0 {
+		err = lgc.db.Table(common.BKTableNameDiscoverSchema).Insert(lgc.ctx, schema)
+	} else {
+		err = lgc.db.Table(common.BKTableNameDiscoverSchema).Update(lgc.ctx, filter, schema)
+	}
+	if err != nil {
+		blog.Errorf("[DiscoverSchema][UpdateDiscoverSchema] save schema %+v failed, err: %v, rid: %s", schema, err, rid)
+		return err
+	}
+	return nil
+}
+
+func (lgc *Logics) DeleteDiscoverSchema(header http.Header, param metadata.ParamDeleteDiscoverSchema) error {
+	rid := util.GetHTTPCCRequestID(header)
+	cond := mapstr.MapStr{
+		common.BKOwnerIDField: util.GetOwnerID(header),
+		"source":              mapstr.MapStr{common.BKDBIN: param.Sources},
+	}
+	if err := lgc.db.Table(common.BKTableNameDiscoverSchema).Delete(lgc.ctx, cond); err != nil {
+		blog.Errorf("[DiscoverSchema][DeleteDiscoverSchema] delete schemas by %+v failed, err: %v, rid: %s", cond, err, rid)
+		return err
+	}
+	return nil
+}
@@ -26,6 +26,8 @@ type Logics struct {
 	db  dal.RDB
 	ESB esbserver.EsbClientInterface
 	ctx context.Context
+	// SnmpPoller is true when the built-in snmp poller collects the network devices instead of netdevicebeat
+	SnmpPoller bool
 }
 
 func NewLogics(ctx context.Context, engine *backbone.Engine, mgoCli dal.RDB, esb esbserver.EsbClientInterface) *Logics {
@@ -0,0 +1,204 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/condition"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// the instance fields used to resolve the neighbors
+const (
+	hostMacField = "bk_mac"
+	adminIPField = "bk_admin_ip"
+)
+
+// ProposeNeighborAssociations resolves the lldp/cdp neighbors of the report to the existing instances
+// by mac, ip and sysName when the report arrives, and proposes connect associations to them, which are
+// saved with the report and created by the confirm workflow like the collected associations.
+func (lgc *Logics) ProposeNeighborAssociations(report *metadata.NetcollectReport) error {
+	return lgc.proposeNeighborAssociations(collectorHeader(report.OwnerID), report)
+}
+
+func (lgc *Logics) proposeNeighborAssociations(header http.Header, report *metadata.NetcollectReport) error {
+	rid := util.GetHTTPCCRequestID(header)
+	if len(report.Neighbors) == 0 {
+		return nil
+	}
+
+	cond := condition.CreateCondition()
+	cond.Field(common.BKObjIDField).Eq(report.ObjectID)
+	cond.Field(common.AssociationKindIDField).Eq(common.AssociationTypeConnect)
+	resp, err := lgc.CoreAPI.CoreService().Association().ReadModelAssociation(context.Background(), header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	if err != nil {
+		blog.Errorf("[NetDevice][proposeNeighborAssociations] find connect associations by %+v failed, err: %v, rid: %s", cond.ToMapStr(), err, rid)
+		return err
+	}
+	if !resp.Result {
+		blog.Errorf("[NetDevice][proposeNeighborAssociations] find connect associations by %+v failed, err: %+v, rid: %s", cond.ToMapStr(), resp, rid)
+		return errors.New(resp.ErrMsg)
+	}
+	connects := resp.Data.Info
+	if len(connects) == 0 {
+		return nil
+	}
+
+	linked, err := lgc.findLinkedInsts(header, report)
+	if err != nil {
+		return err
+	}
+	for _, neighbor := range report.Neighbors {
+		for _, asst := range connects {
+			instID, instName, err := lgc.findNeighborInst(header, report.CloudID, asst.AsstObjID, &neighbor)
+			if err != nil {
+				return err
+			}
+			if instName == "" {
+				continue
+			}
+			if asst.AsstObjID == report.ObjectID && instName == report.InstKey {
+				break
+			}
+			if linked[linkedKey(asst.AsstObjID, instID)] || isNeighborProposed(report, asst.AsstObjID, instName) {
+				break
+			}
+			report.Associations = append(report.Associations, metadata.NetcollectReportAssociation{
+				AsstInstName:  instName,
+				AsstObjectID:  asst.AsstObjID,
+				ObjectAsstID:  asst.AssociationName,
+				Configuration: neighbor.Link(),
+			})
+			break
+		}
+	}
+	return nil
+}
+
+func linkedKey(objID string, instID int64) string {
+	return fmt.Sprintf("%s:%d", objID, instID)
+}
+
+func isNeighborProposed(report *metadata.NetcollectReport, asstObjID, asstInstName string) bool {
+	for _, asst := range report.Associations {
+		if asst.AsstObjectID == asstObjID && asst.AsstInstName == asstInstName {
+			return true
+		}
+	}
+	return false
+}
+
+// findLinkedInsts returns the instances already connected with the reported instance in either direction
+func (lgc *Logics) findLinkedInsts(header http.Header, report *metadata.NetcollectReport) (map[string]bool, error) {
+	linked := make(map[string]bool)
+	cond := condition.CreateCondition()
+	if common.GetObjByType(report.ObjectID) == common.BKInnerObjIDHost {
+		cond.Field(common.BKCloudIDField).Eq(report.CloudID)
+		cond.Field(common.BKHostInnerIPField).Eq(report.InstKey)
+	} else {
+		cond.Field(common.GetInstNameField(report.ObjectID)).Eq(report.InstKey)
+		cond.Field(common.BKObjIDField).Eq(report.ObjectID)
+	}
+	insts, err := lgc.findInst(header, report.ObjectID, &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	if err != nil {
+		return nil, err
+	}
+	if len(insts) == 0 {
+		return linked, nil
+	}
+	instID, err := insts[0].Int64(common.GetInstIDField(report.ObjectID))
+	if err != nil {
+		return nil, err
+	}
+
+	assts, err := lgc.findInstAssociation(header, report.ObjectID, instID)
+	if err != nil {
+		return nil, err
+	}
+	for _, asst := range assts {
+		if asst.AssociationKindID != common.AssociationTypeConnect {
+			continue
+		}
+		if asst.ObjectID == report.ObjectID && asst.InstID == instID {
+			linked[linkedKey(asst.AsstObjectID, asst.AsstInstID)] = true
+		} else {
+			linked[linkedKey(asst.ObjectID, asst.InstID)] = true
+		}
+	}
+	return linked, nil
+}
+
+// findNeighborInst finds the instance of the neighbor, the hosts are matched by inner ip, mac and host name,
+// the others are matched by instance name and admin ip. returns empty name if not found.
+func (lgc *Logics) findNeighborInst(header http.Header, cloudID int64, objID string, neighbor *metadata.NetcollectNeighbor) (int64, string, error) {
+	isHost := common.GetObjByType(objID) == common.BKInnerObjIDHost
+	ors := make([]mapstr.MapStr, 0)
+	if isHost {
+		if neighbor.Address != "" {
+			ors = append(ors, mapstr.MapStr{common.BKHostInnerIPField: neighbor.Address})
+		}
+		if neighbor.ChassisID != "" {
+			ors = append(ors, mapstr.MapStr{hostMacField: mapstr.MapStr{common.BKDBLIKE: "(?i)^" + regexp.QuoteMeta(neighbor.ChassisID) + "$"}})
+		}
+		if neighbor.SysName != "" {
+			ors = append(ors, mapstr.MapStr{common.BKHostNameField: neighbor.SysName})
+		}
+	} else {
+		if neighbor.SysName != "" {
+			ors = append(ors, mapstr.MapStr{common.GetInstNameField(objID): neighbor.SysName})
+		}
+		if neighbor.Address != "" {
+			ors = append(ors, mapstr.MapStr{adminIPField: neighbor.Address})
+		}
+	}
+	if len(ors) == 0 {
+		return 0, "", nil
+	}
+
+	cond := mapstr.MapStr{common.BKDBOR: ors}
+	if isHost {
+		cond.Set(common.BKCloudIDField, cloudID)
+	} else {
+		cond.Set(common.BKObjIDField, objID)
+	}
+	insts, err := lgc.findInst(header, objID, &metadata.QueryCondition{Condition: cond})
+	if err != nil {
+		return 0, "", err
+	}
+	if len(insts) == 0 {
+		return 0, "", nil
+	}
+
+	instID, err := insts[0].Int64(common.GetInstIDField(objID))
+	if err != nil {
+		return 0, "", err
+	}
+	nameField := common.GetInstNameField(objID)
+	if isHost {
+		nameField = common.BKHostInnerIPField
+	}
+	name, err := insts[0].String(nameField)
+	if err != nil {
+		return 0, "", err
+	}
+	return instID, name, nil
+}
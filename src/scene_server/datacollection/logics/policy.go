@@ -0,0 +1,268 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"fmt"
+	"net/http"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+func (lgc *Logics) SearchConfirmPolicy(header http.Header, param metadata.ParamSearchNetcollectPolicy) ([]metadata.NetcollectConfirmPolicy, error) {
+	rid := util.GetHTTPCCRequestID(header)
+	cond := mapstr.MapStr{common.BKOwnerIDField: util.GetOwnerID(header)}
+	if len(param.ObjectIDs) > 0 {
+		cond.Set(common.BKObjIDField, mapstr.MapStr{common.BKDBIN: param.ObjectIDs})
+	}
+
+	policies := make([]metadata.NetcollectConfirmPolicy, 0)
+	if err := lgc.db.Table(common.BKTableNameNetcollectConfirmPolicy).Find(cond).All(lgc.ctx, &policies); err != nil {
+		blog.Errorf("[NetDevice][SearchConfirmPolicy] search policies by %+v failed, err: %v, rid: %s", cond, err, rid)
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (lgc *Logics) UpdateConfirmPolicy(header http.Header, policy metadata.NetcollectConfirmPolicy) error {
+	rid := util.GetHTTPCCRequestID(header)
+	if err := policy.Validate(); err != nil {
+		blog.Errorf("[NetDevice][UpdateConfirmPolicy] invalid policy %+v, err: %v, rid: %s", policy, err, rid)
+		return err
+	}
+
+	attrsMap, err := lgc.findAttrsMap(header, policy.ObjectID)
+	if err != nil {
+		blog.Errorf("[NetDevice][UpdateConfirmPolicy] find attributes of %s failed, err: %v, rid: %s", policy.ObjectID, err, rid)
+		return err
+	}
+	fields := append(append([]string{}, policy.AutoApplyFields...), policy.ManualFields...)
+	for _, cond := range policy.CreateConditions {
+		fields = append(fields, cond.PropertyID)
+	}
+	for _, field := range fields {
+		if field == metadata.NetcollectPolicyAllFields {
+			continue
+		}
+		if _, ok := attrsMap[attrMapKey(policy.ObjectID, field)]; !ok {
+			blog.Errorf("[NetDevice][UpdateConfirmPolicy] property %s of %s not exist, rid: %s", field, policy.ObjectID, rid)
+			return fmt.Errorf("property %s of %s not exist", field, policy.ObjectID)
+		}
+	}
+
+	policy.OwnerID = util.GetOwnerID(header)
+	policy.Modifier = util.GetUser(header)
+	policy.LastTime = metadata.Now()
+	filter := mapstr.MapStr{
+		common.BKOwnerIDField: policy.OwnerID,
+		common.BKObjIDField:   policy.ObjectID,
+	}
+	count, err := lgc.db.Table(common.BKTableNameNetcollectConfirmPolicy).Find(filter).Count(lgc.ctx)
+	if err != nil {
+		blog.Errorf("[NetDevice][UpdateConfirmPolicy] count policy by %+v failed, err: %v, rid: %s", filter, err, rid)
+		return err
+	}
+	if count <= 0 {
+		err = lgc.db.Table(common.BKTableNameNetcollectConfirmPolicy).Insert(lgc.ctx, policy)
+	} else {
+		err = lgc.db.Table(common.BKTableNameNetcollectConfirmPolicy).Update(lgc.ctx, filter, policy)
+	}
+	if err != nil {
+		blog.Errorf("[NetDevice][UpdateConfirmPolicy] save policy %+v failed, err: %v, rid: %s", policy, err, rid)
+		return err
+	}
+	return nil
+}
+
+func (lgc *Logics) DeleteConfirmPolicy(header http.Header, param metadata.ParamDeleteNetcollectPolicy) error {
+	rid := util.GetHTTPCCRequestID(header)
+	cond := mapstr.MapStr{
+		common.BKOwnerIDField: util.GetOwnerID(header),
+		common.BKObjIDField:   mapstr.MapStr{common.BKDBIN: param.ObjectIDs},
+	}
+	if err := lgc.db.Table(common.BKTableNameNetcollectConfirmPolicy).Delete(lgc.ctx, cond); err != nil {
+		blog.Errorf("[NetDevice][DeleteConfirmPolicy] delete policies by %+v failed, err: %v, rid: %s", cond, err, rid)
+		return err
+	}
+	return nil
+}
+
+// confirmClient reads the policies and instances and applies the changes confirmed by the policies
+type confirmClient interface {
+	// findConfirmPolicy returns nil if the model has no confirm policy
+	findConfirmPolicy(header http.Header, objID string) (*metadata.NetcollectConfirmPolicy, error)
+	findReportInst(header http.Header, report *metadata.NetcollectReport) (mapstr.MapStr, error)
+	confirmAttributes(header http.Header, report *metadata.NetcollectReport) (int, error)
+	confirmAssociations(header http.Header, report *metadata.NetcollectReport) (int, []error)
+	saveAutoHistory(report *metadata.NetcollectReport, success bool, remark string) error
+}
+
+// AutoConfirmReport confirms the changes allowed by the confirm policy of the reported model when the
+// report arrives, and removes them from the report. the changes not allowed are left in the report for
+// the manual review, pending is false when nothing is left.
+// the report is left untouched when the policy fails to be applied.
+func (lgc *Logics) AutoConfirmReport(report *metadata.NetcollectReport) (pending bool, err error) {
+	return autoConfirmReport(lgc, report)
+}
+
+func autoConfirmReport(client confirmClient, report *metadata.NetcollectReport) (pending bool, err error) {
+	header := collectorHeader(report.OwnerID)
+	rid := util.GetHTTPCCRequestID(header)
+
+	policy, err := client.findConfirmPolicy(header, report.ObjectID)
+	if err != nil {
+		return true, err
+	}
+	if policy == nil {
+		return true, nil
+	}
+	// the deletions are always reviewed manually
+	if report.Action == metadata.ReporctActionDelete {
+		return true, nil
+	}
+
+	inst, err := client.findReportInst(header, report)
+	if err != nil {
+		blog.Errorf("[NetDevice][AutoConfirmReport] find inst of %s %s failed, err: %v, rid: %s", report.ObjectID, report.InstKey, err, rid)
+		return true, err
+	}
+
+	keyField := common.GetInstNameField(report.ObjectID)
+	if report.ObjectID == common.BKInnerObjIDHost {
+		keyField = common.BKHostInnerIPField
+	}
+	auto := *report
+	auto.Attributes = make([]metadata.NetcollectReportAttribute, 0)
+	auto.Associations = make([]metadata.NetcollectReportAssociation, 0)
+	auto.Neighbors = nil
+	pendingAttrs := make([]metadata.NetcollectReportAttribute, 0)
+	switch {
+	case inst != nil:
+		for _, attr := range report.Attributes {
+			if fmt.Sprint(inst[attr.PropertyID]) == fmt.Sprint(attr.CurValue) {
+				continue
+			}
+			// the key field identifies the instance, so that it is always reviewed manually
+			if attr.PropertyID == keyField || !policy.AutoApply(attr.PropertyID) {
+				pendingAttrs = append(pendingAttrs, attr)
+				continue
+			}
+			attr.Method = metadata.ReporctMethodAccept
+			auto.Attributes = append(auto.Attributes, attr)
+		}
+		// the hosts are updated by the inner ip
+		if len(auto.Attributes) > 0 && report.ObjectID == common.BKInnerObjIDHost {
+			auto.Attributes = append(auto.Attributes, metadata.NetcollectReportAttribute{
+				PropertyID: keyField,
+				CurValue:   report.InstKey,
+				Method:     metadata.ReporctMethodAccept,
+			})
+		}
+	case policy.CanCreate(report.Attributes):
+		for _, attr := range report.Attributes {
+			attr.Method = metadata.ReporctMethodAccept
+			auto.Attributes = append(auto.Attributes, attr)
+		}
+	default:
+		pendingAttrs = report.Attributes
+	}
+
+	remark := fmt.Sprintf("confirmed by the confirm policy of %s", report.ObjectID)
+	if len(auto.Attributes) > 0 {
+		if _, err := client.confirmAttributes(header, &auto); err != nil {
+			blog.Errorf("[NetDevice][AutoConfirmReport] confirm attributes of %s %s failed, err: %v, rid: %s", report.ObjectID, report.InstKey, err, rid)
+			client.saveAutoHistory(&auto, false, err.Error())
+			return true, err
+		}
+		client.saveAutoHistory(&auto, true, remark)
+	}
+
+	pendingAssts := report.Associations
+	created := inst != nil || len(auto.Attributes) > 0
+	if policy.AutoAssociate && created {
+		asstReport := *report
+		asstReport.Attributes = nil
+		asstReport.Neighbors = nil
+		if len(asstReport.Associations) > 0 {
+			if _, errs := client.confirmAssociations(header, &asstReport); len(errs) > 0 {
+				blog.Errorf("[NetDevice][AutoConfirmReport] confirm associations of %s %s failed, err: %v, rid: %s", report.ObjectID, report.InstKey, errs, rid)
+				client.saveAutoHistory(&asstReport, false, errs[0].Error())
+			} else {
+				client.saveAutoHistory(&asstReport, true, remark)
+				pendingAssts = nil
+			}
+		} else {
+			pendingAssts = nil
+		}
+	}
+
+	report.Attributes = pendingAttrs
+	report.Associations = pendingAssts
+	pending = len(pendingAttrs) > 0 || len(pendingAssts) > 0
+	blog.V(4).Infof("[NetDevice][AutoConfirmReport] auto confirmed %s %s, pending: %v, rid: %s", report.ObjectID, report.InstKey, pending, rid)
+	return pending, nil
+}
+
+// findConfirmPolicy returns the confirm policy of the model, nil if the model has no policy
+func (lgc *Logics) findConfirmPolicy(header http.Header, objID string) (*metadata.NetcollectConfirmPolicy, error) {
+	rid := util.GetHTTPCCRequestID(header)
+	policy := metadata.NetcollectConfirmPolicy{}
+	filter := mapstr.MapStr{
+		common.BKOwnerIDField: util.GetOwnerID(header),
+		common.BKObjIDField:   objID,
+	}
+	if err := lgc.db.Table(common.BKTableNameNetcollectConfirmPolicy).Find(filter).One(lgc.ctx, &policy); err != nil {
+		if lgc.db.IsNotFoundError(err) {
+			return nil, nil
+		}
+		blog.Errorf("[NetDevice][findConfirmPolicy] find policy by %+v failed, err: %v, rid: %s", filter, err, rid)
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// collectorHeader returns the header of the requests made for the reports of the owner
+func collectorHeader(ownerID string) http.Header {
+	if ownerID == "" {
+		ownerID = common.BKDefaultOwnerID
+	}
+	header := http.Header{}
+	header.Add(common.BKHTTPOwnerID, ownerID)
+	header.Add(common.BKHTTPHeaderUser, common.CCSystemCollectorUserName)
+	header.Add(common.BKHTTPCCRequestID, util.GenerateRID())
+	return header
+}
+
+// findReportInst returns the instance of the report, nil if it does not exist
+func (lgc *Logics) findReportInst(header http.Header, report *metadata.NetcollectReport) (mapstr.MapStr, error) {
+	cond := mapstr.MapStr{}
+	if common.GetObjByType(report.ObjectID) == common.BKInnerObjIDHost {
+		cond.Set(common.BKCloudIDField, report.CloudID)
+		cond.Set(common.BKHostInnerIPField, report.InstKey)
+	} else {
+		cond.Set(common.GetInstNameField(report.ObjectID), report.InstKey)
+		cond.Set(common.BKObjIDField, report.ObjectID)
+	}
+	insts, err := lgc.findInst(header, report.ObjectID, &metadata.QueryCondition{Condition: cond})
+	if err != nil {
+		return nil, err
+	}
+	if len(insts) == 0 {
+		return nil, nil
+	}
+	return insts[0], nil
+}
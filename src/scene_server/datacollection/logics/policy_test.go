@@ -0,0 +1,232 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConfirmClient struct {
+	policy   *metadata.NetcollectConfirmPolicy
+	inst     mapstr.MapStr
+	attrErr  error
+	asstErrs []error
+
+	appliedAttrs []metadata.NetcollectReportAttribute
+	appliedAssts []metadata.NetcollectReportAssociation
+	histories    []bool
+}
+
+func (c *fakeConfirmClient) findConfirmPolicy(header http.Header, objID string) (*metadata.NetcollectConfirmPolicy, error) {
+	return c.policy, nil
+}
+
+func (c *fakeConfirmClient) findReportInst(header http.Header, report *metadata.NetcollectReport) (mapstr.MapStr, error) {
+	return c.inst, nil
+}
+
+func (c *fakeConfirmClient) confirmAttributes(header http.Header, report *metadata.NetcollectReport) (int, error) {
+	if c.attrErr != nil {
+		return 0, c.attrErr
+	}
+	c.appliedAttrs = append(c.appliedAttrs, report.Attributes...)
+	return len(report.Attributes), nil
+}
+
+func (c *fakeConfirmClient) confirmAssociations(header http.Header, report *metadata.NetcollectReport) (int, []error) {
+	if len(c.asstErrs) > 0 {
+		return 0, c.asstErrs
+	}
+	c.appliedAssts = append(c.appliedAssts, report.Associations...)
+	return len(report.Associations), nil
+}
+
+func (c *fakeConfirmClient) saveAutoHistory(report *metadata.NetcollectReport, success bool, remark string) error {
+	c.histories = append(c.histories, success)
+	return nil
+}
+
+func attrIDs(attrs []metadata.NetcollectReportAttribute) []string {
+	ids := make([]string, 0)
+	for _, attr := range attrs {
+		ids = append(ids, attr.PropertyID)
+	}
+	return ids
+}
+
+func asstNames(assts []metadata.NetcollectReportAssociation) []string {
+	names := make([]string, 0)
+	for _, asst := range assts {
+		names = append(names, asst.AsstInstName)
+	}
+	return names
+}
+
+func TestAutoConfirmReport(t *testing.T) {
+	policy := &metadata.NetcollectConfirmPolicy{
+		ObjectID:        "bk_switch",
+		AutoApplyFields: []string{metadata.NetcollectPolicyAllFields},
+		ManualFields:    []string{"bk_admin_ip"},
+		AutoCreate:      true,
+		CreateConditions: []metadata.NetcollectPolicyCondition{
+			{PropertyID: "bk_vendor", Operator: metadata.NetcollectPolicyOperatorEqual, Value: "huawei"},
+		},
+		AutoAssociate: true,
+	}
+	manualAsst := *policy
+	manualAsst.AutoAssociate = false
+	switchReport := func(vendor string) metadata.NetcollectReport {
+		return metadata.NetcollectReport{
+			ObjectID: "bk_switch",
+			InstKey:  "switch-01",
+			Attributes: []metadata.NetcollectReportAttribute{
+				{PropertyID: common.BKInstNameField, CurValue: "switch-01"},
+				{PropertyID: "bk_vendor", CurValue: vendor},
+				{PropertyID: "bk_os_version", CurValue: "v2"},
+				{PropertyID: "bk_admin_ip", CurValue: "10.0.0.2"},
+			},
+			Associations: []metadata.NetcollectReportAssociation{{AsstObjectID: common.BKInnerObjIDHost, AsstInstName: "192.168.1.1"}},
+		}
+	}
+	existing := mapstr.MapStr{common.BKInstNameField: "switch-01", "bk_vendor": "huawei", "bk_os_version": "v1", "bk_admin_ip": "10.0.0.1"}
+
+	tests := []struct {
+		name   string
+		client *fakeConfirmClient
+		action string
+		vendor string
+
+		wantErr          bool
+		wantPending      bool
+		wantApplied      []string
+		wantAppliedAssts []string
+		wantPendingAttrs []string
+		wantPendingAssts []string
+		wantHistories    []bool
+	}{
+		{
+			name:             "without policy",
+			client:           &fakeConfirmClient{},
+			vendor:           "huawei",
+			wantPending:      true,
+			wantApplied:      []string{},
+			wantAppliedAssts: []string{},
+			wantPendingAttrs: []string{common.BKInstNameField, "bk_vendor", "bk_os_version", "bk_admin_ip"},
+			wantPendingAssts: []string{"192.168.1.1"},
+		},
+		{
+			name:             "deletion",
+			client:           &fakeConfirmClient{policy: policy, inst: existing},
+			action:           metadata.ReporctActionDelete,
+			vendor:           "huawei",
+			wantPending:      true,
+			wantApplied:      []string{},
+			wantAppliedAssts: []string{},
+			wantPendingAttrs: []string{common.BKInstNameField, "bk_vendor", "bk_os_version", "bk_admin_ip"},
+			wantPendingAssts: []string{"192.168.1.1"},
+		},
+		{
+			name:             "update with manual field",
+			client:           &fakeConfirmClient{policy: policy, inst: existing},
+			vendor:           "huawei",
+			wantPending:      true,
+			wantApplied:      []string{"bk_os_version"},
+			wantAppliedAssts: []string{"192.168.1.1"},
+			wantPendingAttrs: []string{"bk_admin_ip"},
+			wantPendingAssts: []string{},
+			wantHistories:    []bool{true, true},
+		},
+		{
+			name:             "update with manual associations",
+			client:           &fakeConfirmClient{policy: &manualAsst, inst: existing},
+			vendor:           "huawei",
+			wantPending:      true,
+			wantApplied:      []string{"bk_os_version"},
+			wantAppliedAssts: []string{},
+			wantPendingAttrs: []string{"bk_admin_ip"},
+			wantPendingAssts: []string{"192.168.1.1"},
+			wantHistories:    []bool{true},
+		},
+		{
+			name:             "create matched",
+			client:           &fakeConfirmClient{policy: policy},
+			vendor:           "huawei",
+			wantPending:      false,
+			wantApplied:      []string{common.BKInstNameField, "bk_vendor", "bk_os_version", "bk_admin_ip"},
+			wantAppliedAssts: []string{"192.168.1.1"},
+			wantPendingAttrs: []string{},
+			wantPendingAssts: []string{},
+			wantHistories:    []bool{true, true},
+		},
+		{
+			name:             "create unmatched",
+			client:           &fakeConfirmClient{policy: policy},
+			vendor:           "cisco",
+			wantPending:      true,
+			wantApplied:      []string{},
+			wantAppliedAssts: []string{},
+			wantPendingAttrs: []string{common.BKInstNameField, "bk_vendor", "bk_os_version", "bk_admin_ip"},
+			wantPendingAssts: []string{"192.168.1.1"},
+		},
+		{
+			name:             "attributes failed",
+			client:           &fakeConfirmClient{policy: policy, attrErr: errors.New("create failed")},
+			vendor:           "huawei",
+			wantErr:          true,
+			wantPending:      true,
+			wantApplied:      []string{},
+			wantAppliedAssts: []string{},
+			wantPendingAttrs: []string{common.BKInstNameField, "bk_vendor", "bk_os_version", "bk_admin_ip"},
+			wantPendingAssts: []string{"192.168.1.1"},
+			wantHistories:    []bool{false},
+		},
+		{
+			name:             "associations failed",
+			client:           &fakeConfirmClient{policy: policy, asstErrs: []error{errors.New("asst failed")}},
+			vendor:           "huawei",
+			wantPending:      true,
+			wantApplied:      []string{common.BKInstNameField, "bk_vendor", "bk_os_version", "bk_admin_ip"},
+			wantAppliedAssts: []string{},
+			wantPendingAttrs: []string{},
+			wantPendingAssts: []string{"192.168.1.1"},
+			wantHistories:    []bool{true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := switchReport(tt.vendor)
+			report.Action = tt.action
+			pending, err := autoConfirmReport(tt.client, &report)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantPending, pending)
+			require.Equal(t, tt.wantApplied, attrIDs(tt.client.appliedAttrs))
+			require.Equal(t, tt.wantAppliedAssts, asstNames(tt.client.appliedAssts))
+			require.Equal(t, tt.wantPendingAttrs, attrIDs(report.Attributes))
+			require.Equal(t, tt.wantPendingAssts, asstNames(report.Associations))
+			require.Equal(t, tt.wantHistories, tt.client.histories)
+		})
+	}
+}
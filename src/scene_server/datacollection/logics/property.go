@@ -479,7 +479,7 @@ func (lgc *Logics) checkNetPropertyExist(deviceID uint64, propertyID, ownerID st
 
 	if 0 != rowCount {
 		blog.V(5).Infof(
-			"[NetProperty] check if net deviceID and propertyID exist, device_id[%s] and bk_property_id[%s] device is exist, rid: %s",
+			"[NetProperty] check if net deviceID and propertyID exist, device_id[%d] and bk_property_id[%s] device is exist, rid: %s",
 			deviceID, propertyID, rid)
 		return true, nil
 	}
@@ -14,6 +14,7 @@ package logics
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -395,6 +396,9 @@ func (lgc *Logics) ConfirmReport(header http.Header, reports []metadata.Netcolle
 
 func (lgc *Logics) confirmAttributes(header http.Header, report *metadata.NetcollectReport) (int, error) {
 	rid := util.GetHTTPCCRequestID(header)
+	// the confirmed values are collected, so they are subject to the source priorities of the fields
+	header = util.CloneHeader(header)
+	header.Set(common.BKHTTPDataSource, common.FieldSourceNetcollect)
 	data := mapstr.MapStr{}
 	attrCount := 0
 	for _, attr := range report.Attributes {
@@ -454,7 +458,7 @@ func (lgc *Logics) confirmAttributes(header http.Header, report *metadata.Netcol
 		}
 		if !resp.Result {
 			blog.Errorf("[NetDevice][ConfirmReport] add host error: %v, %+v, rid: %s", resp, data, rid)
-			return attrCount, fmt.Errorf(resp.ErrMsg)
+			return attrCount, errors.New(resp.ErrMsg)
 		}
 		return attrCount, nil
 	}
@@ -473,7 +477,7 @@ func (lgc *Logics) confirmAttributes(header http.Header, report *metadata.Netcol
 		}
 		if !resp.Result {
 			blog.Errorf("[NetDevice][ConfirmReport] update inst error: %v, %+v, rid: %s", resp, data, rid)
-			return attrCount, fmt.Errorf(resp.ErrMsg)
+			return attrCount, errors.New(resp.ErrMsg)
 		}
 	} else {
 		resp, err := lgc.CoreAPI.TopoServer().Instance().CreateInst(context.Background(), util.GetOwnerID(header), report.ObjectID, header, data)
@@ -483,7 +487,7 @@ func (lgc *Logics) confirmAttributes(header http.Header, report *metadata.Netcol
 		}
 		if !resp.Result {
 			blog.Errorf("[NetDevice][ConfirmReport] create inst to %s error: %v, %+v, rid: %s", report.ObjectID, resp, data, rid)
-			return attrCount, fmt.Errorf(resp.ErrMsg)
+			return attrCount, errors.New(resp.ErrMsg)
 		}
 	}
 	return attrCount, nil
@@ -563,7 +567,7 @@ func (lgc *Logics) confirmAssociations(header http.Header, report *metadata.Netc
 				}
 				if !resp.Result {
 					blog.Errorf("[NetDevice][ConfirmReport] create inst association error: %v, %+v, rid: %s", resp.ErrMsg, req, rid)
-					errs = append(errs, fmt.Errorf(resp.ErrMsg))
+					errs = append(errs, errors.New(resp.ErrMsg))
 					continue
 				}
 			}
@@ -586,8 +590,16 @@ func isAssociationExists(assts []*metadata.InstAsst, objectID string, instID int
 }
 
 func (lgc *Logics) saveHistory(report *metadata.NetcollectReport, success bool) error {
+	return lgc.insertHistory(metadata.NetcollectHistory{NetcollectReport: *report, Success: success})
+}
+
+// saveAutoHistory saves the audit trail of the changes confirmed by the confirm policy
+func (lgc *Logics) saveAutoHistory(report *metadata.NetcollectReport, success bool, remark string) error {
+	return lgc.insertHistory(metadata.NetcollectHistory{NetcollectReport: *report, Success: success, AutoConfirmed: true, Remark: remark})
+}
+
+func (lgc *Logics) insertHistory(history metadata.NetcollectHistory) error {
 	rid := util.ExtractRequestIDFromContext(lgc.ctx)
-	history := metadata.NetcollectHistory{NetcollectReport: *report, Success: success}
 	err := lgc.db.Table(common.BKTableNameNetcollectHistory).Insert(lgc.ctx, history)
 	if err != nil {
 		blog.Errorf("[NetDevice][ConfirmReport] save history %+v failed: %v, rid: %s", history, err, rid)
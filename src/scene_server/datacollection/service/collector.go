@@ -60,7 +60,9 @@ func (s *Service) UpdateCollector(req *restful.Request, resp *restful.Response)
 		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
 		return
 	}
-	blog.Infof("[NetDevice][UpdateCollector] update by %+v", cond)
+	logged := cond
+	logged.Config.HideSecrets()
+	blog.Infof("[NetDevice][UpdateCollector] update by %+v", logged)
 
 	if cond.BizID <= 0 {
 		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsLostField, common.BKAppIDField)})
@@ -0,0 +1,101 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/emicklei/go-restful"
+)
+
+func (s *Service) SearchDiscoverSchema(req *restful.Request, resp *restful.Response) {
+	pHeader := req.Request.Header
+	rid := util.GetHTTPCCRequestID(pHeader)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pHeader))
+
+	param := metadata.ParamSearchDiscoverSchema{}
+	if err := json.NewDecoder(req.Request.Body).Decode(&param); err != nil {
+		blog.Errorf("[DiscoverSchema][SearchDiscoverSchema] decode body failed, err: %v, rid: %s", err, rid)
+		_ = resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+
+	result, err := s.Logics.SearchDiscoverSchema(pHeader, param)
+	if err != nil {
+		blog.Errorf("[DiscoverSchema][SearchDiscoverSchema] SearchDiscoverSchema failed, err: %v, rid: %s", err, rid)
+		_ = resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: defErr.Error(common.CCErrCollectDiscoverSchemaSearchFail)})
+		return
+	}
+
+	_ = resp.WriteEntity(metadata.NewSuccessResp(metadata.RspDiscoverSchema{
+		Count: uint64(len(result)),
+		Info:  result,
+	}))
+}
+
+func (s *Service) UpdateDiscoverSchema(req *restful.Request, resp *restful.Response) {
+	pHeader := req.Request.Header
+	rid := util.GetHTTPCCRequestID(pHeader)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pHeader))
+
+	schema := metadata.DiscoverSchema{}
+	if err := json.NewDecoder(req.Request.Body).Decode(&schema); err != nil {
+		blog.Errorf("[DiscoverSchema][UpdateDiscoverSchema] decode body failed, err: %v, rid: %s", err, rid)
+		_ = resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	if err := schema.Validate(); err != nil {
+		blog.Errorf("[DiscoverSchema][UpdateDiscoverSchema] invalid schema %+v, err: %v, rid: %s", schema, err, rid)
+		_ = resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsInvalid, err.Error())})
+		return
+	}
+
+	if err := s.Logics.UpdateDiscoverSchema(pHeader, schema); err != nil {
+		blog.Errorf("[DiscoverSchema][UpdateDiscoverSchema] UpdateDiscoverSchema failed, err: %v, rid: %s", err, rid)
+		_ = resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: defErr.Error(common.CCErrCollectDiscoverSchemaUpdateFail)})
+		return
+	}
+
+	_ = resp.WriteEntity(metadata.NewSuccessResp(nil))
+}
+
+func (s *Service) DeleteDiscoverSchema(req *restful.Request, resp *restful.Response) {
+	pHeader := req.Request.Header
+	rid := util.GetHTTPCCRequestID(pHeader)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pHeader))
+
+	param := metadata.ParamDeleteDiscoverSchema{}
+	if err := json.NewDecoder(req.Request.Body).Decode(&param); err != nil {
+		blog.Errorf("[DiscoverSchema][DeleteDiscoverSchema] decode body failed, err: %v, rid: %s", err, rid)
+		_ = resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	if len(param.Sources) == 0 {
+		_ = resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsNeedSet, "sources")})
+		return
+	}
+
+	if err := s.Logics.DeleteDiscoverSchema(pHeader, param); err != nil {
+		blog.Errorf("[DiscoverSchema][DeleteDiscoverSchema] DeleteDiscoverSchema failed, err: %v, rid: %s", err, rid)
+		_ = resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: defErr.Error(common.CCErrCollectDiscoverSchemaDeleteFail)})
+		return
+	}
+
+	_ = resp.WriteEntity(metadata.NewSuccessResp(nil))
+}
@@ -0,0 +1,101 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/emicklei/go-restful"
+)
+
+func (s *Service) SearchConfirmPolicy(req *restful.Request, resp *restful.Response) {
+	pHeader := req.Request.Header
+	rid := util.GetHTTPCCRequestID(pHeader)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pHeader))
+
+	param := metadata.ParamSearchNetcollectPolicy{}
+	if err := json.NewDecoder(req.Request.Body).Decode(&param); err != nil {
+		blog.Errorf("[NetDevice][SearchConfirmPolicy] decode body failed, err: %v, rid: %s", err, rid)
+		_ = resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+
+	result, err := s.Logics.SearchConfirmPolicy(pHeader, param)
+	if err != nil {
+		blog.Errorf("[NetDevice][SearchConfirmPolicy] SearchConfirmPolicy failed, err: %v, rid: %s", err, rid)
+		_ = resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: defErr.Error(common.CCErrCollectNetPolicySearchFail)})
+		return
+	}
+
+	_ = resp.WriteEntity(metadata.NewSuccessResp(metadata.RspNetcollectPolicy{
+		Count: uint64(len(result)),
+		Info:  result,
+	}))
+}
+
+func (s *Service) UpdateConfirmPolicy(req *restful.Request, resp *restful.Response) {
+	pHeader := req.Request.Header
+	rid := util.GetHTTPCCRequestID(pHeader)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pHeader))
+
+	policy := metadata.NetcollectConfirmPolicy{}
+	if err := json.NewDecoder(req.Request.Body).Decode(&policy); err != nil {
+		blog.Errorf("[NetDevice][UpdateConfirmPolicy] decode body failed, err: %v, rid: %s", err, rid)
+		_ = resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	if err := policy.Validate(); err != nil {
+		blog.Errorf("[NetDevice][UpdateConfirmPolicy] invalid policy %+v, err: %v, rid: %s", policy, err, rid)
+		_ = resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsInvalid, err.Error())})
+		return
+	}
+
+	if err := s.Logics.UpdateConfirmPolicy(pHeader, policy); err != nil {
+		blog.Errorf("[NetDevice][UpdateConfirmPolicy] UpdateConfirmPolicy failed, err: %v, rid: %s", err, rid)
+		_ = resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: defErr.Error(common.CCErrCollectNetPolicyUpdateFail)})
+		return
+	}
+
+	_ = resp.WriteEntity(metadata.NewSuccessResp(nil))
+}
+
+func (s *Service) DeleteConfirmPolicy(req *restful.Request, resp *restful.Response) {
+	pHeader := req.Request.Header
+	rid := util.GetHTTPCCRequestID(pHeader)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pHeader))
+
+	param := metadata.ParamDeleteNetcollectPolicy{}
+	if err := json.NewDecoder(req.Request.Body).Decode(&param); err != nil {
+		blog.Errorf("[NetDevice][DeleteConfirmPolicy] decode body failed, err: %v, rid: %s", err, rid)
+		_ = resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	if len(param.ObjectIDs) == 0 {
+		_ = resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsNeedSet, "bk_obj_ids")})
+		return
+	}
+
+	if err := s.Logics.DeleteConfirmPolicy(pHeader, param); err != nil {
+		blog.Errorf("[NetDevice][DeleteConfirmPolicy] DeleteConfirmPolicy failed, err: %v, rid: %s", err, rid)
+		_ = resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: defErr.Error(common.CCErrCollectNetPolicyDeleteFail)})
+		return
+	}
+
+	_ = resp.WriteEntity(metadata.NewSuccessResp(nil))
+}
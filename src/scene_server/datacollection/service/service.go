@@ -18,8 +18,10 @@ import (
 	"configcenter/src/common/errors"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/metric"
+	"configcenter/src/common/openapi"
 	"configcenter/src/common/rdapi"
 	"configcenter/src/common/types"
+	"configcenter/src/common/version"
 	"configcenter/src/scene_server/datacollection/logics"
 	"configcenter/src/storage/dal"
 
@@ -70,6 +72,14 @@ func (s *Service) WebService() *restful.Container {
 	api.Route(api.POST("/netcollect/report/action/confirm").To(s.ConfirmReport))
 	api.Route(api.POST("/netcollect/history/action/search").To(s.SearchHistory))
 
+	api.Route(api.POST("/netcollect/policy/action/search").To(s.SearchConfirmPolicy))
+	api.Route(api.POST("/netcollect/policy/action/update").To(s.UpdateConfirmPolicy))
+	api.Route(api.DELETE("/netcollect/policy/action/delete").To(s.DeleteConfirmPolicy))
+
+	api.Route(api.POST("/discover/schema/action/search").To(s.SearchDiscoverSchema))
+	api.Route(api.POST("/discover/schema/action/update").To(s.UpdateDiscoverSchema))
+	api.Route(api.DELETE("/discover/schema/action/delete").To(s.DeleteDiscoverSchema))
+
 	api.Route(api.POST("/netcollect/collector/action/search").To(s.SearchCollector))
 	api.Route(api.POST("/netcollect/collector/action/update").To(s.UpdateCollector))
 	api.Route(api.POST("/netcollect/collector/action/discover").To(s.DiscoverNetDevice))
@@ -78,6 +88,7 @@ func (s *Service) WebService() *restful.Container {
 
 	healthzAPI := new(restful.WebService).Produces(restful.MIME_JSON)
 	healthzAPI.Route(healthzAPI.GET("/healthz").To(s.Healthz))
+	openapi.AddDocumentRoute(healthzAPI, openapi.Info{Title: types.CC_MODULE_DATACOLLECTION, Version: version.CCVersion}, container)
 	container.Add(healthzAPI)
 
 	return container
@@ -110,8 +110,9 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 			errCh <- distribution.SubscribeChannel(subCli)
 		}()
 
+		distribution.RegisterMetrics(engine.Metric().Registry(), cache)
 		go func() {
-			errCh <- distribution.Start(ctx, cache, db, rpcCli)
+			errCh <- distribution.Start(ctx, cache, db, rpcCli, engine.ServiceManageInterface.IsMaster)
 		}()
 
 		break
@@ -31,6 +31,10 @@ import (
 
 func (dh *DistHandler) SendCallback(receiver *metadata.Subscription, event string) (err error) {
 	increaseTotal(dh.cache, receiver.SubscriptionID)
+	start := time.Now()
+	defer func() {
+		observeCallback(receiver.SubscriptionID, err, time.Since(start).Seconds())
+	}()
 
 	body := bytes.NewBufferString(event)
 	req, err := http.NewRequest("POST", receiver.CallbackURL, body)
@@ -22,6 +22,7 @@ import (
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/event_server/dynamicgroup"
 	"configcenter/src/scene_server/event_server/types"
 
 	"gopkg.in/redis.v5"
@@ -59,7 +60,11 @@ func (eh *EventHandler) Run() (err error) {
 		}
 		if err := eh.handleEvent(event); err != nil {
 			blog.Errorf("handle event failed, err: %+v, event: %+v", err, event)
+			eventHandledTotal.WithLabelValues("failed").Inc()
+		} else {
+			eventHandledTotal.WithLabelValues("success").Inc()
 		}
+		eventLastHandledTime.SetToCurrentTime()
 	}
 }
 
@@ -259,6 +264,13 @@ func (eh *EventHandler) popEvent() *metadata.EventInstCtx {
 		blog.Errorf("event distribute fail, unmarshal error: %v, date=[%s]", err, eventBytes)
 		return nil
 	}
+
+	// push the event into types.EventCacheEventQueueGroupKey queue so that dynamicGroupHandler could deal with it
+	if dynamicgroup.IsConcerned(&event) {
+		if err := eh.cache.LPush(types.EventCacheEventQueueGroupKey, eventStr).Err(); err != nil {
+			blog.Errorf("push event %d to dynamic group queue failed, err: %v", event.ID, err)
+		}
+	}
 	return &metadata.EventInstCtx{EventInst: event, Raw: eventStr}
 }
 
@@ -0,0 +1,159 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package distribution
+
+import (
+	"strconv"
+	"strings"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/scene_server/event_server/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/redis.v5"
+)
+
+const (
+	labelQueue          = "queue"
+	labelStatus         = "status"
+	labelSubscriptionID = "subscription_id"
+
+	// distQueueScanCount the number of keys scanned in one step to find the distribute queues
+	distQueueScanCount = 100
+)
+
+var (
+	eventHandledTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cmdb_event_handled_total",
+			Help: "number of the event instances handled by the distributor.",
+		},
+		[]string{labelStatus},
+	)
+
+	eventLastHandledTime = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "cmdb_event_last_handled_timestamp_seconds",
+			Help: "unix timestamp of the last handled event instance, used to find the stuck queue.",
+		},
+	)
+
+	callbackTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cmdb_event_callback_total",
+			Help: "number of the event callbacks sent to the subscribers.",
+		},
+		[]string{labelSubscriptionID, labelStatus},
+	)
+
+	callbackDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "cmdb_event_callback_duration_seconds",
+			Help: "duration of the event callbacks sent to the subscribers.",
+		},
+		[]string{labelSubscriptionID},
+	)
+)
+
+// RegisterMetrics register the metrics of the event distribution, the queue length is read from the cache on scrape
+func RegisterMetrics(registry prometheus.Registerer, cache *redis.Client) {
+	registry.MustRegister(eventHandledTotal, eventLastHandledTime, callbackTotal, callbackDuration)
+	registry.MustRegister(&queueCollector{
+		cache: cache,
+		queueLength: prometheus.NewDesc(
+			"cmdb_event_queue_length",
+			"current number of the event instances waiting in the queue.",
+			[]string{labelQueue}, nil,
+		),
+		distQueueLength: prometheus.NewDesc(
+			"cmdb_event_distribute_queue_length",
+			"current number of the events waiting to be sent to the subscriber.",
+			[]string{labelSubscriptionID}, nil,
+		),
+	})
+}
+
+func observeCallback(subscriptionID int64, err error, seconds float64) {
+	status := "success"
+	if err != nil {
+		status = "failed"
+	}
+	subID := strconv.FormatInt(subscriptionID, 10)
+	callbackTotal.WithLabelValues(subID, status).Inc()
+	callbackDuration.WithLabelValues(subID).Observe(seconds)
+}
+
+// queueCache the commands of the cache used by queueCollector
+type queueCache interface {
+	LLen(key string) *redis.IntCmd
+	Scan(cursor uint64, match string, count int64) *redis.ScanCmd
+}
+
+// queueCollector collect the length of the event queues in cache
+type queueCollector struct {
+	cache           queueCache
+	queueLength     *prometheus.Desc
+	distQueueLength *prometheus.Desc
+}
+
+// Describe implement prometheus.Collector interface
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.queueLength
+	ch <- c.distQueueLength
+}
+
+// Collect implement prometheus.Collector interface
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	queues := map[string]string{
+		"inst_queue":           types.EventCacheEventQueueKey,
+		"inst_queue_duplicate": types.EventCacheEventQueueDuplicateKey,
+		"inst_queue_group":     types.EventCacheEventQueueGroupKey,
+	}
+	for name, key := range queues {
+		length, err := c.cache.LLen(key).Result()
+		if err != nil {
+			blog.Errorf("get length of event queue %s failed, err: %v", key, err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.queueLength, prometheus.GaugeValue, float64(length), name)
+	}
+
+	// the distribute queues are found by SCAN, KEYS would block the cache on every scrape
+	scanned := make(map[string]bool)
+	var cursor uint64
+	for {
+		keys, next, err := c.cache.Scan(cursor, types.EventCacheDistQueuePrefix+"*", distQueueScanCount).Result()
+		if err != nil {
+			blog.Errorf("scan distribute queues failed, err: %v", err)
+			return
+		}
+		for _, key := range keys {
+			// a key may be returned more than once by SCAN
+			if scanned[key] {
+				continue
+			}
+			scanned[key] = true
+			length, err := c.cache.LLen(key).Result()
+			if err != nil {
+				blog.Errorf("get length of distribute queue %s failed, err: %v", key, err)
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(c.distQueueLength, prometheus.GaugeValue, float64(length),
+				strings.TrimPrefix(key, types.EventCacheDistQueuePrefix))
+		}
+		if next == 0 {
+			return
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package distribution
+
+import (
+	"errors"
+	"testing"
+
+	"configcenter/src/scene_server/event_server/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/redis.v5"
+)
+
+// fakeQueueCache returns the keys of the pages in order, and the lengths of the queues
+type fakeQueueCache struct {
+	pages   [][]string
+	lengths map[string]int64
+	scanErr error
+	cursors []uint64
+}
+
+func (c *fakeQueueCache) LLen(key string) *redis.IntCmd {
+	length, exist := c.lengths[key]
+	if !exist {
+		return redis.NewIntResult(0, errors.New("no such key"))
+	}
+	return redis.NewIntResult(length, nil)
+}
+
+func (c *fakeQueueCache) Scan(cursor uint64, match string, count int64) *redis.ScanCmd {
+	c.cursors = append(c.cursors, cursor)
+	if c.scanErr != nil {
+		return redis.NewScanCmdResult(nil, 0, c.scanErr)
+	}
+	next := cursor + 1
+	if int(next) >= len(c.pages) {
+		next = 0
+	}
+	return redis.NewScanCmdResult(c.pages[cursor], next, nil)
+}
+
+func collectQueueLengths(t *testing.T, cache queueCache) map[string]float64 {
+	collector := &queueCollector{
+		cache:           cache,
+		queueLength:     prometheus.NewDesc("queue_length", "", []string{labelQueue}, nil),
+		distQueueLength: prometheus.NewDesc("dist_queue_length", "", []string{labelSubscriptionID}, nil),
+	}
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	lengths := make(map[string]float64)
+	for metric := range ch {
+		m := new(dto.Metric)
+		require.NoError(t, metric.Write(m))
+		label := m.GetLabel()[0]
+		key := label.GetName() + "=" + label.GetValue()
+		require.NotContains(t, lengths, key, "the metric is collected more than once")
+		lengths[key] = m.GetGauge().GetValue()
+	}
+	return lengths
+}
+
+func TestQueueCollector(t *testing.T) {
+	queues := map[string]int64{
+		types.EventCacheEventQueueKey:          3,
+		types.EventCacheEventQueueDuplicateKey: 0,
+		types.EventCacheEventQueueGroupKey:     1,
+	}
+	tests := []struct {
+		name    string
+		pages   [][]string
+		lengths map[string]int64
+		scanErr error
+		want    map[string]float64
+		cursors []uint64
+	}{
+		{
+			name: "scan all pages",
+			pages: [][]string{
+				{types.EventCacheDistQueuePrefix + "1"},
+				{},
+				{types.EventCacheDistQueuePrefix + "2", types.EventCacheDistQueuePrefix + "1"},
+			},
+			lengths: map[string]int64{types.EventCacheDistQueuePrefix + "1": 5, types.EventCacheDistQueuePrefix + "2": 7},
+			want:    map[string]float64{"subscription_id=1": 5, "subscription_id=2": 7},
+			cursors: []uint64{0, 1, 2},
+		},
+		{
+			name:    "skip the queue removed while scanning",
+			pages:   [][]string{{types.EventCacheDistQueuePrefix + "1", types.EventCacheDistQueuePrefix + "3"}},
+			lengths: map[string]int64{types.EventCacheDistQueuePrefix + "1": 2},
+			want:    map[string]float64{"subscription_id=1": 2},
+			cursors: []uint64{0},
+		},
+		{
+			name:    "scan failed",
+			scanErr: errors.New("scan failed"),
+			want:    map[string]float64{},
+			cursors: []uint64{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &fakeQueueCache{pages: tt.pages, lengths: make(map[string]int64), scanErr: tt.scanErr}
+			for key, length := range queues {
+				cache.lengths[key] = length
+			}
+			for key, length := range tt.lengths {
+				cache.lengths[key] = length
+			}
+
+			want := map[string]float64{"queue=inst_queue": 3, "queue=inst_queue_duplicate": 0, "queue=inst_queue_group": 1}
+			for key, length := range tt.want {
+				want[key] = length
+			}
+			require.Equal(t, want, collectQueueLengths(t, cache))
+			require.Equal(t, tt.cursors, cache.cursors)
+		})
+	}
+}
@@ -24,12 +24,13 @@ import (
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/util"
+	"configcenter/src/scene_server/event_server/dynamicgroup"
 	"configcenter/src/scene_server/event_server/identifier"
 	"configcenter/src/storage/dal"
 	"configcenter/src/storage/rpc"
 )
 
-func Start(ctx context.Context, cache *redis.Client, db dal.RDB, rc rpc.Client) error {
+func Start(ctx context.Context, cache *redis.Client, db dal.RDB, rc rpc.Client, isMaster func() bool) error {
 	chErr := make(chan error, 1)
 	err := migrateIDToMongo(ctx, cache, db)
 	if err != nil {
@@ -51,6 +52,11 @@ func Start(ctx context.Context, cache *redis.Client, db dal.RDB, rc rpc.Client)
 		chErr <- ih.Run()
 	}()
 
+	gh := dynamicgroup.NewDynamicGroupHandler(ctx, cache, db, isMaster)
+	go func() {
+		chErr <- gh.Run()
+	}()
+
 	go cleanExpiredEvents(cache)
 
 	if rc != nil {
@@ -0,0 +1,193 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dynamicgroup
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/scene_server/event_server/types"
+)
+
+// syncGroup recompute the membership of the group, only the given instances are checked if instIDs is not nil,
+// and the members joined or left the group are published.
+func (h *DynamicGroupHandler) syncGroup(group metadata.UserConfigMeta, instIDs []int64) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	condition, err := metadata.ParseDynamicGroupCondition(group.Info)
+	if err != nil {
+		return err
+	}
+	ruleFilter, key, err := condition.ToMgo()
+	if err != nil {
+		return fmt.Errorf("invalid condition, key: %s, err: %v", key, err)
+	}
+	scopeFilter, err := h.scopeFilter(group)
+	if err != nil {
+		return err
+	}
+
+	instIDField := common.GetInstIDField(group.ObjID)
+	filters := []map[string]interface{}{scopeFilter, ruleFilter}
+	memberFilter := map[string]interface{}{"group_id": group.ID}
+	if instIDs != nil {
+		filters = append(filters, map[string]interface{}{instIDField: map[string]interface{}{common.BKDBIN: instIDs}})
+		memberFilter["bk_inst_id"] = map[string]interface{}{common.BKDBIN: instIDs}
+	}
+
+	// the instances matched the group now
+	filter := util.SetQueryOwner(map[string]interface{}{common.BKDBAND: filters}, group.OwnerID)
+	instances := make([]map[string]interface{}, 0)
+	err = h.db.Table(common.GetInstTableName(group.ObjID)).Find(filter).Fields(instIDField).All(h.ctx, &instances)
+	if err != nil {
+		return fmt.Errorf("find instances failed, filter: %+v, err: %v", filter, err)
+	}
+	matched := make(map[int64]bool, len(instances))
+	for _, inst := range instances {
+		instID, err := util.GetInt64ByInterface(inst[instIDField])
+		if err != nil {
+			blog.Errorf("dynamic group: get instance id of %+v failed, err: %v", inst, err)
+			continue
+		}
+		matched[instID] = true
+	}
+
+	// the members kept before
+	members := make([]metadata.DynamicGroupMember, 0)
+	if err := h.db.Table(common.BKTableNameDynamicGroupMember).Find(memberFilter).All(h.ctx, &members); err != nil {
+		return fmt.Errorf("find members failed, filter: %+v, err: %v", memberFilter, err)
+	}
+
+	removed := make([]metadata.DynamicGroupMember, 0)
+	removedIDs := make([]int64, 0)
+	for _, member := range members {
+		if matched[member.InstID] {
+			delete(matched, member.InstID)
+			continue
+		}
+		removed = append(removed, member)
+		removedIDs = append(removedIDs, member.InstID)
+	}
+	added := make([]metadata.DynamicGroupMember, 0, len(matched))
+	now := time.Now().UTC()
+	for instID := range matched {
+		added = append(added, metadata.DynamicGroupMember{
+			GroupID:    group.ID,
+			GroupName:  group.Name,
+			BizID:      group.AppID,
+			ObjID:      group.ObjID,
+			InstID:     instID,
+			OwnerID:    group.OwnerID,
+			CreateTime: now,
+		})
+	}
+
+	if len(removed) > 0 {
+		removeFilter := map[string]interface{}{
+			"group_id":   group.ID,
+			"bk_inst_id": map[string]interface{}{common.BKDBIN: removedIDs},
+		}
+		if err := h.db.Table(common.BKTableNameDynamicGroupMember).Delete(h.ctx, removeFilter); err != nil {
+			return fmt.Errorf("delete members failed, filter: %+v, err: %v", removeFilter, err)
+		}
+		h.publish(metadata.EventObjTypeGroupMemberRemoved, removed)
+	}
+	return h.addMembers(added)
+}
+
+// addMembers insert the members one by one, the members inserted by the former master are skipped, and only the
+// members inserted are published.
+func (h *DynamicGroupHandler) addMembers(members []metadata.DynamicGroupMember) error {
+	inserted := make([]metadata.DynamicGroupMember, 0, len(members))
+	var insertErr error
+	for _, member := range members {
+		if err := h.db.Table(common.BKTableNameDynamicGroupMember).Insert(h.ctx, member); err != nil {
+			if h.db.IsDuplicatedError(err) {
+				continue
+			}
+			insertErr = fmt.Errorf("insert member %d failed, err: %v", member.InstID, err)
+			break
+		}
+		inserted = append(inserted, member)
+	}
+	if len(inserted) > 0 {
+		h.publish(metadata.EventObjTypeGroupMemberAdded, inserted)
+	}
+	return insertErr
+}
+
+// scopeFilter the members of the dynamic group must be in the business
+func (h *DynamicGroupHandler) scopeFilter(group metadata.UserConfigMeta) (map[string]interface{}, error) {
+	switch group.ObjID {
+	case common.BKInnerObjIDHost:
+		relations := make([]metadata.ModuleHost, 0)
+		relationFilter := util.SetQueryOwner(map[string]interface{}{common.BKAppIDField: group.AppID}, group.OwnerID)
+		err := h.db.Table(common.BKTableNameModuleHostConfig).Find(relationFilter).Fields(common.BKHostIDField).All(h.ctx, &relations)
+		if err != nil {
+			return nil, fmt.Errorf("get hosts of business %d failed, err: %v", group.AppID, err)
+		}
+		hostIDs := make([]int64, 0, len(relations))
+		for _, relation := range relations {
+			hostIDs = append(hostIDs, relation.HostID)
+		}
+		return map[string]interface{}{
+			common.BKHostIDField: map[string]interface{}{common.BKDBIN: util.IntArrayUnique(hostIDs)},
+		}, nil
+
+	case common.BKInnerObjIDApp, common.BKInnerObjIDSet, common.BKInnerObjIDModule, common.BKInnerObjIDProc:
+		return map[string]interface{}{common.BKAppIDField: group.AppID}, nil
+
+	default:
+		return map[string]interface{}{
+			common.BKObjIDField: group.ObjID,
+			common.BKDBOR: []map[string]interface{}{
+				{common.BKAppIDField: group.AppID},
+				{common.MetadataLabelBiz: strconv.FormatInt(group.AppID, 10)},
+			},
+		}, nil
+	}
+}
+
+// publish push the member change event of the group into the event queue, so that it can be distributed to
+// the subscribers like the other events.
+func (h *DynamicGroupHandler) publish(objType string, members []metadata.DynamicGroupMember) {
+	event := metadata.EventInst{
+		EventType:   metadata.EventTypeRelation,
+		ObjType:     objType,
+		Action:      metadata.EventActionCreate,
+		ActionTime:  metadata.Now(),
+		OwnerID:     members[0].OwnerID,
+		RequestTime: metadata.Now(),
+	}
+	for _, member := range members {
+		if objType == metadata.EventObjTypeGroupMemberRemoved {
+			event.Action = metadata.EventActionDelete
+			event.Data = append(event.Data, metadata.EventData{PreData: member})
+		} else {
+			event.Data = append(event.Data, metadata.EventData{CurData: member})
+		}
+	}
+
+	event.ID = h.cache.Incr(types.EventCacheEventIDKey).Val()
+	if err := h.cache.LPush(types.EventCacheEventQueueKey, &event).Err(); err != nil {
+		blog.Errorf("dynamic group: push event %s of group %s failed, err: %v", objType, members[0].GroupID, err)
+		return
+	}
+	blog.V(4).Infof("dynamic group: pushed event %s of group %s, %d members", objType, members[0].GroupID, len(members))
+}
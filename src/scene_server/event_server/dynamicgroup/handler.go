@@ -0,0 +1,227 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package dynamicgroup keep the membership of the materialized dynamic groups, the instances matched the rules of
+// the group are recomputed when the instance or host relation events flow through, and the members joined or left
+// the group are published as group_member_added/group_member_removed events.
+package dynamicgroup
+
+import (
+	"context"
+	"encoding/json"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/scene_server/event_server/types"
+	"configcenter/src/storage/dal"
+
+	"gopkg.in/redis.v5"
+)
+
+// syncPeriod the period to recompute all of the materialized dynamic groups, so that the membership would be
+// corrected if the events are lost or the groups are changed.
+var syncPeriod = time.Minute * 10
+
+const nilStr = "nil"
+
+type DynamicGroupHandler struct {
+	ctx   context.Context
+	cache *redis.Client
+	db    dal.RDB
+	// isMaster only the master event server recompute the groups, otherwise the members would be written and
+	// published by every event server.
+	isMaster func() bool
+	// lock make the recomputation of the groups serial
+	lock sync.Mutex
+}
+
+func NewDynamicGroupHandler(ctx context.Context, cache *redis.Client, db dal.RDB, isMaster func() bool) *DynamicGroupHandler {
+	return &DynamicGroupHandler{ctx: ctx, cache: cache, db: db, isMaster: isMaster}
+}
+
+// IsConcerned returns whether the event may change the membership of the dynamic groups
+func IsConcerned(event *metadata.EventInst) bool {
+	if event.EventType == metadata.EventTypeInstData {
+		return event.ObjType != common.BKInnerObjIDPlat
+	}
+	return event.EventType == metadata.EventTypeRelation && event.ObjType == metadata.EventObjTypeModuleTransfer
+}
+
+func (h *DynamicGroupHandler) Run() error {
+	blog.Infof("dynamic group: handle materialized dynamic groups started")
+	go func() {
+		if h.isMaster() {
+			h.syncAll()
+		}
+		for range time.Tick(syncPeriod) {
+			if h.isMaster() {
+				h.syncAll()
+			}
+		}
+	}()
+	go func() {
+		if err := h.handleEventLoop(); err != nil {
+			blog.Errorf("dynamic group: handleEventLoop failed, err: %+v", err)
+		}
+	}()
+	select {}
+}
+
+func (h *DynamicGroupHandler) handleEventLoop() error {
+	defer func() {
+		procErr := recover()
+		if procErr != nil {
+			blog.Errorf("dynamic group: handleEventLoop panic: %v, stack:\n%s", procErr, debug.Stack())
+		}
+		// keep handleEventLoop run forever
+		go func() {
+			if err := h.handleEventLoop(); err != nil {
+				blog.Errorf("dynamic group: handleEventLoop failed, err: %+v", err)
+			}
+		}()
+	}()
+	for {
+		if !h.isMaster() {
+			// leave the events in the queue to the master
+			time.Sleep(time.Second * 2)
+			continue
+		}
+		event := h.popEvent()
+		if nil == event {
+			time.Sleep(time.Second * 2)
+			continue
+		}
+		h.handleEvent(event)
+	}
+}
+
+func (h *DynamicGroupHandler) popEvent() *metadata.EventInstCtx {
+	eventStrs := h.cache.BRPop(time.Second*60, types.EventCacheEventQueueGroupKey).Val()
+	if len(eventStrs) == 0 || eventStrs[1] == nilStr || len(eventStrs[1]) == 0 {
+		return nil
+	}
+
+	// eventStrs format is []string{key, event}
+	eventStr := eventStrs[1]
+	event := metadata.EventInst{}
+	if err := json.Unmarshal([]byte(eventStr), &event); err != nil {
+		blog.Errorf("dynamic group: unmarshal event failed, err: %+v, data: [%s]", err, eventStr)
+		return nil
+	}
+	return &metadata.EventInstCtx{EventInst: event, Raw: eventStr}
+}
+
+func (h *DynamicGroupHandler) handleEvent(event *metadata.EventInstCtx) {
+	objID := event.ObjType
+	if event.EventType == metadata.EventTypeRelation {
+		// the host moved into or out of the business
+		objID = common.BKInnerObjIDHost
+	}
+
+	instIDs := make(map[string][]int64)
+	for _, data := range event.Data {
+		inst, ok := data.CurData.(map[string]interface{})
+		if !ok || event.Action == metadata.EventActionDelete {
+			inst, ok = data.PreData.(map[string]interface{})
+		}
+		if !ok {
+			continue
+		}
+		instObjID := objID
+		if objID == common.BKInnerObjIDObject {
+			instObjID = util.GetStrByInterface(inst[common.BKObjIDField])
+		}
+		instID, err := util.GetInt64ByInterface(inst[common.GetInstIDField(objID)])
+		if err != nil || instObjID == "" {
+			blog.Errorf("dynamic group: get instance id of event %d failed, data: %+v", event.ID, inst)
+			continue
+		}
+		instIDs[instObjID] = append(instIDs[instObjID], instID)
+	}
+
+	for instObjID, ids := range instIDs {
+		groups, err := h.findGroups(instObjID, event.OwnerID)
+		if err != nil {
+			continue
+		}
+		for _, group := range groups {
+			if err := h.syncGroup(group, util.IntArrayUnique(ids)); err != nil {
+				blog.Errorf("dynamic group: sync group %s with event %d failed, err: %v", group.ID, event.ID, err)
+			}
+		}
+	}
+}
+
+// syncAll recompute all of the materialized groups, and remove the members of the groups which are deleted or no
+// longer materialized.
+func (h *DynamicGroupHandler) syncAll() {
+	groups, err := h.findGroups("", "")
+	if err != nil {
+		return
+	}
+
+	groupIDs := make([]string, 0, len(groups))
+	for _, group := range groups {
+		groupIDs = append(groupIDs, group.ID)
+		if err := h.syncGroup(group, nil); err != nil {
+			blog.Errorf("dynamic group: sync group %s failed, err: %v", group.ID, err)
+		}
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	filter := map[string]interface{}{"group_id": map[string]interface{}{common.BKDBNIN: groupIDs}}
+	staleMembers := make([]metadata.DynamicGroupMember, 0)
+	if err := h.db.Table(common.BKTableNameDynamicGroupMember).Find(filter).All(h.ctx, &staleMembers); err != nil {
+		blog.Errorf("dynamic group: find the members of the removed groups failed, err: %v", err)
+		return
+	}
+	if len(staleMembers) == 0 {
+		return
+	}
+	if err := h.db.Table(common.BKTableNameDynamicGroupMember).Delete(h.ctx, filter); err != nil {
+		blog.Errorf("dynamic group: delete the members of the removed groups failed, err: %v", err)
+		return
+	}
+	staleGroupMembers := make(map[string][]metadata.DynamicGroupMember)
+	for _, member := range staleMembers {
+		staleGroupMembers[member.GroupID] = append(staleGroupMembers[member.GroupID], member)
+	}
+	for _, members := range staleGroupMembers {
+		h.publish(metadata.EventObjTypeGroupMemberRemoved, members)
+	}
+}
+
+// findGroups find the materialized groups of the model, all of the groups are returned if objID is empty
+func (h *DynamicGroupHandler) findGroups(objID, ownerID string) ([]metadata.UserConfigMeta, error) {
+	filter := map[string]interface{}{
+		"materialize":       true,
+		common.BKObjIDField: map[string]interface{}{common.BKDBNE: ""},
+	}
+	if objID != "" {
+		filter[common.BKObjIDField] = objID
+	}
+	if ownerID != "" {
+		filter[common.BKOwnerIDField] = ownerID
+	}
+	groups := make([]metadata.UserConfigMeta, 0)
+	if err := h.db.Table(common.BKTableNameUserAPI).Find(filter).All(h.ctx, &groups); err != nil {
+		blog.Errorf("dynamic group: find materialized groups failed, filter: %+v, err: %v", filter, err)
+		return nil, err
+	}
+	return groups, nil
+}
@@ -21,8 +21,10 @@ import (
 	"configcenter/src/common/errors"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/metric"
+	"configcenter/src/common/openapi"
 	"configcenter/src/common/rdapi"
 	"configcenter/src/common/types"
+	"configcenter/src/common/version"
 	"configcenter/src/storage/dal"
 
 	"github.com/emicklei/go-restful"
@@ -80,6 +82,7 @@ func (s *Service) WebService() *restful.Container {
 
 	healthzAPI := new(restful.WebService).Produces(restful.MIME_JSON)
 	healthzAPI.Route(healthzAPI.GET("/healthz").To(s.Healthz))
+	openapi.AddDocumentRoute(healthzAPI, openapi.Info{Title: types.CC_MODULE_EVENTSERVER, Version: version.CCVersion}, container)
 	container.Add(healthzAPI)
 
 	return container
@@ -23,6 +23,7 @@ const (
 	EventCacheEventIDKey             = common.BKCacheKeyV3Prefix + "event:inst_id"
 	EventCacheEventQueueKey          = common.BKCacheKeyV3Prefix + "event:inst_queue"
 	EventCacheEventQueueDuplicateKey = common.BKCacheKeyV3Prefix + "event:inst_queue_duplicate"
+	EventCacheEventQueueGroupKey     = common.BKCacheKeyV3Prefix + "event:inst_queue_group"
 	EventCacheEventPendingKey        = common.BKCacheKeyV3Prefix + "event:inst_pending"
 	EventCacheEventRunningPrefix     = common.BKCacheKeyV3Prefix + "event:inst_running_"
 	EventCacheEventTimeoutKey        = common.BKCacheKeyV3Prefix + "event:inst_timeout"
@@ -37,8 +37,8 @@ func NewServerOption() *ServerOption {
 // AddFlags add flags
 func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.ServConf.AddrPort, "addrport", "127.0.0.1:60002", "The ip address and port for the serve on")
-	fs.StringVar(&s.ServConf.RegDiscover, "regdiscv", "", "hosts of register and discover server. e.g: 127.0.0.1:2181")
-	fs.StringVar(&s.ServConf.ExConfig, "config", "", "The config path. e.g conf/api.conf")
+	fs.StringVar(&s.ServConf.RegDiscover, "regdiscv", "", "address of register and discover server. e.g: 127.0.0.1:2181, zk://127.0.0.1:2181, file:///data/cmdb/services.yaml or dns://cmdb.svc.cluster.local")
+	fs.StringVar(&s.ServConf.ExConfig, "config", "", "The config path. e.g conf/api.conf, or the directory of the config files which are reloaded once changed")
 }
 
 type Config struct {
@@ -29,6 +29,7 @@ import (
 	"configcenter/src/common/types"
 	"configcenter/src/common/version"
 	"configcenter/src/scene_server/host_server/app/options"
+	"configcenter/src/scene_server/host_server/logics"
 	hostsvc "configcenter/src/scene_server/host_server/service"
 	"configcenter/src/storage/dal/redis"
 
@@ -83,6 +84,7 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 		return fmt.Errorf("new host authorizer failed, err: %+v", err)
 	}
 	authManager := extensions.NewAuthManager(engine.CoreAPI, authorizer)
+	logics.RegisterMetrics(engine.Metric().Registry())
 	service.AuthManager = authManager
 	service.Engine = engine
 	service.Config = &hostSrv.Config
@@ -551,6 +551,7 @@ func (lgc *Logics) ExecSync(ctx context.Context, taskInfo meta.CloudTaskInfo) {
 			}
 		}
 		lgc.CloudSyncHistory(ctx, taskInfo.TaskID, startTime, cloudHistory)
+		observeCloudSync(taskInfo.TaskID, cloudHistory.Status, cloudHistory.FailReason, float64(time.Now().Unix()-startTime))
 	}()
 
 	// obtain the hosts from cc_HostBase
@@ -797,7 +798,10 @@ func (lgc *Logics) AddCloudHosts(ctx context.Context, newCloudHost []mapstr.MapS
 		hostInfoMap[int64(index)][common.BKCloudIDField] = 1
 	}
 
-	hostIDs, succ, updateErrRow, errRow, ok := lgc.AddHost(ctx, appID, []int64{moduleID}, util.GetOwnerID(lgc.header), hostInfoMap, hostList.InputType)
+	// mark the hosts as written by cloud sync, so that the source priorities of the fields take effect
+	header := copyHeader(ctx, lgc.header)
+	header.Set(common.BKHTTPDataSource, common.FieldSourceCloudSync)
+	hostIDs, succ, updateErrRow, errRow, ok := lgc.NewFromHeader(header).AddHost(ctx, appID, []int64{moduleID}, util.GetOwnerID(lgc.header), hostInfoMap, hostList.InputType)
 	if ok != nil {
 		blog.Errorf("add host failed, hostIDs: %+v, succ: %v, update: %v, err: %v, %v, rid: %s", hostIDs, succ, updateErrRow, ok, errRow, lgc.rid)
 		return ok
@@ -807,6 +811,8 @@ func (lgc *Logics) AddCloudHosts(ctx context.Context, newCloudHost []mapstr.MapS
 }
 
 func (lgc *Logics) UpdateCloudHosts(ctx context.Context, cloudHostAttr []mapstr.MapStr) error {
+	header := copyHeader(ctx, lgc.header)
+	header.Set(common.BKHTTPDataSource, common.FieldSourceCloudSync)
 	for _, hostInfo := range cloudHostAttr {
 		hostID, err := hostInfo.Int64(common.BKHostIDField)
 		if err != nil {
@@ -822,11 +828,14 @@ func (lgc *Logics) UpdateCloudHosts(ctx context.Context, cloudHostAttr []mapstr.
 			Data:      hostInfo,
 			Condition: mapstr.MapStr{common.BKHostIDField: hostID},
 		}
-		result, err := lgc.CoreAPI.CoreService().Instance().UpdateInstance(ctx, lgc.header, common.BKInnerObjIDHost, updateParam)
+		result, err := lgc.CoreAPI.CoreService().Instance().UpdateInstance(ctx, header, common.BKInnerObjIDHost, updateParam)
 		if err != nil || (err == nil && !result.Result) {
 			blog.Errorf("update host batch failed, ids[%v], err: %v, %v, rid: %s", hostID, err, result.ErrMsg, lgc.rid)
 			return err
 		}
+		for _, rejected := range result.Data.Rejected {
+			blog.Infof("fields %v of host %d are set by the sources with higher priority, skip, rid: %s", rejected.Fields, rejected.InstID, lgc.rid)
+		}
 	}
 	return nil
 }
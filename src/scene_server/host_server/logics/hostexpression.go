@@ -0,0 +1,278 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"strconv"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/querybuilder"
+)
+
+// maxExpressionDeep the max deep of the host search expression tree
+const maxExpressionDeep = 5
+
+// searchByExpression convert the expression tree to the condition of the host, the rules of the other objects are
+// replaced by the ids of the hosts related to the matched instances.
+func (sh *searchHost) searchByExpression() errors.CCError {
+	if sh.hostSearchParam.Expression == nil || sh.hostSearchParam.Expression.Rule == nil {
+		return nil
+	}
+	rule := sh.hostSearchParam.Expression.Rule
+	if rule.GetDeep() > maxExpressionDeep {
+		blog.Errorf("search host by expression failed, exceed max deep %d, rid: %s", maxExpressionDeep, sh.ccRid)
+		return sh.ccErr.Errorf(common.CCErrCommParamsInvalid, "expression")
+	}
+
+	filter, err := sh.expressionToHostFilter(rule, "expression")
+	if err != nil {
+		return err
+	}
+	sh.conds.expressionFilter = filter
+	return nil
+}
+
+func (sh *searchHost) expressionToHostFilter(rule querybuilder.Rule, key string) (map[string]interface{}, errors.CCError) {
+	switch r := rule.(type) {
+	case querybuilder.CombinedRule:
+		if err := r.Condition.Validate(); err != nil {
+			blog.Errorf("search host by expression failed, %s.condition is invalid, err: %v, rid: %s", key, err, sh.ccRid)
+			return nil, sh.ccErr.Errorf(common.CCErrCommParamsInvalid, key+".condition")
+		}
+		if len(r.Rules) == 0 {
+			return nil, sh.ccErr.Errorf(common.CCErrCommParamsNeedSet, key+".rules")
+		}
+		filters := make([]map[string]interface{}, 0, len(r.Rules))
+		for idx, child := range r.Rules {
+			filter, err := sh.expressionToHostFilter(child, key+".rules["+strconv.Itoa(idx)+"]")
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, filter)
+		}
+		if r.Condition == querybuilder.ConditionNot {
+			filters = []map[string]interface{}{{common.BKDBAND: filters}}
+		}
+		operator, _ := r.Condition.ToMgo()
+		return map[string]interface{}{operator: filters}, nil
+
+	case querybuilder.AtomRule:
+		return sh.atomRuleToHostFilter(r, key)
+
+	default:
+		return nil, sh.ccErr.Errorf(common.CCErrCommParamsInvalid, key)
+	}
+}
+
+// atomRuleToHostFilter convert the rule of the object attribute to the condition of the host, the field of the
+// rule without the object id prefix is the attribute of the host.
+func (sh *searchHost) atomRuleToHostFilter(rule querybuilder.AtomRule, key string) (map[string]interface{}, errors.CCError) {
+	objID := common.BKInnerObjIDHost
+	if idx := strings.Index(rule.Field, "."); idx > 0 {
+		// the field like "bk_host_innerip.xx" is the nested field of the host if the prefix isn't a model
+		objIDs, err := sh.getObjectIDs()
+		if err != nil {
+			return nil, err
+		}
+		if objIDs[rule.Field[:idx]] {
+			objID, rule.Field = rule.Field[:idx], rule.Field[idx+1:]
+		}
+	}
+	filter, errKey, err := rule.ToMgo()
+	if err != nil {
+		blog.Errorf("search host by expression failed, %s.%s is invalid, err: %v, rid: %s", key, errKey, err, sh.ccRid)
+		return nil, sh.ccErr.Errorf(common.CCErrCommParamsInvalid, key+"."+errKey)
+	}
+	if objID == common.BKInnerObjIDHost {
+		return filter, nil
+	}
+
+	var hostIDs []int64
+	var ccErr errors.CCError
+	switch objID {
+	case common.BKInnerObjIDPlat:
+		cloudIDs, err := sh.getInstIDsByFilter(objID, filter)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{common.BKCloudIDField: map[string]interface{}{common.BKDBIN: cloudIDs}}, nil
+	case common.BKInnerObjIDApp:
+		hostIDs, ccErr = sh.getHostIDsByBizFilter(filter)
+	case common.BKInnerObjIDSet, common.BKInnerObjIDModule:
+		hostIDs, ccErr = sh.getHostIDsByTopoFilter(objID, filter)
+	default:
+		hostIDs, ccErr = sh.getHostIDsByObjectFilter(objID, filter)
+	}
+	if ccErr != nil {
+		return nil, ccErr
+	}
+	return map[string]interface{}{common.BKHostIDField: map[string]interface{}{common.BKDBIN: hostIDs}}, nil
+}
+
+func (sh *searchHost) getInstIDsByFilter(objID string, filter map[string]interface{}) ([]int64, errors.CCError) {
+	instIDField := common.GetInstIDField(objID)
+	cond := mapstr.NewFromMap(filter)
+	if common.GetObjByType(objID) == common.BKInnerObjIDObject {
+		cond = mapstr.MapStr{common.BKDBAND: []map[string]interface{}{filter, {common.BKObjIDField: objID}}}
+	}
+	query := &metadata.QueryCondition{
+		Condition: cond,
+		Fields:    []string{instIDField},
+		Limit:     metadata.SearchLimit{Offset: 0, Limit: common.BKNoLimit},
+	}
+	result, err := sh.lgc.CoreAPI.CoreService().Instance().ReadInstance(sh.ctx, sh.pheader, objID, query)
+	if err != nil {
+		blog.Errorf("search host by expression, search %s failed, err: %v, input: %+v, rid: %s", objID, err, query, sh.ccRid)
+		return nil, sh.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !result.Result {
+		blog.Errorf("search host by expression, search %s failed, err: %s, input: %+v, rid: %s", objID, result.ErrMsg, query, sh.ccRid)
+		return nil, sh.ccErr.New(result.Code, result.ErrMsg)
+	}
+
+	instIDs := make([]int64, 0, len(result.Data.Info))
+	for _, inst := range result.Data.Info {
+		instID, err := inst.Int64(instIDField)
+		if err != nil {
+			blog.Errorf("search host by expression, convert %s %s to integer failed, inst: %+v, rid: %s", objID, instIDField, inst, sh.ccRid)
+			return nil, sh.ccErr.Errorf(common.CCErrCommInstFieldConvertFail, objID, instIDField, "int", err.Error())
+		}
+		instIDs = append(instIDs, instID)
+	}
+	return instIDs, nil
+}
+
+func (sh *searchHost) getHostIDsByBizFilter(filter map[string]interface{}) ([]int64, errors.CCError) {
+	bizIDs, err := sh.getInstIDsByFilter(common.BKInnerObjIDApp, filter)
+	if err != nil {
+		return nil, err
+	}
+	hostIDs := make([]int64, 0)
+	for _, bizID := range bizIDs {
+		ids, err := sh.lgc.GetHostIDByCond(sh.ctx, metadata.HostModuleRelationRequest{ApplicationID: bizID})
+		if err != nil {
+			return nil, err
+		}
+		hostIDs = append(hostIDs, ids...)
+	}
+	return hostIDs, nil
+}
+
+// getHostIDsByTopoFilter get the hosts in the sets or modules matched the filter
+func (sh *searchHost) getHostIDsByTopoFilter(objID string, filter map[string]interface{}) ([]int64, errors.CCError) {
+	instIDs, err := sh.getInstIDsByFilter(objID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(instIDs) == 0 {
+		return instIDs, nil
+	}
+	cond := metadata.HostModuleRelationRequest{}
+	if objID == common.BKInnerObjIDSet {
+		cond.SetIDArr = instIDs
+	} else {
+		cond.ModuleIDArr = instIDs
+	}
+	return sh.lgc.GetHostIDByCond(sh.ctx, cond)
+}
+
+// getHostIDsByObjectFilter get the hosts under the mainline instances or associated with the instances matched
+// the filter.
+func (sh *searchHost) getHostIDsByObjectFilter(objID string, filter map[string]interface{}) ([]int64, errors.CCError) {
+	instIDs, err := sh.getInstIDsByFilter(objID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(instIDs) == 0 {
+		return instIDs, nil
+	}
+
+	childObjIDs, err := sh.getMainlineChildren()
+	if err != nil {
+		return nil, err
+	}
+	if _, isMainline := childObjIDs[objID]; !isMainline {
+		return sh.lgc.GetHostIDByInstID(sh.ctx, objID, instIDs)
+	}
+
+	// walk down the mainline topology to the sets
+	for objID != common.BKInnerObjIDSet {
+		childObjID := childObjIDs[objID]
+		if childObjID == "" {
+			return make([]int64, 0), nil
+		}
+		instIDs, err = sh.getInstIDsByFilter(childObjID, map[string]interface{}{
+			common.BKParentIDField: map[string]interface{}{common.BKDBIN: instIDs},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(instIDs) == 0 {
+			return instIDs, nil
+		}
+		objID = childObjID
+	}
+	return sh.lgc.GetHostIDByCond(sh.ctx, metadata.HostModuleRelationRequest{SetIDArr: instIDs})
+}
+
+// getObjectIDs returns the ids of all the models, which are the prefixes of the fields of the other objects
+func (sh *searchHost) getObjectIDs() (map[string]bool, errors.CCError) {
+	if sh.objectIDs != nil {
+		return sh.objectIDs, nil
+	}
+	result, err := sh.lgc.CoreAPI.CoreService().Model().ReadModel(sh.ctx, sh.pheader, &metadata.QueryCondition{})
+	if err != nil {
+		blog.Errorf("search host by expression, search models failed, err: %v, rid: %s", err, sh.ccRid)
+		return nil, sh.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !result.Result {
+		blog.Errorf("search host by expression, search models failed, err: %s, rid: %s", result.ErrMsg, sh.ccRid)
+		return nil, sh.ccErr.New(result.Code, result.ErrMsg)
+	}
+
+	sh.objectIDs = make(map[string]bool, len(result.Data.Info))
+	for _, model := range result.Data.Info {
+		sh.objectIDs[model.Spec.ObjectID] = true
+	}
+	return sh.objectIDs, nil
+}
+
+// getMainlineChildren returns the map of mainline object to its child object
+func (sh *searchHost) getMainlineChildren() (map[string]string, errors.CCError) {
+	if sh.mainlineChildren != nil {
+		return sh.mainlineChildren, nil
+	}
+	query := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{common.AssociationKindIDField: common.AssociationKindMainline},
+	}
+	result, err := sh.lgc.CoreAPI.CoreService().Association().ReadModelAssociation(sh.ctx, sh.pheader, query)
+	if err != nil {
+		blog.Errorf("search host by expression, search mainline association failed, err: %v, rid: %s", err, sh.ccRid)
+		return nil, sh.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !result.Result {
+		blog.Errorf("search host by expression, search mainline association failed, err: %s, rid: %s", result.ErrMsg, sh.ccRid)
+		return nil, sh.ccErr.New(result.Code, result.ErrMsg)
+	}
+
+	sh.mainlineChildren = make(map[string]string)
+	for _, asst := range result.Data.Info {
+		sh.mainlineChildren[asst.AsstObjID] = asst.ObjectID
+	}
+	return sh.mainlineChildren, nil
+}
@@ -46,6 +46,7 @@ func (lgc *Logics) SearchHost(ctx context.Context, data *metadata.HostCommonSear
 	if cnt > 0 {
 		retHostInfo.Info = hostInfoArr
 	}
+	retHostInfo.NextCursor = searchHostInst.NextCursor()
 	return retHostInfo, nil
 }
 
@@ -57,6 +58,8 @@ type searchHostConds struct {
 	mainlineCond  metadata.SearchCondition
 	platCond      metadata.SearchCondition
 	objectCondMap map[string][]metadata.ConditionItem
+	// expressionFilter the host condition converted from the expression
+	expressionFilter map[string]interface{}
 }
 
 type searchHostTopologyShowSection struct {
@@ -102,6 +105,12 @@ type searchHost struct {
 	hostInfoArr  []hostInfoStruct // int64 is hostID
 	cacheInfoMap searchHostInfoMapCache
 	totalHostCnt int
+	// nextCursor the cursor of the next page if the hosts are paged by cursor
+	nextCursor string
+	// mainlineChildren the map of mainline object to its child object
+	mainlineChildren map[string]string
+	// objectIDs the ids of all the models
+	objectIDs map[string]bool
 
 	ccErr errors.DefaultCCErrorIf
 	ccRid string
@@ -125,6 +134,7 @@ type searchHostInterface interface {
 	ParseCondition()
 	SearchHostByConds() errors.CCError
 	FillTopologyData() ([]mapstr.MapStr, int, errors.CCError)
+	NextCursor() string
 }
 
 func NewSearchHost(ctx context.Context, lgc *Logics, hostSearchParam *metadata.HostCommonSearch) searchHostInterface {
@@ -143,6 +153,11 @@ func NewSearchHost(ctx context.Context, lgc *Logics, hostSearchParam *metadata.H
 	return sh
 }
 
+// NextCursor returns the cursor of the next page, it's empty if there is no more host or the hosts are not paged by cursor
+func (sh *searchHost) NextCursor() string {
+	return sh.nextCursor
+}
+
 func (sh *searchHost) ParseCondition() {
 
 	for _, object := range sh.hostSearchParam.Condition {
@@ -172,7 +187,11 @@ func (sh *searchHost) ParseCondition() {
 
 func (sh *searchHost) SearchHostByConds() errors.CCError {
 
-	err := sh.searchByTopo()
+	err := sh.searchByExpression()
+	if err != nil {
+		return err
+	}
+	err = sh.searchByTopo()
 	if err != nil {
 		return err
 	}
@@ -687,6 +706,17 @@ func (sh *searchHost) searchByHostConds() errors.CCError {
 	if err != nil {
 		return err
 	}
+	if sh.conds.expressionFilter != nil {
+		condition = map[string]interface{}{
+			common.BKDBAND: []map[string]interface{}{condition, sh.conds.expressionFilter},
+		}
+	}
+
+	condition, cursorErr := sh.hostSearchParam.Page.ApplyCursor(common.BKHostIDField, condition)
+	if cursorErr != nil {
+		blog.Errorf("apply the page cursor failed, cursor: %s, err: %v, rid: %s", sh.hostSearchParam.Page.Cursor, cursorErr, sh.ccRid)
+		return sh.ccErr.CCErrorf(common.CCErrCommParamsInvalid, metadata.PageCursor)
+	}
 
 	query := &metadata.QueryInput{
 		Condition: condition,
@@ -711,16 +741,19 @@ func (sh *searchHost) searchByHostConds() errors.CCError {
 	}
 
 	sh.totalHostCnt = gResult.Data.Count
+	var lastHostID int64
 	for _, host := range gResult.Data.Info {
 		hostID, err := util.GetInt64ByInterface(host[common.BKHostIDField])
 		if err != nil {
 			return err
 		}
+		lastHostID = hostID
 		sh.hostInfoArr = append(sh.hostInfoArr, hostInfoStruct{
 			hostID:   hostID,
 			hostInfo: host,
 		})
 	}
+	sh.nextCursor = sh.hostSearchParam.Page.NextCursor(common.BKHostIDField, len(gResult.Data.Info), lastHostID)
 	return nil
 }
 
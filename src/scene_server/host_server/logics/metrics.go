@@ -0,0 +1,64 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	cloudSyncTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cmdb_cloud_sync_total",
+			Help: "number of the executed cloud sync tasks.",
+		},
+		[]string{"status", "fail_reason"},
+	)
+
+	cloudSyncDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "cmdb_cloud_sync_duration_seconds",
+			Help:    "duration of the cloud sync tasks.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		},
+	)
+
+	cloudSyncLastStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cmdb_cloud_sync_last_success",
+			Help: "describe whether the last execution of the cloud sync task succeed.",
+		},
+		[]string{"bk_task_id"},
+	)
+)
+
+// RegisterMetrics register the metrics of the host server background tasks
+func RegisterMetrics(registry prometheus.Registerer) {
+	registry.MustRegister(cloudSyncTotal, cloudSyncDuration, cloudSyncLastStatus)
+}
+
+func observeCloudSync(taskID int64, status, failReason string, seconds float64) {
+	if status == "" {
+		status = "unknown"
+	}
+	cloudSyncTotal.WithLabelValues(status, failReason).Inc()
+	cloudSyncDuration.Observe(seconds)
+
+	success := float64(0)
+	if status == "success" {
+		success = 1
+	}
+	cloudSyncLastStatus.WithLabelValues(strconv.FormatInt(taskID, 10)).Set(success)
+}
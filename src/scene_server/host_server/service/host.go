@@ -467,6 +467,37 @@ func (s *Service) SearchHostWithAsstDetail(req *restful.Request, resp *restful.R
 	})
 }
 
+// SearchHostStatistics group the hosts by the attributes or the mainline parents and calculate the metrics of each group
+func (s *Service) SearchHostStatistics(req *restful.Request, resp *restful.Response) {
+	srvData := s.newSrvComm(req.Request.Header)
+
+	option := new(meta.StatisticsOption)
+	if err := json.NewDecoder(req.Request.Body).Decode(option); err != nil {
+		blog.Errorf("search host statistics failed with decode body err: %v, rid: %s", err, srvData.rid)
+		_ = resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: srvData.ccErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	if key, err := option.Validate(); err != nil {
+		blog.Errorf("search host statistics failed, invalid option, key: %s, err: %v, rid: %s", key, err, srvData.rid)
+		_ = resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: srvData.ccErr.Errorf(common.CCErrCommParamsInvalid, key)})
+		return
+	}
+
+	result, err := s.CoreAPI.CoreService().Instance().StatisticsInstance(srvData.ctx, srvData.header, common.BKInnerObjIDHost, option)
+	if err != nil {
+		blog.Errorf("search host statistics failed, err: %v, input: %+v, rid: %s", err, option, srvData.rid)
+		_ = resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)})
+		return
+	}
+	if !result.Result {
+		blog.Errorf("search host statistics failed, err: %s, input: %+v, rid: %s", result.ErrMsg, option, srvData.rid)
+		_ = resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.New(result.Code, result.ErrMsg)})
+		return
+	}
+
+	_ = resp.WriteEntity(result)
+}
+
 func (s *Service) UpdateHostBatch(req *restful.Request, resp *restful.Response) {
 	srvData := s.newSrvComm(req.Request.Header)
 
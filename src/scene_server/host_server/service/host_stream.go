@@ -0,0 +1,87 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	authmeta "configcenter/src/auth/meta"
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/emicklei/go-restful"
+)
+
+// StreamHost write all the hosts matched the condition as NDJSON stream, the hosts are read page by page with cursor,
+// so the full dump is consistent with the data changed during the iteration.
+func (s *Service) StreamHost(req *restful.Request, resp *restful.Response) {
+	srvData := s.newSrvComm(req.Request.Header)
+
+	body := new(meta.HostCommonSearch)
+	if err := json.NewDecoder(req.Request.Body).Decode(body); err != nil {
+		blog.Errorf("stream host failed with decode body err: %v,rid:%s", err, srvData.rid)
+		_ = resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: srvData.ccErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	body.Page = meta.BasePage{Limit: meta.StreamPageSize, Sort: common.BKHostIDField}
+
+	encoder := json.NewEncoder(resp)
+	written := false
+	for {
+		host, err := srvData.lgc.SearchHost(srvData.ctx, body, false)
+		if err != nil {
+			blog.Errorf("stream host failed, err: %v,input:%+v,rid:%s", err, body, srvData.rid)
+			s.writeStreamError(resp, written, http.StatusBadRequest, srvData.ccErr.CCError(common.CCErrHostGetFail))
+			return
+		}
+
+		hostIDArray := host.ExtractHostIDs()
+		if err := s.AuthManager.AuthorizeByHostsIDs(srvData.ctx, srvData.header, authmeta.Find, *hostIDArray...); err != nil {
+			blog.Errorf("check host authorization failed, hostID: %+v, err: %+v, rid: %s", hostIDArray, err, srvData.rid)
+			s.writeStreamError(resp, written, http.StatusForbidden, srvData.ccErr.CCError(common.CCErrCommAuthorizeFailed))
+			return
+		}
+
+		if !written {
+			resp.Header().Set("Content-Type", meta.ContentTypeNDJSON)
+			resp.WriteHeader(http.StatusOK)
+			written = true
+		}
+		for _, item := range host.Info {
+			if err := encoder.Encode(item); err != nil {
+				blog.Errorf("stream host failed, write response err: %v, rid: %s", err, srvData.rid)
+				return
+			}
+		}
+		util.FlushResponse(resp)
+
+		if host.NextCursor == "" {
+			return
+		}
+		body.Page.Cursor = host.NextCursor
+	}
+}
+
+// writeStreamError write the error as response if nothing is written, or else write it as the last line of the stream
+func (s *Service) writeStreamError(resp *restful.Response, written bool, status int, err errors.CCErrorCoder) {
+	if !written {
+		_ = resp.WriteError(status, &meta.RespError{Msg: err})
+		return
+	}
+	_ = json.NewEncoder(resp).Encode(meta.StreamError{Code: err.GetCode(), Message: err.Error()})
+	util.FlushResponse(resp)
+}
@@ -24,9 +24,11 @@ import (
 	"configcenter/src/common/language"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/metric"
+	"configcenter/src/common/openapi"
 	"configcenter/src/common/rdapi"
 	"configcenter/src/common/types"
 	"configcenter/src/common/util"
+	"configcenter/src/common/version"
 	"configcenter/src/scene_server/host_server/app/options"
 	"configcenter/src/scene_server/host_server/logics"
 
@@ -105,8 +107,10 @@ func (s *Service) WebService() *restful.Container {
 	api.Route(api.POST("/usercustom").To(s.SaveUserCustom))
 	api.Route(api.POST("/usercustom/user/search").To(s.GetUserCustom))
 	api.Route(api.POST("/usercustom/default/search").To(s.GetDefaultCustom))
-	api.Route(api.POST("/hosts/search").To(s.SearchHost))
+	api.Route(api.POST("/hosts/search").To(s.SearchHost).Reads(metadata.HostCommonSearch{}).Writes(metadata.SearchHostResult{}))
 	api.Route(api.POST("/hosts/search/asstdetail").To(s.SearchHostWithAsstDetail))
+	api.Route(api.POST("/hosts/search/stream").To(s.StreamHost).Produces(metadata.ContentTypeNDJSON, restful.MIME_JSON))
+	api.Route(api.POST("/hosts/statistics").To(s.SearchHostStatistics).Reads(metadata.StatisticsOption{}).Writes(metadata.StatisticsInstanceResult{}))
 	api.Route(api.PUT("/hosts/batch").To(s.UpdateHostBatch))
 	api.Route(api.PUT("/hosts/property/clone").To(s.CloneHostProperty))
 	api.Route(api.POST("/hosts/modules/idle/set").To(s.MoveSetHost2IdleModule))
@@ -119,8 +123,8 @@ func (s *Service) WebService() *restful.Container {
 	api.Route(api.DELETE("/hosts/module/biz/delete").To(s.DeleteHostFromBusiness))
 
 	// next generation host search api
-	api.Route(api.POST("/hosts/list_hosts_without_app").To(s.ListHostsWithNoBiz))
-	api.Route(api.POST("/hosts/app/{appid}/list_hosts").To(s.ListBizHosts))
+	api.Route(api.POST("/hosts/list_hosts_without_app").To(s.ListHostsWithNoBiz).Reads(metadata.ListHostsWithNoBizParameter{}).Writes(metadata.SearchHostResult{}))
+	api.Route(api.POST("/hosts/app/{appid}/list_hosts").To(s.ListBizHosts).Reads(metadata.ListHostsParameter{}).Writes(metadata.SearchHostResult{}))
 
 	api.Route(api.POST("/userapi").To(s.AddUserCustomQuery))
 	api.Route(api.PUT("/userapi/{bk_biz_id}/{id}").To(s.UpdateUserCustomQuery))
@@ -128,6 +132,7 @@ func (s *Service) WebService() *restful.Container {
 	api.Route(api.POST("/userapi/search/{bk_biz_id}").To(s.GetUserCustomQuery))
 	api.Route(api.GET("/userapi/detail/{bk_biz_id}/{id}").To(s.GetUserCustomQueryDetail))
 	api.Route(api.GET("/userapi/data/{bk_biz_id}/{id}/{start}/{limit}").To(s.GetUserCustomQueryResult))
+	api.Route(api.POST("/userapi/data/{bk_biz_id}/{id}").To(s.GetDynamicGroupData))
 
 	api.Route(api.POST("/host/lock").To(s.LockHost))
 	api.Route(api.DELETE("/host/lock").To(s.UnlockHost))
@@ -174,6 +179,7 @@ func (s *Service) WebService() *restful.Container {
 
 	healthzAPI := new(restful.WebService).Produces(restful.MIME_JSON)
 	healthzAPI.Route(healthzAPI.GET("/healthz").To(s.Healthz))
+	openapi.AddDocumentRoute(healthzAPI, openapi.Info{Title: types.CC_MODULE_HOST, Version: version.CCVersion}, container)
 	container.Add(healthzAPI)
 
 	return container
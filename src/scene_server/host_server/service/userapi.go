@@ -15,6 +15,7 @@ package service
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
 	meta "configcenter/src/common/metadata"
 	parser "configcenter/src/common/paraparse"
 	"configcenter/src/common/util"
@@ -48,6 +50,12 @@ func (s *Service) AddUserCustomQuery(req *restful.Request, resp *restful.Respons
 		return
 	}
 
+	if err := s.validateDynamicGroup(srvData, ucq.ObjID, ucq.Info, ucq.Materialize); err != nil {
+		blog.Errorf("AddUserCustomQuery add user custom query failed, invalid dynamic group, input:%+v, err: %v, rid:%s", ucq, err, srvData.rid)
+		_ = resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: err})
+		return
+	}
+
 	ucq.CreateUser = srvData.user
 	result, err := s.CoreAPI.CoreService().Host().AddUserConfig(srvData.ctx, srvData.header, ucq)
 	if err != nil {
@@ -82,9 +90,42 @@ func (s *Service) UpdateUserCustomQuery(req *restful.Request, resp *restful.Resp
 		return
 	}
 
+	bizID := req.PathParameter("bk_biz_id")
+	_, infoChanged := params["info"]
+	_, objChanged := params[common.BKObjIDField]
+	_, materializeChanged := params["materialize"]
+	if infoChanged || objChanged || materializeChanged {
+		detail, err := s.CoreAPI.CoreService().Host().GetUserConfigDetail(srvData.ctx, bizID, req.PathParameter("id"), srvData.header)
+		if err != nil {
+			blog.Errorf("UpdateUserCustomQuery get user custom query detail failed, err:%s, biz:%v, rid:%s", err.Error(), bizID, srvData.rid)
+			_ = resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)})
+			return
+		}
+		if !detail.Result {
+			blog.Errorf("UpdateUserCustomQuery get user custom query detail failed, err code:%d, err msg:%s, biz:%v, rid:%s", detail.Code, detail.ErrMsg, bizID, srvData.rid)
+			_ = resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.New(detail.Code, detail.ErrMsg)})
+			return
+		}
+		objID, info := detail.Data.ObjID, detail.Data.Info
+		if objChanged {
+			objID = util.GetStrByInterface(params[common.BKObjIDField])
+		}
+		if infoChanged {
+			info = util.GetStrByInterface(params["info"])
+		}
+		materialize := detail.Data.Materialize != nil && *detail.Data.Materialize
+		if materializeChanged {
+			materialize, _ = params["materialize"].(bool)
+		}
+		if err := s.validateDynamicGroup(srvData, objID, info, materialize); err != nil {
+			blog.Errorf("UpdateUserCustomQuery update user custom query failed, invalid dynamic group, input:%+v, err: %v, rid:%s", params, err, srvData.rid)
+			_ = resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: err})
+			return
+		}
+	}
+
 	params["modify_user"] = srvData.user
 	params[common.LastTimeField] = time.Now().UTC()
-	bizID := req.PathParameter("bk_biz_id")
 	result, err := s.CoreAPI.CoreService().Host().UpdateUserConfig(srvData.ctx, bizID, req.PathParameter("id"), srvData.header, params)
 	if err != nil {
 		blog.Errorf("UpdateUserCustomQuery http do error,err:%s, biz:%v,input:%+v,rid:%s", err.Error(), bizID, params, srvData.rid)
@@ -294,3 +335,146 @@ func (s *Service) GetUserCustomQueryResult(req *restful.Request, resp *restful.R
 
 	return
 }
+
+// GetDynamicGroupData get the instances matched the dynamic group with the selected fields and paging
+func (s *Service) GetDynamicGroupData(req *restful.Request, resp *restful.Response) {
+	srvData := s.newSrvComm(req.Request.Header)
+
+	appID := req.PathParameter("bk_biz_id")
+	ID := req.PathParameter("id")
+
+	intAppID, err := util.GetInt64ByInterface(appID)
+	if nil != err {
+		blog.Errorf("GetDynamicGroupData failed, invalid business id, err: %v, appid: %s, id:%s, rid:%s", err, appID, ID, srvData.rid)
+		_ = resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: srvData.ccErr.Errorf(common.CCErrCommParamsNeedInt, common.BKAppIDField)})
+		return
+	}
+
+	input := new(meta.DynamicGroupDataRequest)
+	if err := json.NewDecoder(req.Request.Body).Decode(input); nil != err && err != io.EOF {
+		blog.Errorf("GetDynamicGroupData failed with decode body err: %v, rid:%s", err, srvData.rid)
+		_ = resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: srvData.ccErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	if input.Page.Limit <= 0 {
+		input.Page.Limit = common.BKDefaultLimit
+	}
+	if key, err := input.Page.Validate(); err != nil {
+		blog.Errorf("GetDynamicGroupData failed, invalid page, err: %v, rid:%s", err, srvData.rid)
+		_ = resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: srvData.ccErr.Errorf(common.CCErrCommParamsInvalid, "page."+key)})
+		return
+	}
+
+	result, err := s.CoreAPI.CoreService().Host().GetUserConfigDetail(srvData.ctx, appID, ID, srvData.header)
+	if nil != err || (nil == err && !result.Result) {
+		if nil == err {
+			err = fmt.Errorf("%s", result.ErrMsg)
+		}
+		blog.Errorf("GetDynamicGroupData get dynamic group failed, err: %v, appid:%s, id:%s, rid: %s", err, appID, ID, srvData.rid)
+		_ = resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: srvData.ccErr.Errorf(common.CCErrGetUserCustomQueryDetailFailed, err.Error())})
+		return
+	}
+	if "" == result.Data.Name {
+		blog.Errorf("GetDynamicGroupData dynamic group not found, appid:%s, id:%s, rid:%s", appID, ID, srvData.rid)
+		_ = resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: srvData.ccErr.Errorf(common.CCErrCommNotFound)})
+		return
+	}
+
+	// the host query saved before the dynamic group supports models
+	if "" == result.Data.ObjID {
+		var search meta.HostCommonSearch
+		if err := json.Unmarshal([]byte(result.Data.Info), &search); nil != err {
+			blog.Errorf("GetDynamicGroupData unmarshal host query failed, err: %v, appid:%s, id:%s, rid:%s", err, appID, ID, srvData.rid)
+			_ = resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+			return
+		}
+		search.AppID = intAppID
+		search.Page = input.Page
+		if len(input.Fields) > 0 {
+			for idx := range search.Condition {
+				if search.Condition[idx].ObjectID == common.BKInnerObjIDHost {
+					search.Condition[idx].Fields = input.Fields
+				}
+			}
+		}
+
+		hosts, err := srvData.lgc.SearchHost(srvData.ctx, &search, false)
+		if nil != err {
+			blog.Errorf("GetDynamicGroupData search host failed, err: %v, appid:%s, id:%s, rid: %s", err, appID, ID, srvData.rid)
+			_ = resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.Errorf(common.CCErrGetUserCustomQueryDetailFailed, err.Error())})
+			return
+		}
+		_ = resp.WriteEntity(meta.Response{
+			BaseResp: meta.SuccessBaseResp,
+			Data: meta.DynamicGroupInstances{
+				Count: hosts.Count,
+				Info:  hosts.Info,
+			},
+		})
+		return
+	}
+
+	condition, err := meta.ParseDynamicGroupCondition(result.Data.Info)
+	if err != nil {
+		blog.Errorf("GetDynamicGroupData parse condition failed, err: %v, appid:%s, id:%s, rid:%s", err, appID, ID, srvData.rid)
+		_ = resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.Errorf(common.CCErrCommParamsInvalid, "info")})
+		return
+	}
+	option := &meta.ListDynamicGroupInstances{
+		BizID:     intAppID,
+		ObjID:     result.Data.ObjID,
+		Condition: condition,
+		Fields:    input.Fields,
+		Page:      input.Page,
+	}
+	instances, err := s.CoreAPI.CoreService().Host().ListDynamicGroupInstances(srvData.ctx, srvData.header, option)
+	if err != nil {
+		blog.Errorf("GetDynamicGroupData http do error, err: %v, option: %+v, rid:%s", err, option, srvData.rid)
+		_ = resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)})
+		return
+	}
+	if !instances.Result {
+		blog.Errorf("GetDynamicGroupData http response error, err code:%d, err msg:%s, option: %+v, rid:%s", instances.Code, instances.ErrMsg, option, srvData.rid)
+		_ = resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.New(instances.Code, instances.ErrMsg)})
+		return
+	}
+
+	_ = resp.WriteEntity(meta.Response{
+		BaseResp: meta.SuccessBaseResp,
+		Data:     instances.Data,
+	})
+}
+
+// validateDynamicGroup validate the target model and the rules of the dynamic group, the user custom
+// query without target model is the host query saved before, which can not be materialized.
+func (s *Service) validateDynamicGroup(srvData *srvComm, objID, info string, materialize bool) error {
+	if "" == objID {
+		if materialize {
+			return srvData.ccErr.Errorf(common.CCErrCommParamsNeedSet, common.BKObjIDField)
+		}
+		return nil
+	}
+	if objID == common.BKInnerObjIDPlat {
+		return srvData.ccErr.Errorf(common.CCErrCommParamsInvalid, common.BKObjIDField)
+	}
+	if _, err := meta.ParseDynamicGroupCondition(info); err != nil {
+		blog.Errorf("validate dynamic group failed, invalid condition, err: %v, rid: %s", err, srvData.rid)
+		return srvData.ccErr.Errorf(common.CCErrCommParamsInvalid, "info")
+	}
+
+	cond := &meta.QueryCondition{Condition: mapstr.MapStr{common.BKObjIDField: objID}}
+	result, err := s.CoreAPI.CoreService().Model().ReadModel(srvData.ctx, srvData.header, cond)
+	if err != nil {
+		blog.Errorf("validate dynamic group failed, search model %s failed, err: %v, rid: %s", objID, err, srvData.rid)
+		return srvData.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !result.Result {
+		blog.Errorf("validate dynamic group failed, search model %s failed, err: %s, rid: %s", objID, result.ErrMsg, srvData.rid)
+		return srvData.ccErr.New(result.Code, result.ErrMsg)
+	}
+	if len(result.Data.Info) == 0 {
+		blog.Errorf("validate dynamic group failed, model %s not found, rid: %s", objID, srvData.rid)
+		return srvData.ccErr.Errorf(common.CCErrCommParamsInvalid, common.BKObjIDField)
+	}
+	return nil
+}
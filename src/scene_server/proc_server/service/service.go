@@ -25,9 +25,11 @@ import (
 	"configcenter/src/common/language"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/metric"
+	"configcenter/src/common/openapi"
 	"configcenter/src/common/rdapi"
 	"configcenter/src/common/types"
 	"configcenter/src/common/util"
+	"configcenter/src/common/version"
 	"configcenter/src/scene_server/proc_server/app/options"
 	"configcenter/src/scene_server/proc_server/logics"
 	"configcenter/src/storage/dal"
@@ -103,6 +105,7 @@ func (ps *ProcServer) WebService() *restful.Container {
 
 	healthzAPI := new(restful.WebService).Produces(restful.MIME_JSON)
 	healthzAPI.Route(healthzAPI.GET("/healthz").To(ps.Healthz))
+	openapi.AddDocumentRoute(healthzAPI, openapi.Info{Title: types.CC_MODULE_PROC, Version: version.CCVersion}, container)
 	container.Add(healthzAPI)
 
 	return container
@@ -16,6 +16,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"configcenter/src/common/core/cc/config"
+	"configcenter/src/common/metadata"
 )
 
 //ServerOption define option of server in flags
@@ -35,8 +36,8 @@ func NewServerOption() *ServerOption {
 //AddFlags add flags
 func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.ServConf.AddrPort, "addrport", "127.0.0.1:60006", "The ip address and port for the serve on")
-	fs.StringVar(&s.ServConf.RegDiscover, "regdiscv", "", "hosts of register and discover server. e.g: 127.0.0.1:2181")
-	fs.StringVar(&s.ServConf.ExConfig, "config", "", "The config path. e.g conf/api.conf")
+	fs.StringVar(&s.ServConf.RegDiscover, "regdiscv", "", "address of register and discover server. e.g: 127.0.0.1:2181, zk://127.0.0.1:2181, file:///data/cmdb/services.yaml or dns://cmdb.svc.cluster.local")
+	fs.StringVar(&s.ServConf.ExConfig, "config", "", "The config path. e.g conf/api.conf, or the directory of the config files which are reloaded once changed")
 }
 
 // Config config file set
@@ -105,4 +106,8 @@ type ConfigItem struct {
 
 	// EnableInstFilter  是否开启实例数据根据同步身份过滤
 	EnableInstFilter bool
+
+	// ConflictPolicy how to handle the instance field which both changed in source and target since last synchronize.
+	// source: source value overwrite target, target: keep target value, manual: keep target value and wait for resolve
+	ConflictPolicy metadata.SynchronizeConflictPolicy
 }
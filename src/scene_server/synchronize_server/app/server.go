@@ -24,7 +24,8 @@ import (
 	"configcenter/src/common"
 	"configcenter/src/common/backbone"
 	cc "configcenter/src/common/backbone/configcenter"
-	//"configcenter/src/common/blog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
 	"configcenter/src/common/types"
 	"configcenter/src/common/version"
 	"configcenter/src/scene_server/synchronize_server/app/options"
@@ -114,6 +115,7 @@ func (s *SynchronizeServer) onSynchronizeServerConfigUpdate(previous, current cc
 		objectIDs := current.ConfigMap[name+".ObjectID"]
 		ignoreModelAttr := current.ConfigMap[name+".IgnoreModelAttribute"]
 		strEnableInstFilter := current.ConfigMap[name+".EnableInstFilter"]
+		conflictPolicy := metadata.SynchronizeConflictPolicy(current.ConfigMap[name+".ConflictPolicy"])
 
 		configItem.AppNames = SplitFilter(appNames, ",")
 		if syncResource == "1" {
@@ -138,6 +140,11 @@ func (s *SynchronizeServer) onSynchronizeServerConfigUpdate(previous, current cc
 		if strEnableInstFilter == "1" {
 			configItem.EnableInstFilter = true
 		}
+		if !conflictPolicy.Validate() {
+			blog.Warnf("synchronize %s ConflictPolicy %s not supported, use default policy %s", name, conflictPolicy, metadata.SynchronizeConflictPolicySource)
+			conflictPolicy = metadata.SynchronizeConflictPolicySource
+		}
+		configItem.ConflictPolicy = conflictPolicy
 
 		configInfo.ConifgItemArray = append(configInfo.ConifgItemArray, configItem)
 		if targetHost != "" {
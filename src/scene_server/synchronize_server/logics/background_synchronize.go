@@ -185,6 +185,7 @@ func (s *synchronizeItem) synchronizeInstance(ctx context.Context, objID string,
 		input.InfoArray = info.Info
 		input.Version = s.version
 		input.SynchronizeFlag = s.config.SynchronizeFlag
+		input.ConflictPolicy = s.config.ConflictPolicy
 		// synchronize api
 		pageErrInfoArr, err := s.sycnhronizePartInstance(ctx, input)
 		if err != nil {
@@ -215,6 +216,7 @@ func (s *synchronizeItem) sycnhronizePartInstance(ctx context.Context, input *me
 		DataClassify:    input.DataClassify,
 		Version:         input.Version,
 		SynchronizeFlag: input.SynchronizeFlag,
+		ConflictPolicy:  input.ConflictPolicy,
 	}
 	if len(input.InfoArray) == 0 {
 		return errorInfoArr, nil
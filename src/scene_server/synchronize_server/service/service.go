@@ -81,6 +81,8 @@ func (s *Service) WebService() *restful.WebService {
 
 	ws.Route(ws.POST("/search").To(s.Find))
 	ws.Route(ws.POST("/set/identifier/flag").To(s.SetIdentifierFlag))
+	ws.Route(ws.POST("/search/conflict").To(s.SearchConflict))
+	ws.Route(ws.POST("/resolve/conflict").To(s.ResolveConflict))
 
 	return ws
 }
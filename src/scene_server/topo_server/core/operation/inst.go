@@ -387,6 +387,12 @@ func (c *commonInst) DeleteInstByInstID(params types.ContextParams, obj model.Ob
 			return err
 		}
 
+		// keep the instance with its associations in the recycle bin, so that it can be restored.
+		recycleIDs, err := c.recycleInst(params, delInst.obj.GetObjectID(), delInst.instID)
+		if nil != err {
+			return err
+		}
+
 		// this instance has not be bind to another instance, we can delete all the associations it created
 		// by the association with other instances.
 		innerCond = condition.CreateCondition()
@@ -394,6 +400,7 @@ func (c *commonInst) DeleteInstByInstID(params types.ContextParams, obj model.Ob
 		innerCond.Field(common.BKInstIDField).Eq(delInst.instID)
 		if err := c.asst.DeleteInstAssociation(params, innerCond); nil != err {
 			blog.Errorf("[operation-inst] failed to delete the inst asst, err: %s, rid: %s", err.Error(), params.ReqID)
+			c.purgeRecycleItems(params, recycleIDs)
 			return err
 		}
 
@@ -407,11 +414,13 @@ func (c *commonInst) DeleteInstByInstID(params types.ContextParams, obj model.Ob
 		rsp, err := c.clientSet.CoreService().Instance().DeleteInstance(context.Background(), params.Header, delInst.obj.GetObjectID(), &metadata.DeleteOption{Condition: delCond.ToMapStr()})
 		if nil != err {
 			blog.Errorf("[operation-inst] failed to request object controller, err: %s, rid: %s", err.Error(), params.ReqID)
+			c.purgeRecycleItems(params, recycleIDs)
 			return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
 		}
 
 		if !rsp.Result {
 			blog.Errorf("[operation-inst] failed to delete the object(%s) inst by the condition(%#v), err: %s, rid: %s", delInst.obj.GetObjectID(), delCond.ToMapStr(), rsp.ErrMsg, params.ReqID)
+			c.purgeRecycleItems(params, recycleIDs)
 			return params.Err.New(rsp.Code, rsp.ErrMsg)
 		}
 
@@ -420,6 +429,32 @@ func (c *commonInst) DeleteInstByInstID(params types.ContextParams, obj model.Ob
 	return nil
 }
 
+// recycleInst save the instance with its associations into the recycle bin before it is deleted
+func (c *commonInst) recycleInst(params types.ContextParams, objID string, instID int64) ([]int64, error) {
+	rsp, err := c.clientSet.CoreService().RecycleBin().RecycleInstances(context.Background(), params.Header, objID,
+		&metadata.RecycleInstancesOption{InstIDs: []int64{instID}})
+	if nil != err {
+		blog.Errorf("[operation-inst] failed to recycle the object(%s) inst(%d), err: %s, rid: %s", objID, instID, err.Error(), params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[operation-inst] failed to recycle the object(%s) inst(%d), err: %s, rid: %s", objID, instID, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return rsp.Data, nil
+}
+
+// purgeRecycleItems remove the recycle items of the instance which failed to delete
+func (c *commonInst) purgeRecycleItems(params types.ContextParams, ids []int64) {
+	if len(ids) == 0 {
+		return
+	}
+	rsp, err := c.clientSet.CoreService().RecycleBin().PurgeRecycleItems(context.Background(), params.Header, &metadata.PurgeRecycleItemsOption{IDs: ids})
+	if nil != err || !rsp.Result {
+		blog.Errorf("[operation-inst] failed to purge the recycle items(%v), err: %v, rsp: %v, rid: %s", ids, err, rsp, params.ReqID)
+	}
+}
+
 func (c *commonInst) DeleteMainlineInstWithID(params types.ContextParams, obj model.Object, instID int64) error {
 	object := obj.Object()
 	preAudit := NewSupplementary().Audit(params, c.clientSet, obj, c).CreateSnapshot(instID, condition.CreateCondition().ToMapStr())
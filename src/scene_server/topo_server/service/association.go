@@ -301,6 +301,45 @@ func (s *Service) SearchAssociationInst(params types.ContextParams, pathParams,
 	}
 }
 
+// SearchAssociationInstByPage search the inst associations by offset or cursor paging
+func (s *Service) SearchAssociationInstByPage(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	request := &metadata.SearchAssociationInstPageRequest{}
+	if err := data.MarshalJSONInto(request); err != nil {
+		return nil, params.Err.New(common.CCErrCommParamsInvalid, err.Error())
+	}
+	if key, err := request.Page.Validate(); err != nil {
+		blog.Errorf("search association inst by page failed, invalid page: %+v, err: %v, rid: %s", request.Page, err, params.ReqID)
+		return nil, params.Err.Errorf(common.CCErrCommParamsInvalid, key)
+	}
+	cond, err := request.Page.ApplyCursor(common.BKFieldID, request.Condition)
+	if err != nil {
+		blog.Errorf("search association inst by page failed, invalid cursor: %s, err: %v, rid: %s", request.Page.Cursor, err, params.ReqID)
+		return nil, params.Err.Errorf(common.CCErrCommParamsInvalid, metadata.PageCursor)
+	}
+
+	input := &metadata.QueryCondition{
+		Condition: cond,
+		Limit:     metadata.SearchLimit{Offset: int64(request.Page.Start), Limit: int64(request.Page.Limit)},
+		SortArr:   metadata.NewSearchSortParse().String(request.Page.Sort).ToSearchSortArr(),
+	}
+	rsp, err := s.Engine.CoreAPI.CoreService().Association().ReadInstAssociation(params.Context, params.Header, input)
+	if err != nil {
+		blog.Errorf("search association inst by page failed, http do error, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("search association inst by page failed, err: %s, rid: %s", rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+
+	result := &metadata.SearchAssociationInstPageResult{Count: rsp.Data.Count, Info: rsp.Data.Info}
+	if len(rsp.Data.Info) != 0 {
+		lastID := rsp.Data.Info[len(rsp.Data.Info)-1].ID
+		result.NextCursor = request.Page.NextCursor(common.BKFieldID, len(rsp.Data.Info), lastID)
+	}
+	return result, nil
+}
+
 func (s *Service) CreateAssociationInst(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
 	request := &metadata.CreateAssociationInstRequest{}
 	if err := data.MarshalJSONInto(request); err != nil {
@@ -0,0 +1,95 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/topo_server/core/types"
+)
+
+// SetFieldSourcePriority declare or replace the sources allowed to overwrite the field, the former the higher priority
+func (s *Service) SetFieldSourcePriority(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.FieldSourcePriority{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("[SetFieldSourcePriority] unmarshal error: %v, data: %#v, rid: %s", err, data, params.ReqID)
+		return nil, params.Err.New(common.CCErrCommParamsInvalid, err.Error())
+	}
+
+	objID := pathParams(common.BKObjIDField)
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().SetFieldSourcePriority(params.Context, params.Header, objID, input)
+	if nil != err {
+		blog.Errorf("[SetFieldSourcePriority] set the field source priority of %s failed, err: %v, rid: %s", objID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[SetFieldSourcePriority] set the field source priority of %s failed, err: %s, rid: %s", objID, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return nil, nil
+}
+
+// GetFieldSourcePriorities get the source priorities of the fields of the object
+func (s *Service) GetFieldSourcePriorities(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	objID := pathParams(common.BKObjIDField)
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().GetFieldSourcePriorities(params.Context, params.Header, objID)
+	if nil != err {
+		blog.Errorf("[GetFieldSourcePriorities] get the field source priorities of %s failed, err: %v, rid: %s", objID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[GetFieldSourcePriorities] get the field source priorities of %s failed, err: %s, rid: %s", objID, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return rsp.Data, nil
+}
+
+// DeleteFieldSourcePriority remove the source priority of the field, so that the last writer wins again
+func (s *Service) DeleteFieldSourcePriority(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	objID := pathParams(common.BKObjIDField)
+	propertyID := pathParams(common.BKPropertyIDField)
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().DeleteFieldSourcePriority(params.Context, params.Header, objID, propertyID)
+	if nil != err {
+		blog.Errorf("[DeleteFieldSourcePriority] delete the source priority of %s.%s failed, err: %v, rid: %s", objID, propertyID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[DeleteFieldSourcePriority] delete the source priority of %s.%s failed, err: %s, rid: %s", objID, propertyID, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return nil, nil
+}
+
+// SearchInstFieldSources get which source last set each field of the instance, and when
+func (s *Service) SearchInstFieldSources(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	objID := pathParams(common.BKObjIDField)
+	instID, err := strconv.ParseInt(pathParams(common.BKInstIDField), 10, 64)
+	if nil != err {
+		blog.Errorf("[SearchInstFieldSources] invalid instance id %s, rid: %s", pathParams(common.BKInstIDField), params.ReqID)
+		return nil, params.Err.Errorf(common.CCErrCommParamsNeedInt, common.BKInstIDField)
+	}
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().SearchInstFieldSources(params.Context, params.Header, objID, instID)
+	if nil != err {
+		blog.Errorf("[SearchInstFieldSources] search the field sources of %s instance %d failed, err: %v, rid: %s", objID, instID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[SearchInstFieldSources] search the field sources of %s instance %d failed, err: %s, rid: %s", objID, instID, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return rsp.Data, nil
+}
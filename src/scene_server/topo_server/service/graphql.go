@@ -0,0 +1,596 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"configcenter/src/auth/meta"
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/graphql"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/scene_server/topo_server/core/types"
+)
+
+const (
+	// graphQLMaxDepth the max depth of the nested fields of a query
+	graphQLMaxDepth = 10
+	// graphQLMaxRelatedInstances the max number of the related instances of all the sources of a field
+	graphQLMaxRelatedInstances = 2000
+	// graphQLSchemaTTL how long the schema of a supplier account is cached, the model changes take effect after it
+	graphQLSchemaTTL = time.Minute
+)
+
+// GraphQLRequest the GraphQL query request
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQLQuery execute a read only GraphQL query over the models, instances and associations
+func (s *Service) GraphQLQuery(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	request := new(GraphQLRequest)
+	if err := data.MarshalJSONInto(request); err != nil {
+		blog.Errorf("graphql query failed, parse request body failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommJSONUnmarshalFailed)
+	}
+	if len(request.Query) == 0 {
+		return nil, params.Err.Errorf(common.CCErrCommParamsNeedSet, "query")
+	}
+
+	schema, err := s.graphQLSchemas.get(params, s.buildGraphQLSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Context:       context.WithValue(params.Context, graphQLParamsKey{}, params),
+		Schema:        schema,
+		Query:         request.Query,
+		OperationName: request.OperationName,
+		Variables:     request.Variables,
+		MaxDepth:      graphQLMaxDepth,
+	}), nil
+}
+
+// GraphQLSchema get the GraphQL schema of the models in the schema definition language
+func (s *Service) GraphQLSchema(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	schema, err := s.graphQLSchemas.get(params, s.buildGraphQLSchema)
+	if err != nil {
+		return nil, err
+	}
+	return schema.String(), nil
+}
+
+// graphQLSchemaCache cache the schema of each supplier account, so that the models are not read for every query
+type graphQLSchemaCache struct {
+	lock    sync.Mutex
+	schemas map[string]*cachedGraphQLSchema
+}
+
+type cachedGraphQLSchema struct {
+	schema   *graphql.Schema
+	expireAt time.Time
+}
+
+// get the cached schema of the supplier account, or build it if it is not cached or expired
+func (c *graphQLSchemaCache) get(params types.ContextParams,
+	build func(types.ContextParams) (*graphql.Schema, error)) (*graphql.Schema, error) {
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if cached, exist := c.schemas[params.SupplierAccount]; exist && time.Now().Before(cached.expireAt) {
+		return cached.schema, nil
+	}
+	schema, err := build(params)
+	if err != nil {
+		return nil, err
+	}
+	c.schemas[params.SupplierAccount] = &cachedGraphQLSchema{schema: schema, expireAt: time.Now().Add(graphQLSchemaTTL)}
+	return schema, nil
+}
+
+// graphQLParamsKey the context key of the params of the request which executes the query
+type graphQLParamsKey struct{}
+
+// graphQLRequestParams get the params of the request which executes the query, the schema is shared by the
+// requests, so the resolvers must not use the params which the schema is built with.
+func graphQLRequestParams(ctx context.Context) types.ContextParams {
+	params, _ := ctx.Value(graphQLParamsKey{}).(types.ContextParams)
+	return params
+}
+
+// graphQLBuilder generate the GraphQL schema from the models, each model is a object type, the attributes
+// are the scalar fields, the model associations and the mainline relations are the object fields.
+type graphQLBuilder struct {
+	s *Service
+	// params the params of the request which builds the schema, it's only used when building
+	params  types.ContextParams
+	objects map[string]*graphql.Object
+}
+
+func (s *Service) buildGraphQLSchema(params types.ContextParams) (*graphql.Schema, error) {
+	modelResult, err := s.Engine.CoreAPI.CoreService().Model().ReadModel(params.Context, params.Header, &metadata.QueryCondition{})
+	if err != nil {
+		blog.Errorf("build graphql schema failed, search models failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !modelResult.Result {
+		blog.Errorf("build graphql schema failed, search models failed, err: %s, rid: %s", modelResult.ErrMsg, params.ReqID)
+		return nil, params.Err.New(modelResult.Code, modelResult.ErrMsg)
+	}
+
+	asstResult, err := s.Engine.CoreAPI.CoreService().Association().ReadModelAssociation(params.Context, params.Header, &metadata.QueryCondition{})
+	if err != nil {
+		blog.Errorf("build graphql schema failed, search model associations failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !asstResult.Result {
+		blog.Errorf("build graphql schema failed, search model associations failed, err: %s, rid: %s", asstResult.ErrMsg, params.ReqID)
+		return nil, params.Err.New(asstResult.Code, asstResult.ErrMsg)
+	}
+
+	b := &graphQLBuilder{s: s, params: params, objects: make(map[string]*graphql.Object)}
+	query := &graphql.Object{Name: "Query", Description: "the models of cmdb, query the instances of them"}
+	for _, model := range modelResult.Data.Info {
+		if !graphql.ValidName(model.Spec.ObjectID) || model.Spec.ObjectID == query.Name {
+			blog.V(5).Infof("model %s is not a valid graphql type name, skip it, rid: %s", model.Spec.ObjectID, params.ReqID)
+			continue
+		}
+		object := &graphql.Object{Name: model.Spec.ObjectID, Description: model.Spec.ObjectName}
+		for _, attr := range model.Attributes {
+			b.addField(object, &graphql.FieldDefinition{
+				Name:        attr.PropertyID,
+				Description: attr.PropertyName,
+				Type:        graphQLAttributeType(attr.PropertyType),
+			})
+		}
+		b.objects[object.Name] = object
+	}
+
+	for _, asst := range asstResult.Data.Info {
+		if asst.AsstKindID == common.AssociationKindMainline {
+			b.addMainlineFields(asst.AsstObjID, asst.ObjectID)
+			continue
+		}
+		b.addAssociationFields(asst)
+	}
+	// the processes are not in the mainline, but belong to the business
+	b.addMainlineFields(common.BKInnerObjIDApp, common.BKInnerObjIDProc)
+
+	names := make([]string, 0, len(b.objects))
+	for name := range b.objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.addField(query, &graphql.FieldDefinition{
+			Name:        name,
+			Description: b.objects[name].Description,
+			Type:        graphql.ListOf(graphql.ObjectType(b.objects[name])),
+			Args: []*graphql.ArgumentDefinition{
+				{Name: "condition", Description: "the query condition of the instances", Type: graphql.ScalarType(graphql.JSON)},
+				{Name: "start", Type: graphql.ScalarType(graphql.Int), DefaultValue: int64(0)},
+				{Name: "limit", Description: fmt.Sprintf("at most %d", common.BKMaxPageSize), Type: graphql.ScalarType(graphql.Int), DefaultValue: int64(common.BKDefaultLimit)},
+				{Name: "sort", Description: "the sort fields, like \"-bk_inst_id\"", Type: graphql.ScalarType(graphql.String)},
+			},
+			Resolve: b.resolveInstances(name),
+		})
+	}
+
+	return &graphql.Schema{Query: query}, nil
+}
+
+// addField add the field to the object if the field name is not used
+func (b *graphQLBuilder) addField(object *graphql.Object, field *graphql.FieldDefinition) {
+	if !graphql.ValidName(field.Name) || object.Field(field.Name) != nil {
+		blog.V(5).Infof("field %s of %s is invalid or duplicated, skip it, rid: %s", field.Name, object.Name, b.params.ReqID)
+		return
+	}
+	object.AddField(field)
+}
+
+func graphQLAttributeType(propertyType string) *graphql.Type {
+	switch propertyType {
+	case common.FieldTypeInt:
+		return graphql.ScalarType(graphql.Int)
+	case common.FieldTypeFloat:
+		return graphql.ScalarType(graphql.Float)
+	case common.FieldTypeBool:
+		return graphql.ScalarType(graphql.Boolean)
+	case common.FieldTypeSingleChar, common.FieldTypeLongChar, common.FieldTypeEnum, common.FieldTypeDate,
+		common.FieldTypeTime, common.FieldTypeUser, common.FieldTypeTimeZone:
+		return graphql.ScalarType(graphql.String)
+	default:
+		return graphql.ScalarType(graphql.JSON)
+	}
+}
+
+// addAssociationFields add the field named with the association id to the source object, and the field named
+// with "reverse_" prefix to the destination object.
+func (b *graphQLBuilder) addAssociationFields(asst metadata.Association) {
+	source, destination := b.objects[asst.ObjectID], b.objects[asst.AsstObjID]
+	if source == nil || destination == nil {
+		return
+	}
+
+	b.addField(source, &graphql.FieldDefinition{
+		Name:         asst.AssociationName,
+		Description:  asst.AssociationAliasName,
+		Type:         graphql.ListOf(graphql.ObjectType(destination)),
+		BatchResolve: b.resolveAssociation(asst, false),
+	})
+	b.addField(destination, &graphql.FieldDefinition{
+		Name:         "reverse_" + asst.AssociationName,
+		Description:  asst.AssociationAliasName,
+		Type:         graphql.ListOf(graphql.ObjectType(source)),
+		BatchResolve: b.resolveAssociation(asst, true),
+	})
+}
+
+// addMainlineFields add the field named with the child model to the parent object, and the field named with
+// the parent model to the child object.
+func (b *graphQLBuilder) addMainlineFields(parentObjID, childObjID string) {
+	parent, child := b.objects[parentObjID], b.objects[childObjID]
+	if parent == nil || child == nil {
+		return
+	}
+
+	parentType := graphql.ObjectType(parent)
+	if childObjID == common.BKInnerObjIDHost {
+		// a host can be in several modules
+		parentType = graphql.ListOf(parentType)
+	}
+	b.addField(parent, &graphql.FieldDefinition{
+		Name:         childObjID,
+		Description:  child.Description,
+		Type:         graphql.ListOf(graphql.ObjectType(child)),
+		BatchResolve: b.resolveMainline(parentObjID, childObjID, false),
+	})
+	b.addField(child, &graphql.FieldDefinition{
+		Name:         parentObjID,
+		Description:  parent.Description,
+		Type:         parentType,
+		BatchResolve: b.resolveMainline(parentObjID, childObjID, true),
+	})
+}
+
+func (b *graphQLBuilder) resolveInstances(objID string) graphql.ResolveFunc {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		params := graphQLRequestParams(p.Context)
+		cond := mapstr.New()
+		if condition, ok := p.Args["condition"].(map[string]interface{}); ok {
+			cond = mapstr.NewFromMap(condition)
+		} else if p.Args["condition"] != nil {
+			return nil, params.Err.Errorf(common.CCErrCommParamsInvalid, "condition")
+		}
+
+		limit, _ := p.Args["limit"].(int64)
+		if limit <= 0 || limit > common.BKMaxPageSize {
+			return nil, params.Err.Errorf(common.CCErrCommParamsInvalid, "limit")
+		}
+		start, _ := p.Args["start"].(int64)
+		page := metadata.BasePage{Start: int(start), Limit: int(limit)}
+		if sort, ok := p.Args["sort"].(string); ok {
+			page.Sort = sort
+		}
+
+		return b.searchInstances(params, objID, cond, page)
+	}
+}
+
+// searchInstances search the instances of the model, and check if the user can read them
+func (b *graphQLBuilder) searchInstances(params types.ContextParams, objID string, cond mapstr.MapStr,
+	page metadata.BasePage) ([]mapstr.MapStr, error) {
+
+	input := &metadata.QueryCondition{
+		Condition: cond,
+		Limit:     metadata.SearchLimit{Offset: int64(page.Start), Limit: int64(page.Limit)},
+		SortArr:   metadata.NewSearchSortParse().String(page.Sort).ToSearchSortArr(),
+	}
+
+	result, err := b.s.Engine.CoreAPI.CoreService().Instance().ReadInstance(params.Context, params.Header, objID, input)
+	if err != nil {
+		blog.Errorf("graphql search instances of %s failed, err: %v, rid: %s", objID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !result.Result {
+		blog.Errorf("graphql search instances of %s failed, err: %s, rid: %s", objID, result.ErrMsg, params.ReqID)
+		return nil, params.Err.New(result.Code, result.ErrMsg)
+	}
+
+	ids := make([]int64, 0, len(result.Data.Info))
+	for _, inst := range result.Data.Info {
+		if id, err := inst.Int64(common.GetInstIDField(objID)); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if err := b.authorize(params, objID, ids); err != nil {
+		blog.Errorf("graphql search instances of %s failed, authorize failed, err: %v, rid: %s", objID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommAuthNotHavePermission)
+	}
+	return result.Data.Info, nil
+}
+
+func (b *graphQLBuilder) searchInstancesByIDs(params types.ContextParams, objID string, ids []int64) (map[int64]mapstr.MapStr, error) {
+	instances := make(map[int64]mapstr.MapStr)
+	if len(ids) == 0 {
+		return instances, nil
+	}
+
+	if err := checkGraphQLRelatedCount(params, objID, len(ids)); err != nil {
+		return nil, err
+	}
+
+	idField := common.GetInstIDField(objID)
+	cond := mapstr.MapStr{idField: mapstr.MapStr{common.BKDBIN: ids}}
+	items, err := b.searchInstances(params, objID, cond, metadata.BasePage{Limit: len(ids)})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if id, err := item.Int64(idField); err == nil {
+			instances[id] = item
+		}
+	}
+	return instances, nil
+}
+
+func (b *graphQLBuilder) authorize(params types.ContextParams, objID string, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	am := b.s.AuthManager
+	switch objID {
+	case common.BKInnerObjIDApp:
+		return am.AuthorizeByBusinessID(params.Context, params.Header, meta.Find, ids...)
+	case common.BKInnerObjIDSet:
+		return am.AuthorizeBySetID(params.Context, params.Header, meta.Find, ids...)
+	case common.BKInnerObjIDModule:
+		return am.AuthorizeByModuleID(params.Context, params.Header, meta.Find, ids...)
+	case common.BKInnerObjIDHost:
+		return am.AuthorizeByHostsIDs(params.Context, params.Header, meta.Find, ids...)
+	case common.BKInnerObjIDProc:
+		return am.AuthorizeByProcessID(params.Context, params.Header, meta.Find, ids...)
+	case common.BKInnerObjIDPlat:
+		return am.AuthorizeByPlatIDs(params.Context, params.Header, meta.Find, ids...)
+	default:
+		return am.AuthorizeByInstanceID(params.Context, params.Header, meta.Find, objID, ids...)
+	}
+}
+
+// checkGraphQLRelatedCount check the number of the related instances of a field, the sources should be
+// narrowed with the limit of the query if there are too many.
+func checkGraphQLRelatedCount(params types.ContextParams, objID string, count int) error {
+	if count > graphQLMaxRelatedInstances {
+		blog.Errorf("graphql search related %s failed, %d related instances exceed the limit %d, rid: %s", objID, count,
+			graphQLMaxRelatedInstances, params.ReqID)
+		return params.Err.Errorf(common.CCErrCommXXExceedLimit, "related "+objID, graphQLMaxRelatedInstances)
+	}
+	return nil
+}
+
+// sourceIDs get the instance ids of the sources with the id field
+func sourceIDs(sources []interface{}, field string) []int64 {
+	ids := make([]int64, 0, len(sources))
+	for _, source := range sources {
+		inst, ok := source.(mapstr.MapStr)
+		if !ok {
+			continue
+		}
+		if id, err := inst.Int64(field); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// groupValues build the values of the sources in the same order, the related ids of each source are
+// got from the relations, and the values are the instances with the related ids.
+func groupValues(sources []interface{}, field string, relations map[int64][]int64, instances map[int64]mapstr.MapStr,
+	single bool) []interface{} {
+
+	values := make([]interface{}, len(sources))
+	for idx, source := range sources {
+		inst, ok := source.(mapstr.MapStr)
+		if !ok {
+			continue
+		}
+		id, err := inst.Int64(field)
+		if err != nil {
+			continue
+		}
+		related := make([]mapstr.MapStr, 0)
+		for _, relatedID := range relations[id] {
+			if item, exist := instances[relatedID]; exist {
+				related = append(related, item)
+			}
+		}
+		if !single {
+			values[idx] = related
+		} else if len(related) > 0 {
+			values[idx] = related[0]
+		}
+	}
+	return values
+}
+
+func (b *graphQLBuilder) resolveAssociation(asst metadata.Association, reverse bool) graphql.BatchResolveFunc {
+	return func(p graphql.BatchResolveParams) ([]interface{}, error) {
+		params := graphQLRequestParams(p.Context)
+		objID, relatedObjID := asst.ObjectID, asst.AsstObjID
+		instField := common.BKInstIDField
+		if reverse {
+			objID, relatedObjID = relatedObjID, objID
+			instField = common.BKAsstInstIDField
+		}
+
+		idField := common.GetInstIDField(objID)
+		ids := sourceIDs(p.Sources, idField)
+		if len(ids) == 0 {
+			return make([]interface{}, len(p.Sources)), nil
+		}
+
+		cond := mapstr.MapStr{
+			common.AssociationObjAsstIDField: asst.AssociationName,
+			instField:                        mapstr.MapStr{common.BKDBIN: ids},
+		}
+		result, err := b.s.Engine.CoreAPI.CoreService().Association().ReadInstAssociation(params.Context, params.Header,
+			&metadata.QueryCondition{Condition: cond, Limit: metadata.SearchLimit{Limit: graphQLMaxRelatedInstances + 1}})
+		if err != nil {
+			blog.Errorf("graphql search instance associations of %s failed, err: %v, rid: %s", asst.AssociationName, err, params.ReqID)
+			return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+		}
+		if !result.Result {
+			blog.Errorf("graphql search instance associations of %s failed, err: %s, rid: %s", asst.AssociationName, result.ErrMsg, params.ReqID)
+			return nil, params.Err.New(result.Code, result.ErrMsg)
+		}
+		if err := checkGraphQLRelatedCount(params, relatedObjID, len(result.Data.Info)); err != nil {
+			return nil, err
+		}
+
+		relations := make(map[int64][]int64)
+		relatedIDs := make([]int64, 0, len(result.Data.Info))
+		for _, instAsst := range result.Data.Info {
+			id, relatedID := instAsst.InstID, instAsst.AsstInstID
+			if reverse {
+				id, relatedID = relatedID, id
+			}
+			relations[id] = append(relations[id], relatedID)
+			relatedIDs = append(relatedIDs, relatedID)
+		}
+
+		instances, err := b.searchInstancesByIDs(params, relatedObjID, util.IntArrayUnique(relatedIDs))
+		if err != nil {
+			return nil, err
+		}
+		return groupValues(p.Sources, idField, relations, instances, false), nil
+	}
+}
+
+func (b *graphQLBuilder) resolveMainline(parentObjID, childObjID string, toParent bool) graphql.BatchResolveFunc {
+	return func(p graphql.BatchResolveParams) ([]interface{}, error) {
+		params := graphQLRequestParams(p.Context)
+		objID, relatedObjID := parentObjID, childObjID
+		if toParent {
+			objID, relatedObjID = childObjID, parentObjID
+		}
+		idField := common.GetInstIDField(objID)
+		ids := sourceIDs(p.Sources, idField)
+		if len(ids) == 0 {
+			return make([]interface{}, len(p.Sources)), nil
+		}
+
+		relations := make(map[int64][]int64)
+		var instances map[int64]mapstr.MapStr
+		switch {
+		case childObjID == common.BKInnerObjIDHost:
+			// the hosts and modules are related by the host module relations
+			page := metadata.BasePage{Limit: graphQLMaxRelatedInstances + 1}
+			input := &metadata.HostModuleRelationRequest{ModuleIDArr: ids, Page: page}
+			if toParent {
+				input = &metadata.HostModuleRelationRequest{HostIDArr: ids, Page: page}
+			}
+			result, err := b.s.Engine.CoreAPI.CoreService().Host().GetHostModuleRelation(params.Context, params.Header, input)
+			if err != nil {
+				blog.Errorf("graphql search host module relations failed, err: %v, rid: %s", err, params.ReqID)
+				return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+			}
+			if !result.Result {
+				blog.Errorf("graphql search host module relations failed, err: %s, rid: %s", result.ErrMsg, params.ReqID)
+				return nil, params.Err.New(result.Code, result.ErrMsg)
+			}
+			if err := checkGraphQLRelatedCount(params, relatedObjID, len(result.Data.Info)); err != nil {
+				return nil, err
+			}
+
+			relatedIDs := make([]int64, 0, len(result.Data.Info))
+			for _, relation := range result.Data.Info {
+				id, relatedID := relation.ModuleID, relation.HostID
+				if toParent {
+					id, relatedID = relatedID, id
+				}
+				relations[id] = append(relations[id], relatedID)
+				relatedIDs = append(relatedIDs, relatedID)
+			}
+			if instances, err = b.searchInstancesByIDs(params, relatedObjID, util.IntArrayUnique(relatedIDs)); err != nil {
+				return nil, err
+			}
+
+		case toParent:
+			parentField := graphQLParentField(childObjID)
+			parentIDs := sourceIDs(p.Sources, parentField)
+			for _, source := range p.Sources {
+				inst, ok := source.(mapstr.MapStr)
+				if !ok {
+					continue
+				}
+				id, idErr := inst.Int64(idField)
+				parentID, parentErr := inst.Int64(parentField)
+				if idErr == nil && parentErr == nil {
+					relations[id] = []int64{parentID}
+				}
+			}
+			var err error
+			if instances, err = b.searchInstancesByIDs(params, relatedObjID, util.IntArrayUnique(parentIDs)); err != nil {
+				return nil, err
+			}
+
+		default:
+			parentField := graphQLParentField(childObjID)
+			children, err := b.searchInstances(params, childObjID, mapstr.MapStr{parentField: mapstr.MapStr{common.BKDBIN: ids}},
+				metadata.BasePage{Limit: graphQLMaxRelatedInstances + 1})
+			if err != nil {
+				return nil, err
+			}
+			if err := checkGraphQLRelatedCount(params, childObjID, len(children)); err != nil {
+				return nil, err
+			}
+			instances = make(map[int64]mapstr.MapStr, len(children))
+			childIDField := common.GetInstIDField(childObjID)
+			for _, child := range children {
+				childID, idErr := child.Int64(childIDField)
+				parentID, parentErr := child.Int64(parentField)
+				if idErr == nil && parentErr == nil {
+					relations[parentID] = append(relations[parentID], childID)
+					instances[childID] = child
+				}
+			}
+		}
+
+		single := toParent && childObjID != common.BKInnerObjIDHost
+		return groupValues(p.Sources, idField, relations, instances, single), nil
+	}
+}
+
+// graphQLParentField the field of the child instance which is the id of the parent instance
+func graphQLParentField(childObjID string) string {
+	switch childObjID {
+	case common.BKInnerObjIDModule:
+		return common.BKSetIDField
+	case common.BKInnerObjIDProc:
+		return common.BKAppIDField
+	default:
+		return common.BKInstParentStr
+	}
+}
@@ -14,7 +14,6 @@ package service
 
 import (
 	"strconv"
-	"strings"
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -273,13 +272,10 @@ func (s *Service) SearchInsts(params types.ContextParams, pathParams, queryParam
 		blog.Errorf("[api-inst] failed to parse the data and the condition, the input (%#v), error info is %s, rid: %s", data, err.Error(), params.ReqID)
 		return nil, err
 	}
-	page := metadata.ParsePage(queryCond.Page)
-	query := &metadata.QueryInput{}
-	query.Condition = queryCond.Condition
-	query.Fields = strings.Join(queryCond.Fields, ",")
-	query.Limit = page.Limit
-	query.Sort = page.Sort
-	query.Start = page.Start
+	query, page, err := parseInstQuery(params, obj, queryCond)
+	if nil != err {
+		return nil, err
+	}
 
 	cnt, instItems, err := s.Core.InstOperation().FindInst(params, obj, query, false)
 	if nil != err {
@@ -290,6 +286,10 @@ func (s *Service) SearchInsts(params types.ContextParams, pathParams, queryParam
 	result := mapstr.MapStr{}
 	result.Set("count", cnt)
 	result.Set("info", instItems)
+	if err := setInstNextCursor(result, page, obj, instItems); nil != err {
+		blog.Errorf("[api-inst] failed to get the next cursor of the object(%s), error info is %s, rid: %s", objID, err.Error(), params.ReqID)
+		return nil, err
+	}
 	return result, nil
 }
 
@@ -310,13 +310,10 @@ func (s *Service) SearchInstAndAssociationDetail(params types.ContextParams, pat
 		blog.Errorf("[api-inst] failed to parse the data and the condition, the input (%#v), error info is %s, rid: %s", data, err.Error(), params.ReqID)
 		return nil, err
 	}
-	page := metadata.ParsePage(queryCond.Page)
-	query := &metadata.QueryInput{}
-	query.Condition = queryCond.Condition
-	query.Fields = strings.Join(queryCond.Fields, ",")
-	query.Limit = page.Limit
-	query.Sort = page.Sort
-	query.Start = page.Start
+	query, page, err := parseInstQuery(params, obj, queryCond)
+	if nil != err {
+		return nil, err
+	}
 
 	cnt, instItems, err := s.Core.InstOperation().FindInst(params, obj, query, true)
 	if nil != err {
@@ -327,6 +324,10 @@ func (s *Service) SearchInstAndAssociationDetail(params types.ContextParams, pat
 	result := mapstr.MapStr{}
 	result.Set("count", cnt)
 	result.Set("info", instItems)
+	if err := setInstNextCursor(result, page, obj, instItems); nil != err {
+		blog.Errorf("[api-inst] failed to get the next cursor of the object(%s), error info is %s, rid: %s", objID, err.Error(), params.ReqID)
+		return nil, err
+	}
 	return result, nil
 }
 
@@ -347,13 +348,10 @@ func (s *Service) SearchInstByObject(params types.ContextParams, pathParams, que
 		blog.Errorf("[api-inst] failed to parse the data and the condition, the input (%#v), error info is %s, rid: %s", data, err.Error(), params.ReqID)
 		return nil, err
 	}
-	page := metadata.ParsePage(queryCond.Page)
-	query := &metadata.QueryInput{}
-	query.Condition = queryCond.Condition
-	query.Fields = strings.Join(queryCond.Fields, ",")
-	query.Limit = page.Limit
-	query.Sort = page.Sort
-	query.Start = page.Start
+	query, page, err := parseInstQuery(params, obj, queryCond)
+	if nil != err {
+		return nil, err
+	}
 	cnt, instItems, err := s.Core.InstOperation().FindInst(params, obj, query, false)
 	if nil != err {
 		blog.Errorf("[api-inst] failed to find the objects(%s), error info is %s, rid: %s", pathParams("bk_obj_id"), err.Error(), params.ReqID)
@@ -363,6 +361,10 @@ func (s *Service) SearchInstByObject(params types.ContextParams, pathParams, que
 	result := mapstr.MapStr{}
 	result.Set("count", cnt)
 	result.Set("info", instItems)
+	if err := setInstNextCursor(result, page, obj, instItems); nil != err {
+		blog.Errorf("[api-inst] failed to get the next cursor of the object(%s), error info is %s, rid: %s", objID, err.Error(), params.ReqID)
+		return nil, err
+	}
 	return result, nil
 }
 
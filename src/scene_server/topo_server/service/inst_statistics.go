@@ -0,0 +1,92 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"fmt"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/topo_server/core/types"
+	"configcenter/src/scene_server/validator"
+)
+
+// SearchInstStatistics group the insts of the object by the attributes and calculate the metrics of each group,
+// the name of the enum value is returned as the <field>_name key of the group.
+func (s *Service) SearchInstStatistics(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	objID := pathParams("bk_obj_id")
+	obj, err := s.Core.ObjectOperation().FindSingleObject(params, objID)
+	if nil != err {
+		blog.Errorf("[api-inst] failed to find the objects(%s), error info is %s, rid: %s", objID, err.Error(), params.ReqID)
+		return nil, err
+	}
+
+	option := new(metadata.StatisticsOption)
+	if err := data.MarshalJSONInto(option); nil != err {
+		blog.Errorf("[api-inst] failed to parse the statistics option, the input (%#v), error info is %s, rid: %s", data, err.Error(), params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommJSONUnmarshalFailed)
+	}
+	if key, err := option.Validate(); nil != err {
+		blog.Errorf("[api-inst] invalid statistics option, key: %s, error info is %s, rid: %s", key, err.Error(), params.ReqID)
+		return nil, params.Err.Errorf(common.CCErrCommParamsInvalid, key)
+	}
+
+	rsp, err := s.Engine.CoreAPI.CoreService().Instance().StatisticsInstance(params.Context, params.Header, obj.GetObjectID(), option)
+	if nil != err {
+		blog.Errorf("[api-inst] failed to request core service, error info is %s, rid: %s", err.Error(), params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[api-inst] failed to search the statistics of the object(%s), error info is %s, rid: %s", objID, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+
+	attrs, err := obj.GetAttributes()
+	if nil != err {
+		blog.Errorf("[api-inst] failed to get the attributes of the object(%s), error info is %s, rid: %s", objID, err.Error(), params.ReqID)
+		return nil, err
+	}
+	enumNames := make(map[string]map[string]string)
+	for _, attr := range attrs {
+		property := attr.Attribute()
+		if property.PropertyType != common.FieldTypeEnum {
+			continue
+		}
+		options, err := validator.ParseEnumOption(property.Option)
+		if nil != err {
+			blog.Warnf("[api-inst] the enum option of the attribute(%s) is invalid, error info is %s, rid: %s", property.PropertyID, err.Error(), params.ReqID)
+			continue
+		}
+		names := make(map[string]string)
+		for _, option := range options {
+			names[option.ID] = option.Name
+		}
+		enumNames[property.PropertyID] = names
+	}
+
+	for _, group := range rsp.Data.Info {
+		for _, field := range option.GroupBy {
+			names, ok := enumNames[field.Field]
+			if !ok {
+				continue
+			}
+			if value, exist := group.Key[field.Field]; exist && nil != value {
+				group.Key[field.Field+"_name"] = names[fmt.Sprint(value)]
+			}
+		}
+	}
+
+	return rsp.Data, nil
+}
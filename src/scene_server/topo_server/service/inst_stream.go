@@ -0,0 +1,114 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	paraparse "configcenter/src/common/paraparse"
+	"configcenter/src/scene_server/topo_server/core/inst"
+	"configcenter/src/scene_server/topo_server/core/model"
+	"configcenter/src/scene_server/topo_server/core/types"
+)
+
+// parseInstQuery convert the search params to query input, the insts after the page cursor are queried if it's set
+func parseInstQuery(params types.ContextParams, obj model.Object, queryCond *paraparse.SearchParams) (*metadata.QueryInput, *metadata.BasePage, error) {
+	page := metadata.ParsePage(queryCond.Page)
+	cond, err := page.ApplyCursor(obj.GetInstIDFieldName(), queryCond.Condition)
+	if nil != err {
+		blog.Errorf("[api-inst] failed to apply the page cursor(%s), error info is %s, rid: %s", page.Cursor, err.Error(), params.ReqID)
+		return nil, nil, params.Err.Errorf(common.CCErrCommParamsInvalid, metadata.PageCursor)
+	}
+
+	query := &metadata.QueryInput{}
+	query.Condition = cond
+	query.Fields = strings.Join(queryCond.Fields, ",")
+	query.Limit = page.Limit
+	query.Sort = page.Sort
+	query.Start = page.Start
+	return query, &page, nil
+}
+
+// setInstNextCursor set the cursor of the next page to the result if there are more insts
+func setInstNextCursor(result mapstr.MapStr, page *metadata.BasePage, obj model.Object, instItems []inst.Inst) error {
+	if len(instItems) == 0 {
+		return nil
+	}
+	lastID, err := instItems[len(instItems)-1].GetInstID()
+	if nil != err {
+		return err
+	}
+	if cursor := page.NextCursor(obj.GetInstIDFieldName(), len(instItems), lastID); cursor != "" {
+		result.Set("next_cursor", cursor)
+	}
+	return nil
+}
+
+// SearchInstStream write all the insts of the object matched the condition as NDJSON stream,
+// the insts are read page by page with cursor.
+func (s *Service) SearchInstStream(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	objID := pathParams("bk_obj_id")
+	obj, err := s.Core.ObjectOperation().FindSingleObject(params, objID)
+	if nil != err {
+		blog.Errorf("[api-inst] failed to find the objects(%s), error info is %s, rid: %s", objID, err.Error(), params.ReqID)
+		return nil, err
+	}
+
+	queryCond := &paraparse.SearchParams{
+		Condition: mapstr.New(),
+	}
+	if err := data.MarshalJSONInto(queryCond); nil != err {
+		blog.Errorf("[api-inst] failed to parse the data and the condition, the input (%#v), error info is %s, rid: %s", data, err.Error(), params.ReqID)
+		return nil, err
+	}
+	idField := obj.GetInstIDFieldName()
+	queryCond.Page = map[string]interface{}{"limit": metadata.StreamPageSize, metadata.PageSort: idField}
+	if len(queryCond.Fields) != 0 {
+		queryCond.Fields = append(queryCond.Fields, idField)
+	}
+
+	return StreamFunc(func(write func(records []mapstr.MapStr) error) error {
+		for {
+			query, page, err := parseInstQuery(params, obj, queryCond)
+			if nil != err {
+				return err
+			}
+
+			result, err := s.Core.InstOperation().FindOriginInst(params, obj, query)
+			if nil != err {
+				blog.Errorf("[api-inst] failed to find the insts of the object(%s), error info is %s, rid: %s", objID, err.Error(), params.ReqID)
+				return err
+			}
+			if len(result.Info) == 0 {
+				return nil
+			}
+			if err := write(result.Info); nil != err {
+				return err
+			}
+
+			lastID, err := result.Info[len(result.Info)-1].Int64(idField)
+			if nil != err {
+				return err
+			}
+			cursor := page.NextCursor(idField, len(result.Info), lastID)
+			if cursor == "" {
+				return nil
+			}
+			queryCond.Page[metadata.PageCursor] = cursor
+		}
+	}), nil
+}
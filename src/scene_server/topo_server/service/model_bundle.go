@@ -0,0 +1,126 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/topo_server/core/types"
+)
+
+// ExportModelBundle export the models with their classifications, attributes, uniques and associations as a bundle
+func (s *Service) ExportModelBundle(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.ExportModelBundleOption{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("[ExportModelBundle] unmarshal error: %v, data: %#v, rid: %s", err, data, params.ReqID)
+		return nil, params.Err.New(common.CCErrCommParamsInvalid, err.Error())
+	}
+
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().ExportModelBundle(params.Context, params.Header, input)
+	if nil != err {
+		blog.Errorf("[ExportModelBundle] export the models %v failed, err: %v, rid: %s", input.ObjectIDs, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[ExportModelBundle] export the models %v failed, err: %s, rid: %s", input.ObjectIDs, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return rsp.Data, nil
+}
+
+// PlanModelBundle preview what will be created, changed or left untouched when the bundle is applied
+func (s *Service) PlanModelBundle(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	bundle := metadata.ModelBundle{}
+	if err := data.MarshalJSONInto(&bundle); nil != err {
+		blog.Errorf("[PlanModelBundle] unmarshal error: %v, rid: %s", err, params.ReqID)
+		return nil, params.Err.New(common.CCErrCommParamsInvalid, err.Error())
+	}
+
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().PlanModelBundle(params.Context, params.Header, bundle)
+	if nil != err {
+		blog.Errorf("[PlanModelBundle] plan the bundle failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[PlanModelBundle] plan the bundle failed, err: %s, rid: %s", rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return rsp.Data, nil
+}
+
+// ApplyModelBundle create or update the items in the bundle, nothing is deleted
+func (s *Service) ApplyModelBundle(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	bundle := metadata.ModelBundle{}
+	if err := data.MarshalJSONInto(&bundle); nil != err {
+		blog.Errorf("[ApplyModelBundle] unmarshal error: %v, rid: %s", err, params.ReqID)
+		return nil, params.Err.New(common.CCErrCommParamsInvalid, err.Error())
+	}
+
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().ApplyModelBundle(params.Context, params.Header, bundle)
+	if nil != err {
+		blog.Errorf("[ApplyModelBundle] apply the bundle failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[ApplyModelBundle] apply the bundle failed, err: %s, rid: %s", rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+
+	if err := s.registerModelBundleAuth(params, rsp.Data.Created); nil != err {
+		return nil, err
+	}
+	return rsp.Data, nil
+}
+
+// registerModelBundleAuth register the resources created by the bundle to iam
+func (s *Service) registerModelBundleAuth(params types.ContextParams, created metadata.ModelBundleCreated) error {
+	if len(created.Classifications) > 0 {
+		if err := s.AuthManager.RegisterClassification(params.Context, params.Header, created.Classifications...); nil != err {
+			blog.Errorf("[ApplyModelBundle] register the classifications to iam failed, err: %v, rid: %s", err, params.ReqID)
+			return params.Err.New(common.CCErrCommRegistResourceToIAMFailed, err.Error())
+		}
+	}
+	if len(created.AssociationKindIDs) > 0 {
+		if err := s.AuthManager.RegisterAssociationTypeByID(params.Context, params.Header, created.AssociationKindIDs...); nil != err {
+			blog.Errorf("[ApplyModelBundle] register the association kinds to iam failed, err: %v, rid: %s", err, params.ReqID)
+			return params.Err.New(common.CCErrCommRegistResourceToIAMFailed, err.Error())
+		}
+	}
+	if len(created.Objects) > 0 {
+		if err := s.AuthManager.RegisterObject(params.Context, params.Header, created.Objects...); nil != err {
+			blog.Errorf("[ApplyModelBundle] register the objects to iam failed, err: %v, rid: %s", err, params.ReqID)
+			return params.Err.New(common.CCErrCommRegistResourceToIAMFailed, err.Error())
+		}
+	}
+	if len(created.Groups) > 0 {
+		if err := s.AuthManager.RegisterModelAttributeGroup(params.Context, params.Header, created.Groups...); nil != err {
+			blog.Errorf("[ApplyModelBundle] register the groups to iam failed, err: %v, rid: %s", err, params.ReqID)
+			return params.Err.New(common.CCErrCommRegistResourceToIAMFailed, err.Error())
+		}
+	}
+	if len(created.Attributes) > 0 {
+		if err := s.AuthManager.RegisterModelAttribute(params.Context, params.Header, created.Attributes...); nil != err {
+			blog.Errorf("[ApplyModelBundle] register the attributes to iam failed, err: %v, rid: %s", err, params.ReqID)
+			return params.Err.New(common.CCErrCommRegistResourceToIAMFailed, err.Error())
+		}
+	}
+	if len(created.UniqueIDs) > 0 {
+		if err := s.AuthManager.RegisterModuleUniqueByID(params.Context, params.Header, created.UniqueIDs...); nil != err {
+			blog.Errorf("[ApplyModelBundle] register the uniques to iam failed, err: %v, rid: %s", err, params.ReqID)
+			return params.Err.New(common.CCErrCommRegistResourceToIAMFailed, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/topo_server/core/types"
+)
+
+// SetObjectLifecycle declare or replace the lifecycle of the object, which is enforced on the instance changes
+func (s *Service) SetObjectLifecycle(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.ObjectLifecycle{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("[SetObjectLifecycle] unmarshal error: %v, data: %#v, rid: %s", err, data, params.ReqID)
+		return nil, params.Err.New(common.CCErrCommParamsInvalid, err.Error())
+	}
+
+	objID := pathParams(common.BKObjIDField)
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().SetObjectLifecycle(params.Context, params.Header, objID, input)
+	if nil != err {
+		blog.Errorf("[SetObjectLifecycle] set the lifecycle of %s failed, err: %v, rid: %s", objID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[SetObjectLifecycle] set the lifecycle of %s failed, err: %s, rid: %s", objID, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return nil, nil
+}
+
+// GetObjectLifecycle get the lifecycle of the object, null if the object has no lifecycle
+func (s *Service) GetObjectLifecycle(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	objID := pathParams(common.BKObjIDField)
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().GetObjectLifecycle(params.Context, params.Header, objID)
+	if nil != err {
+		blog.Errorf("[GetObjectLifecycle] get the lifecycle of %s failed, err: %v, rid: %s", objID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[GetObjectLifecycle] get the lifecycle of %s failed, err: %s, rid: %s", objID, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return rsp.Data, nil
+}
+
+// DeleteObjectLifecycle remove the lifecycle of the object, the states kept in the instances are left untouched
+func (s *Service) DeleteObjectLifecycle(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	objID := pathParams(common.BKObjIDField)
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().DeleteObjectLifecycle(params.Context, params.Header, objID)
+	if nil != err {
+		blog.Errorf("[DeleteObjectLifecycle] delete the lifecycle of %s failed, err: %v, rid: %s", objID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[DeleteObjectLifecycle] delete the lifecycle of %s failed, err: %s, rid: %s", objID, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return nil, nil
+}
+
+// SearchLifecycleHistory search the state transitions of the instances of the object, the latest comes first
+func (s *Service) SearchLifecycleHistory(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.QueryCondition{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("[SearchLifecycleHistory] unmarshal error: %v, data: %#v, rid: %s", err, data, params.ReqID)
+		return nil, params.Err.New(common.CCErrCommParamsInvalid, err.Error())
+	}
+	if input.Limit.Limit <= 0 {
+		input.Limit.Limit = common.BKDefaultLimit
+	}
+
+	objID := pathParams(common.BKObjIDField)
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().SearchLifecycleHistory(params.Context, params.Header, objID, input)
+	if nil != err {
+		blog.Errorf("[SearchLifecycleHistory] search the lifecycle history of %s failed, err: %v, rid: %s", objID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[SearchLifecycleHistory] search the lifecycle history of %s failed, err: %s, rid: %s", objID, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return rsp.Data, nil
+}
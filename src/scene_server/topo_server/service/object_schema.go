@@ -0,0 +1,185 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/topo_server/core/types"
+)
+
+// SearchObjectSchemaVersion search the schema versions of the object, the latest version comes first
+func (s *Service) SearchObjectSchemaVersion(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.QueryCondition{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("[SearchObjectSchemaVersion] unmarshal error: %v, data: %#v, rid: %s", err, data, params.ReqID)
+		return nil, params.Err.New(common.CCErrCommParamsInvalid, err.Error())
+	}
+	if input.Limit.Limit <= 0 {
+		input.Limit.Limit = common.BKDefaultLimit
+	}
+
+	objID := pathParams(common.BKObjIDField)
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().ReadModelSchemaVersion(params.Context, params.Header, objID, input)
+	if nil != err {
+		blog.Errorf("[SearchObjectSchemaVersion] search the schema versions of %s failed, err: %v, rid: %s", objID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[SearchObjectSchemaVersion] search the schema versions of %s failed, err: %s, rid: %s", objID, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return rsp.Data, nil
+}
+
+// GetObjectSchemaVersion get the schema snapshot of the object version
+func (s *Service) GetObjectSchemaVersion(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	version, err := strconv.ParseInt(pathParams("version"), 10, 64)
+	if nil != err {
+		return nil, params.Err.Errorf(common.CCErrCommParamsNeedInt, "version")
+	}
+	return s.getObjectSchemaVersion(params, pathParams(common.BKObjIDField), version)
+}
+
+// DiffObjectSchemaVersion compare the schema of the object version from with the version to
+func (s *Service) DiffObjectSchemaVersion(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	objID := pathParams(common.BKObjIDField)
+	from, err := strconv.ParseInt(pathParams("from"), 10, 64)
+	if nil != err {
+		return nil, params.Err.Errorf(common.CCErrCommParamsNeedInt, "from")
+	}
+	to, err := strconv.ParseInt(pathParams("to"), 10, 64)
+	if nil != err {
+		return nil, params.Err.Errorf(common.CCErrCommParamsNeedInt, "to")
+	}
+
+	fromVersion, err := s.getObjectSchemaVersion(params, objID, from)
+	if nil != err {
+		return nil, err
+	}
+	toVersion, err := s.getObjectSchemaVersion(params, objID, to)
+	if nil != err {
+		return nil, err
+	}
+
+	diff := metadata.DiffModelSchema(fromVersion.Schema, toVersion.Schema)
+	diff.ObjectID = objID
+	diff.FromVersion = from
+	diff.ToVersion = to
+	return diff, nil
+}
+
+// RollbackObjectSchema restore the schema of the object to the version, and record it as a new version
+func (s *Service) RollbackObjectSchema(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	objID := pathParams(common.BKObjIDField)
+	version, err := strconv.ParseInt(pathParams("version"), 10, 64)
+	if nil != err {
+		return nil, params.Err.Errorf(common.CCErrCommParamsNeedInt, "version")
+	}
+
+	// the latest version is the schema before the rollback, which is used to find out the restored resources.
+	latest, err := s.Engine.CoreAPI.CoreService().Model().ReadModelSchemaVersion(params.Context, params.Header, objID,
+		metadata.QueryCondition{Limit: metadata.SearchLimit{Limit: 1}})
+	if nil != err {
+		blog.Errorf("[RollbackObjectSchema] search the latest schema version of %s failed, err: %v, rid: %s", objID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !latest.Result {
+		blog.Errorf("[RollbackObjectSchema] search the latest schema version of %s failed, err: %s, rid: %s", objID, latest.ErrMsg, params.ReqID)
+		return nil, params.Err.New(latest.Code, latest.ErrMsg)
+	}
+
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().RollbackModelSchema(params.Context, params.Header, objID, version)
+	if nil != err {
+		blog.Errorf("[RollbackObjectSchema] rollback %s to version %d failed, err: %v, rid: %s", objID, version, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[RollbackObjectSchema] rollback %s to version %d failed, err: %s, rid: %s", objID, version, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+
+	if len(latest.Data.Info) > 0 {
+		if err := s.syncObjectSchemaAuth(params, latest.Data.Info[0].Schema, rsp.Data.Schema); nil != err {
+			return nil, err
+		}
+	}
+	return rsp.Data, nil
+}
+
+// syncObjectSchemaAuth register the resources restored by the rollback to iam, and deregister the removed uniques
+func (s *Service) syncObjectSchemaAuth(params types.ContextParams, before, after metadata.ModelSchema) error {
+	diff := metadata.DiffModelSchema(before, after)
+
+	if len(diff.AddedGroups) > 0 {
+		if err := s.AuthManager.RegisterModelAttributeGroup(params.Context, params.Header, diff.AddedGroups...); nil != err {
+			blog.Errorf("[RollbackObjectSchema] register the groups to iam failed, err: %v, rid: %s", err, params.ReqID)
+			return params.Err.New(common.CCErrCommRegistResourceToIAMFailed, err.Error())
+		}
+	}
+	if len(diff.AddedAttributes) > 0 {
+		if err := s.AuthManager.RegisterModelAttribute(params.Context, params.Header, diff.AddedAttributes...); nil != err {
+			blog.Errorf("[RollbackObjectSchema] register the attributes to iam failed, err: %v, rid: %s", err, params.ReqID)
+			return params.Err.New(common.CCErrCommRegistResourceToIAMFailed, err.Error())
+		}
+	}
+
+	beforeUniques := make(map[uint64]bool, len(before.Uniques))
+	for _, unique := range before.Uniques {
+		beforeUniques[unique.ID] = true
+	}
+	afterUniques := make(map[uint64]bool, len(after.Uniques))
+	added := make([]int64, 0)
+	for _, unique := range after.Uniques {
+		afterUniques[unique.ID] = true
+		if !beforeUniques[unique.ID] {
+			added = append(added, int64(unique.ID))
+		}
+	}
+	removed := make([]int64, 0)
+	for _, unique := range before.Uniques {
+		if !afterUniques[unique.ID] {
+			removed = append(removed, int64(unique.ID))
+		}
+	}
+	if len(added) > 0 {
+		if err := s.AuthManager.RegisterModuleUniqueByID(params.Context, params.Header, added...); nil != err {
+			blog.Errorf("[RollbackObjectSchema] register the uniques %v to iam failed, err: %v, rid: %s", added, err, params.ReqID)
+			return params.Err.New(common.CCErrCommRegistResourceToIAMFailed, err.Error())
+		}
+	}
+	if len(removed) > 0 {
+		if err := s.AuthManager.DeregisterModelUniqueByID(params.Context, params.Header, removed...); nil != err {
+			blog.Errorf("[RollbackObjectSchema] deregister the uniques %v from iam failed, err: %v, rid: %s", removed, err, params.ReqID)
+			return params.Err.New(common.CCErrCommUnRegistResourceToIAMFailed, err.Error())
+		}
+	}
+	return nil
+}
+
+func (s *Service) getObjectSchemaVersion(params types.ContextParams, objID string, version int64) (*metadata.ModelSchemaVersion, error) {
+	rsp, err := s.Engine.CoreAPI.CoreService().Model().GetModelSchemaVersion(params.Context, params.Header, objID, version)
+	if nil != err {
+		blog.Errorf("get the schema version %d of %s failed, err: %v, rid: %s", version, objID, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("get the schema version %d of %s failed, err: %s, rid: %s", version, objID, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return &rsp.Data, nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"net/http"
+
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/openapi"
+)
+
+// routeSchemas the typed request and response of the routes, which are used to generate the api document
+func routeSchemas() openapi.RouteSchemas {
+	schemas := openapi.RouteSchemas{}
+	schemas.Add(http.MethodPost, "/topo/association/type/action/search", metadata.SearchAssociationTypeRequest{}, metadata.SearchAssociationTypeResult{})
+	schemas.Add(http.MethodPost, "/topo/association/type/action/create", metadata.AssociationKind{}, metadata.CreateAssociationTypeResult{})
+	schemas.Add(http.MethodPut, "/topo/association/type/{id}/action/update", metadata.UpdateAssociationTypeRequest{}, metadata.UpdateAssociationTypeResult{})
+	schemas.Add(http.MethodDelete, "/topo/association/type/{id}/action/delete", nil, metadata.DeleteAssociationTypeResult{})
+	schemas.Add(http.MethodPost, "/object/association/action/search", metadata.SearchAssociationObjectRequest{}, metadata.SearchAssociationObjectResult{})
+	schemas.Add(http.MethodPost, "/object/association/action/create", metadata.Association{}, metadata.CreateAssociationObjectResult{})
+	schemas.Add(http.MethodPut, "/object/association/{id}/action/update", metadata.UpdateAssociationObjectRequest{}, metadata.UpdateAssociationObjectResult{})
+	schemas.Add(http.MethodDelete, "/object/association/{id}/action/delete", nil, metadata.DeleteAssociationObjectResult{})
+	schemas.Add(http.MethodPost, "/inst/association/action/search", metadata.SearchAssociationInstRequest{}, metadata.SearchAssociationInstResult{})
+	schemas.Add(http.MethodPost, "/inst/association/action/create", metadata.CreateAssociationInstRequest{}, metadata.CreateAssociationInstResult{})
+	schemas.Add(http.MethodDelete, "/inst/association/{association_id}/action/delete", nil, metadata.DeleteAssociationInstResult{})
+	schemas.Add(http.MethodPost, "/topo/association/type/action/search/batch", metadata.AssociationKindIDs{}, metadata.ListAssociationsWithAssociationKindResult{})
+	schemas.Add(http.MethodPost, "/app/{owner_id}", nil, metadata.CreateInstResult{})
+	schemas.Add(http.MethodPost, "/app/search/{owner_id}", nil, metadata.SearchInstResult{})
+	schemas.Add(http.MethodPost, "/app/default/{owner_id}/search", nil, metadata.SearchInstResult{})
+	schemas.Add(http.MethodPost, "/app/default/{owner_id}", nil, metadata.CreateInstResult{})
+	schemas.Add(http.MethodPost, "/inst/{owner_id}/{bk_obj_id}", nil, metadata.CreateInstResult{})
+	schemas.Add(http.MethodPost, "/inst/search/{owner_id}/{bk_obj_id}", metadata.SearchParams{}, metadata.SearchInstResult{})
+	schemas.Add(http.MethodPost, "/inst/search/owner/{owner_id}/object/{bk_obj_id}/detail", metadata.SearchParams{}, metadata.SearchInstResult{})
+	schemas.Add(http.MethodPost, "/inst/search/owner/{owner_id}/object/{bk_obj_id}", metadata.SearchParams{}, metadata.SearchInstResult{})
+	schemas.Add(http.MethodPost, "/inst/association/search/owner/{owner_id}/object/{bk_obj_id}", metadata.AssociationParams{}, metadata.SearchInstResult{})
+	schemas.Add(http.MethodPost, "/inst/search/{owner_id}/{bk_obj_id}/{inst_id}", metadata.SearchParams{}, metadata.SearchInstResult{})
+	schemas.Add(http.MethodPost, "/inst/search/topo/owner/{owner_id}/object/{bk_object_id}/inst/{inst_id}", metadata.SearchParams{}, metadata.SearchTopoResult{})
+	schemas.Add(http.MethodPost, "/inst/association/topo/search/owner/{owner_id}/object/{bk_obj_id}/inst/{inst_id}", metadata.SearchParams{}, metadata.SearchAssociationTopoResult{})
+	schemas.Add(http.MethodPost, "/audit/search", metadata.QueryInput{}, metadata.Response{})
+	schemas.Add(http.MethodGet, "/topo/internal/{owner_id}/{app_id}", nil, metadata.SearchInnterAppTopoResult{})
+	schemas.Add(http.MethodPost, "/set/{app_id}", nil, metadata.CreateInstResult{})
+	schemas.Add(http.MethodPost, "/set/search/{owner_id}/{app_id}", nil, metadata.SearchInstResult{})
+	schemas.Add(http.MethodPost, "/module/{app_id}/{set_id}", nil, metadata.CreateInstResult{})
+	schemas.Add(http.MethodPost, "/module/search/{owner_id}/{app_id}/{set_id}", nil, metadata.SearchInstResult{})
+	schemas.Add(http.MethodPost, "/object/classification", metadata.Classification{}, metadata.Response{})
+	schemas.Add(http.MethodPost, "/objectatt/group/new", metadata.Group{}, metadata.Response{})
+	schemas.Add(http.MethodPut, "/objectatt/group/update", metadata.PropertyGroupCondition{}, metadata.Response{})
+	schemas.Add(http.MethodPost, "/object", metadata.Object{}, metadata.Response{})
+	schemas.Add(http.MethodPost, "/objectattr", metadata.ObjAttDes{}, metadata.Response{})
+	schemas.Add(http.MethodPost, "/topo/model/mainline", metadata.MainLineObject{}, metadata.Response{})
+	schemas.Add(http.MethodGet, "/topo/model/{owner_id}", nil, metadata.MainlineObjectTopoResult{})
+	return schemas
+}
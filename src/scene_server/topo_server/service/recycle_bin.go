@@ -0,0 +1,189 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/auditoplog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/topo_server/core/types"
+)
+
+// SearchRecycleItems search the deleted instances, hosts and models kept in the recycle bin
+func (s *Service) SearchRecycleItems(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.QueryCondition{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("[SearchRecycleItems] unmarshal error: %v, data: %#v, rid: %s", err, data, params.ReqID)
+		return nil, params.Err.New(common.CCErrCommParamsInvalid, err.Error())
+	}
+
+	rsp, err := s.Engine.CoreAPI.CoreService().RecycleBin().SearchRecycleItems(params.Context, params.Header, &input)
+	if nil != err {
+		blog.Errorf("[SearchRecycleItems] search the recycle items failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[SearchRecycleItems] search the recycle items failed, err: %s, rid: %s", rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return rsp.Data, nil
+}
+
+// RestoreRecycleItem put the deleted record back, the associations whose peers are gone are skipped
+func (s *Service) RestoreRecycleItem(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	id, err := strconv.ParseInt(pathParams("id"), 10, 64)
+	if nil != err {
+		blog.Errorf("[RestoreRecycleItem] invalid id: %s, rid: %s", pathParams("id"), params.ReqID)
+		return nil, params.Err.Errorf(common.CCErrCommParamsInvalid, "id")
+	}
+
+	rsp, err := s.Engine.CoreAPI.CoreService().RecycleBin().RestoreRecycleItem(params.Context, params.Header, id)
+	if nil != err {
+		blog.Errorf("[RestoreRecycleItem] restore the recycle item %d failed, err: %v, rid: %s", id, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[RestoreRecycleItem] restore the recycle item %d failed, err: %s, rid: %s", id, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+
+	if err := s.registerRestoredAuth(params, rsp.Data.Item); nil != err {
+		return nil, err
+	}
+	if err := s.saveRestoredAuditLog(params, rsp.Data.Item); nil != err {
+		return nil, err
+	}
+	return rsp.Data, nil
+}
+
+// PurgeRecycleItems delete the records in the recycle bin permanently
+func (s *Service) PurgeRecycleItems(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.PurgeRecycleItemsOption{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("[PurgeRecycleItems] unmarshal error: %v, data: %#v, rid: %s", err, data, params.ReqID)
+		return nil, params.Err.New(common.CCErrCommParamsInvalid, err.Error())
+	}
+
+	rsp, err := s.Engine.CoreAPI.CoreService().RecycleBin().PurgeRecycleItems(params.Context, params.Header, &input)
+	if nil != err {
+		blog.Errorf("[PurgeRecycleItems] purge the recycle items %v failed, err: %v, rid: %s", input.IDs, err, params.ReqID)
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[PurgeRecycleItems] purge the recycle items %v failed, err: %s, rid: %s", input.IDs, rsp.ErrMsg, params.ReqID)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return nil, nil
+}
+
+// saveRestoredAuditLog record the restored record as created again
+func (s *Service) saveRestoredAuditLog(params types.ContextParams, item metadata.RecycleItem) error {
+	var bizID int64
+	var curData interface{} = item.Data
+	switch item.Kind {
+	case metadata.RecycleKindInstance:
+		bizID, _ = metadata.ParseBizIDFromData(item.Data)
+	case metadata.RecycleKindHost:
+		if len(item.HostRelations) > 0 {
+			bizID, _ = item.HostRelations[0].Int64(common.BKAppIDField)
+		}
+	case metadata.RecycleKindModel:
+		if item.Schema != nil {
+			curData = item.Schema.Object
+		}
+	}
+
+	headers := make([]metadata.Header, 0)
+	if item.Kind != metadata.RecycleKindModel {
+		cond := &metadata.QueryCondition{Condition: mapstr.MapStr{common.BKObjIDField: item.ObjectID}}
+		attrRsp, err := s.Engine.CoreAPI.CoreService().Model().ReadModelAttr(params.Context, params.Header, item.ObjectID, cond)
+		if nil != err || !attrRsp.Result {
+			blog.Errorf("[RestoreRecycleItem] get the attributes of %s failed, err: %v, resp: %v, rid: %s", item.ObjectID, err, attrRsp, params.ReqID)
+			return params.Err.Error(common.CCErrAuditSaveLogFailed)
+		}
+		for _, attr := range attrRsp.Data.Info {
+			headers = append(headers, metadata.Header{PropertyID: attr.PropertyID, PropertyName: attr.PropertyName})
+		}
+	}
+
+	auditLog := metadata.SaveAuditLogParams{
+		ID:    item.InstID,
+		Model: item.ObjectID,
+		Content: metadata.Content{
+			CurData: curData,
+			Headers: headers,
+		},
+		OpDesc: "restore " + item.Kind + " from the recycle bin",
+		OpType: auditoplog.AuditOpTypeAdd,
+		BizID:  bizID,
+	}
+	auditRsp, err := s.Engine.CoreAPI.CoreService().Audit().SaveAuditLog(params.Context, params.Header, auditLog)
+	if nil != err {
+		blog.Errorf("[RestoreRecycleItem] restore success, but save audit log failed, err: %v, rid: %s", err, params.ReqID)
+		return params.Err.Error(common.CCErrAuditSaveLogFailed)
+	}
+	if !auditRsp.Result {
+		blog.Errorf("[RestoreRecycleItem] restore success, but save audit log failed, err: %s, rid: %s", auditRsp.ErrMsg, params.ReqID)
+		return params.Err.New(auditRsp.Code, auditRsp.ErrMsg)
+	}
+	return nil
+}
+
+// registerRestoredAuth register the restored resources to iam again
+func (s *Service) registerRestoredAuth(params types.ContextParams, item metadata.RecycleItem) error {
+	var err error
+	switch item.Kind {
+	case metadata.RecycleKindInstance:
+		err = s.AuthManager.RegisterInstancesByID(params.Context, params.Header, item.ObjectID, item.InstID)
+	case metadata.RecycleKindHost:
+		err = s.AuthManager.RegisterHostsByID(params.Context, params.Header, item.InstID)
+	case metadata.RecycleKindModel:
+		err = s.registerRestoredModelAuth(params, item.Schema)
+	}
+	if nil != err {
+		blog.Errorf("[RestoreRecycleItem] register the restored %s %s(%d) to iam failed, err: %v, rid: %s", item.Kind, item.ObjectID, item.InstID, err, params.ReqID)
+		return params.Err.New(common.CCErrCommRegistResourceToIAMFailed, err.Error())
+	}
+	return nil
+}
+
+func (s *Service) registerRestoredModelAuth(params types.ContextParams, schema *metadata.ModelSchema) error {
+	if schema == nil {
+		return nil
+	}
+	if err := s.AuthManager.RegisterObject(params.Context, params.Header, schema.Object); nil != err {
+		return err
+	}
+	if len(schema.Groups) > 0 {
+		if err := s.AuthManager.RegisterModelAttributeGroup(params.Context, params.Header, schema.Groups...); nil != err {
+			return err
+		}
+	}
+	if len(schema.Attributes) > 0 {
+		if err := s.AuthManager.RegisterModelAttribute(params.Context, params.Header, schema.Attributes...); nil != err {
+			return err
+		}
+	}
+	uniqueIDs := make([]int64, 0, len(schema.Uniques))
+	for _, unique := range schema.Uniques {
+		uniqueIDs = append(uniqueIDs, int64(unique.ID))
+	}
+	if len(uniqueIDs) > 0 {
+		return s.AuthManager.RegisterModuleUniqueByID(params.Context, params.Header, uniqueIDs...)
+	}
+	return nil
+}
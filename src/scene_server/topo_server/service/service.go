@@ -30,8 +30,11 @@ import (
 	"configcenter/src/common/language"
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/openapi"
 	"configcenter/src/common/rdapi"
+	cctypes "configcenter/src/common/types"
 	"configcenter/src/common/util"
+	"configcenter/src/common/version"
 	"configcenter/src/scene_server/topo_server/app/options"
 	"configcenter/src/scene_server/topo_server/core"
 	"configcenter/src/scene_server/topo_server/core/types"
@@ -51,6 +54,8 @@ type Service struct {
 	Error       errors.CCErrorIf
 	Language    language.CCLanguageIf
 	actions     []action
+
+	graphQLSchemas *graphQLSchemaCache
 }
 
 // WebService the web service
@@ -70,6 +75,7 @@ func (s *Service) WebService() *restful.Container {
 
 	innerActions := s.Actions()
 
+	schemas := routeSchemas()
 	for _, actionItem := range innerActions {
 		action := api
 		if actionItem.Path == "/healthz" {
@@ -77,18 +83,19 @@ func (s *Service) WebService() *restful.Container {
 		}
 		switch actionItem.Verb {
 		case http.MethodPost:
-			action.Route(action.POST(actionItem.Path).To(actionItem.Handler))
+			action.Route(schemas.Apply(action.POST(actionItem.Path).To(actionItem.Handler), http.MethodPost, actionItem.Path))
 		case http.MethodDelete:
-			action.Route(action.DELETE(actionItem.Path).To(actionItem.Handler))
+			action.Route(schemas.Apply(action.DELETE(actionItem.Path).To(actionItem.Handler), http.MethodDelete, actionItem.Path))
 		case http.MethodPut:
-			action.Route(action.PUT(actionItem.Path).To(actionItem.Handler))
+			action.Route(schemas.Apply(action.PUT(actionItem.Path).To(actionItem.Handler), http.MethodPut, actionItem.Path))
 		case http.MethodGet:
-			action.Route(action.GET(actionItem.Path).To(actionItem.Handler))
+			action.Route(schemas.Apply(action.GET(actionItem.Path).To(actionItem.Handler), http.MethodGet, actionItem.Path))
 		default:
 			blog.Errorf(" the url (%s), the http method (%s) is not supported", actionItem.Path, actionItem.Verb)
 		}
 	}
 	container := restful.NewContainer().Add(api)
+	openapi.AddDocumentRoute(healthz, openapi.Info{Title: cctypes.CC_MODULE_TOPO, Version: version.CCVersion}, container)
 	container.Add(healthz)
 
 	return container
@@ -157,6 +164,45 @@ func (s *Service) sendCompleteResponse(resp *restful.Response, errorCode int, er
 
 }
 
+// sendStream write the records of the stream as NDJSON, the error is returned if it occurs before anything is
+// written, or else it's written as the last line of the stream.
+func (s *Service) sendStream(resp *restful.Response, rid string, stream StreamFunc) error {
+	written := false
+	encoder := json.NewEncoder(resp)
+	err := stream(func(records []mapstr.MapStr) error {
+		if !written {
+			resp.Header().Set("Content-Type", metadata.ContentTypeNDJSON)
+			resp.WriteHeader(http.StatusOK)
+			written = true
+		}
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		util.FlushResponse(resp)
+		return nil
+	})
+	if err == nil && !written {
+		resp.Header().Set("Content-Type", metadata.ContentTypeNDJSON)
+		resp.WriteHeader(http.StatusOK)
+		return nil
+	}
+	if err == nil || !written {
+		return err
+	}
+
+	blog.Errorf("write stream failed, err: %v, rid: %s", err, rid)
+	streamErr := metadata.StreamError{Code: common.CCSystemBusy, Message: err.Error()}
+	if e, ok := err.(errors.CCErrorCoder); ok {
+		streamErr.Code = e.GetCode()
+	}
+	if err := encoder.Encode(streamErr); err != nil {
+		blog.Errorf("write stream error failed, err: %v, rid: %s", err, rid)
+	}
+	return nil
+}
+
 func (s *Service) sendNoAuthResp(resp *restful.Response, dataMsg interface{}) {
 	js, err := json.Marshal(dataMsg)
 	if err != nil {
@@ -269,6 +315,14 @@ func (s *Service) Actions() []*httpserver.Action {
 
 				data, dataErr := act.HandlerFunc(handlerContext, req.PathParameter, req.QueryParameter, mData)
 
+				if stream, ok := data.(StreamFunc); ok && dataErr == nil {
+					// the error is returned only if nothing is written to the stream
+					if dataErr = s.sendStream(resp, rid, stream); dataErr == nil {
+						return
+					}
+					data = nil
+				}
+
 				if dataErr == nil {
 					s.sendResponse(resp, common.CCSuccess, data)
 					return
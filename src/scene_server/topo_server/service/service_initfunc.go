@@ -48,6 +48,7 @@ func (s *Service) initAssociation() {
 
 	// inst association methods
 	s.addAction(http.MethodPost, "/inst/association/action/search", s.SearchAssociationInst, nil)
+	s.addAction(http.MethodPost, "/inst/association/action/search/page", s.SearchAssociationInstByPage, nil)
 	s.addAction(http.MethodPost, "/inst/association/action/create", s.CreateAssociationInst, nil)
 	s.addAction(http.MethodDelete, "/inst/association/{association_id}/action/delete", s.DeleteAssociationInst, nil)
 
@@ -121,6 +122,8 @@ func (s *Service) initInst() {
 	s.addAction(http.MethodPut, "/inst/{owner_id}/{bk_obj_id}/batch/update", s.UpdateInsts, nil)
 	s.addAction(http.MethodPost, "/inst/search/{owner_id}/{bk_obj_id}", s.SearchInsts, nil)
 	s.addAction(http.MethodPost, "/inst/search/owner/{owner_id}/object/{bk_obj_id}/detail", s.SearchInstAndAssociationDetail, nil)
+	s.addAction(http.MethodPost, "/inst/search/owner/{owner_id}/object/{bk_obj_id}/statistics", s.SearchInstStatistics, nil)
+	s.addAction(http.MethodPost, "/inst/search/owner/{owner_id}/object/{bk_obj_id}/stream", s.SearchInstStream, nil)
 	s.addAction(http.MethodPost, "/inst/search/owner/{owner_id}/object/{bk_obj_id}", s.SearchInstByObject, nil)
 	s.addAction(http.MethodPost, "/inst/search/{owner_id}/{bk_obj_id}/{inst_id}", s.SearchInstByInstID, nil)
 
@@ -148,6 +151,39 @@ func (s *Service) initObjectObjectUnique() {
 	s.addAction(http.MethodGet, "/object/{bk_obj_id}/unique/action/search", s.SearchObjectUnique, nil)
 }
 
+func (s *Service) initObjectSchema() {
+	s.addAction(http.MethodPost, "/object/{bk_obj_id}/schema/versions/action/search", s.SearchObjectSchemaVersion, nil)
+	s.addAction(http.MethodGet, "/object/{bk_obj_id}/schema/version/{version}", s.GetObjectSchemaVersion, nil)
+	s.addAction(http.MethodGet, "/object/{bk_obj_id}/schema/diff/{from}/{to}", s.DiffObjectSchemaVersion, nil)
+	s.addAction(http.MethodPost, "/object/{bk_obj_id}/schema/version/{version}/action/rollback", s.RollbackObjectSchema, nil)
+}
+
+func (s *Service) initObjectLifecycle() {
+	s.addAction(http.MethodPut, "/object/{bk_obj_id}/lifecycle", s.SetObjectLifecycle, nil)
+	s.addAction(http.MethodGet, "/object/{bk_obj_id}/lifecycle", s.GetObjectLifecycle, nil)
+	s.addAction(http.MethodDelete, "/object/{bk_obj_id}/lifecycle", s.DeleteObjectLifecycle, nil)
+	s.addAction(http.MethodPost, "/object/{bk_obj_id}/lifecycle/history/action/search", s.SearchLifecycleHistory, nil)
+}
+
+func (s *Service) initFieldSource() {
+	s.addAction(http.MethodPut, "/object/{bk_obj_id}/field_source/priority", s.SetFieldSourcePriority, nil)
+	s.addAction(http.MethodGet, "/object/{bk_obj_id}/field_source/priority", s.GetFieldSourcePriorities, nil)
+	s.addAction(http.MethodDelete, "/object/{bk_obj_id}/field_source/priority/{bk_property_id}", s.DeleteFieldSourcePriority, nil)
+	s.addAction(http.MethodGet, "/inst/{bk_obj_id}/{bk_inst_id}/field_source", s.SearchInstFieldSources, nil)
+}
+
+func (s *Service) initModelBundle() {
+	s.addAction(http.MethodPost, "/model/bundle/action/export", s.ExportModelBundle, nil)
+	s.addAction(http.MethodPost, "/model/bundle/action/plan", s.PlanModelBundle, nil)
+	s.addAction(http.MethodPost, "/model/bundle/action/apply", s.ApplyModelBundle, nil)
+}
+
+func (s *Service) initRecycleBin() {
+	s.addAction(http.MethodPost, "/recycle/items/action/search", s.SearchRecycleItems, nil)
+	s.addAction(http.MethodPost, "/recycle/item/{id}/action/restore", s.RestoreRecycleItem, nil)
+	s.addAction(http.MethodPost, "/recycle/items/action/purge", s.PurgeRecycleItems, nil)
+}
+
 func (s *Service) initObjectGroup() {
 	s.addAction(http.MethodPost, "/objectatt/group/new", s.CreateObjectGroup, nil)
 	s.addAction(http.MethodPut, "/objectatt/group/update", s.UpdateObjectGroup, nil)
@@ -198,6 +234,12 @@ func (s *Service) initFind() {
 	s.addAction(http.MethodPost, "/find/full_text", s.FullTextFind, nil)
 }
 
+func (s *Service) initGraphQL() {
+	s.graphQLSchemas = &graphQLSchemaCache{schemas: make(map[string]*cachedGraphQLSchema)}
+	s.addPublicAction(http.MethodPost, "/topo/graphql", s.GraphQLQuery, nil)
+	s.addPublicAction(http.MethodGet, "/topo/graphql/schema", s.GraphQLSchema, nil)
+}
+
 func (s *Service) initService() {
 	s.initHealth()
 	s.initAssociation()
@@ -217,6 +259,11 @@ func (s *Service) initService() {
 	s.initGraphics()
 	s.initIdentifier()
 	s.initObjectObjectUnique()
+	s.initObjectSchema()
+	s.initObjectLifecycle()
+	s.initFieldSource()
+	s.initModelBundle()
+	s.initRecycleBin()
 
 	s.initBusinessObject()
 	s.initBusinessClassification()
@@ -228,4 +275,5 @@ func (s *Service) initService() {
 	s.initBusinessInst()
 
 	s.initFind()
+	s.initGraphQL()
 }
@@ -23,6 +23,10 @@ import (
 // LogicFunc the core logic function definition
 type LogicFunc func(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error)
 
+// StreamFunc the handler result which is written as NDJSON stream instead of json response,
+// it calls the write function with the records of each page.
+type StreamFunc func(write func(records []mapstr.MapStr) error) error
+
 // ParamsGetter get param by key
 type ParamsGetter func(name string) string
 
@@ -16,12 +16,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"configcenter/src/common"
 	"configcenter/src/common/backbone"
 	cc "configcenter/src/common/backbone/configcenter"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
 	"configcenter/src/common/types"
 	"configcenter/src/common/version"
 	"configcenter/src/source_controller/coreservice/app/options"
@@ -41,6 +43,15 @@ func (t *CoreServer) onCoreServiceConfigUpdate(previous, current cc.ProcessConfi
 
 	t.Config.Mongo = mongo.ParseConfigFromKV("mongodb", current.ConfigMap)
 	t.Config.Redis = redis.ParseConfigFromKV("redis", current.ConfigMap)
+	t.Config.RecycleRetentionDays = metadata.DefaultRecycleRetentionDays
+	if current.ConfigMap["recyclebin.retention_days"] != "" {
+		days, err := strconv.Atoi(current.ConfigMap["recyclebin.retention_days"])
+		if err != nil || days <= 0 {
+			blog.Errorf("invalid recycle bin retention days %s, use the default value", current.ConfigMap["recyclebin.retention_days"])
+		} else {
+			t.Config.RecycleRetentionDays = days
+		}
+	}
 
 	blog.V(3).Infof("the new cfg:%#v the origin cfg:%#v", t.Config, current.ConfigMap)
 
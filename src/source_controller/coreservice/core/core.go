@@ -53,6 +53,8 @@ type ModelAttribute interface {
 	DeleteModelAttributes(ctx ContextParams, objID string, inputParam metadata.DeleteOption) (*metadata.DeletedCount, error)
 	SearchModelAttributes(ctx ContextParams, objID string, inputParam metadata.QueryCondition) (*metadata.QueryModelAttributeDataResult, error)
 	SearchModelAttributesByCondition(ctx ContextParams, inputParam metadata.QueryCondition) (*metadata.QueryModelAttributeDataResult, error)
+	// ValidateModelAttribute check the attribute before it is written without the model operations
+	ValidateModelAttribute(ctx ContextParams, attribute metadata.Attribute, create bool) error
 }
 
 // ModelAttrUnique model attribute  unique methods definitions
@@ -63,12 +65,22 @@ type ModelAttrUnique interface {
 	SearchModelAttrUnique(ctx ContextParams, inputParam metadata.QueryCondition) (*metadata.QueryUniqueResult, error)
 }
 
+// ModelSchema model schema version methods definitions
+type ModelSchema interface {
+	SearchModelSchemaVersion(ctx ContextParams, objID string, inputParam metadata.QueryCondition) (*metadata.QueryModelSchemaVersionResult, error)
+	GetModelSchemaVersion(ctx ContextParams, objID string, version int64) (*metadata.ModelSchemaVersion, error)
+	RollbackModelSchema(ctx ContextParams, objID string, version int64) (*metadata.ModelSchemaVersion, error)
+	// SaveModelSchemaVersion record the schema versions of the models changed without the model operations
+	SaveModelSchemaVersion(ctx ContextParams, objIDs ...string)
+}
+
 // ModelOperation model methods
 type ModelOperation interface {
 	ModelClassification
 	ModelAttributeGroup
 	ModelAttribute
 	ModelAttrUnique
+	ModelSchema
 
 	CreateModel(ctx ContextParams, inputParam metadata.CreateModel) (*metadata.CreateOneDataResult, error)
 	SetModel(ctx ContextParams, inputParam metadata.SetModel) (*metadata.SetDataResult, error)
@@ -87,6 +99,8 @@ type InstanceOperation interface {
 	SearchModelInstance(ctx ContextParams, objID string, inputParam metadata.QueryCondition) (*metadata.QueryResult, error)
 	DeleteModelInstance(ctx ContextParams, objID string, inputParam metadata.DeleteOption) (*metadata.DeletedCount, error)
 	CascadeDeleteModelInstance(ctx ContextParams, objID string, inputParam metadata.DeleteOption) (*metadata.DeletedCount, error)
+	// ValidateInstanceUnique check the instance written without the instance operations against the unique rules
+	ValidateInstanceUnique(ctx ContextParams, objID string, instanceData mapstr.MapStr) error
 }
 
 // AssociationKind association kind methods
@@ -128,6 +142,8 @@ type DataSynchronizeOperation interface {
 	Find(ctx ContextParams, find *metadata.SynchronizeFindInfoParameter) ([]mapstr.MapStr, uint64, error)
 	ClearData(ctx ContextParams, input *metadata.SynchronizeClearDataParameter) error
 	SetIdentifierFlag(ctx ContextParams, input *metadata.SetIdenifierFlag) ([]metadata.ExceptionResult, error)
+	SearchConflict(ctx ContextParams, input *metadata.SynchronizeConflictSearchParameter) ([]metadata.SynchronizeConflict, uint64, error)
+	ResolveConflict(ctx ContextParams, input *metadata.SynchronizeConflictResolveParameter) ([]metadata.ExceptionResult, error)
 }
 
 // TopoOperation methods
@@ -183,6 +199,9 @@ type Core interface {
 	AuditOperation() AuditOperation
 	ProcessOperation() ProcessOperation
 	LabelOperation() LabelOperation
+	RecycleBinOperation() RecycleBinOperation
+	LifecycleOperation() LifecycleOperation
+	FieldSourceOperation() FieldSourceOperation
 }
 
 // ProcessOperation methods
@@ -236,6 +255,31 @@ type LabelOperation interface {
 	RemoveLabel(ctx ContextParams, tableName string, option selector.LabelRemoveOption) errors.CCErrorCoder
 }
 
+// RecycleBinOperation the deleted records kept for restoring
+type RecycleBinOperation interface {
+	RecycleInstances(ctx ContextParams, objID string, instIDs []int64) ([]int64, error)
+	SearchRecycleItems(ctx ContextParams, inputParam metadata.QueryCondition) (*metadata.RecycleItemList, error)
+	RestoreRecycleItem(ctx ContextParams, id int64) (*metadata.RestoreRecycleItemResult, error)
+	PurgeRecycleItems(ctx ContextParams, ids []int64) error
+	PurgeExpiredRecycleItems(ctx ContextParams, retentionDays int) error
+}
+
+// LifecycleOperation the lifecycle declared by the models and the state transitions of the instances
+type LifecycleOperation interface {
+	SetObjectLifecycle(ctx ContextParams, objID string, lifecycle metadata.ObjectLifecycle) error
+	GetObjectLifecycle(ctx ContextParams, objID string) (*metadata.ObjectLifecycle, error)
+	DeleteObjectLifecycle(ctx ContextParams, objID string) error
+	SearchLifecycleHistory(ctx ContextParams, objID string, inputParam metadata.QueryCondition) (*metadata.LifecycleHistoryList, error)
+}
+
+// FieldSourceOperation the source priorities of the model fields and the sources which last set the instance fields
+type FieldSourceOperation interface {
+	SetFieldSourcePriority(ctx ContextParams, objID string, priority metadata.FieldSourcePriority) error
+	GetFieldSourcePriorities(ctx ContextParams, objID string) ([]metadata.FieldSourcePriority, error)
+	DeleteFieldSourcePriority(ctx ContextParams, objID, propertyID string) error
+	SearchInstFieldSources(ctx ContextParams, objID string, instID int64) (*metadata.InstFieldSource, error)
+}
+
 type core struct {
 	model           ModelOperation
 	instance        InstanceOperation
@@ -246,12 +290,16 @@ type core struct {
 	audit           AuditOperation
 	process         ProcessOperation
 	label           LabelOperation
+	recycleBin      RecycleBinOperation
+	lifecycle       LifecycleOperation
+	fieldSource     FieldSourceOperation
 }
 
 // New create core
 func New(model ModelOperation, instance InstanceOperation, association AssociationOperation,
 	dataSynchronize DataSynchronizeOperation, topo TopoOperation, host HostOperation,
-	audit AuditOperation, process ProcessOperation, label LabelOperation) Core {
+	audit AuditOperation, process ProcessOperation, label LabelOperation, recycleBin RecycleBinOperation,
+	lifecycle LifecycleOperation, fieldSource FieldSourceOperation) Core {
 	return &core{
 		model:           model,
 		instance:        instance,
@@ -262,6 +310,9 @@ func New(model ModelOperation, instance InstanceOperation, association Associati
 		audit:           audit,
 		process:         process,
 		label:           label,
+		recycleBin:      recycleBin,
+		lifecycle:       lifecycle,
+		fieldSource:     fieldSource,
 	}
 }
 
@@ -300,3 +351,15 @@ func (m *core) ProcessOperation() ProcessOperation {
 func (m *core) LabelOperation() LabelOperation {
 	return m.label
 }
+
+func (m *core) RecycleBinOperation() RecycleBinOperation {
+	return m.recycleBin
+}
+
+func (m *core) LifecycleOperation() LifecycleOperation {
+	return m.lifecycle
+}
+
+func (m *core) FieldSourceOperation() FieldSourceOperation {
+	return m.fieldSource
+}
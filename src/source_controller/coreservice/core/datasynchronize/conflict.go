@@ -0,0 +1,374 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package datasynchronize
+
+import (
+	"encoding/json"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/auditoplog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/storage/dal"
+)
+
+// conflictIgnoreFields the fields maintained by cmdb itself, not take part in conflict detection
+var conflictIgnoreFields = map[string]bool{
+	"_id":                  true,
+	common.MetadataField:   true,
+	common.CreateTimeField: true,
+	common.LastTimeField:   true,
+	// the ip keys are computed from the ips
+	common.BKHostInnerIPKeyField: true,
+	common.BKHostOuterIPKeyField: true,
+}
+
+// diffConflictField three-way merge the source and target instance with the base of last synchronize.
+// conflicts are the fields which both changed in source and target since base, and the source value is
+// different from the target value. targetOnly are the fields only changed in target, which must keep
+// the target value, otherwise the stale source value overwrites the target edit.
+func diffConflictField(base, source, target mapstr.MapStr, instIDField string) (conflicts, targetOnly []string) {
+	for field, sourceVal := range source {
+		if conflictIgnoreFields[field] || field == instIDField {
+			continue
+		}
+		baseVal := base[field]
+		targetVal := target[field]
+		if isSameValue(sourceVal, targetVal) || isSameValue(baseVal, targetVal) {
+			// target not changed, or changed to the same value, the source value is used
+			continue
+		}
+		if isSameValue(baseVal, sourceVal) {
+			targetOnly = append(targetOnly, field)
+			continue
+		}
+		conflicts = append(conflicts, field)
+	}
+	return conflicts, targetOnly
+}
+
+// isSameValue compare value by json, the number from db and http request has different type
+func isSameValue(a, b interface{}) bool {
+	aBytes, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bBytes, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(aBytes) == string(bBytes)
+}
+
+func snapshotCondition(flag, dataClassify string, instID int64) mapstr.MapStr {
+	return mapstr.MapStr{
+		"synchronize_flag": flag,
+		"data_classify":    dataClassify,
+		"bk_inst_id":       instID,
+	}
+}
+
+// handleConflict compare the synchronize instance with the target instance and the last synchronize snapshot,
+// handle the conflict field by conflict policy. the field keep target value, including the field only changed
+// in target, will be removed from item.
+func (s *synchronizeAdapter) handleConflict(ctx core.ContextParams, dbParam synchronizeAdapterDBParameter, conds mapstr.MapStr, item *metadata.SynchronizeItem) errors.CCError {
+	snapshot := metadata.SynchronizeInstSnapshot{}
+	err := s.dbProxy.Table(common.BKTableNameSynchronizeSnapshot).Find(snapshotCondition(s.syncData.SynchronizeFlag, s.syncData.DataClassify, item.ID)).One(ctx, &snapshot)
+	if err != nil {
+		if s.dbProxy.IsNotFoundError(err) {
+			// first synchronize, nothing to compare
+			return nil
+		}
+		blog.Errorf("handleConflict get snapshot error. err:%s, DataClassify:%s, instID:%d, rid:%s", err.Error(), s.syncData.DataClassify, item.ID, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+
+	target := mapstr.New()
+	err = s.dbProxy.Table(dbParam.tableName).Find(conds).One(ctx, &target)
+	if err != nil {
+		blog.Errorf("handleConflict get target instance error. err:%s, DataClassify:%s, condition:%#v, rid:%s", err.Error(), s.syncData.DataClassify, conds, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+
+	policy := s.syncData.ConflictPolicy
+	if policy == "" {
+		policy = metadata.SynchronizeConflictPolicySource
+	}
+	fields, targetOnly := diffConflictField(snapshot.Info, item.Info, target, dbParam.InstIDField)
+	for _, field := range targetOnly {
+		delete(item.Info, field)
+	}
+	for _, field := range fields {
+		conflict := metadata.SynchronizeConflict{
+			SynchronizeFlag: s.syncData.SynchronizeFlag,
+			DataClassify:    s.syncData.DataClassify,
+			InstID:          item.ID,
+			Field:           field,
+			BaseValue:       snapshot.Info[field],
+			SourceValue:     item.Info[field],
+			TargetValue:     target[field],
+			Policy:          policy,
+			Version:         s.syncData.Version,
+		}
+		switch policy {
+		case metadata.SynchronizeConflictPolicyTarget:
+			conflict.Status = metadata.SynchronizeConflictStatusResolved
+			conflict.Action = metadata.SynchronizeConflictActionUseTarget
+			delete(item.Info, field)
+		case metadata.SynchronizeConflictPolicyManual:
+			conflict.Status = metadata.SynchronizeConflictStatusPending
+			delete(item.Info, field)
+		default:
+			conflict.Status = metadata.SynchronizeConflictStatusResolved
+			conflict.Action = metadata.SynchronizeConflictActionUseSource
+		}
+		if err := s.saveConflict(ctx, conflict); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveConflict save the conflict, the pending conflict of the same field will be replaced by the latest one
+func (s *synchronizeAdapter) saveConflict(ctx core.ContextParams, conflict metadata.SynchronizeConflict) errors.CCError {
+	now := time.Now()
+	conflict.LastTime = now
+
+	if conflict.Status == metadata.SynchronizeConflictStatusPending {
+		cond := snapshotCondition(conflict.SynchronizeFlag, conflict.DataClassify, conflict.InstID)
+		cond.Set("field", conflict.Field)
+		cond.Set("status", metadata.SynchronizeConflictStatusPending)
+		exist := metadata.SynchronizeConflict{}
+		err := s.dbProxy.Table(common.BKTableNameSynchronizeConflict).Find(cond).One(ctx, &exist)
+		if err != nil && !s.dbProxy.IsNotFoundError(err) {
+			blog.Errorf("saveConflict get pending conflict error. err:%s, condition:%#v, rid:%s", err.Error(), cond, ctx.ReqID)
+			return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+		}
+		if err == nil {
+			updateData := mapstr.MapStr{
+				"source_value": conflict.SourceValue,
+				"target_value": conflict.TargetValue,
+				"version":      conflict.Version,
+				"last_time":    now,
+			}
+			if err := s.dbProxy.Table(common.BKTableNameSynchronizeConflict).Update(ctx, mapstr.MapStr{"id": exist.ID}, updateData); err != nil {
+				blog.Errorf("saveConflict update pending conflict error. err:%s, id:%d, rid:%s", err.Error(), exist.ID, ctx.ReqID)
+				return ctx.Error.Error(common.CCErrCommDBUpdateFailed)
+			}
+			return nil
+		}
+	}
+
+	id, err := s.dbProxy.NextSequence(ctx, common.BKTableNameSynchronizeConflict)
+	if err != nil {
+		blog.Errorf("saveConflict get id error. err:%s, rid:%s", err.Error(), ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+	}
+	conflict.ID = int64(id)
+	conflict.CreateTime = now
+	if err := s.dbProxy.Table(common.BKTableNameSynchronizeConflict).Insert(ctx, conflict); err != nil {
+		blog.Errorf("saveConflict insert conflict error. err:%s, conflict:%#v, rid:%s", err.Error(), conflict, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+	}
+	return nil
+}
+
+// saveSnapshot record the source instance of this synchronize as the base of next synchronize
+func (s *synchronizeAdapter) saveSnapshot(ctx core.ContextParams, instID int64, info mapstr.MapStr) errors.CCError {
+	cond := snapshotCondition(s.syncData.SynchronizeFlag, s.syncData.DataClassify, instID)
+	snapshotInfo := mapstr.New()
+	for field, val := range info {
+		if conflictIgnoreFields[field] {
+			continue
+		}
+		snapshotInfo[field] = val
+	}
+	snapshot := metadata.SynchronizeInstSnapshot{
+		SynchronizeFlag: s.syncData.SynchronizeFlag,
+		DataClassify:    s.syncData.DataClassify,
+		InstID:          instID,
+		Version:         s.syncData.Version,
+		Info:            snapshotInfo,
+		LastTime:        time.Now(),
+	}
+	if err := s.dbProxy.Table(common.BKTableNameSynchronizeSnapshot).Upsert(ctx, cond, snapshot); err != nil {
+		blog.Errorf("saveSnapshot error. err:%s, condition:%#v, rid:%s", err.Error(), cond, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBUpdateFailed)
+	}
+	return nil
+}
+
+// deleteSnapshot delete the snapshot of the synchronize deleted instance
+func (s *synchronizeAdapter) deleteSnapshot(ctx core.ContextParams, instIDArr []int64) {
+	cond := mapstr.MapStr{
+		"synchronize_flag": s.syncData.SynchronizeFlag,
+		"data_classify":    s.syncData.DataClassify,
+		"bk_inst_id":       mapstr.MapStr{common.BKDBIN: instIDArr},
+	}
+	if err := s.dbProxy.Table(common.BKTableNameSynchronizeSnapshot).Delete(ctx, cond); err != nil {
+		blog.Warnf("deleteSnapshot error. err:%s, condition:%#v, rid:%s", err.Error(), cond, ctx.ReqID)
+	}
+}
+
+type conflictManager struct {
+	dbProxy   dal.RDB
+	dependent OperationDependences
+}
+
+func (c *conflictManager) search(ctx core.ContextParams, input *metadata.SynchronizeConflictSearchParameter) ([]metadata.SynchronizeConflict, uint64, errors.CCError) {
+	cond := mapstr.New()
+	if input.SynchronizeFlag != "" {
+		cond.Set("synchronize_flag", input.SynchronizeFlag)
+	}
+	if input.DataClassify != "" {
+		cond.Set("data_classify", input.DataClassify)
+	}
+	if input.InstID != 0 {
+		cond.Set("bk_inst_id", input.InstID)
+	}
+	if input.Status != "" {
+		cond.Set("status", input.Status)
+	}
+
+	cnt, err := c.dbProxy.Table(common.BKTableNameSynchronizeConflict).Find(cond).Count(ctx)
+	if err != nil {
+		blog.Errorf("search synchronize conflict count error. err:%s, condition:%#v, rid:%s", err.Error(), cond, ctx.ReqID)
+		return nil, 0, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+
+	sort := input.Page.Sort
+	if sort == "" {
+		sort = "id"
+	}
+	info := make([]metadata.SynchronizeConflict, 0)
+	err = c.dbProxy.Table(common.BKTableNameSynchronizeConflict).Find(cond).Sort(sort).
+		Start(uint64(input.Page.Start)).Limit(uint64(input.Page.Limit)).All(ctx, &info)
+	if err != nil {
+		blog.Errorf("search synchronize conflict error. err:%s, condition:%#v, rid:%s", err.Error(), cond, ctx.ReqID)
+		return nil, 0, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	return info, cnt, nil
+}
+
+func (c *conflictManager) resolve(ctx core.ContextParams, input *metadata.SynchronizeConflictResolveParameter) ([]metadata.ExceptionResult, errors.CCError) {
+	cond := mapstr.MapStr{
+		"id":     mapstr.MapStr{common.BKDBIN: input.IDs},
+		"status": metadata.SynchronizeConflictStatusPending,
+	}
+	conflicts := make([]metadata.SynchronizeConflict, 0)
+	if err := c.dbProxy.Table(common.BKTableNameSynchronizeConflict).Find(cond).All(ctx, &conflicts); err != nil {
+		blog.Errorf("resolve synchronize conflict get conflict error. err:%s, condition:%#v, rid:%s", err.Error(), cond, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+
+	var exceptions []metadata.ExceptionResult
+	for _, conflict := range conflicts {
+		if input.Action == metadata.SynchronizeConflictActionUseSource {
+			if err := c.useSource(ctx, conflict); err != nil {
+				blog.Errorf("resolve synchronize conflict update instance error. err:%s, conflict:%#v, rid:%s", err.Error(), conflict, ctx.ReqID)
+				code := int64(common.CCErrCommDBUpdateFailed)
+				if ccErr, ok := err.(errors.CCErrorCoder); ok {
+					code = int64(ccErr.GetCode())
+				}
+				exceptions = append(exceptions, metadata.ExceptionResult{
+					Code:        code,
+					Message:     err.Error(),
+					OriginIndex: conflict.ID,
+				})
+				continue
+			}
+		}
+
+		updateData := mapstr.MapStr{
+			"status":    metadata.SynchronizeConflictStatusResolved,
+			"action":    input.Action,
+			"resolver":  ctx.User,
+			"last_time": time.Now(),
+		}
+		err := c.dbProxy.Table(common.BKTableNameSynchronizeConflict).Update(ctx, mapstr.MapStr{"id": conflict.ID}, updateData)
+		if err != nil {
+			blog.Errorf("resolve synchronize conflict update status error. err:%s, conflict:%#v, rid:%s", err.Error(), conflict, ctx.ReqID)
+			exceptions = append(exceptions, metadata.ExceptionResult{
+				Code:        common.CCErrCommDBUpdateFailed,
+				Message:     ctx.Error.Error(common.CCErrCommDBUpdateFailed).Error(),
+				OriginIndex: conflict.ID,
+			})
+		}
+	}
+	if len(exceptions) > 0 {
+		return exceptions, ctx.Error.Error(common.CCErrCoreServiceSyncError)
+	}
+	return nil, nil
+}
+
+// useSource overwrite the target field with the source value by the instance manager like the other updates,
+// and save the audit log of the change made by the resolver
+func (c *conflictManager) useSource(ctx core.ContextParams, conflict metadata.SynchronizeConflict) error {
+	objID := conflict.DataClassify
+	instCond := mapstr.MapStr{common.GetInstIDField(objID): conflict.InstID}
+	preData := mapstr.New()
+	if err := c.dbProxy.Table(common.GetInstTableName(objID)).Find(instCond).One(ctx, &preData); err != nil {
+		blog.Errorf("resolve synchronize conflict get instance error. err:%s, condition:%#v, rid:%s", err.Error(), instCond, ctx.ReqID)
+		if c.dbProxy.IsNotFoundError(err) {
+			return ctx.Error.Error(common.CCErrCommNotFound)
+		}
+		return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+
+	input := metadata.UpdateOption{
+		Data:      mapstr.MapStr{conflict.Field: conflict.SourceValue},
+		Condition: instCond.Clone(),
+	}
+	result, err := c.dependent.UpdateInstance(ctx, objID, input)
+	if err != nil {
+		return err
+	}
+	if len(result.Rejected) > 0 {
+		blog.Errorf("resolve synchronize conflict field %s rejected by the field source priorities, rid:%s", conflict.Field, ctx.ReqID)
+		return ctx.Error.Errorf(common.CCErrCommParamsInvalid, conflict.Field)
+	}
+
+	curData := preData.Clone()
+	curData.Set(conflict.Field, conflict.SourceValue)
+	bizID, _ := preData.Int64(common.BKAppIDField)
+	auditLog := metadata.SaveAuditLogParams{
+		ID:    conflict.InstID,
+		Model: objID,
+		Content: metadata.Content{
+			PreData: preData,
+			CurData: curData,
+			Headers: []metadata.Header{{PropertyID: conflict.Field, PropertyName: c.propertyName(ctx, objID, conflict.Field)}},
+		},
+		OpDesc: "update " + objID,
+		OpType: auditoplog.AuditOpTypeModify,
+		BizID:  bizID,
+	}
+	if err := c.dependent.SaveAuditLog(ctx, auditLog); err != nil {
+		blog.Errorf("resolve synchronize conflict save audit log error. err:%s, conflict:%#v, rid:%s", err.Error(), conflict, ctx.ReqID)
+	}
+	return nil
+}
+
+// propertyName the name of the model attribute shown in the audit log, the property id if not found
+func (c *conflictManager) propertyName(ctx core.ContextParams, objID, propertyID string) string {
+	cond := mapstr.MapStr{common.BKObjIDField: objID, common.BKPropertyIDField: propertyID}
+	attr := metadata.Attribute{}
+	if err := c.dbProxy.Table(common.BKTableNameObjAttDes).Find(cond).One(ctx, &attr); err != nil {
+		blog.Warnf("resolve synchronize conflict get attribute error. err:%s, condition:%#v, rid:%s", err.Error(), cond, ctx.ReqID)
+		return propertyID
+	}
+	return attr.PropertyName
+}
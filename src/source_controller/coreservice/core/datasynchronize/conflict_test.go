@@ -0,0 +1,58 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasynchronize
+
+import (
+	"sort"
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConflictField(t *testing.T) {
+	base := mapstr.MapStr{"bk_inst_id": 1, "name": "a", "ip": "127.0.0.1", "cpu": int64(4), "os": "linux"}
+	source := mapstr.MapStr{"bk_inst_id": 1, "name": "b", "ip": "127.0.0.2", "cpu": float64(8), "os": "linux",
+		common.LastTimeField: "2019-09-02 00:00:00"}
+	target := mapstr.MapStr{"bk_inst_id": 1, "name": "c", "ip": "127.0.0.2", "cpu": int32(16), "os": "windows",
+		common.LastTimeField: "2019-09-01 00:00:00"}
+
+	fields, targetOnly := diffConflictField(base, source, target, common.BKInstIDField)
+	sort.Strings(fields)
+	// ip changed to the same value, os only changed in target
+	require.Equal(t, []string{"cpu", "name"}, fields)
+	require.Equal(t, []string{"os"}, targetOnly)
+}
+
+func TestDiffConflictFieldNewField(t *testing.T) {
+	base := mapstr.MapStr{"bk_inst_id": 1}
+	source := mapstr.MapStr{"bk_inst_id": 1, "name": "a", "cpu": 8}
+	target := mapstr.MapStr{"bk_inst_id": 1, "name": "b"}
+
+	fields, targetOnly := diffConflictField(base, source, target, common.BKInstIDField)
+	require.Equal(t, []string{"name"}, fields)
+	require.Empty(t, targetOnly)
+}
+
+func TestDiffConflictFieldTargetOnlyChanged(t *testing.T) {
+	base := mapstr.MapStr{"bk_inst_id": 1, "name": "a", "ip": "127.0.0.1"}
+	source := mapstr.MapStr{"bk_inst_id": 1, "name": "a", "ip": "127.0.0.2"}
+	target := mapstr.MapStr{"bk_inst_id": 1, "name": "b", "ip": "127.0.0.1"}
+
+	fields, targetOnly := diffConflictField(base, source, target, common.BKInstIDField)
+	// name is only edited in target, the stale source value must not overwrite it
+	require.Empty(t, fields)
+	require.Equal(t, []string{"name"}, targetOnly)
+}
@@ -13,6 +13,7 @@
 package datasynchronize
 
 import (
+	"configcenter/src/common/metadata"
 	"configcenter/src/source_controller/coreservice/core"
 )
 
@@ -23,4 +24,11 @@ type OperationDependences interface {
 
 	// IsInstanceExist used to check if the  instances exist
 	IsInstanceExist(ctx core.ContextParams, objID string, instID uint64) (exists bool, err error)
+
+	// UpdateInstance update the instances by the instance manager, which validates the data, pushes the events
+	// and records the field sources
+	UpdateInstance(ctx core.ContextParams, objID string, input metadata.UpdateOption) (*metadata.UpdatedCount, error)
+
+	// SaveAuditLog save the audit logs of the instance changes
+	SaveAuditLog(ctx core.ContextParams, logs ...metadata.SaveAuditLogParams) error
 }
@@ -108,3 +108,29 @@ func (s *SynchronizeManager) SetIdentifierFlag(ctx core.ContextParams, input *me
 	}
 	return nil, nil
 }
+
+// SearchConflict search the field conflict of synchronize instance
+func (s *SynchronizeManager) SearchConflict(ctx core.ContextParams, input *metadata.SynchronizeConflictSearchParameter) ([]metadata.SynchronizeConflict, uint64, error) {
+	if _, err := input.Page.Validate(); err != nil {
+		blog.Errorf("SearchConflict parameter page illegal, input:%#v, rid:%s", input, ctx.ReqID)
+		return nil, 0, ctx.Error.Errorf(common.CCErrCommParamsInvalid, "page")
+	}
+	manager := &conflictManager{dbProxy: s.dbProxy, dependent: s.dependent}
+	return manager.search(ctx, input)
+}
+
+// ResolveConflict resolve the pending field conflict of synchronize instance
+func (s *SynchronizeManager) ResolveConflict(ctx core.ContextParams, input *metadata.SynchronizeConflictResolveParameter) ([]metadata.ExceptionResult, error) {
+	if len(input.IDs) == 0 {
+		blog.Errorf("ResolveConflict parameter ids illegal, ids empty. input:%#v, rid:%s", input, ctx.ReqID)
+		return nil, ctx.Error.Errorf(common.CCErrCommParamsNeedSet, "ids")
+	}
+	switch input.Action {
+	case metadata.SynchronizeConflictActionUseSource, metadata.SynchronizeConflictActionUseTarget:
+	default:
+		blog.Errorf("ResolveConflict parameter action illegal, input:%#v, rid:%s", input, ctx.ReqID)
+		return nil, ctx.Error.Errorf(common.CCErrCommParamsInvalid, "action")
+	}
+	manager := &conflictManager{dbProxy: s.dbProxy, dependent: s.dependent}
+	return manager.resolve(ctx, input)
+}
@@ -61,6 +61,9 @@ func (s *synchronizeAdapter) PreSynchronizeFilter(ctx core.ContextParams) errors
 		// TODO  return error not synchronize sign
 		return ctx.Error.Errorf(common.CCErrCommParamsNeedSet, "synchronize_flag")
 	}
+	if !s.syncData.ConflictPolicy.Validate() {
+		return ctx.Error.Errorf(common.CCErrCommParamsInvalid, "conflict_policy")
+	}
 	if s.syncData.InfoArray == nil {
 		// TODO return error not found synchroize data
 		return ctx.Error.Errorf(common.CCErrCommParamsNeedSet, "instance_info_array")
@@ -165,6 +168,21 @@ func (s *synchronizeAdapter) replaceSynchronize(ctx core.ContextParams, dbParam
 		}
 
 		blog.V(6).Infof("replaceSynchronize DataClassify:%s, info:%#v, table:%s, version:%v, exist:%v, rid:%s", s.syncData.DataClassify, item, dbParam.tableName, s.syncData.Version, exist, ctx.ReqID)
+		isInstance := s.syncData.OperateDataType == metadata.SynchronizeOperateDataTypeInstance
+		// the source value before conflict handle, it is the base of next synchronize
+		sourceInfo := item.Info.Clone()
+		if exist && isInstance {
+			if err := s.handleConflict(ctx, dbParam, conds, item); err != nil {
+				s.errorArray[item.ID] = synchronizeAdapterError{
+					instInfo: item,
+					err:      err,
+				}
+				continue
+			}
+		}
+		if dbParam.tableName == common.BKTableNameBaseHost {
+			util.SetHostIPKeys(item.Info)
+		}
 		if exist {
 			// Existing data, does not update the ID field
 			delete(item.Info, dbParam.InstIDField)
@@ -188,6 +206,14 @@ func (s *synchronizeAdapter) replaceSynchronize(ctx core.ContextParams, dbParam
 				continue
 			}
 		}
+		if isInstance {
+			if err := s.saveSnapshot(ctx, item.ID, sourceInfo); err != nil {
+				s.errorArray[item.ID] = synchronizeAdapterError{
+					instInfo: item,
+					err:      err,
+				}
+			}
+		}
 	}
 }
 
@@ -205,6 +231,10 @@ func (s *synchronizeAdapter) deleteSynchronize(ctx core.ContextParams, dbParam s
 				err:      ctx.Error.Error(common.CCErrCommDBDeleteFailed),
 			}
 		}
+		return
+	}
+	if s.syncData.OperateDataType == metadata.SynchronizeOperateDataTypeInstance {
+		s.deleteSnapshot(ctx, instIDArr)
 	}
 }
 
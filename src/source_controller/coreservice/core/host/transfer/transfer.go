@@ -18,10 +18,13 @@ import (
 	"configcenter/src/common/condition"
 	"configcenter/src/common/errors"
 	"configcenter/src/common/eventclient"
+	"configcenter/src/common/fieldsource"
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/util"
 	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/source_controller/coreservice/core/lifecycle"
+	"configcenter/src/source_controller/coreservice/core/recyclebin"
 	"configcenter/src/storage/dal"
 )
 
@@ -50,6 +53,9 @@ type genericTransfer struct {
 	innerModuleID []int64
 	// map[bk_module_id]bk_set_id
 	moduleIDSetIDMap map[int64]int64
+	// the lifecycle of the host, nil if the host model has no lifecycle
+	hostLifecycle       *metadata.ObjectLifecycle
+	hostLifecycleLoaded bool
 }
 
 // validParameter valid parameter legal
@@ -90,6 +96,9 @@ func (t *genericTransfer) Transfer(ctx core.ContextParams, hostID int64) errors.
 	if err != nil {
 		return err
 	}
+	if err := t.validLifecycle(ctx, hostID); err != nil {
+		return err
+	}
 
 	// hostInfo
 	var hostInfo mapstr.MapStr
@@ -98,17 +107,28 @@ func (t *genericTransfer) Transfer(ctx core.ContextParams, hostID int64) errors.
 	// must be slice ptr address, Each assignment will change the address
 	defer t.generateEvent(ctx, &originDatas, &curDatas, hostInfo)
 
+	var recycleIDs []int64
+	if t.delHost {
+		// keep the host with its module relations in the recycle bin before they are deleted
+		recycleIDs, err = t.recycleHost(ctx, hostID)
+		if err != nil {
+			return err
+		}
+	}
+
 	originDatas, err = t.delHostModuleRelation(ctx, hostID)
 	if err != nil {
 		// It is not the time to merge and base the time. When it fails,
 		// it is clear that the data before the change is pushed.
 		// t.origindatas = nil
+		t.purgeRecycleItems(ctx, recycleIDs)
 		return err
 	}
 	// delete host.
 	if t.delHost {
 		hostInfo, err = t.deleteHost(ctx, hostID)
 		if err != nil {
+			t.purgeRecycleItems(ctx, recycleIDs)
 			return err
 		}
 		return nil
@@ -158,10 +178,64 @@ func (t *genericTransfer) deleteHost(ctx core.ContextParams, hostID int64) (maps
 		blog.ErrorJSON("deleteHost delete host error. err:%s, cond:%s, rid:%s", err.Error(), hostCondMap, ctx.ReqID)
 		return nil, ctx.Error.CCErrorf(common.CCErrCommDBDeleteFailed)
 	}
+	// the field sources are kept by the recycle item of the host
+	if err := fieldsource.Clear(ctx, t.dbProxy, common.BKInnerObjIDHost, []int64{hostID}); err != nil {
+		blog.ErrorJSON("deleteHost clear the field sources of the host error. err:%s, hostID:%s, rid:%s", err.Error(), hostID, ctx.ReqID)
+	}
 
 	return hostInfoArr[0], nil
 }
 
+// recycleHost save the host and all of its module relations into the recycle bin
+func (t *genericTransfer) recycleHost(ctx core.ContextParams, hostID int64) ([]int64, errors.CCErrorCoder) {
+	cond := util.SetQueryOwner(mapstr.MapStr{common.BKHostIDField: hostID}, ctx.SupplierAccount)
+	hostInfoArr := make([]mapstr.MapStr, 0)
+	if err := t.dbProxy.Table(common.BKTableNameBaseHost).Find(cond).All(ctx, &hostInfoArr); err != nil {
+		blog.ErrorJSON("recycleHost find host error. err:%s, cond:%s, rid:%s", err.Error(), cond, ctx.ReqID)
+		return nil, ctx.Error.CCErrorf(common.CCErrCommDBSelectFailed)
+	}
+	if len(hostInfoArr) == 0 {
+		return nil, ctx.Error.CCErrorf(common.CCErrCoreServiceHostNotExist, hostID)
+	}
+	relations := make([]mapstr.MapStr, 0)
+	if err := t.dbProxy.Table(common.BKTableNameModuleHostConfig).Find(cond).All(ctx, &relations); err != nil {
+		blog.ErrorJSON("recycleHost find module host relation error. err:%s, cond:%s, rid:%s", err.Error(), cond, ctx.ReqID)
+		return nil, ctx.Error.CCErrorf(common.CCErrCommDBSelectFailed)
+	}
+
+	sources, err := fieldsource.Find(ctx, t.dbProxy, common.BKInnerObjIDHost, hostID)
+	if err != nil {
+		blog.ErrorJSON("recycleHost find the field sources of the host error. err:%s, hostID:%s, rid:%s", err.Error(), hostID, ctx.ReqID)
+		return nil, ctx.Error.CCErrorf(common.CCErrCommDBSelectFailed)
+	}
+
+	innerIP, _ := hostInfoArr[0].String(common.BKHostInnerIPField)
+	ids, err := recyclebin.Save(ctx, t.dbProxy, metadata.RecycleItem{
+		Kind:          metadata.RecycleKindHost,
+		ObjectID:      common.BKInnerObjIDHost,
+		InstID:        hostID,
+		InstName:      innerIP,
+		Data:          hostInfoArr[0],
+		HostRelations: relations,
+		FieldSources:  sources.Fields,
+	})
+	if err != nil {
+		return nil, ctx.Error.CCErrorf(common.CCErrCommDBInsertFailed)
+	}
+	return ids, nil
+}
+
+// purgeRecycleItems remove the recycle items of the host which failed to delete
+func (t *genericTransfer) purgeRecycleItems(ctx core.ContextParams, ids []int64) {
+	if len(ids) == 0 {
+		return
+	}
+	cond := mapstr.MapStr{common.BKFieldID: mapstr.MapStr{common.BKDBIN: ids}}
+	if err := t.dbProxy.Table(common.BKTableNameRecycleBin).Delete(ctx, cond); err != nil {
+		blog.ErrorJSON("purge the recycle items of the host error. err:%s, ids:%s, rid:%s", err.Error(), ids, ctx.ReqID)
+	}
+}
+
 // generateEvent handle event trigger.
 // Data from before and after changes cannot be merged for historical reasons.
 func (t *genericTransfer) generateEvent(ctx core.ContextParams, originDatas, curDatas *[]mapstr.MapStr, hostInfo mapstr.MapStr) errors.CCErrorCoder {
@@ -340,6 +414,38 @@ func (t *genericTransfer) validHost(ctx core.ContextParams, hostID int64) errors
 	return nil
 }
 
+// validLifecycle check whether the host is allowed to be transferred or deleted in its lifecycle state
+func (t *genericTransfer) validLifecycle(ctx core.ContextParams, hostID int64) errors.CCErrorCoder {
+	if !t.hostLifecycleLoaded {
+		hostLifecycle, err := lifecycle.Find(ctx, t.dbProxy, common.BKInnerObjIDHost)
+		if err != nil {
+			return ctx.Error.CCErrorf(common.CCErrCommDBSelectFailed)
+		}
+		t.hostLifecycle = hostLifecycle
+		t.hostLifecycleLoaded = true
+	}
+	if t.hostLifecycle == nil {
+		return nil
+	}
+
+	cond := util.SetQueryOwner(mapstr.MapStr{common.BKHostIDField: hostID}, ctx.SupplierAccount)
+	host := make(mapstr.MapStr)
+	if err := t.dbProxy.Table(common.BKTableNameBaseHost).Find(cond).Fields(t.hostLifecycle.PropertyID).One(ctx, &host); err != nil {
+		blog.ErrorJSON("validLifecycle find host error. err:%s, cond:%s, rid:%s", err.Error(), cond, ctx.ReqID)
+		return ctx.Error.CCErrorf(common.CCErrCommDBSelectFailed)
+	}
+	operation := metadata.LifecycleOperationTransfer
+	if t.delHost {
+		operation = metadata.LifecycleOperationDelete
+	}
+	state := t.hostLifecycle.CurrentState(host)
+	if t.hostLifecycle.IsBlocked(state, operation) {
+		blog.Errorf("validLifecycle the %s of host %d is blocked by the state %s, rid: %s", operation, hostID, state, ctx.ReqID)
+		return ctx.Error.CCErrorf(common.CCErrCoreServiceLifecycleOperationBlocked, state, operation)
+	}
+	return nil
+}
+
 // delHostModuleRelation delete single host module relation
 func (t *genericTransfer) delHostModuleRelation(ctx core.ContextParams, hostID int64) ([]mapstr.MapStr, errors.CCErrorCoder) {
 	bizID := t.bizID
@@ -20,11 +20,13 @@ import (
 	"configcenter/src/common/condition"
 	"configcenter/src/common/errors"
 	"configcenter/src/common/eventclient"
+	"configcenter/src/common/fieldsource"
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/universalsql/mongo"
 	"configcenter/src/common/util"
 	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/source_controller/coreservice/core/lifecycle"
 	"configcenter/src/storage/dal"
 )
 
@@ -57,7 +59,14 @@ func (m *instanceManager) instCnt(ctx core.ContextParams, objID string, cond map
 func (m *instanceManager) CreateModelInstance(ctx core.ContextParams, objID string, inputParam metadata.CreateModelInstance) (*metadata.CreateOneDataResult, error) {
 	rid := util.ExtractRequestIDFromContext(ctx)
 
-	err := m.validCreateInstanceData(ctx, objID, inputParam.Data)
+	objLifecycle, err := lifecycle.Find(ctx, m.dbProxy, objID)
+	if nil != err {
+		return nil, err
+	}
+	if err := lifecycle.CheckCreate(ctx, objLifecycle, inputParam.Data); nil != err {
+		return nil, err
+	}
+	err = m.validCreateInstanceData(ctx, objID, inputParam.Data)
 	if nil != err {
 		blog.Errorf("CreateModelInstance failed, valid error: %+v, rid: %s", err, rid)
 		return nil, err
@@ -67,6 +76,8 @@ func (m *instanceManager) CreateModelInstance(ctx core.ContextParams, objID stri
 		blog.ErrorJSON("CreateModelInstance create objID(%s) instance error. err:%s, data:%s, rid:%s", objID, err.Error(), inputParam.Data, ctx.ReqID)
 		return nil, err
 	}
+	m.recordCreatedState(ctx, objLifecycle, inputParam.Data, id)
+	m.recordFieldSource(ctx, objID, []int64{int64(id)}, inputParam.Data)
 
 	instIDFieldName := common.GetInstIDField(objID)
 	// 处理事件数据的
@@ -82,9 +93,16 @@ func (m *instanceManager) CreateModelInstance(ctx core.ContextParams, objID stri
 func (m *instanceManager) CreateManyModelInstance(ctx core.ContextParams, objID string, inputParam metadata.CreateManyModelInstance) (*metadata.CreateManyDataResult, error) {
 	var newIDs []uint64
 	dataResult := &metadata.CreateManyDataResult{}
+	objLifecycle, err := lifecycle.Find(ctx, m.dbProxy, objID)
+	if nil != err {
+		return nil, err
+	}
 	for itemIdx, item := range inputParam.Datas {
 		item.Set(common.BKOwnerIDField, ctx.SupplierAccount)
-		err := m.validCreateInstanceData(ctx, objID, item)
+		err := lifecycle.CheckCreate(ctx, objLifecycle, item)
+		if nil == err {
+			err = m.validCreateInstanceData(ctx, objID, item)
+		}
 		if nil != err {
 			dataResult.Exceptions = append(dataResult.Exceptions, metadata.ExceptionResult{
 				Message:     err.Error(),
@@ -106,6 +124,9 @@ func (m *instanceManager) CreateManyModelInstance(ctx core.ContextParams, objID
 			continue
 		}
 
+		m.recordCreatedState(ctx, objLifecycle, item, id)
+		m.recordFieldSource(ctx, objID, []int64{int64(id)}, item)
+
 		dataResult.Created = append(dataResult.Created, metadata.CreatedDataResult{
 			ID: id,
 		})
@@ -115,7 +136,7 @@ func (m *instanceManager) CreateManyModelInstance(ctx core.ContextParams, objID
 	instIDFieldName := common.GetInstIDField(objID)
 	// 处理事件数据的
 	eh := m.NewEventClient(objID)
-	err := eh.SetCurDataAndPush(ctx, objID, metadata.EventActionCreate, condition.CreateCondition().Field(instIDFieldName).In(newIDs).ToMapStr())
+	err = eh.SetCurDataAndPush(ctx, objID, metadata.EventActionCreate, condition.CreateCondition().Field(instIDFieldName).In(newIDs).ToMapStr())
 	if err != nil {
 		blog.ErrorJSON("CreateManyModelInstance  event push instance current data error. err:%s, objID:%s inst id:%s, rid:%s", err, objID, newIDs, ctx.ReqID)
 		return dataResult, err
@@ -152,14 +173,52 @@ func (m *instanceManager) UpdateModelInstance(ctx core.ContextParams, objID stri
 		}
 	}
 
+	objLifecycle, err := lifecycle.Find(ctx, m.dbProxy, objID)
+	if nil != err {
+		return nil, err
+	}
+	priorities, err := fieldsource.FindPriorities(ctx, m.dbProxy, ctx.SupplierAccount, objID)
+	if nil != err {
+		blog.Errorf("UpdateModelInstance get %s field source priorities failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return nil, err
+	}
+	source := util.GetDataSource(ctx.Header)
+	// the fields each instance accept from the source, only used when some fields are rejected
+	allowedData := make(map[int64]mapstr.MapStr)
+	rejectedFields := make([]metadata.RejectedFields, 0)
+	transitions := make([]metadata.LifecycleHistory, 0)
 	for _, origin := range origins {
 		instIDI := origin[instIDFieldName]
 		instID, _ := util.GetInt64ByInterface(instIDI)
-		err := m.validUpdateInstanceData(ctx, objID, inputParam.Data, instMedataData, uint64(instID))
+		allowed, rejected, err := fieldsource.FilterInst(ctx, m.dbProxy, priorities, objID, instID, source, inputParam.Data)
+		if nil != err {
+			blog.Errorf("UpdateModelInstance filter the fields of %s instance %d failed, err: %v, rid: %s", objID, instID, err, ctx.ReqID)
+			return nil, err
+		}
+		if len(rejected) > 0 {
+			blog.Infof("UpdateModelInstance source %s is not allowed to overwrite fields %v of %s instance %d, rid: %s", source, rejected, objID, instID, ctx.ReqID)
+			rejectedFields = append(rejectedFields, metadata.RejectedFields{InstID: instID, Fields: rejected})
+		}
+		allowedData[instID] = allowed
+		// only the fields accepted from the source are validated and may change the lifecycle state,
+		// allowed is the input data itself if nothing is rejected.
+		err = m.validUpdateInstanceData(ctx, objID, allowed, instMedataData, uint64(instID))
 		if nil != err {
 			blog.Errorf("update module instance validate error :%v ,rid:%s", err, ctx.ReqID)
 			return nil, err
 		}
+		state, changed, err := lifecycle.CheckUpdate(ctx, objLifecycle, origin, allowed)
+		if nil != err {
+			return nil, err
+		}
+		if changed {
+			transitions = append(transitions, metadata.LifecycleHistory{
+				ObjectID: objID,
+				InstID:   instID,
+				From:     objLifecycle.CurrentState(origin),
+				To:       state,
+			})
+		}
 		// 设置实例变更前数据
 		eh.SetPreData(instID, origin)
 	}
@@ -168,10 +227,36 @@ func (m *instanceManager) UpdateModelInstance(ctx core.ContextParams, objID stri
 		blog.Errorf("update module instance validate error :%v ,rid:%s", err, ctx.ReqID)
 		return &metadata.UpdatedCount{}, err
 	}
-	cnt, err := m.update(ctx, objID, inputParam.Data, inputParam.Condition)
-	if err != nil {
-		blog.ErrorJSON("UpdateModelInstance update objID(%s) inst error. err:%s, condition:%s, rid:%s", objID, inputParam.Condition, ctx.ReqID)
-		return nil, err
+	var cnt uint64
+	if len(rejectedFields) == 0 {
+		cnt, err = m.update(ctx, objID, inputParam.Data, inputParam.Condition)
+		if err != nil {
+			blog.ErrorJSON("UpdateModelInstance update objID(%s) inst error. err:%s, condition:%s, rid:%s", objID, inputParam.Condition, ctx.ReqID)
+			return nil, err
+		}
+		instIDs := make([]int64, 0, len(allowedData))
+		for instID := range allowedData {
+			instIDs = append(instIDs, instID)
+		}
+		m.recordFieldSource(ctx, objID, instIDs, inputParam.Data)
+	} else {
+		// update the instances one by one, as each of them may accept different fields
+		for instID, data := range allowedData {
+			if len(data) == 0 {
+				continue
+			}
+			cond := mapstr.MapStr{instIDFieldName: instID, common.BKOwnerIDField: ctx.SupplierAccount}
+			updated, err := m.update(ctx, objID, data, cond)
+			if err != nil {
+				blog.ErrorJSON("UpdateModelInstance update objID(%s) inst error. err:%s, condition:%s, rid:%s", objID, err, cond, ctx.ReqID)
+				return nil, err
+			}
+			cnt += updated
+			m.recordFieldSource(ctx, objID, []int64{instID}, data)
+		}
+	}
+	if err := lifecycle.Record(ctx, m.dbProxy, transitions...); nil != err {
+		blog.Errorf("UpdateModelInstance record the lifecycle transitions failed, err: %v, rid: %s", err, ctx.ReqID)
 	}
 	err = eh.SetCurDataAndPush(ctx, objID, metadata.EventActionUpdate, inputParam.Condition)
 	if err != nil {
@@ -179,7 +264,8 @@ func (m *instanceManager) UpdateModelInstance(ctx core.ContextParams, objID stri
 		return nil, err
 	}
 
-	return &metadata.UpdatedCount{Count: cnt}, nil
+	// the rejected fields are returned to the writer, so that it knows the values are not taken
+	return &metadata.UpdatedCount{Count: cnt, Rejected: rejectedFields}, nil
 }
 
 func (m *instanceManager) SearchModelInstance(ctx core.ContextParams, objID string, inputParam metadata.QueryCondition) (*metadata.QueryResult, error) {
@@ -219,6 +305,11 @@ func (m *instanceManager) DeleteModelInstance(ctx core.ContextParams, objID stri
 		return &metadata.DeletedCount{}, err
 	}
 
+	objLifecycle, err := lifecycle.Find(ctx, m.dbProxy, objID)
+	if nil != err {
+		return &metadata.DeletedCount{}, err
+	}
+
 	// 处理事件数据的
 	eh := m.NewEventClient(objID)
 
@@ -227,6 +318,9 @@ func (m *instanceManager) DeleteModelInstance(ctx core.ContextParams, objID stri
 		if nil != err {
 			return nil, err
 		}
+		if err := lifecycle.CheckOperation(ctx, objLifecycle, origin, metadata.LifecycleOperationDelete); nil != err {
+			return &metadata.DeletedCount{}, err
+		}
 		exists, err := m.dependent.IsInstAsstExist(ctx, objID, uint64(instID))
 		if nil != err {
 			return nil, err
@@ -241,6 +335,7 @@ func (m *instanceManager) DeleteModelInstance(ctx core.ContextParams, objID stri
 		blog.ErrorJSON("DeleteModelInstance delete objID(%s) instance error. err:%s, coniditon:%s, rid:%s", objID, err.Error(), inputParam.Condition, ctx.ReqID)
 		return &metadata.DeletedCount{}, err
 	}
+	m.clearFieldSource(ctx, objID, origins)
 	err = eh.Push(ctx, objID, metadata.EventActionDelete)
 	if err != nil {
 		blog.ErrorJSON("DeleteModelInstance push delete objType(%s) instance to event server error. data:%s, rid:%s", objID, origins, ctx.ReqID)
@@ -260,6 +355,16 @@ func (m *instanceManager) CascadeDeleteModelInstance(ctx core.ContextParams, obj
 		return &metadata.DeletedCount{}, err
 	}
 
+	objLifecycle, err := lifecycle.Find(ctx, m.dbProxy, objID)
+	if nil != err {
+		return &metadata.DeletedCount{}, err
+	}
+	for _, origin := range origins {
+		if err := lifecycle.CheckOperation(ctx, objLifecycle, origin, metadata.LifecycleOperationDelete); nil != err {
+			return &metadata.DeletedCount{}, err
+		}
+	}
+
 	for _, origin := range origins {
 		instID, err := util.GetInt64ByInterface(origin[instIDFieldName])
 		if nil != err {
@@ -275,5 +380,48 @@ func (m *instanceManager) CascadeDeleteModelInstance(ctx core.ContextParams, obj
 	if nil != err {
 		return &metadata.DeletedCount{}, err
 	}
+	m.clearFieldSource(ctx, objID, origins)
 	return &metadata.DeletedCount{Count: uint64(len(origins))}, nil
 }
+
+// recordCreatedState record the initial state of the new instance
+func (m *instanceManager) recordCreatedState(ctx core.ContextParams, objLifecycle *metadata.ObjectLifecycle, data mapstr.MapStr, id uint64) {
+	if objLifecycle == nil {
+		return
+	}
+	history := metadata.LifecycleHistory{
+		ObjectID: objLifecycle.ObjectID,
+		InstID:   int64(id),
+		To:       objLifecycle.CurrentState(data),
+	}
+	if err := lifecycle.Record(ctx, m.dbProxy, history); nil != err {
+		blog.Errorf("record the initial state of %s instance %d failed, err: %v, rid: %s", objLifecycle.ObjectID, id, err, ctx.ReqID)
+	}
+}
+
+// recordFieldSource record the source of the request as the last writer of the instance fields
+func (m *instanceManager) recordFieldSource(ctx core.ContextParams, objID string, instIDs []int64, data mapstr.MapStr) {
+	source := util.GetDataSource(ctx.Header)
+	if err := fieldsource.Record(ctx, m.dbProxy, ctx.SupplierAccount, objID, instIDs, source, ctx.User, data); nil != err {
+		blog.Errorf("record the field source of %s instances %v failed, err: %v, rid: %s", objID, instIDs, err, ctx.ReqID)
+	}
+}
+
+// clearFieldSource remove the field sources of the deleted instances
+func (m *instanceManager) clearFieldSource(ctx core.ContextParams, objID string, origins []mapstr.MapStr) {
+	instIDFieldName := common.GetInstIDField(objID)
+	instIDs := make([]int64, 0, len(origins))
+	for _, origin := range origins {
+		instID, err := util.GetInt64ByInterface(origin[instIDFieldName])
+		if nil != err {
+			continue
+		}
+		instIDs = append(instIDs, instID)
+	}
+	if len(instIDs) == 0 {
+		return
+	}
+	if err := fieldsource.Clear(ctx, m.dbProxy, objID, instIDs); nil != err {
+		blog.Errorf("clear the field sources of %s instances %v failed, err: %v, rid: %s", objID, instIDs, err, ctx.ReqID)
+	}
+}
@@ -36,6 +36,9 @@ func (m *instanceManager) save(ctx core.ContextParams, objID string, inputParam
 	if !util.IsInnerObject(objID) {
 		inputParam[common.BKObjIDField] = objID
 	}
+	if objID == common.BKInnerObjIDHost {
+		util.SetHostIPKeys(inputParam)
+	}
 	ts := time.Now()
 	inputParam.Set(common.BKOwnerIDField, ctx.SupplierAccount)
 	inputParam.Set(common.CreateTimeField, ts)
@@ -53,6 +56,9 @@ func (m *instanceManager) update(ctx core.ContextParams, objID string, data maps
 	if nil != err {
 		return cnt, err
 	}
+	if objID == common.BKInnerObjIDHost {
+		util.SetHostIPKeys(data)
+	}
 	ts := time.Now()
 	data.Set(common.LastTimeField, ts)
 	data.Remove(common.BKObjIDField)
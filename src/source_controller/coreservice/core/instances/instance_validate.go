@@ -80,6 +80,7 @@ func (m *instanceManager) validCreateInstanceData(ctx core.ContextParams, objID
 		blog.Errorf("init validator failed %s, rid: %s", err.Error(), ctx.ReqID)
 		return err
 	}
+	valid.fillDefaultValue(ctx.Context, instanceData)
 	FillLostedFieldValue(ctx.Context, instanceData, valid.propertyslice, valid.requirefields)
 	for _, key := range valid.requirefields {
 		if _, ok := instanceData[key]; !ok {
@@ -87,6 +88,9 @@ func (m *instanceManager) validCreateInstanceData(ctx core.ContextParams, objID
 			return valid.errif.Errorf(common.CCErrCommParamsNeedSet, key)
 		}
 	}
+	if err := valid.validRequiredRules(ctx.Context, instanceData, nil); err != nil {
+		return err
+	}
 	var instMedataData metadata.Metadata
 	instMedataData.Label = make(metadata.Label)
 	for key, val := range instanceData {
@@ -113,32 +117,7 @@ func (m *instanceManager) validCreateInstanceData(ctx core.ContextParams, objID
 			// blog.Errorf("field [%s] is not a valid property for model [%s], rid: %s", key, objID, ctx.ReqID)
 			// return valid.errif.CCErrorf(common.CCErrCommParamsIsInvalid, key)
 		}
-		fieldType := property.PropertyType
-		switch fieldType {
-		case common.FieldTypeSingleChar:
-			err = valid.validChar(ctx.Context, val, key)
-		case common.FieldTypeLongChar:
-			err = valid.validLongChar(ctx.Context, val, key)
-		case common.FieldTypeInt:
-			err = valid.validInt(ctx.Context, val, key)
-		case common.FieldTypeFloat:
-			err = valid.validFloat(ctx.Context, val, key)
-		case common.FieldTypeEnum:
-			err = valid.validEnum(ctx.Context, val, key)
-		case common.FieldTypeDate:
-			err = valid.validDate(ctx.Context, val, key)
-		case common.FieldTypeTime:
-			err = valid.validTime(ctx.Context, val, key)
-		case common.FieldTypeTimeZone:
-			err = valid.validTimeZone(ctx.Context, val, key)
-		case common.FieldTypeBool:
-			err = valid.validBool(ctx.Context, val, key)
-		case common.FieldTypeForeignKey:
-			err = valid.validForeignKey(ctx.Context, val, key)
-		default:
-			continue
-		}
-		if nil != err {
+		if err = valid.validField(ctx.Context, property.PropertyType, val, key); nil != err {
 			return err
 		}
 	}
@@ -152,6 +131,28 @@ func (m *instanceManager) validCreateInstanceData(ctx core.ContextParams, objID
 	return valid.validCreateUnique(ctx, instanceData, instMedataData, m)
 }
 
+// ValidateInstanceUnique check the instance data against the unique rules of the model, it's used when the
+// instance is written again, such as restored from the recycle bin, whose data were valid when it was deleted.
+func (m *instanceManager) ValidateInstanceUnique(ctx core.ContextParams, objID string, instanceData mapstr.MapStr) error {
+	bizID, err := FetchBizIDFromInstance(objID, instanceData)
+	if err != nil {
+		blog.Errorf("ValidateInstanceUnique failed, FetchBizIDFromInstance failed, err: %+v, rid: %s", err, ctx.ReqID)
+		return ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, "bk_biz_id")
+	}
+
+	valid, err := NewValidator(ctx, m.dependent, objID, bizID)
+	if nil != err {
+		blog.Errorf("init validator failed %s, rid: %s", err.Error(), ctx.ReqID)
+		return err
+	}
+
+	instMedataData := metadata.Metadata{Label: make(metadata.Label)}
+	if _, exist := instanceData[metadata.BKMetadata]; exist && bizID != 0 {
+		instMedataData.Label.Set(metadata.LabelBusinessID, strconv.FormatInt(bizID, 10))
+	}
+	return valid.validCreateUnique(ctx, instanceData, instMedataData, m)
+}
+
 func (m *instanceManager) validateModuleCreate(ctx core.ContextParams, instanceData mapstr.MapStr, valid *validator) error {
 	svcTplIDIf, exist := instanceData[common.BKServiceTemplateIDField]
 	if exist == false {
@@ -223,35 +224,18 @@ func (m *instanceManager) validUpdateInstanceData(ctx core.ContextParams, objID
 		if !ok {
 			delete(instanceData, key)
 		}
-		fieldType := property.PropertyType
-		switch fieldType {
-		case common.FieldTypeSingleChar:
-			err = valid.validChar(ctx.Context, val, key)
-		case common.FieldTypeLongChar:
-			err = valid.validLongChar(ctx.Context, val, key)
-		case common.FieldTypeInt:
-			err = valid.validInt(ctx.Context, val, key)
-		case common.FieldTypeFloat:
-			err = valid.validFloat(ctx.Context, val, key)
-		case common.FieldTypeEnum:
-			err = valid.validEnum(ctx.Context, val, key)
-		case common.FieldTypeDate:
-			err = valid.validDate(ctx.Context, val, key)
-		case common.FieldTypeTime:
-			err = valid.validTime(ctx.Context, val, key)
-		case common.FieldTypeTimeZone:
-			err = valid.validTimeZone(ctx.Context, val, key)
-		case common.FieldTypeBool:
-			err = valid.validBool(ctx.Context, val, key)
-		case common.FieldTypeForeignKey:
-			err = valid.validForeignKey(ctx.Context, val, key)
-		default:
-			continue
-		}
-		if nil != err {
+		if err = valid.validField(ctx.Context, property.PropertyType, val, key); nil != err {
 			return err
 		}
 	}
 
+	// the conditional required attributes are checked with the data after updated
+	updatedData := mapstr.New()
+	updatedData.Merge(originData)
+	updatedData.Merge(instanceData)
+	if err := valid.validRequiredRules(ctx.Context, updatedData, instanceData); err != nil {
+		return err
+	}
+
 	return valid.validUpdateUnique(ctx, instanceData, instMetaData, instID, m)
 }
@@ -14,6 +14,7 @@ package instances
 
 import (
 	"configcenter/src/common"
+	"configcenter/src/common/blog"
 	"configcenter/src/common/errors"
 	"configcenter/src/common/metadata"
 	"configcenter/src/source_controller/coreservice/core"
@@ -26,6 +27,7 @@ type validator struct {
 	propertyslice []metadata.Attribute
 	require       map[string]bool
 	requirefields []string
+	requiredRules map[string][]metadata.RequiredRule
 	dependent     OperationDependences
 	objID         string
 }
@@ -38,6 +40,7 @@ func NewValidator(ctx core.ContextParams, dependent OperationDependences, objID
 	valid.propertyslice = make([]metadata.Attribute, 0)
 	valid.require = make(map[string]bool)
 	valid.requirefields = make([]string, 0)
+	valid.requiredRules = make(map[string][]metadata.RequiredRule)
 	valid.errif = ctx.Error
 	result, err := dependent.SelectObjectAttWithParams(ctx, objID, bizID)
 	if nil != err {
@@ -54,6 +57,14 @@ func NewValidator(ctx core.ContextParams, dependent OperationDependences, objID
 			valid.require[attr.PropertyID] = true
 			valid.requirefields = append(valid.requirefields, attr.PropertyID)
 		}
+		rules, err := metadata.ParseRequiredRules(attr.RequiredRules)
+		if err != nil {
+			blog.Warnf("parse required rules of attribute %s failed, err: %v, rid: %s", attr.PropertyID, err, ctx.ReqID)
+			continue
+		}
+		if len(rules) > 0 {
+			valid.requiredRules[attr.PropertyID] = rules
+		}
 	}
 	valid.objID = objID
 	valid.dependent = dependent
@@ -19,9 +19,94 @@ import (
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
 	"configcenter/src/common/util"
 )
 
+// ValidateAttributeValue check the value with the type and the option of the attribute in the same way as
+// the field of the instances, it's used to check the default value of the attribute.
+func ValidateAttributeValue(ctx context.Context, errif errors.DefaultCCErrorIf, attr metadata.Attribute, val interface{}) error {
+	valid := &validator{
+		errif:     errif,
+		propertys: map[string]metadata.Attribute{attr.PropertyID: attr},
+		require:   map[string]bool{attr.PropertyID: attr.IsRequired},
+	}
+	return valid.validField(ctx, attr.PropertyType, val, attr.PropertyID)
+}
+
+// validField valid the field of the instance with the validator of the field type
+func (valid *validator) validField(ctx context.Context, fieldType string, val interface{}, key string) error {
+	switch fieldType {
+	case common.FieldTypeSingleChar:
+		return valid.validChar(ctx, val, key)
+	case common.FieldTypeLongChar:
+		return valid.validLongChar(ctx, val, key)
+	case common.FieldTypeInt:
+		return valid.validInt(ctx, val, key)
+	case common.FieldTypeFloat:
+		return valid.validFloat(ctx, val, key)
+	case common.FieldTypeEnum:
+		return valid.validEnum(ctx, val, key)
+	case common.FieldTypeDate:
+		return valid.validDate(ctx, val, key)
+	case common.FieldTypeTime:
+		return valid.validTime(ctx, val, key)
+	case common.FieldTypeTimeZone:
+		return valid.validTimeZone(ctx, val, key)
+	case common.FieldTypeBool:
+		return valid.validBool(ctx, val, key)
+	case common.FieldTypeForeignKey:
+		return valid.validForeignKey(ctx, val, key)
+	}
+	return nil
+}
+
+// fillDefaultValue set the default value of the attributes which are absent in the instance data
+func (valid *validator) fillDefaultValue(ctx context.Context, valData mapstr.MapStr) {
+	rid := util.ExtractRequestIDFromContext(ctx)
+	for _, attr := range valid.propertyslice {
+		if attr.Default == nil {
+			continue
+		}
+		if _, exist := valData[attr.PropertyID]; exist {
+			continue
+		}
+		if attr.PropertyType == common.FieldTypeInt {
+			val, err := util.GetInt64ByInterface(attr.Default)
+			if err != nil {
+				blog.Warnf("invalid default value %v of attribute %s, rid: %s", attr.Default, attr.PropertyID, rid)
+				continue
+			}
+			valData[attr.PropertyID] = val
+			continue
+		}
+		valData[attr.PropertyID] = attr.Default
+	}
+}
+
+// validRequiredRules check the attributes which are required by the rules, only the rules that relate to
+// the changed fields are checked if changed is not nil, so that the old instances will not be blocked.
+func (valid *validator) validRequiredRules(ctx context.Context, valData mapstr.MapStr, changed mapstr.MapStr) error {
+	rid := util.ExtractRequestIDFromContext(ctx)
+	for key, rules := range valid.requiredRules {
+		if !metadata.IsEmptyAttributeValue(valData[key]) {
+			continue
+		}
+		for _, rule := range rules {
+			if changed != nil && !changed.Exists(key) && !changed.Exists(rule.Field) {
+				continue
+			}
+			if rule.Hit(valData) {
+				blog.Errorf("field [%s] is required when [%s] is %v, rid: %s", key, rule.Field, valData[rule.Field], rid)
+				return valid.errif.Errorf(common.CCErrCommParamsNeedSet, key)
+			}
+		}
+	}
+	return nil
+}
+
 // validTime valid object Attribute that is time type
 func (valid *validator) validTime(ctx context.Context, val interface{}, key string) error {
 	rid := util.ExtractRequestIDFromContext(ctx)
@@ -0,0 +1,61 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package instances
+
+import (
+	"context"
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAttributeValue(t *testing.T) {
+	errif := errors.NewFromCtx(errors.EmptyErrorsSetting).CreateDefaultCCErrorIf("en")
+	intOption := map[string]interface{}{"min": "1", "max": "10"}
+	floatOption := map[string]interface{}{"min": "0.5", "max": "1.5"}
+
+	tests := []struct {
+		name  string
+		attr  metadata.Attribute
+		val   interface{}
+		valid bool
+	}{
+		{"char matches regex", metadata.Attribute{PropertyType: common.FieldTypeSingleChar, Option: "^[a-z]+$"}, "abc", true},
+		{"char mismatches regex", metadata.Attribute{PropertyType: common.FieldTypeSingleChar, Option: "^[a-z]+$"}, "ABC", false},
+		{"longchar mismatches regex", metadata.Attribute{PropertyType: common.FieldTypeLongChar, Option: "^[0-9]+$"}, "abc", false},
+		{"empty char of required field", metadata.Attribute{PropertyType: common.FieldTypeSingleChar, IsRequired: true}, "", false},
+		{"int in range", metadata.Attribute{PropertyType: common.FieldTypeInt, Option: intOption}, float64(5), true},
+		{"int out of range", metadata.Attribute{PropertyType: common.FieldTypeInt, Option: intOption}, float64(11), false},
+		{"float in range", metadata.Attribute{PropertyType: common.FieldTypeFloat, Option: floatOption}, 1.0, true},
+		{"float out of range", metadata.Attribute{PropertyType: common.FieldTypeFloat, Option: floatOption}, 2.0, false},
+		{"valid date", metadata.Attribute{PropertyType: common.FieldTypeDate}, "2019-09-12", true},
+		{"invalid date", metadata.Attribute{PropertyType: common.FieldTypeDate}, "2019/09/12", false},
+		{"valid time", metadata.Attribute{PropertyType: common.FieldTypeTime}, "2019-09-12 10:00:00", true},
+		{"invalid time", metadata.Attribute{PropertyType: common.FieldTypeTime}, "10:00", false},
+		{"valid timezone", metadata.Attribute{PropertyType: common.FieldTypeTimeZone}, "Asia/Shanghai", true},
+		{"invalid timezone", metadata.Attribute{PropertyType: common.FieldTypeTimeZone}, "UTC 8", false},
+		{"user is not checked", metadata.Attribute{PropertyType: common.FieldTypeUser}, "admin", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.attr.PropertyID = "field"
+			err := ValidateAttributeValue(context.Background(), errif, tt.attr, tt.val)
+			require.Equal(t, tt.valid, err == nil, "err: %v", err)
+		})
+	}
+}
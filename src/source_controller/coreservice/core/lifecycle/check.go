@@ -0,0 +1,118 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+import (
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/storage/dal"
+)
+
+// Find returns the lifecycle declared by the model, nil if the model has no lifecycle
+func Find(ctx core.ContextParams, db dal.RDB, objID string) (*metadata.ObjectLifecycle, error) {
+	cond := util.SetQueryOwner(mapstr.MapStr{common.BKObjIDField: objID}, ctx.SupplierAccount)
+	lifecycles := make([]metadata.ObjectLifecycle, 0)
+	if err := db.Table(common.BKTableNameObjLifecycle).Find(cond).Limit(1).All(ctx, &lifecycles); nil != err {
+		blog.Errorf("get the lifecycle of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	if len(lifecycles) == 0 {
+		return nil, nil
+	}
+	return &lifecycles[0], nil
+}
+
+// CheckOperation returns error if the operation is blocked by the current state of the instance
+func CheckOperation(ctx core.ContextParams, lifecycle *metadata.ObjectLifecycle, data mapstr.MapStr, operation string) error {
+	if lifecycle == nil {
+		return nil
+	}
+	state := lifecycle.CurrentState(data)
+	if lifecycle.IsBlocked(state, operation) {
+		blog.Errorf("the %s of %s instance is blocked by the state %s, rid: %s", operation, lifecycle.ObjectID, state, ctx.ReqID)
+		return ctx.Error.Errorf(common.CCErrCoreServiceLifecycleOperationBlocked, state, operation)
+	}
+	return nil
+}
+
+// CheckCreate set the initial state to the new instance if it has no state, and check the fields required by the state
+func CheckCreate(ctx core.ContextParams, lifecycle *metadata.ObjectLifecycle, data mapstr.MapStr) error {
+	if lifecycle == nil {
+		return nil
+	}
+	if metadata.IsEmptyAttributeValue(data[lifecycle.PropertyID]) {
+		data[lifecycle.PropertyID] = lifecycle.InitialState
+	}
+	return checkState(ctx, lifecycle, lifecycle.CurrentState(data), data)
+}
+
+// CheckUpdate check the update of the instance, returns the new state if the update changes the state
+func CheckUpdate(ctx core.ContextParams, lifecycle *metadata.ObjectLifecycle, origin, data mapstr.MapStr) (string, bool, error) {
+	if lifecycle == nil {
+		return "", false, nil
+	}
+	from := lifecycle.CurrentState(origin)
+	updated := mapstr.New()
+	updated.Merge(origin)
+	updated.Merge(data)
+	to := lifecycle.CurrentState(updated)
+	if from == to {
+		return "", false, CheckOperation(ctx, lifecycle, origin, metadata.LifecycleOperationUpdate)
+	}
+
+	if !lifecycle.CanTransit(from, to) {
+		blog.Errorf("the state of %s instance can not be changed from %s to %s, rid: %s", lifecycle.ObjectID, from, to, ctx.ReqID)
+		return "", false, ctx.Error.Errorf(common.CCErrCoreServiceLifecycleTransitionNotAllowed, from, to)
+	}
+	if err := checkState(ctx, lifecycle, to, updated); nil != err {
+		return "", false, err
+	}
+	return to, true, nil
+}
+
+func checkState(ctx core.ContextParams, lifecycle *metadata.ObjectLifecycle, state string, data mapstr.MapStr) error {
+	if _, ok := lifecycle.State(state); !ok {
+		return ctx.Error.Errorf(common.CCErrCoreServiceLifecycleStateInvalid, state)
+	}
+	if missing := lifecycle.MissingFields(state, data); len(missing) > 0 {
+		blog.Errorf("the fields %v are required by the state %s of %s instance, rid: %s", missing, state, lifecycle.ObjectID, ctx.ReqID)
+		return ctx.Error.Errorf(common.CCErrCommParamsNeedSet, strings.Join(missing, ","))
+	}
+	return nil
+}
+
+// Record save the state transitions of the instances
+func Record(ctx core.ContextParams, db dal.RDB, history ...metadata.LifecycleHistory) error {
+	for _, item := range history {
+		id, err := db.NextSequence(ctx, common.BKTableNameLifecycleHistory)
+		if nil != err {
+			blog.Errorf("record the lifecycle history failed, get sequence failed, err: %v, rid: %s", err, ctx.ReqID)
+			return ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+		}
+		item.ID = int64(id)
+		item.Operator = ctx.User
+		item.OwnerID = ctx.SupplierAccount
+		item.CreateTime = metadata.Now()
+		if err := db.Table(common.BKTableNameLifecycleHistory).Insert(ctx, item); nil != err {
+			blog.Errorf("record the lifecycle history of %s instance %d failed, err: %v, rid: %s", item.ObjectID, item.InstID, err, ctx.ReqID)
+			return ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/source_controller/coreservice/core"
+
+	"github.com/stretchr/testify/require"
+)
+
+var defaultCtx = core.ContextParams{
+	Context:         context.Background(),
+	ReqID:           "test_req_id",
+	SupplierAccount: "test_owner",
+	User:            "test_user",
+	Error:           errors.NewFromCtx(errors.EmptyErrorsSetting).CreateDefaultCCErrorIf("en"),
+}
+
+var assetLifecycle = &metadata.ObjectLifecycle{
+	ObjectID:     "asset",
+	PropertyID:   "asset_state",
+	InitialState: "in_stock",
+	States: []metadata.LifecycleState{
+		{ID: "in_stock"},
+		{ID: "racked", RequiredFields: []string{"rack"}},
+		{ID: "in_service"},
+		{ID: "retired", BlockedOperations: []string{metadata.LifecycleOperationUpdate, metadata.LifecycleOperationDelete}},
+	},
+	Transitions: []metadata.LifecycleTransition{
+		{From: "in_stock", To: "racked"},
+		{From: "racked", To: "in_service"},
+		{From: "in_service", To: "retired"},
+	},
+}
+
+func requireErrCode(t *testing.T, code int, err error) {
+	if code == 0 {
+		require.NoError(t, err)
+		return
+	}
+	require.Error(t, err)
+	coder, ok := err.(errors.CCErrorCoder)
+	require.True(t, ok, "err %v is not a CCErrorCoder", err)
+	require.Equal(t, code, coder.GetCode())
+}
+
+func TestCheckCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		lifecycle *metadata.ObjectLifecycle
+		data      mapstr.MapStr
+		wantState interface{}
+		wantCode  int
+	}{
+		{"without lifecycle", nil, mapstr.MapStr{"name": "a"}, nil, 0},
+		{"initial state", assetLifecycle, mapstr.MapStr{"name": "a"}, "in_stock", 0},
+		{"empty state", assetLifecycle, mapstr.MapStr{"asset_state": ""}, "in_stock", 0},
+		{"declared state", assetLifecycle, mapstr.MapStr{"asset_state": "racked", "rack": "r1"}, "racked", 0},
+		{"missing required field", assetLifecycle, mapstr.MapStr{"asset_state": "racked"}, "racked", common.CCErrCommParamsNeedSet},
+		{"undeclared state", assetLifecycle, mapstr.MapStr{"asset_state": "lost"}, "lost", common.CCErrCoreServiceLifecycleStateInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckCreate(defaultCtx, tt.lifecycle, tt.data)
+			requireErrCode(t, tt.wantCode, err)
+			require.Equal(t, tt.wantState, tt.data["asset_state"])
+		})
+	}
+}
+
+func TestCheckUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		lifecycle   *metadata.ObjectLifecycle
+		origin      mapstr.MapStr
+		data        mapstr.MapStr
+		wantState   string
+		wantChanged bool
+		wantCode    int
+	}{
+		{"without lifecycle", nil, mapstr.MapStr{"asset_state": "retired"}, mapstr.MapStr{"name": "a"}, "", false, 0},
+		{"same state", assetLifecycle, mapstr.MapStr{"asset_state": "in_stock"}, mapstr.MapStr{"name": "a"}, "", false, 0},
+		{"update blocked", assetLifecycle, mapstr.MapStr{"asset_state": "retired"}, mapstr.MapStr{"name": "a"}, "", false, common.CCErrCoreServiceLifecycleOperationBlocked},
+		{"declared transition", assetLifecycle, mapstr.MapStr{"asset_state": "in_stock"}, mapstr.MapStr{"asset_state": "racked", "rack": "r1"}, "racked", true, 0},
+		{"required field kept by origin", assetLifecycle, mapstr.MapStr{"asset_state": "in_stock", "rack": "r1"}, mapstr.MapStr{"asset_state": "racked"}, "racked", true, 0},
+		{"missing required field", assetLifecycle, mapstr.MapStr{"asset_state": "in_stock"}, mapstr.MapStr{"asset_state": "racked"}, "", false, common.CCErrCommParamsNeedSet},
+		{"undeclared transition", assetLifecycle, mapstr.MapStr{"asset_state": "in_stock"}, mapstr.MapStr{"asset_state": "retired"}, "", false, common.CCErrCoreServiceLifecycleTransitionNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, changed, err := CheckUpdate(defaultCtx, tt.lifecycle, tt.origin, tt.data)
+			requireErrCode(t, tt.wantCode, err)
+			require.Equal(t, tt.wantState, state)
+			require.Equal(t, tt.wantChanged, changed)
+		})
+	}
+}
+
+func TestCheckOperation(t *testing.T) {
+	tests := []struct {
+		name      string
+		lifecycle *metadata.ObjectLifecycle
+		data      mapstr.MapStr
+		operation string
+		wantCode  int
+	}{
+		{"without lifecycle", nil, mapstr.MapStr{"asset_state": "retired"}, metadata.LifecycleOperationDelete, 0},
+		{"delete allowed", assetLifecycle, mapstr.MapStr{"asset_state": "in_service"}, metadata.LifecycleOperationDelete, 0},
+		{"delete blocked", assetLifecycle, mapstr.MapStr{"asset_state": "retired"}, metadata.LifecycleOperationDelete, common.CCErrCoreServiceLifecycleOperationBlocked},
+		{"transfer allowed", assetLifecycle, mapstr.MapStr{"asset_state": "retired"}, metadata.LifecycleOperationTransfer, 0},
+		{"initial state when unset", assetLifecycle, mapstr.MapStr{}, metadata.LifecycleOperationDelete, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requireErrCode(t, tt.wantCode, CheckOperation(defaultCtx, tt.lifecycle, tt.data, tt.operation))
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/storage/dal"
+)
+
+type lifecycleManager struct {
+	dbProxy dal.RDB
+}
+
+// New create a new lifecycle manager instance
+func New(dbProxy dal.RDB) core.LifecycleOperation {
+	return &lifecycleManager{
+		dbProxy: dbProxy,
+	}
+}
+
+// SetObjectLifecycle declare or replace the lifecycle of the model
+func (m *lifecycleManager) SetObjectLifecycle(ctx core.ContextParams, objID string, lifecycle metadata.ObjectLifecycle) error {
+	lifecycle.ObjectID = objID
+	if err := lifecycle.Validate(); nil != err {
+		blog.Errorf("set the lifecycle of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return ctx.Error.New(common.CCErrCommParamsInvalid, err.Error())
+	}
+
+	cond := util.SetQueryOwner(mapstr.MapStr{common.BKObjIDField: objID}, ctx.SupplierAccount)
+	cnt, err := m.dbProxy.Table(common.BKTableNameObjDes).Find(cond).Count(ctx)
+	if nil != err {
+		blog.Errorf("set the lifecycle of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	if cnt == 0 {
+		return ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, common.BKObjIDField)
+	}
+
+	attrs := make([]metadata.Attribute, 0)
+	if err := m.dbProxy.Table(common.BKTableNameObjAttDes).Find(cond).Fields(common.BKPropertyIDField).All(ctx, &attrs); nil != err {
+		blog.Errorf("set the lifecycle of the model %s failed, get attributes failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	propertyIDs := make(map[string]bool)
+	for _, attr := range attrs {
+		propertyIDs[attr.PropertyID] = true
+	}
+	if !propertyIDs[lifecycle.PropertyID] {
+		return ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, lifecycle.PropertyID)
+	}
+	for _, state := range lifecycle.States {
+		for _, field := range state.RequiredFields {
+			if !propertyIDs[field] {
+				return ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, field)
+			}
+		}
+	}
+
+	lifecycle.OwnerID = ctx.SupplierAccount
+	lifecycle.Modifier = ctx.User
+	lifecycle.LastTime = metadata.Now()
+	if err := m.dbProxy.Table(common.BKTableNameObjLifecycle).Upsert(ctx, cond, lifecycle); nil != err {
+		blog.Errorf("set the lifecycle of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+	}
+	return nil
+}
+
+func (m *lifecycleManager) GetObjectLifecycle(ctx core.ContextParams, objID string) (*metadata.ObjectLifecycle, error) {
+	return Find(ctx, m.dbProxy, objID)
+}
+
+func (m *lifecycleManager) DeleteObjectLifecycle(ctx core.ContextParams, objID string) error {
+	cond := util.SetModOwner(mapstr.MapStr{common.BKObjIDField: objID}, ctx.SupplierAccount)
+	if err := m.dbProxy.Table(common.BKTableNameObjLifecycle).Delete(ctx, cond); nil != err {
+		blog.Errorf("delete the lifecycle of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBDeleteFailed)
+	}
+	return nil
+}
+
+func (m *lifecycleManager) SearchLifecycleHistory(ctx core.ContextParams, objID string, inputParam metadata.QueryCondition) (*metadata.LifecycleHistoryList, error) {
+	cond := inputParam.Condition
+	if cond == nil {
+		cond = mapstr.New()
+	}
+	cond[common.BKObjIDField] = objID
+	cond = util.SetQueryOwner(cond, ctx.SupplierAccount)
+	count, err := m.dbProxy.Table(common.BKTableNameLifecycleHistory).Find(cond).Count(ctx)
+	if nil != err {
+		blog.Errorf("search the lifecycle history of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+
+	history := make([]metadata.LifecycleHistory, 0)
+	finder := m.dbProxy.Table(common.BKTableNameLifecycleHistory).Find(cond)
+	if len(inputParam.SortArr) == 0 {
+		finder = finder.Sort("-" + common.BKFieldID)
+	}
+	for _, sort := range inputParam.SortArr {
+		field := sort.Field
+		if sort.IsDsc {
+			field = "-" + field
+		}
+		finder = finder.Sort(field)
+	}
+	err = finder.Start(uint64(inputParam.Limit.Offset)).Limit(uint64(inputParam.Limit.Limit)).Fields(inputParam.Fields...).All(ctx, &history)
+	if nil != err {
+		blog.Errorf("search the lifecycle history of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	return &metadata.LifecycleHistoryList{Count: int64(count), Info: history}, nil
+}
@@ -89,6 +89,7 @@ func (m *modelAttribute) CreateModelAttributes(ctx core.ContextParams, objID str
 		})
 	}
 
+	m.model.saveSchemaVersion(ctx, objID)
 	return dataResult, nil
 }
 
@@ -152,6 +153,7 @@ func (m *modelAttribute) SetModelAttributes(ctx core.ContextParams, objID string
 
 	}
 
+	m.model.saveSchemaVersion(ctx, objID)
 	return dataResult, nil
 }
 func (m *modelAttribute) UpdateModelAttributes(ctx core.ContextParams, objID string, inputParam metadata.UpdateOption) (*metadata.UpdatedCount, error) {
@@ -173,6 +175,7 @@ func (m *modelAttribute) UpdateModelAttributes(ctx core.ContextParams, objID str
 		return &metadata.UpdatedCount{}, err
 	}
 
+	m.model.saveSchemaVersion(ctx, objID)
 	return &metadata.UpdatedCount{Count: cnt}, nil
 }
 
@@ -184,12 +187,14 @@ func (m *modelAttribute) UpdateModelAttributesByCondition(ctx core.ContextParams
 		return &metadata.UpdatedCount{}, err
 	}
 
+	objIDs := m.model.objectIDsByCondition(ctx, common.BKTableNameObjAttDes, cond.ToMapStr())
 	cnt, err := m.update(ctx, inputParam.Data, cond)
 	if nil != err {
 		blog.Errorf("UpdateModelAttributesByCondition failed, failed to update fields (%#v) by condition(%#v), err: %s, rid: %s", inputParam.Data, cond.ToMapStr(), err.Error(), ctx.ReqID)
 		return &metadata.UpdatedCount{}, err
 	}
 
+	m.model.saveSchemaVersion(ctx, objIDs...)
 	return &metadata.UpdatedCount{Count: cnt}, nil
 }
 
@@ -208,6 +213,9 @@ func (m *modelAttribute) DeleteModelAttributes(ctx core.ContextParams, objID str
 
 	cond.Element(&mongo.Eq{Key: metadata.AttributeFieldSupplierAccount, Val: ctx.SupplierAccount})
 	cnt, err := m.delete(ctx, cond)
+	if nil == err {
+		m.model.saveSchemaVersion(ctx, objID)
+	}
 	return &metadata.DeletedCount{Count: cnt}, err
 }
 
@@ -264,3 +272,20 @@ func (m *modelAttribute) SearchModelAttributesByCondition(ctx core.ContextParams
 	dataResult.Info = attrResult
 	return dataResult, nil
 }
+
+// ValidateModelAttribute check the attribute with the same rules as the attributes created or updated by
+// the model operations, the creating attribute is also checked whether it could be added to the model.
+func (m *modelAttribute) ValidateModelAttribute(ctx core.ContextParams, attribute metadata.Attribute, create bool) error {
+	if create {
+		if err := m.checkAddField(ctx, attribute); err != nil {
+			return err
+		}
+	}
+	if err := m.checkAttributeMustNotEmpty(ctx, attribute); err != nil {
+		return err
+	}
+	if err := m.checkAttributeValidity(ctx, attribute); err != nil {
+		return err
+	}
+	return m.checkAttributeDefault(ctx, attribute)
+}
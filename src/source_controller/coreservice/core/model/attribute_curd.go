@@ -25,6 +25,7 @@ import (
 	"configcenter/src/common/universalsql/mongo"
 	"configcenter/src/common/util"
 	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/source_controller/coreservice/core/instances"
 )
 
 var (
@@ -165,6 +166,35 @@ func (m *modelAttribute) checkAttributeValidity(ctx core.ContextParams, attribut
 	return nil
 }
 
+// checkAttributeDefault check the default value and the required rules of the attribute, the default value
+// must pass the option of the attribute too, for it's filled to the instances before they are validated.
+func (m *modelAttribute) checkAttributeDefault(ctx core.ContextParams, attribute metadata.Attribute) error {
+	if err := attribute.ValidateDefault(); err != nil {
+		blog.Errorf("invalid default value of attribute %s, err: %v, rid: %s", attribute.PropertyID, err, ctx.ReqID)
+		return ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, metadata.AttributeFieldDefault)
+	}
+	if attribute.Default != nil {
+		if err := instances.ValidateAttributeValue(ctx.Context, ctx.Error, attribute, attribute.Default); err != nil {
+			blog.Errorf("default value %v of attribute %s does not match the option %v, err: %v, rid: %s",
+				attribute.Default, attribute.PropertyID, attribute.Option, err, ctx.ReqID)
+			return ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, metadata.AttributeFieldDefault)
+		}
+	}
+
+	rules, err := metadata.ParseRequiredRules(attribute.RequiredRules)
+	if err != nil {
+		blog.Errorf("invalid required rules of attribute %s, err: %v, rid: %s", attribute.PropertyID, err, ctx.ReqID)
+		return ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, metadata.AttributeFieldRequiredRules)
+	}
+	for _, rule := range rules {
+		if rule.Field == attribute.PropertyID {
+			blog.Errorf("required rule of attribute %s depends on itself, rid: %s", attribute.PropertyID, ctx.ReqID)
+			return ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, metadata.AttributeFieldRequiredRules)
+		}
+	}
+	return nil
+}
+
 func (m *modelAttribute) update(ctx core.ContextParams, data mapstr.MapStr, cond universalsql.Condition) (cnt uint64, err error) {
 	cnt, err = m.checkUpdate(ctx, data, cond)
 	if err != nil {
@@ -248,6 +278,9 @@ func (m *modelAttribute) saveCheck(ctx core.ContextParams, attribute metadata.At
 	if err := m.checkAttributeValidity(ctx, attribute); err != nil {
 		return err
 	}
+	if err := m.checkAttributeDefault(ctx, attribute); err != nil {
+		return err
+	}
 
 	// check name duplicate
 	if err := m.checkUnique(ctx, true, attribute.ObjectID, attribute.PropertyID, attribute.PropertyName); err != nil {
@@ -280,12 +313,14 @@ func (m *modelAttribute) checkUpdate(ctx core.ContextParams, data mapstr.MapStr,
 		}
 	}
 
-	// 预定义字段，只能更新分组和分组内排序
+	// 预定义字段，只能更新分组和分组内排序，以及默认值和条件必填规则
 	if hasIsPreProperty {
 		hasNotAllowField := false
 		data.ForEach(func(key string, val interface{}) error {
 			if key != metadata.AttributeFieldPropertyGroup &&
-				key != metadata.AttributeFieldPropertyIndex {
+				key != metadata.AttributeFieldPropertyIndex &&
+				key != metadata.AttributeFieldDefault &&
+				key != metadata.AttributeFieldRequiredRules {
 				hasNotAllowField = true
 			}
 			return nil
@@ -324,6 +359,24 @@ func (m *modelAttribute) checkUpdate(ctx core.ContextParams, data mapstr.MapStr,
 		if err = m.checkChangeField(ctx, dbAttribute.ObjectID, data); err != nil {
 			return changeRow, err
 		}
+
+		// the default value is checked with the type and the option of the attribute after updated
+		merged := dbAttribute
+		if data.Exists(metadata.AttributeFieldOption) {
+			merged.Option = attribute.Option
+		}
+		if data.Exists(metadata.AttributeFieldDefault) {
+			merged.Default = attribute.Default
+		}
+		if data.Exists(metadata.AttributeFieldRequiredRules) {
+			merged.RequiredRules = attribute.RequiredRules
+		}
+		if data.Exists(metadata.AttributeFieldIsRequired) {
+			merged.IsRequired = attribute.IsRequired
+		}
+		if err = m.checkAttributeDefault(ctx, merged); err != nil {
+			return changeRow, err
+		}
 	}
 
 	return uint64(len(dbAttributeArr)), err
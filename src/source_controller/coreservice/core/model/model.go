@@ -42,7 +42,7 @@ func New(dbProxy dal.RDB, dependent OperationDependences) core.ModelOperation {
 	coreMgr.modelAttribute = &modelAttribute{dbProxy: dbProxy, model: coreMgr}
 	coreMgr.modelClassification = &modelClassification{dbProxy: dbProxy, model: coreMgr}
 	coreMgr.modelAttributeGroup = &modelAttributeGroup{dbProxy: dbProxy, model: coreMgr}
-	coreMgr.modelAttrUnique = &modelAttrUnique{dbProxy: dbProxy}
+	coreMgr.modelAttrUnique = &modelAttrUnique{dbProxy: dbProxy, model: coreMgr}
 
 	return coreMgr
 }
@@ -215,7 +215,11 @@ func (m *modelManager) UpdateModel(ctx core.ContextParams, inputParam metadata.U
 	}
 	updateCond.Element(&mongo.Eq{Key: metadata.ModelFieldOwnerID, Val: ctx.SupplierAccount})
 
+	objIDs := m.objectIDsByCondition(ctx, common.BKTableNameObjDes, updateCond.ToMapStr())
 	cnt, err := m.update(ctx, inputParam.Data, updateCond)
+	if nil == err {
+		m.saveSchemaVersion(ctx, objIDs...)
+	}
 	return &metadata.UpdatedCount{Count: cnt}, err
 }
 
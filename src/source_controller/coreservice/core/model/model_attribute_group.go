@@ -66,6 +66,7 @@ func (g *modelAttributeGroup) CreateModelAttributeGroup(ctx core.ContextParams,
 		return dataResult, err
 	}
 	dataResult.Created.ID = id
+	g.model.saveSchemaVersion(ctx, objID)
 	return dataResult, err
 }
 
@@ -113,6 +114,7 @@ func (g *modelAttributeGroup) SetModelAttributeGroup(ctx core.ContextParams, obj
 				ID: id,
 			}}
 
+		g.model.saveSchemaVersion(ctx, objID)
 		return dataResult, nil
 	}
 
@@ -132,6 +134,7 @@ func (g *modelAttributeGroup) SetModelAttributeGroup(ctx core.ContextParams, obj
 			ID: uint64(existsGroup.ID),
 		},
 	}
+	g.model.saveSchemaVersion(ctx, objID)
 	return dataResult, nil
 }
 
@@ -160,6 +163,7 @@ func (g *modelAttributeGroup) UpdateModelAttributeGroup(ctx core.ContextParams,
 		return &metadata.UpdatedCount{}, err
 	}
 
+	g.model.saveSchemaVersion(ctx, objID)
 	return &metadata.UpdatedCount{Count: cnt}, nil
 }
 
@@ -176,12 +180,14 @@ func (g *modelAttributeGroup) UpdateModelAttributeGroupByCondition(ctx core.Cont
 	inputParam.Data.Remove(metadata.GroupFieldSupplierAccount)
 	inputParam.Data.Remove(metadata.GroupFieldIsPre)
 
+	objIDs := g.model.objectIDsByCondition(ctx, common.BKTableNamePropertyGroup, cond.ToMapStr())
 	cnt, err := g.update(ctx, inputParam.Data, cond)
 	if nil != err {
 		blog.Errorf("request(%s): it is failed to update the data (%s) by the condition (%#v), error info is %s", ctx.ReqID, inputParam.Data, err.Error())
 		return &metadata.UpdatedCount{}, err
 	}
 
+	g.model.saveSchemaVersion(ctx, objIDs...)
 	return &metadata.UpdatedCount{Count: cnt}, nil
 }
 
@@ -263,8 +269,10 @@ func (g *modelAttributeGroup) DeleteModelAttributeGroupByCondition(ctx core.Cont
 	}
 
 	grpIDS := []string{}
+	objIDs := []string{}
 	for _, grp := range grps {
 		grpIDS = append(grpIDS, grp.GroupID)
+		objIDs = append(objIDs, grp.ObjectID)
 	}
 
 	cnt, err := g.delete(ctx, cond)
@@ -273,6 +281,7 @@ func (g *modelAttributeGroup) DeleteModelAttributeGroupByCondition(ctx core.Cont
 		return &metadata.DeletedCount{}, err
 	}
 
+	g.model.saveSchemaVersion(ctx, objIDs...)
 	return &metadata.DeletedCount{Count: cnt}, nil
 }
 
@@ -317,5 +326,6 @@ func (g *modelAttributeGroup) DeleteModelAttributeGroup(ctx core.ContextParams,
 		return &metadata.DeletedCount{}, err
 	}
 
+	g.model.saveSchemaVersion(ctx, objID)
 	return &metadata.DeletedCount{Count: cnt}, nil
 }
@@ -23,6 +23,7 @@ import (
 	"configcenter/src/common/universalsql"
 	"configcenter/src/common/universalsql/mongo"
 	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/source_controller/coreservice/core/recyclebin"
 )
 
 func (m *modelManager) count(ctx core.ContextParams, cond universalsql.Condition) (uint64, error) {
@@ -186,6 +187,29 @@ func (m *modelManager) cascadeDelete(ctx core.ContextParams, cond universalsql.C
 		return 0, err
 	}
 
+	// keep the schema of the models in the recycle bin, so that they can be restored
+	recycleItems := make([]metadata.RecycleItem, 0, len(modelItems))
+	for _, modelItem := range modelItems {
+		schema, err := m.loadModelSchema(ctx, modelItem.ObjectID)
+		if nil != err {
+			return 0, err
+		}
+		if schema == nil {
+			continue
+		}
+		recycleItems = append(recycleItems, metadata.RecycleItem{
+			Kind:     metadata.RecycleKindModel,
+			ObjectID: modelItem.ObjectID,
+			InstID:   modelItem.ID,
+			InstName: modelItem.ObjectName,
+			Schema:   &schema.ModelSchema,
+		})
+	}
+	recycleIDs, err := recyclebin.Save(ctx, m.dbProxy, recycleItems...)
+	if nil != err {
+		return 0, err
+	}
+
 	delCond := mongo.NewCondition()
 	delCond.Element(mongo.Field(common.BKObjIDField).In(targetObjIDS))
 	delCondMap := util.SetQueryOwner(delCond.ToMapStr(), ctx.SupplierAccount)
@@ -193,21 +217,31 @@ func (m *modelManager) cascadeDelete(ctx core.ContextParams, cond universalsql.C
 	// delete model property group
 	if err := m.dbProxy.Table(common.BKTableNamePropertyGroup).Delete(ctx, delCondMap); err != nil {
 		blog.ErrorJSON("delete mdoel attribute group error. err:%s, cond:%s, rid:%s", err.Error(), delCondMap, ctx.ReqID)
+		m.purgeRecycleItems(ctx, recycleIDs)
 		return 0, ctx.Error.Error(common.CCErrCommDBSelectFailed)
 	}
 	// delete model property attribute
 	if err := m.dbProxy.Table(common.BKTableNameObjAttDes).Delete(ctx, delCondMap); err != nil {
 		blog.ErrorJSON("delete mdoel attribute error. err:%s, cond:%s, rid:%s", err.Error(), delCondMap, ctx.ReqID)
+		m.purgeRecycleItems(ctx, recycleIDs)
 		return 0, ctx.Error.Error(common.CCErrCommDBSelectFailed)
 	}
 	// delete model unique
 	if err := m.dbProxy.Table(common.BKTableNameObjUnique).Delete(ctx, delCondMap); err != nil {
 		blog.ErrorJSON("delete mdoel unique error. err:%s, cond:%s, rid:%s", err.Error(), delCondMap, ctx.ReqID)
+		m.purgeRecycleItems(ctx, recycleIDs)
 		return 0, ctx.Error.Error(common.CCErrCommDBSelectFailed)
 	}
+	// delete model lifecycle
+	if err := m.dbProxy.Table(common.BKTableNameObjLifecycle).Delete(ctx, delCondMap); err != nil {
+		blog.ErrorJSON("delete model lifecycle error. err:%s, cond:%s, rid:%s", err.Error(), delCondMap, ctx.ReqID)
+		m.purgeRecycleItems(ctx, recycleIDs)
+		return 0, ctx.Error.Error(common.CCErrCommDBDeleteFailed)
+	}
 	// delete model
 	if err := m.dbProxy.Table(common.BKTableNameObjDes).Delete(ctx, delCondMap); err != nil {
 		blog.ErrorJSON("delete mdoel unique error. err:%s, cond:%s, rid:%s", err.Error(), delCondMap, ctx.ReqID)
+		m.purgeRecycleItems(ctx, recycleIDs)
 		return 0, ctx.Error.Error(common.CCErrCommDBSelectFailed)
 	}
 
@@ -215,6 +249,17 @@ func (m *modelManager) cascadeDelete(ctx core.ContextParams, cond universalsql.C
 
 }
 
+// purgeRecycleItems remove the recycle items of the models which failed to delete
+func (m *modelManager) purgeRecycleItems(ctx core.ContextParams, ids []int64) {
+	if len(ids) == 0 {
+		return
+	}
+	cond := mapstr.MapStr{common.BKFieldID: mapstr.MapStr{common.BKDBIN: ids}}
+	if err := m.dbProxy.Table(common.BKTableNameRecycleBin).Delete(ctx, cond); err != nil {
+		blog.ErrorJSON("purge the recycle items of the models error. err:%s, ids:%s, rid:%s", err.Error(), ids, ctx.ReqID)
+	}
+}
+
 // canCascadeDelete 判断是否可以删除, 判断模型是否可以删除，是否包含实例，是否有关联关系
 func (m *modelManager) canCascadeDelete(ctx core.ContextParams, targetObjIDS []string) (err error) {
 	// notice inner model not can delete
@@ -0,0 +1,456 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/storage/dal"
+)
+
+// schemaVersionMaxRetry the max times to take a new version number when the one taken is used by another change
+const schemaVersionMaxRetry = 3
+
+// schemaIdentityFields the fields identifying the schema item, which are never rolled back
+var schemaIdentityFields = map[string]bool{
+	common.BKFieldID:         true,
+	common.BKObjIDField:      true,
+	common.BKOwnerIDField:    true,
+	common.BKPropertyIDField: true,
+	metadata.BKMetadata:      true,
+	"ispre":                  true,
+	"bk_group_id":            true,
+}
+
+func (m *modelManager) SearchModelSchemaVersion(ctx core.ContextParams, objID string, inputParam metadata.QueryCondition) (*metadata.QueryModelSchemaVersionResult, error) {
+
+	cond := util.SetQueryOwner(inputParam.Condition.ToMapInterface(), ctx.SupplierAccount)
+	cond[common.BKObjIDField] = objID
+
+	cnt, err := m.dbProxy.Table(common.BKTableNameObjSchemaVersion).Find(cond).Count(ctx)
+	if nil != err {
+		blog.Errorf("count the schema versions of the model(%s) failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+	}
+
+	versions := make([]metadata.ModelSchemaVersion, 0)
+	err = m.dbProxy.Table(common.BKTableNameObjSchemaVersion).Find(cond).Fields(inputParam.Fields...).
+		Sort("-version").Start(uint64(inputParam.Limit.Offset)).Limit(uint64(inputParam.Limit.Limit)).All(ctx, &versions)
+	if nil != err {
+		blog.Errorf("search the schema versions of the model(%s) failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+	}
+
+	return &metadata.QueryModelSchemaVersionResult{Count: cnt, Info: versions}, nil
+}
+
+func (m *modelManager) GetModelSchemaVersion(ctx core.ContextParams, objID string, version int64) (*metadata.ModelSchemaVersion, error) {
+
+	cond := util.SetQueryOwner(mapstr.MapStr{common.BKObjIDField: objID, "version": version}, ctx.SupplierAccount)
+	result := new(metadata.ModelSchemaVersion)
+	if err := m.dbProxy.Table(common.BKTableNameObjSchemaVersion).Find(cond).One(ctx, result); nil != err {
+		if m.dbProxy.IsNotFoundError(err) {
+			return nil, ctx.Error.CCErrorf(common.CCErrCommNotFound)
+		}
+		blog.Errorf("get the schema version(%d) of the model(%s) failed, err: %v, rid: %s", version, objID, err, ctx.ReqID)
+		return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+	}
+	return result, nil
+}
+
+// RollbackModelSchema restore the model schema to the version, the removed attributes, groups and uniques are
+// created again and the changed ones are restored, while the ones added after the version are kept because
+// the instances may have data on them. A new version is recorded for the rollback. The applied steps are
+// undone if any of the following ones fails, so that the model is never left half rolled back.
+func (m *modelManager) RollbackModelSchema(ctx core.ContextParams, objID string, version int64) (*metadata.ModelSchemaVersion, error) {
+
+	target, err := m.GetModelSchemaVersion(ctx, objID, version)
+	if nil != err {
+		return nil, err
+	}
+
+	current, err := m.loadModelSchema(ctx, objID)
+	if nil != err {
+		return nil, err
+	}
+	if current == nil {
+		blog.Errorf("rollback the model(%s) failed, the model is not exist, rid: %s", objID, ctx.ReqID)
+		return nil, ctx.Error.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKObjIDField)
+	}
+
+	undo := &schemaUndoLog{db: m.dbProxy}
+	result, err := m.rollbackModelSchema(ctx, objID, current, target, undo)
+	if nil != err {
+		undo.run(ctx, objID)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (m *modelManager) rollbackModelSchema(ctx core.ContextParams, objID string, current *modelSchema,
+	target *metadata.ModelSchemaVersion, undo *schemaUndoLog) (*metadata.ModelSchemaVersion, error) {
+
+	diff := metadata.DiffModelSchema(current.ModelSchema, target.Schema)
+
+	if len(diff.Object) > 0 {
+		data := schemaRollbackData(target.Schema.Object, diff.Object)
+		data.Set(metadata.ModelFieldLastTime, metadata.Now())
+		filter := mapstr.MapStr{common.BKFieldID: current.Object.ID}
+		if err := m.dbProxy.Table(common.BKTableNameObjDes).Update(ctx, filter, data); nil != err {
+			blog.Errorf("rollback the model(%s) failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+			return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+		}
+		undo.update(ctx, common.BKTableNameObjDes, filter, schemaRollbackData(current.Object, diff.Object))
+	}
+
+	// the groups should be restored before the attributes which belong to them
+	targetGroups := make(map[string]metadata.Group, len(target.Schema.Groups))
+	for _, grp := range target.Schema.Groups {
+		targetGroups[target.Schema.GroupKey(grp)] = grp
+	}
+	for _, grp := range diff.RemovedGroups {
+		grp.OwnerID = ctx.SupplierAccount
+		if err := m.dbProxy.Table(common.BKTableNamePropertyGroup).Insert(ctx, grp); nil != err {
+			blog.Errorf("rollback the group(%s) of the model(%s) failed, err: %v, rid: %s", grp.GroupID, objID, err, ctx.ReqID)
+			return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+		}
+		undo.delete(ctx, common.BKTableNamePropertyGroup, mapstr.MapStr{common.BKFieldID: grp.ID})
+	}
+	for _, item := range diff.ChangedGroups {
+		grp := targetGroups[item.Key]
+		origin := current.group(item.Key)
+		filter := mapstr.MapStr{common.BKFieldID: origin.ID}
+		if err := m.dbProxy.Table(common.BKTableNamePropertyGroup).Update(ctx, filter, schemaRollbackData(grp, item.Changes)); nil != err {
+			blog.Errorf("rollback the group(%s) of the model(%s) failed, err: %v, rid: %s", grp.GroupID, objID, err, ctx.ReqID)
+			return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+		}
+		undo.update(ctx, common.BKTableNamePropertyGroup, filter, schemaRollbackData(origin, item.Changes))
+	}
+
+	targetAttrs := make(map[string]metadata.Attribute, len(target.Schema.Attributes))
+	for _, attr := range target.Schema.Attributes {
+		targetAttrs[target.Schema.AttributeKey(attr)] = attr
+	}
+	now := metadata.Now()
+	for _, attr := range diff.RemovedAttributes {
+		attr.OwnerID = ctx.SupplierAccount
+		attr.LastTime = &now
+		if err := m.dbProxy.Table(common.BKTableNameObjAttDes).Insert(ctx, attr); nil != err {
+			blog.Errorf("rollback the attribute(%s) of the model(%s) failed, err: %v, rid: %s", attr.PropertyID, objID, err, ctx.ReqID)
+			return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+		}
+		undo.delete(ctx, common.BKTableNameObjAttDes, mapstr.MapStr{common.BKFieldID: attr.ID})
+	}
+	for _, item := range diff.ChangedAttributes {
+		attr := targetAttrs[item.Key]
+		origin := current.attribute(item.Key)
+		data := schemaRollbackData(attr, item.Changes)
+		data.Set(metadata.AttributeFieldLastTime, now)
+		filter := mapstr.MapStr{common.BKFieldID: origin.ID}
+		if err := m.dbProxy.Table(common.BKTableNameObjAttDes).Update(ctx, filter, data); nil != err {
+			blog.Errorf("rollback the attribute(%s) of the model(%s) failed, err: %v, rid: %s", attr.PropertyID, objID, err, ctx.ReqID)
+			return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+		}
+		undo.update(ctx, common.BKTableNameObjAttDes, filter, schemaRollbackData(origin, item.Changes))
+	}
+
+	if err := m.rollbackModelUniques(ctx, objID, target.Schema, undo); nil != err {
+		return nil, err
+	}
+
+	return m.createSchemaVersion(ctx, objID, metadata.ModelSchemaActionRollback, target.Version)
+}
+
+// rollbackModelUniques make the uniques of the model the same as the ones of the schema, the attributes
+// must be restored before, so that the property ids in the schema can be mapped to the current key ids.
+func (m *modelManager) rollbackModelUniques(ctx core.ContextParams, objID string, schema metadata.ModelSchema, undo *schemaUndoLog) error {
+
+	current, err := m.loadModelSchema(ctx, objID)
+	if nil != err {
+		return err
+	}
+
+	propertyKeys := make(map[string]uint64, len(current.Attributes))
+	for _, attr := range current.Attributes {
+		propertyKeys[attr.PropertyID] = uint64(attr.ID)
+	}
+	uniqueKey := func(unique metadata.SchemaUnique) string {
+		return fmt.Sprintf("%v:%s", unique.MustCheck, strings.Join(unique.Properties, ","))
+	}
+
+	targets := make(map[string]metadata.ObjectUnique, len(schema.Uniques))
+	for _, unique := range schema.Uniques {
+		targets[uniqueKey(schema.SchemaUnique(unique))] = unique
+	}
+	extras := make([]metadata.ObjectUnique, 0)
+	for _, unique := range current.Uniques {
+		key := uniqueKey(current.SchemaUnique(unique))
+		if _, exist := targets[key]; exist {
+			delete(targets, key)
+			continue
+		}
+		if !unique.Ispre {
+			extras = append(extras, unique)
+		}
+	}
+
+	for _, unique := range targets {
+		keys := make([]metadata.UniqueKey, 0, len(unique.Keys))
+		for _, property := range schema.SchemaUnique(unique).Properties {
+			keys = append(keys, metadata.UniqueKey{Kind: metadata.UniqueKeyKindProperty, ID: propertyKeys[property]})
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+		unique.Keys = keys
+
+		// a model can only have one must check unique, so it is replaced instead of created
+		replaced := false
+		for idx, extra := range extras {
+			if !unique.MustCheck || !extra.MustCheck {
+				continue
+			}
+			update := metadata.UpdateModelAttrUnique{Data: metadata.UpdateUniqueRequest{MustCheck: true, Keys: keys, Metadata: extra.Metadata}}
+			if err := m.modelAttrUnique.updateModelAttrUnique(ctx, objID, extra.ID, update); nil != err {
+				blog.Errorf("rollback the unique(%d) of the model(%s) failed, err: %v, rid: %s", extra.ID, objID, err, ctx.ReqID)
+				return err
+			}
+			undo.update(ctx, common.BKTableNameObjUnique, mapstr.MapStr{common.BKFieldID: extra.ID},
+				mapstr.MapStr{"must_check": extra.MustCheck, "keys": extra.Keys})
+			extras = append(extras[:idx], extras[idx+1:]...)
+			replaced = true
+			break
+		}
+		if replaced {
+			continue
+		}
+
+		create := metadata.CreateModelAttrUnique{Data: metadata.ObjectUnique{ObjID: objID, MustCheck: unique.MustCheck, Keys: keys, Metadata: unique.Metadata}}
+		id, err := m.modelAttrUnique.createModelAttrUnique(ctx, objID, create)
+		if nil != err {
+			blog.Errorf("rollback the unique(%v) of the model(%s) failed, err: %v, rid: %s", keys, objID, err, ctx.ReqID)
+			return err
+		}
+		undo.delete(ctx, common.BKTableNameObjUnique, mapstr.MapStr{common.BKFieldID: id})
+	}
+
+	for _, extra := range extras {
+		if err := m.modelAttrUnique.deleteModelAttrUnique(ctx, objID, extra.ID, metadata.DeleteModelAttrUnique{Metadata: extra.Metadata}); nil != err {
+			blog.Errorf("rollback the unique(%d) of the model(%s) failed, err: %v, rid: %s", extra.ID, objID, err, ctx.ReqID)
+			return err
+		}
+		undo.insert(ctx, common.BKTableNameObjUnique, extra)
+	}
+	return nil
+}
+
+func (m *modelManager) SaveModelSchemaVersion(ctx core.ContextParams, objIDs ...string) {
+	m.saveSchemaVersion(ctx, objIDs...)
+}
+
+// saveSchemaVersion record a new schema version for the models after the structural change, the failure
+// is only logged because the change is already done.
+func (m *modelManager) saveSchemaVersion(ctx core.ContextParams, objIDs ...string) {
+	for _, objID := range util.StrArrayUnique(objIDs) {
+		if _, err := m.createSchemaVersion(ctx, objID, metadata.ModelSchemaActionChange, 0); nil != err {
+			blog.Errorf("save the schema version of the model(%s) failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		}
+	}
+}
+
+// createSchemaVersion snapshot the schema of the model, a change without any structural difference from the
+// latest version is not recorded. The version number is taken again if it has been used by a concurrent change.
+func (m *modelManager) createSchemaVersion(ctx core.ContextParams, objID, action string, rollbackVersion int64) (*metadata.ModelSchemaVersion, error) {
+
+	schema, err := m.loadModelSchema(ctx, objID)
+	if nil != err {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, nil
+	}
+
+	cond := util.SetQueryOwner(mapstr.MapStr{common.BKObjIDField: objID}, ctx.SupplierAccount)
+	for retry := 0; ; retry++ {
+		latest := make([]metadata.ModelSchemaVersion, 0)
+		err = m.dbProxy.Table(common.BKTableNameObjSchemaVersion).Find(cond).Sort("-version").Limit(1).All(ctx, &latest)
+		if nil != err {
+			blog.Errorf("get the latest schema version of the model(%s) failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+			return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+		}
+
+		version := &metadata.ModelSchemaVersion{
+			ObjectID:        objID,
+			Version:         1,
+			Action:          action,
+			RollbackVersion: rollbackVersion,
+			Operator:        ctx.User,
+			OwnerID:         ctx.SupplierAccount,
+			CreateTime:      metadata.Now(),
+			Schema:          schema.ModelSchema,
+		}
+		if len(latest) > 0 {
+			if action == metadata.ModelSchemaActionChange && metadata.DiffModelSchema(latest[0].Schema, schema.ModelSchema).IsEmpty() {
+				return &latest[0], nil
+			}
+			version.Version = latest[0].Version + 1
+		}
+
+		err = m.dbProxy.Table(common.BKTableNameObjSchemaVersion).Insert(ctx, version)
+		if nil == err {
+			return version, nil
+		}
+		if m.dbProxy.IsDuplicatedError(err) && retry < schemaVersionMaxRetry {
+			blog.Warnf("the schema version(%d) of the model(%s) is taken, retry, rid: %s", version.Version, objID, ctx.ReqID)
+			continue
+		}
+		blog.Errorf("save the schema version(%d) of the model(%s) failed, err: %v, rid: %s", version.Version, objID, err, ctx.ReqID)
+		return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+	}
+}
+
+// loadModelSchema returns the current schema of the model, nil if the model is not exist
+func (m *modelManager) loadModelSchema(ctx core.ContextParams, objID string) (*modelSchema, error) {
+
+	cond := util.SetQueryOwner(mapstr.MapStr{common.BKObjIDField: objID}, ctx.SupplierAccount)
+	schema := new(modelSchema)
+
+	objects := make([]metadata.Object, 0)
+	if err := m.dbProxy.Table(common.BKTableNameObjDes).Find(cond).Limit(1).All(ctx, &objects); nil != err {
+		blog.Errorf("get the model(%s) failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+	}
+	if len(objects) == 0 {
+		return nil, nil
+	}
+	schema.Object = objects[0]
+
+	if err := m.dbProxy.Table(common.BKTableNameObjAttDes).Find(cond).Sort(common.BKFieldID).All(ctx, &schema.Attributes); nil != err {
+		blog.Errorf("get the attributes of the model(%s) failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+	}
+	if err := m.dbProxy.Table(common.BKTableNamePropertyGroup).Find(cond).Sort(common.BKFieldID).All(ctx, &schema.Groups); nil != err {
+		blog.Errorf("get the groups of the model(%s) failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+	}
+	if err := m.dbProxy.Table(common.BKTableNameObjUnique).Find(cond).Sort(common.BKFieldID).All(ctx, &schema.Uniques); nil != err {
+		blog.Errorf("get the uniques of the model(%s) failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+	}
+	return schema, nil
+}
+
+// objectIDsByCondition returns the models of the schema items matched by the condition
+func (m *modelManager) objectIDsByCondition(ctx core.ContextParams, tableName string, cond mapstr.MapStr) []string {
+	items := make([]struct {
+		ObjectID string `bson:"bk_obj_id"`
+	}, 0)
+	if err := m.dbProxy.Table(tableName).Find(cond).Fields(common.BKObjIDField).All(ctx, &items); nil != err {
+		blog.Errorf("get the models from %s by the condition(%#v) failed, err: %v, rid: %s", tableName, cond, err, ctx.ReqID)
+		return nil
+	}
+	objIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		objIDs = append(objIDs, item.ObjectID)
+	}
+	return objIDs
+}
+
+type modelSchema struct {
+	metadata.ModelSchema
+}
+
+func (s *modelSchema) attribute(key string) metadata.Attribute {
+	for _, attr := range s.Attributes {
+		if s.AttributeKey(attr) == key {
+			return attr
+		}
+	}
+	return metadata.Attribute{}
+}
+
+func (s *modelSchema) group(key string) metadata.Group {
+	for _, grp := range s.Groups {
+		if s.GroupKey(grp) == key {
+			return grp
+		}
+	}
+	return metadata.Group{}
+}
+
+// schemaUndoLog records how to undo the applied steps of a schema rollback
+type schemaUndoLog struct {
+	db    dal.RDB
+	steps []schemaUndoStep
+}
+
+type schemaUndoStep struct {
+	desc string
+	undo func() error
+}
+
+func (l *schemaUndoLog) add(desc string, undo func() error) {
+	l.steps = append(l.steps, schemaUndoStep{desc: desc, undo: undo})
+}
+
+func (l *schemaUndoLog) insert(ctx core.ContextParams, tableName string, doc interface{}) {
+	l.add(fmt.Sprintf("insert into %s", tableName), func() error {
+		return l.db.Table(tableName).Insert(ctx, doc)
+	})
+}
+
+func (l *schemaUndoLog) update(ctx core.ContextParams, tableName string, filter, data mapstr.MapStr) {
+	if len(data) == 0 {
+		return
+	}
+	l.add(fmt.Sprintf("update %s by %v", tableName, filter), func() error {
+		return l.db.Table(tableName).Update(ctx, filter, data)
+	})
+}
+
+func (l *schemaUndoLog) delete(ctx core.ContextParams, tableName string, filter mapstr.MapStr) {
+	l.add(fmt.Sprintf("delete from %s by %v", tableName, filter), func() error {
+		return l.db.Table(tableName).Delete(ctx, filter)
+	})
+}
+
+// run undo the recorded steps in the reverse order, a failed step is logged and the others are still undone
+func (l *schemaUndoLog) run(ctx core.ContextParams, objID string) {
+	for idx := len(l.steps) - 1; idx >= 0; idx-- {
+		step := l.steps[idx]
+		if err := step.undo(); nil != err {
+			blog.Errorf("undo the rollback step(%s) of the model(%s) failed, err: %v, rid: %s", step.desc, objID, err, ctx.ReqID)
+		}
+	}
+}
+
+// schemaRollbackData returns the data to restore the changed fields of the item
+func schemaRollbackData(item interface{}, changes []metadata.SchemaFieldChange) mapstr.MapStr {
+	values := mapstr.NewFromStruct(item, "field")
+	data := mapstr.New()
+	for _, change := range changes {
+		if schemaIdentityFields[change.Field] {
+			continue
+		}
+		if val, exist := values[change.Field]; exist {
+			data[change.Field] = val
+		}
+	}
+	return data
+}
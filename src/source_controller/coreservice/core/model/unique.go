@@ -19,6 +19,7 @@ import (
 )
 
 type modelAttrUnique struct {
+	model   *modelManager
 	dbProxy dal.RDB
 }
 
@@ -27,6 +28,7 @@ func (m *modelAttrUnique) CreateModelAttrUnique(ctx core.ContextParams, objID st
 	if err != nil {
 		return nil, err
 	}
+	m.model.saveSchemaVersion(ctx, objID)
 	return &metadata.CreateOneDataResult{Created: metadata.CreatedDataResult{ID: id}}, nil
 }
 
@@ -35,6 +37,7 @@ func (m *modelAttrUnique) UpdateModelAttrUnique(ctx core.ContextParams, objID st
 	if err != nil {
 		return nil, err
 	}
+	m.model.saveSchemaVersion(ctx, objID)
 	return &metadata.UpdatedCount{Count: 1}, nil
 }
 
@@ -43,6 +46,7 @@ func (m *modelAttrUnique) DeleteModelAttrUnique(ctx core.ContextParams, objID st
 	if err != nil {
 		return nil, err
 	}
+	m.model.saveSchemaVersion(ctx, objID)
 	return &metadata.DeletedCount{Count: 1}, nil
 }
 
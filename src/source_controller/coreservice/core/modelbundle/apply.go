@@ -0,0 +1,354 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package modelbundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Apply create or update the items in the bundle as the plan shows, nothing is deleted. the created
+// resources are returned rather than registered to the iam here, the ApplyModelBundle of the topo_server
+// registers them after the bundle is applied. the applied items are undone if the bundle fails to be applied,
+// so that the models are never half applied.
+func (m *Manager) Apply(ctx context.Context, ownerID, user string, bundle metadata.ModelBundle) (metadata.ModelBundleApplied, error) {
+	undo := &undoLog{manager: m}
+	result, err := m.apply(ctx, ownerID, user, bundle, undo)
+	if err != nil {
+		if undoErr := undo.run(ctx); undoErr != nil {
+			return result, fmt.Errorf("%v, and undo the applied items failed, err: %v", err, undoErr)
+		}
+		return result, err
+	}
+	return result, nil
+}
+
+func (m *Manager) apply(ctx context.Context, ownerID, user string, bundle metadata.ModelBundle, undo *undoLog) (metadata.ModelBundleApplied, error) {
+	result := metadata.ModelBundleApplied{
+		Created: metadata.ModelBundleCreated{
+			Classifications:    make([]metadata.Classification, 0),
+			Objects:            make([]metadata.Object, 0),
+			Groups:             make([]metadata.Group, 0),
+			Attributes:         make([]metadata.Attribute, 0),
+			UniqueIDs:          make([]int64, 0),
+			AssociationKindIDs: make([]int64, 0),
+		},
+	}
+	current, plan, err := m.plan(ctx, ownerID, bundle)
+	if err != nil {
+		return result, err
+	}
+	result.ModelBundlePlan = plan
+	bundle.Normalize()
+
+	items := make(map[string]metadata.ModelBundlePlanItem, len(plan.Items))
+	for _, item := range plan.Items {
+		items[item.Kind+":"+item.Key] = item
+	}
+	action := func(kind, key string) metadata.ModelBundlePlanItem {
+		return items[kind+":"+key]
+	}
+	now := &metadata.Time{Time: time.Now()}
+
+	for _, cls := range bundle.Classifications {
+		item := action(metadata.ModelBundleKindClassification, cls.ClassificationID)
+		switch item.Action {
+		case metadata.ModelBundleActionCreate:
+			id, err := m.db.NextSequence(ctx, common.BKTableNameObjClassifiction)
+			if err != nil {
+				return result, err
+			}
+			cls.ID, cls.OwnerID, cls.Metadata = int64(id), ownerID, metadata.Metadata{}
+			if err := m.db.Table(common.BKTableNameObjClassifiction).Insert(ctx, cls); err != nil {
+				return result, fmt.Errorf("create classification [%s] failed, err: %v", cls.ClassificationID, err)
+			}
+			undo.created(common.BKTableNameObjClassifiction, cls.ID)
+			result.Created.Classifications = append(result.Created.Classifications, cls)
+		case metadata.ModelBundleActionUpdate:
+			for _, origin := range current.bundle.Classifications {
+				if origin.ClassificationID == cls.ClassificationID {
+					if err := m.update(ctx, common.BKTableNameObjClassifiction, origin.ID, cls, item.Changes); err != nil {
+						return result, fmt.Errorf("update classification [%s] failed, err: %v", cls.ClassificationID, err)
+					}
+					undo.updated(common.BKTableNameObjClassifiction, origin.ID, origin, item.Changes)
+				}
+			}
+		}
+	}
+
+	for _, kind := range bundle.AssociationKinds {
+		item := action(metadata.ModelBundleKindAssociationKind, kind.AssociationKindID)
+		switch item.Action {
+		case metadata.ModelBundleActionCreate:
+			id, err := m.db.NextSequence(ctx, common.BKTableNameAsstDes)
+			if err != nil {
+				return result, err
+			}
+			kind.ID, kind.OwnerID, kind.Metadata = int64(id), ownerID, metadata.Metadata{}
+			if err := m.db.Table(common.BKTableNameAsstDes).Insert(ctx, kind); err != nil {
+				return result, fmt.Errorf("create association kind [%s] failed, err: %v", kind.AssociationKindID, err)
+			}
+			undo.created(common.BKTableNameAsstDes, kind.ID)
+			result.Created.AssociationKindIDs = append(result.Created.AssociationKindIDs, kind.ID)
+		case metadata.ModelBundleActionUpdate:
+			for _, origin := range current.bundle.AssociationKinds {
+				if origin.AssociationKindID == kind.AssociationKindID {
+					if err := m.update(ctx, common.BKTableNameAsstDes, origin.ID, kind, item.Changes); err != nil {
+						return result, fmt.Errorf("update association kind [%s] failed, err: %v", kind.AssociationKindID, err)
+					}
+					undo.updated(common.BKTableNameAsstDes, origin.ID, origin, item.Changes)
+				}
+			}
+		}
+	}
+
+	for _, obj := range bundle.Objects {
+		if err := m.applyObject(ctx, ownerID, user, now, current, obj, action, &result.Created, undo); err != nil {
+			return result, err
+		}
+	}
+
+	for _, asst := range bundle.Associations {
+		item := action(metadata.ModelBundleKindAssociation, asst.AssociationName)
+		switch item.Action {
+		case metadata.ModelBundleActionCreate:
+			id, err := m.db.NextSequence(ctx, common.BKTableNameObjAsst)
+			if err != nil {
+				return result, err
+			}
+			asst.ID, asst.OwnerID, asst.Metadata = int64(id), ownerID, metadata.Metadata{}
+			if err := m.db.Table(common.BKTableNameObjAsst).Insert(ctx, asst); err != nil {
+				return result, fmt.Errorf("create association [%s] failed, err: %v", asst.AssociationName, err)
+			}
+			undo.created(common.BKTableNameObjAsst, asst.ID)
+		case metadata.ModelBundleActionUpdate:
+			for _, origin := range current.bundle.Associations {
+				if origin.AssociationName == asst.AssociationName {
+					if err := m.update(ctx, common.BKTableNameObjAsst, origin.ID, asst, item.Changes); err != nil {
+						return result, fmt.Errorf("update association [%s] failed, err: %v", asst.AssociationName, err)
+					}
+					undo.updated(common.BKTableNameObjAsst, origin.ID, origin, item.Changes)
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (m *Manager) applyObject(ctx context.Context, ownerID, user string, now *metadata.Time, current *state,
+	obj metadata.ModelBundleObject, action func(kind, key string) metadata.ModelBundlePlanItem, created *metadata.ModelBundleCreated, undo *undoLog) error {
+
+	objID := obj.Object.ObjectID
+	origin, _ := current.object(objID)
+
+	item := action(metadata.ModelBundleKindObject, objID)
+	switch item.Action {
+	case metadata.ModelBundleActionCreate:
+		id, err := m.db.NextSequence(ctx, common.BKTableNameObjDes)
+		if err != nil {
+			return err
+		}
+		object := obj.Object
+		object.ID, object.OwnerID, object.Metadata = int64(id), ownerID, metadata.Metadata{}
+		object.Creator, object.Modifier, object.CreateTime, object.LastTime = user, "", now, now
+		if err := m.db.Table(common.BKTableNameObjDes).Insert(ctx, object); err != nil {
+			return fmt.Errorf("create object [%s] failed, err: %v", objID, err)
+		}
+		undo.created(common.BKTableNameObjDes, object.ID)
+		created.Objects = append(created.Objects, object)
+	case metadata.ModelBundleActionUpdate:
+		if err := m.update(ctx, common.BKTableNameObjDes, origin.Object.ID, obj.Object, item.Changes); err != nil {
+			return fmt.Errorf("update object [%s] failed, err: %v", objID, err)
+		}
+		undo.updated(common.BKTableNameObjDes, origin.Object.ID, origin.Object, item.Changes)
+	}
+
+	originGroups := make(map[string]metadata.Group, len(origin.Groups))
+	for _, grp := range origin.Groups {
+		originGroups[grp.GroupID] = grp
+	}
+	for _, grp := range obj.Groups {
+		item := action(metadata.ModelBundleKindGroup, metadata.ModelBundle{}.ObjectKey(objID, grp.GroupID))
+		switch item.Action {
+		case metadata.ModelBundleActionCreate:
+			id, err := m.db.NextSequence(ctx, common.BKTableNamePropertyGroup)
+			if err != nil {
+				return err
+			}
+			grp.ID, grp.OwnerID, grp.Metadata = int64(id), ownerID, metadata.Metadata{}
+			if err := m.db.Table(common.BKTableNamePropertyGroup).Insert(ctx, grp); err != nil {
+				return fmt.Errorf("create group [%s] of object [%s] failed, err: %v", grp.GroupID, objID, err)
+			}
+			undo.created(common.BKTableNamePropertyGroup, grp.ID)
+			created.Groups = append(created.Groups, grp)
+		case metadata.ModelBundleActionUpdate:
+			originGroup := originGroups[grp.GroupID]
+			if err := m.update(ctx, common.BKTableNamePropertyGroup, originGroup.ID, grp, item.Changes); err != nil {
+				return fmt.Errorf("update group [%s] of object [%s] failed, err: %v", grp.GroupID, objID, err)
+			}
+			undo.updated(common.BKTableNamePropertyGroup, originGroup.ID, originGroup, item.Changes)
+		}
+	}
+
+	originAttrs := make(map[string]metadata.Attribute, len(origin.Attributes))
+	attrIDs := make(map[string]int64, len(origin.Attributes))
+	for _, attr := range origin.Attributes {
+		originAttrs[attr.PropertyID] = attr
+		attrIDs[attr.PropertyID] = attr.ID
+	}
+	for _, attr := range obj.Attributes {
+		item := action(metadata.ModelBundleKindAttribute, metadata.ModelBundle{}.ObjectKey(objID, attr.PropertyID))
+		switch item.Action {
+		case metadata.ModelBundleActionCreate:
+			id, err := m.db.NextSequence(ctx, common.BKTableNameObjAttDes)
+			if err != nil {
+				return err
+			}
+			attr.ID, attr.OwnerID, attr.Metadata = int64(id), ownerID, metadata.Metadata{}
+			attr.Creator, attr.CreateTime, attr.LastTime = user, now, now
+			if err := m.db.Table(common.BKTableNameObjAttDes).Insert(ctx, attr); err != nil {
+				return fmt.Errorf("create attribute [%s] of object [%s] failed, err: %v", attr.PropertyID, objID, err)
+			}
+			undo.created(common.BKTableNameObjAttDes, attr.ID)
+			attrIDs[attr.PropertyID] = attr.ID
+			created.Attributes = append(created.Attributes, attr)
+		case metadata.ModelBundleActionUpdate:
+			if err := m.update(ctx, common.BKTableNameObjAttDes, attrIDs[attr.PropertyID], attr, item.Changes); err != nil {
+				return fmt.Errorf("update attribute [%s] of object [%s] failed, err: %v", attr.PropertyID, objID, err)
+			}
+			undo.updated(common.BKTableNameObjAttDes, attrIDs[attr.PropertyID], originAttrs[attr.PropertyID], item.Changes)
+		}
+	}
+
+	// the uniques of the model in the db which are not in the bundle are only part of the plan when their
+	// must check is cleared, and the must check unique is cleared before another one is set.
+	uniques := append([]metadata.SchemaUnique{}, obj.Uniques...)
+	incoming := make(map[string]bool, len(obj.Uniques))
+	for _, unique := range obj.Uniques {
+		incoming[uniqueKey(objID, unique)] = true
+	}
+	for _, unique := range origin.Uniques {
+		if !incoming[uniqueKey(objID, unique)] {
+			unique.MustCheck = false
+			uniques = append(uniques, unique)
+		}
+	}
+	sort.SliceStable(uniques, func(i, j int) bool { return !uniques[i].MustCheck && uniques[j].MustCheck })
+	for _, unique := range uniques {
+		key := uniqueKey(objID, unique)
+		item := action(metadata.ModelBundleKindUnique, key)
+		switch item.Action {
+		case metadata.ModelBundleActionCreate:
+			id, err := m.db.NextSequence(ctx, common.BKTableNameObjUnique)
+			if err != nil {
+				return err
+			}
+			keys := make([]metadata.UniqueKey, 0, len(unique.Properties))
+			for _, propertyID := range unique.Properties {
+				keys = append(keys, metadata.UniqueKey{Kind: metadata.UniqueKeyKindProperty, ID: uint64(attrIDs[propertyID])})
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+			data := metadata.ObjectUnique{ID: id, ObjID: objID, MustCheck: unique.MustCheck, Keys: keys, OwnerID: ownerID, LastTime: *now}
+			if err := m.db.Table(common.BKTableNameObjUnique).Insert(ctx, data); err != nil {
+				return fmt.Errorf("create unique [%s] failed, err: %v", key, err)
+			}
+			undo.created(common.BKTableNameObjUnique, id)
+			created.UniqueIDs = append(created.UniqueIDs, int64(id))
+		case metadata.ModelBundleActionUpdate:
+			cond := map[string]interface{}{common.BKFieldID: current.uniqueIDs[key]}
+			doc := map[string]interface{}{"must_check": unique.MustCheck, common.LastTimeField: now}
+			if err := m.db.Table(common.BKTableNameObjUnique).Update(ctx, cond, doc); err != nil {
+				return fmt.Errorf("update unique [%s] failed, err: %v", key, err)
+			}
+			undo.add(func(ctx context.Context) error {
+				return m.db.Table(common.BKTableNameObjUnique).Update(ctx, cond, map[string]interface{}{"must_check": !unique.MustCheck})
+			})
+		}
+	}
+
+	return nil
+}
+
+// update update the changed fields of the item, the values are taken from the item so that their types are kept
+func (m *Manager) update(ctx context.Context, tableName string, id int64, item interface{}, changes []metadata.SchemaFieldChange) error {
+	data, err := bson.Marshal(item)
+	if err != nil {
+		return err
+	}
+	fields := bson.M{}
+	if err := bson.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	doc := map[string]interface{}{}
+	for _, change := range changes {
+		if value, exist := fields[change.Field]; exist {
+			doc[change.Field] = value
+		}
+	}
+	if len(doc) == 0 {
+		return nil
+	}
+	if _, exist := fields[common.LastTimeField]; exist {
+		doc[common.LastTimeField] = time.Now()
+	}
+	return m.db.Table(tableName).Update(ctx, map[string]interface{}{common.BKFieldID: id}, doc)
+}
+
+// undoLog records how to undo the applied items of the bundle
+type undoLog struct {
+	manager *Manager
+	steps   []func(ctx context.Context) error
+}
+
+func (l *undoLog) add(step func(ctx context.Context) error) {
+	l.steps = append(l.steps, step)
+}
+
+// created the item is undone by deleting it
+func (l *undoLog) created(tableName string, id interface{}) {
+	l.add(func(ctx context.Context) error {
+		return l.manager.db.Table(tableName).Delete(ctx, map[string]interface{}{common.BKFieldID: id})
+	})
+}
+
+// updated the item is undone by restoring the changed fields from the origin one
+func (l *undoLog) updated(tableName string, id int64, origin interface{}, changes []metadata.SchemaFieldChange) {
+	l.add(func(ctx context.Context) error {
+		return l.manager.update(ctx, tableName, id, origin, changes)
+	})
+}
+
+// run undo the applied items in the reverse order, all of the items are tried even if some of them fail
+func (l *undoLog) run(ctx context.Context) error {
+	errs := make([]string, 0)
+	for idx := len(l.steps) - 1; idx >= 0; idx-- {
+		if err := l.steps[idx](ctx); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,410 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package modelbundle exports the model definitions into a portable bundle and applies the bundle
+// to another cmdb. it works on the db directly, so that it can be used by the coreservice and the
+// admin_server command as well.
+package modelbundle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/storage/dal"
+)
+
+// AttributeValidator check the created or updated attribute of the bundle, so that the bundle follows
+// the same rules as the attributes written by the model operations.
+type AttributeValidator func(attribute metadata.Attribute, create bool) error
+
+// Manager export, plan and apply the model bundle
+type Manager struct {
+	db                dal.RDB
+	validateAttribute AttributeValidator
+}
+
+// New create a new model bundle manager
+func New(db dal.RDB, validateAttribute AttributeValidator) *Manager {
+	return &Manager{db: db, validateAttribute: validateAttribute}
+}
+
+// invalidBundleError the bundle can not be applied, the error is caused by the bundle rather than the db
+type invalidBundleError struct {
+	error
+}
+
+// IsInvalidBundle returns whether the error is caused by the bundle
+func IsInvalidBundle(err error) bool {
+	_, ok := err.(invalidBundleError)
+	return ok
+}
+
+// state the model definitions in the db, the ids are kept to update the items
+type state struct {
+	bundle    metadata.ModelBundle
+	uniqueIDs map[string]uint64
+}
+
+func (s *state) object(objID string) (metadata.ModelBundleObject, bool) {
+	for _, obj := range s.bundle.Objects {
+		if obj.Object.ObjectID == objID {
+			return obj, true
+		}
+	}
+	return metadata.ModelBundleObject{}, false
+}
+
+// Export export the models with their classifications and associations between them, the business
+// scoped items, the mainline associations and the custom mainline models are not exported.
+func (m *Manager) Export(ctx context.Context, ownerID string, objIDs []string) (metadata.ModelBundle, error) {
+	objCond := map[string]interface{}{}
+	if len(objIDs) > 0 {
+		objCond[common.BKObjIDField] = map[string]interface{}{common.BKDBIN: objIDs}
+	}
+	objects := make([]metadata.Object, 0)
+	if err := m.find(ctx, common.BKTableNameObjDes, ownerID, objCond, &objects); err != nil {
+		return metadata.ModelBundle{}, err
+	}
+
+	mainlineCond := map[string]interface{}{common.AssociationKindIDField: common.AssociationKindMainline}
+	mainlines := make([]metadata.Association, 0)
+	if err := m.find(ctx, common.BKTableNameObjAsst, ownerID, mainlineCond, &mainlines); err != nil {
+		return metadata.ModelBundle{}, err
+	}
+	mainlineObjects := make(map[string]bool)
+	for _, asst := range mainlines {
+		mainlineObjects[asst.ObjectID] = true
+		mainlineObjects[asst.AsstObjID] = true
+	}
+
+	exportIDs := make([]string, 0, len(objects))
+	clsIDs := make([]string, 0)
+	for _, obj := range objects {
+		if mainlineObjects[obj.ObjectID] && !obj.IsPre {
+			continue
+		}
+		exportIDs = append(exportIDs, obj.ObjectID)
+		clsIDs = append(clsIDs, obj.ObjCls)
+	}
+
+	asstCond := map[string]interface{}{
+		common.BKObjIDField:           map[string]interface{}{common.BKDBIN: exportIDs},
+		common.BKAsstObjIDField:       map[string]interface{}{common.BKDBIN: exportIDs},
+		common.AssociationKindIDField: map[string]interface{}{common.BKDBNE: common.AssociationKindMainline},
+	}
+	assts := make([]metadata.Association, 0)
+	if err := m.find(ctx, common.BKTableNameObjAsst, ownerID, asstCond, &assts); err != nil {
+		return metadata.ModelBundle{}, err
+	}
+	kindIDs := make([]string, 0, len(assts))
+	asstNames := make([]string, 0, len(assts))
+	for _, asst := range assts {
+		kindIDs = append(kindIDs, asst.AsstKindID)
+		asstNames = append(asstNames, asst.AssociationName)
+	}
+
+	current, err := m.load(ctx, ownerID, util.StrArrayUnique(clsIDs), exportIDs, util.StrArrayUnique(kindIDs), asstNames)
+	if err != nil {
+		return metadata.ModelBundle{}, err
+	}
+	return strip(current.bundle), nil
+}
+
+// Plan compare the bundle with the model definitions in the db, and check whether it can be applied
+func (m *Manager) Plan(ctx context.Context, ownerID string, bundle metadata.ModelBundle) (metadata.ModelBundlePlan, error) {
+	_, plan, err := m.plan(ctx, ownerID, bundle)
+	return plan, err
+}
+
+func (m *Manager) plan(ctx context.Context, ownerID string, bundle metadata.ModelBundle) (*state, metadata.ModelBundlePlan, error) {
+	bundle.Normalize()
+	if err := bundle.Validate(); err != nil {
+		return nil, metadata.ModelBundlePlan{}, invalidBundleError{err}
+	}
+
+	clsIDs := make([]string, 0)
+	for _, cls := range bundle.Classifications {
+		clsIDs = append(clsIDs, cls.ClassificationID)
+	}
+	objIDs := make([]string, 0)
+	for _, obj := range bundle.Objects {
+		objIDs = append(objIDs, obj.Object.ObjectID)
+		clsIDs = append(clsIDs, obj.Object.ObjCls)
+	}
+	kindIDs := make([]string, 0)
+	for _, kind := range bundle.AssociationKinds {
+		kindIDs = append(kindIDs, kind.AssociationKindID)
+	}
+	asstNames := make([]string, 0)
+	for _, asst := range bundle.Associations {
+		objIDs = append(objIDs, asst.ObjectID, asst.AsstObjID)
+		kindIDs = append(kindIDs, asst.AsstKindID)
+		asstNames = append(asstNames, asst.AssociationName)
+	}
+
+	current, err := m.load(ctx, ownerID, util.StrArrayUnique(clsIDs), util.StrArrayUnique(objIDs),
+		util.StrArrayUnique(kindIDs), asstNames)
+	if err != nil {
+		return nil, metadata.ModelBundlePlan{}, err
+	}
+	if err := m.checkReferences(ctx, ownerID, current, bundle); err != nil {
+		return nil, metadata.ModelBundlePlan{}, err
+	}
+
+	plan := metadata.PlanModelBundle(current.bundle, bundle)
+	if err := m.checkAttributes(bundle, plan); err != nil {
+		return nil, metadata.ModelBundlePlan{}, err
+	}
+	return current, plan, nil
+}
+
+// checkAttributes validate the attributes to be created or updated, before anything is written
+func (m *Manager) checkAttributes(bundle metadata.ModelBundle, plan metadata.ModelBundlePlan) error {
+	if m.validateAttribute == nil {
+		return nil
+	}
+	actions := make(map[string]string, len(plan.Items))
+	for _, item := range plan.Items {
+		if item.Kind == metadata.ModelBundleKindAttribute {
+			actions[item.Key] = item.Action
+		}
+	}
+	for _, obj := range bundle.Objects {
+		for _, attr := range obj.Attributes {
+			action := actions[bundle.ObjectKey(obj.Object.ObjectID, attr.PropertyID)]
+			if action != metadata.ModelBundleActionCreate && action != metadata.ModelBundleActionUpdate {
+				continue
+			}
+			attr.ObjectID = obj.Object.ObjectID
+			if err := m.validateAttribute(attr, action == metadata.ModelBundleActionCreate); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkReferences check the items referred by the bundle exist either in the bundle or in the db
+func (m *Manager) checkReferences(ctx context.Context, ownerID string, current *state, bundle metadata.ModelBundle) error {
+	classifications := make(map[string]bool)
+	for _, cls := range append(current.bundle.Classifications, bundle.Classifications...) {
+		classifications[cls.ClassificationID] = true
+	}
+	objects := make(map[string]bool)
+	for _, obj := range append(current.bundle.Objects, bundle.Objects...) {
+		objects[obj.Object.ObjectID] = true
+	}
+	kinds := make(map[string]bool)
+	for _, kind := range append(current.bundle.AssociationKinds, bundle.AssociationKinds...) {
+		kinds[kind.AssociationKindID] = true
+	}
+
+	for _, obj := range bundle.Objects {
+		objID := obj.Object.ObjectID
+		if !classifications[obj.Object.ObjCls] {
+			return invalidBundleError{fmt.Errorf("the classification [%s] of object [%s] does not exist", obj.Object.ObjCls, objID)}
+		}
+
+		origin, exist := current.object(objID)
+		if !exist {
+			cond := map[string]interface{}{
+				common.BKObjNameField: obj.Object.ObjectName,
+				common.BKObjIDField:   map[string]interface{}{common.BKDBNE: objID},
+			}
+			cond = util.SetQueryOwner(cond, ownerID)
+			count, err := m.db.Table(common.BKTableNameObjDes).Find(cond).Count(ctx)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return invalidBundleError{fmt.Errorf("the name [%s] of object [%s] is used by another object", obj.Object.ObjectName, objID)}
+			}
+		}
+
+		groups := make(map[string]bool)
+		for _, grp := range append(origin.Groups, obj.Groups...) {
+			groups[grp.GroupID] = true
+		}
+		for _, attr := range obj.Attributes {
+			if !groups[attr.PropertyGroup] {
+				return invalidBundleError{fmt.Errorf("the group [%s] of attribute [%s] of object [%s] does not exist",
+					attr.PropertyGroup, attr.PropertyID, objID)}
+			}
+		}
+	}
+
+	assts := make(map[string]metadata.Association)
+	for _, asst := range current.bundle.Associations {
+		assts[asst.AssociationName] = asst
+	}
+	for _, asst := range bundle.Associations {
+		if !objects[asst.ObjectID] || !objects[asst.AsstObjID] {
+			return invalidBundleError{fmt.Errorf("the object of association [%s] does not exist", asst.AssociationName)}
+		}
+		if !kinds[asst.AsstKindID] {
+			return invalidBundleError{fmt.Errorf("the association kind [%s] of association [%s] does not exist", asst.AsstKindID, asst.AssociationName)}
+		}
+		if asst.AsstKindID == common.AssociationKindMainline {
+			return invalidBundleError{fmt.Errorf("the mainline association [%s] can not be imported", asst.AssociationName)}
+		}
+		origin, exist := assts[asst.AssociationName]
+		if !exist {
+			continue
+		}
+		if origin.ObjectID != asst.ObjectID || origin.AsstObjID != asst.AsstObjID ||
+			origin.AsstKindID != asst.AsstKindID || origin.Mapping != asst.Mapping {
+			return invalidBundleError{fmt.Errorf("only the name and the delete action of the existing association [%s] can be changed",
+				asst.AssociationName)}
+		}
+	}
+
+	return nil
+}
+
+// load load the global model definitions from the db
+func (m *Manager) load(ctx context.Context, ownerID string, clsIDs, objIDs, kindIDs, asstNames []string) (*state, error) {
+	current := &state{
+		bundle: metadata.ModelBundle{
+			Version:          metadata.ModelBundleVersion,
+			Classifications:  make([]metadata.Classification, 0),
+			Objects:          make([]metadata.ModelBundleObject, 0),
+			AssociationKinds: make([]metadata.AssociationKind, 0),
+			Associations:     make([]metadata.Association, 0),
+		},
+		uniqueIDs: make(map[string]uint64),
+	}
+
+	clsCond := map[string]interface{}{common.BKClassificationIDField: map[string]interface{}{common.BKDBIN: clsIDs}}
+	if err := m.find(ctx, common.BKTableNameObjClassifiction, ownerID, clsCond, &current.bundle.Classifications); err != nil {
+		return nil, err
+	}
+
+	objCond := map[string]interface{}{common.BKObjIDField: map[string]interface{}{common.BKDBIN: objIDs}}
+	objects := make([]metadata.Object, 0)
+	if err := m.find(ctx, common.BKTableNameObjDes, ownerID, objCond, &objects); err != nil {
+		return nil, err
+	}
+	groups := make([]metadata.Group, 0)
+	if err := m.find(ctx, common.BKTableNamePropertyGroup, ownerID, objCond, &groups); err != nil {
+		return nil, err
+	}
+	attrs := make([]metadata.Attribute, 0)
+	if err := m.find(ctx, common.BKTableNameObjAttDes, ownerID, objCond, &attrs); err != nil {
+		return nil, err
+	}
+	uniques := make([]metadata.ObjectUnique, 0)
+	if err := m.find(ctx, common.BKTableNameObjUnique, ownerID, objCond, &uniques); err != nil {
+		return nil, err
+	}
+
+	for _, obj := range objects {
+		item := metadata.ModelBundleObject{
+			Object:     obj,
+			Groups:     make([]metadata.Group, 0),
+			Attributes: make([]metadata.Attribute, 0),
+			Uniques:    make([]metadata.SchemaUnique, 0),
+		}
+		for _, grp := range groups {
+			if grp.ObjectID == obj.ObjectID {
+				item.Groups = append(item.Groups, grp)
+			}
+		}
+		for _, attr := range attrs {
+			if attr.ObjectID == obj.ObjectID {
+				item.Attributes = append(item.Attributes, attr)
+			}
+		}
+		schema := metadata.ModelSchema{Attributes: item.Attributes}
+		for _, unique := range uniques {
+			if unique.ObjID != obj.ObjectID {
+				continue
+			}
+			schemaUnique := schema.SchemaUnique(unique)
+			item.Uniques = append(item.Uniques, schemaUnique)
+			current.uniqueIDs[uniqueKey(obj.ObjectID, schemaUnique)] = unique.ID
+		}
+		current.bundle.Objects = append(current.bundle.Objects, item)
+	}
+
+	kindCond := map[string]interface{}{common.AssociationKindIDField: map[string]interface{}{common.BKDBIN: kindIDs}}
+	if err := m.find(ctx, common.BKTableNameAsstDes, ownerID, kindCond, &current.bundle.AssociationKinds); err != nil {
+		return nil, err
+	}
+
+	asstCond := map[string]interface{}{common.AssociationObjAsstIDField: map[string]interface{}{common.BKDBIN: asstNames}}
+	if err := m.find(ctx, common.BKTableNameObjAsst, ownerID, asstCond, &current.bundle.Associations); err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+// find find the global items, the business scoped ones are excluded
+func (m *Manager) find(ctx context.Context, tableName, ownerID string, cond map[string]interface{}, result interface{}) error {
+	cond = util.SetQueryOwner(cond, ownerID)
+	for key, value := range metadata.BizLabelNotExist {
+		cond[key] = value
+	}
+	return m.db.Table(tableName).Find(cond).Sort(common.BKFieldID).All(ctx, result)
+}
+
+// uniqueKey the key of the unique, which is the same as the key of the unique item in the plan
+func uniqueKey(objID string, unique metadata.SchemaUnique) string {
+	return metadata.ModelBundle{}.ObjectKey(objID, strings.Join(unique.Properties, ","))
+}
+
+// strip remove the fields which are not portable between two cmdb
+func strip(bundle metadata.ModelBundle) metadata.ModelBundle {
+	for i := range bundle.Classifications {
+		bundle.Classifications[i].ID = 0
+		bundle.Classifications[i].OwnerID = ""
+		bundle.Classifications[i].Metadata = metadata.Metadata{}
+	}
+	for i := range bundle.Objects {
+		obj := &bundle.Objects[i]
+		obj.Object.ID = 0
+		obj.Object.OwnerID = ""
+		obj.Object.Metadata = metadata.Metadata{}
+		obj.Object.Creator = ""
+		obj.Object.Modifier = ""
+		obj.Object.CreateTime = nil
+		obj.Object.LastTime = nil
+		for j := range obj.Groups {
+			obj.Groups[j].ID = 0
+			obj.Groups[j].OwnerID = ""
+			obj.Groups[j].Metadata = metadata.Metadata{}
+		}
+		for j := range obj.Attributes {
+			obj.Attributes[j].ID = 0
+			obj.Attributes[j].OwnerID = ""
+			obj.Attributes[j].Metadata = metadata.Metadata{}
+			obj.Attributes[j].Creator = ""
+			obj.Attributes[j].CreateTime = nil
+			obj.Attributes[j].LastTime = nil
+		}
+	}
+	for i := range bundle.AssociationKinds {
+		bundle.AssociationKinds[i].ID = 0
+		bundle.AssociationKinds[i].OwnerID = ""
+		bundle.AssociationKinds[i].Metadata = metadata.Metadata{}
+	}
+	for i := range bundle.Associations {
+		bundle.Associations[i].ID = 0
+		bundle.Associations[i].OwnerID = ""
+		bundle.Associations[i].Metadata = metadata.Metadata{}
+	}
+	return bundle
+}
@@ -0,0 +1,195 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recyclebin
+
+import (
+	"time"
+
+	redis "gopkg.in/redis.v5"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/eventclient"
+	"configcenter/src/common/fieldsource"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/storage/dal"
+)
+
+type recycleBin struct {
+	dbProxy   dal.RDB
+	dependent OperationDependences
+	eventCli  eventclient.Client
+}
+
+// New create a new recycle bin instance
+func New(dbProxy dal.RDB, dependent OperationDependences, cache *redis.Client) core.RecycleBinOperation {
+	return &recycleBin{
+		dbProxy:   dbProxy,
+		dependent: dependent,
+		eventCli:  eventclient.NewClientViaRedis(cache, dbProxy),
+	}
+}
+
+// Save move the deleted records into the recycle bin, it should be called before the records are deleted
+func Save(ctx core.ContextParams, db dal.RDB, items ...metadata.RecycleItem) ([]int64, error) {
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		id, err := db.NextSequence(ctx, common.BKTableNameRecycleBin)
+		if nil != err {
+			blog.Errorf("save the recycle item failed, get sequence failed, err: %v, rid: %s", err, ctx.ReqID)
+			return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+		}
+		item.ID = int64(id)
+		item.OwnerID = ctx.SupplierAccount
+		item.Operator = ctx.User
+		item.DeleteTime = metadata.Time{Time: time.Now()}
+		if err := db.Table(common.BKTableNameRecycleBin).Insert(ctx, item); nil != err {
+			blog.Errorf("save the recycle item of %s %d failed, err: %v, rid: %s", item.ObjectID, item.InstID, err, ctx.ReqID)
+			return nil, ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+		}
+		ids = append(ids, item.ID)
+	}
+	return ids, nil
+}
+
+// RecycleInstances save the instances with their associations into the recycle bin before they are deleted
+func (r *recycleBin) RecycleInstances(ctx core.ContextParams, objID string, instIDs []int64) ([]int64, error) {
+	if len(instIDs) == 0 {
+		return []int64{}, nil
+	}
+
+	idField := common.GetInstIDField(objID)
+	instCond := mapstr.MapStr{idField: mapstr.MapStr{common.BKDBIN: instIDs}}
+	if !common.IsInnerModel(objID) {
+		instCond[common.BKObjIDField] = objID
+	}
+	insts := make([]mapstr.MapStr, 0)
+	err := r.dbProxy.Table(common.GetInstTableName(objID)).Find(util.SetQueryOwner(instCond, ctx.SupplierAccount)).All(ctx, &insts)
+	if nil != err {
+		blog.Errorf("recycle the instances %v of %s failed, err: %v, rid: %s", instIDs, objID, err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+
+	asstCond := mapstr.MapStr{common.BKDBOR: []mapstr.MapStr{
+		{common.BKObjIDField: objID, common.BKInstIDField: mapstr.MapStr{common.BKDBIN: instIDs}},
+		{common.BKAsstObjIDField: objID, common.BKAsstInstIDField: mapstr.MapStr{common.BKDBIN: instIDs}},
+	}}
+	assts := make([]mapstr.MapStr, 0)
+	err = r.dbProxy.Table(common.BKTableNameInstAsst).Find(util.SetQueryOwner(asstCond, ctx.SupplierAccount)).All(ctx, &assts)
+	if nil != err {
+		blog.Errorf("recycle the associations of the instances %v of %s failed, err: %v, rid: %s", instIDs, objID, err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+
+	sources, err := fieldsource.FindMany(ctx, r.dbProxy, objID, instIDs)
+	if nil != err {
+		blog.Errorf("recycle the field sources of the instances %v of %s failed, err: %v, rid: %s", instIDs, objID, err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+
+	items := make([]metadata.RecycleItem, 0, len(insts))
+	for _, inst := range insts {
+		instID, err := inst.Int64(idField)
+		if nil != err {
+			blog.Errorf("recycle the instance of %s failed, invalid id, inst: %v, rid: %s", objID, inst, ctx.ReqID)
+			return nil, ctx.Error.Errorf(common.CCErrCommParamsNeedInt, idField)
+		}
+		name, _ := inst.String(common.GetInstNameField(objID))
+		item := metadata.RecycleItem{
+			Kind:         metadata.RecycleKindInstance,
+			ObjectID:     objID,
+			InstID:       instID,
+			InstName:     name,
+			Data:         inst,
+			Associations: instAssociations(assts, objID, instID),
+			FieldSources: sources[instID].Fields,
+		}
+		if objID == common.BKInnerObjIDHost {
+			item.Kind = metadata.RecycleKindHost
+		}
+		items = append(items, item)
+	}
+	return Save(ctx, r.dbProxy, items...)
+}
+
+// instAssociations the associations the instance is on either side of
+func instAssociations(assts []mapstr.MapStr, objID string, instID int64) []mapstr.MapStr {
+	result := make([]mapstr.MapStr, 0)
+	for _, asst := range assts {
+		srcObjID, _ := asst.String(common.BKObjIDField)
+		srcInstID, _ := asst.Int64(common.BKInstIDField)
+		dstObjID, _ := asst.String(common.BKAsstObjIDField)
+		dstInstID, _ := asst.Int64(common.BKAsstInstIDField)
+		if (srcObjID == objID && srcInstID == instID) || (dstObjID == objID && dstInstID == instID) {
+			result = append(result, asst)
+		}
+	}
+	return result
+}
+
+func (r *recycleBin) SearchRecycleItems(ctx core.ContextParams, inputParam metadata.QueryCondition) (*metadata.RecycleItemList, error) {
+	cond := util.SetQueryOwner(inputParam.Condition.ToMapInterface(), ctx.SupplierAccount)
+	count, err := r.dbProxy.Table(common.BKTableNameRecycleBin).Find(cond).Count(ctx)
+	if nil != err {
+		blog.Errorf("search the recycle items failed, cond: %v, err: %v, rid: %s", cond, err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+
+	items := make([]metadata.RecycleItem, 0)
+	finder := r.dbProxy.Table(common.BKTableNameRecycleBin).Find(cond)
+	if len(inputParam.SortArr) == 0 {
+		finder = finder.Sort("-" + common.BKFieldID)
+	}
+	for _, sort := range inputParam.SortArr {
+		field := sort.Field
+		if sort.IsDsc {
+			field = "-" + field
+		}
+		finder = finder.Sort(field)
+	}
+	err = finder.Start(uint64(inputParam.Limit.Offset)).Limit(uint64(inputParam.Limit.Limit)).Fields(inputParam.Fields...).All(ctx, &items)
+	if nil != err {
+		blog.Errorf("search the recycle items failed, cond: %v, err: %v, rid: %s", cond, err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	return &metadata.RecycleItemList{Count: int64(count), Info: items}, nil
+}
+
+func (r *recycleBin) PurgeRecycleItems(ctx core.ContextParams, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	cond := util.SetModOwner(mapstr.MapStr{common.BKFieldID: mapstr.MapStr{common.BKDBIN: ids}}, ctx.SupplierAccount)
+	if err := r.dbProxy.Table(common.BKTableNameRecycleBin).Delete(ctx, cond); nil != err {
+		blog.Errorf("purge the recycle items %v failed, err: %v, rid: %s", ids, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBDeleteFailed)
+	}
+	return nil
+}
+
+// PurgeExpiredRecycleItems delete the records kept longer than the retention days of all the owners
+func (r *recycleBin) PurgeExpiredRecycleItems(ctx core.ContextParams, retentionDays int) error {
+	if retentionDays <= 0 {
+		retentionDays = metadata.DefaultRecycleRetentionDays
+	}
+	expire := time.Now().AddDate(0, 0, -retentionDays)
+	cond := mapstr.MapStr{"delete_time": mapstr.MapStr{common.BKDBLT: expire}}
+	if err := r.dbProxy.Table(common.BKTableNameRecycleBin).Delete(ctx, cond); nil != err {
+		blog.Errorf("purge the expired recycle items before %s failed, err: %v, rid: %s", expire, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBDeleteFailed)
+	}
+	return nil
+}
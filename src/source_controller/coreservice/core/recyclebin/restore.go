@@ -0,0 +1,371 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recyclebin
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/eventclient"
+	"configcenter/src/common/fieldsource"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/source_controller/coreservice/core"
+)
+
+// restoreTask the records written by restoring a recycled record, they are removed again if the restore fails,
+// and the events of them are pushed after the restore is done.
+type restoreTask struct {
+	result   *metadata.RestoreRecycleItemResult
+	inserted []restoredRecord
+	events   []*metadata.EventInst
+}
+
+type restoredRecord struct {
+	tableName string
+	cond      mapstr.MapStr
+}
+
+// RestoreRecycleItem put the recycled record back and remove it from the recycle bin,
+// the associations and host module relations are restored only when their peers still exist
+func (r *recycleBin) RestoreRecycleItem(ctx core.ContextParams, id int64) (*metadata.RestoreRecycleItemResult, error) {
+	cond := util.SetQueryOwner(mapstr.MapStr{common.BKFieldID: id}, ctx.SupplierAccount)
+	items := make([]metadata.RecycleItem, 0)
+	if err := r.dbProxy.Table(common.BKTableNameRecycleBin).Find(cond).All(ctx, &items); nil != err {
+		blog.Errorf("restore the recycle item %d failed, err: %v, rid: %s", id, err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	if len(items) == 0 {
+		return nil, ctx.Error.Error(common.CCErrCommNotFound)
+	}
+
+	task := &restoreTask{result: &metadata.RestoreRecycleItemResult{Item: items[0]}}
+	var err error
+	switch task.result.Item.Kind {
+	case metadata.RecycleKindInstance:
+		err = r.restoreInstance(ctx, task)
+	case metadata.RecycleKindHost:
+		err = r.restoreHost(ctx, task)
+	case metadata.RecycleKindModel:
+		err = r.restoreModel(ctx, task)
+	default:
+		blog.Errorf("restore the recycle item %d failed, unknown kind %s, rid: %s", id, task.result.Item.Kind, ctx.ReqID)
+		return nil, ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, "kind")
+	}
+	if nil != err {
+		r.rollbackRestore(ctx, task)
+		return nil, err
+	}
+
+	if err := r.dbProxy.Table(common.BKTableNameRecycleBin).Delete(ctx, cond); nil != err {
+		blog.Errorf("remove the restored recycle item %d failed, err: %v, rid: %s", id, err, ctx.ReqID)
+		r.rollbackRestore(ctx, task)
+		return nil, ctx.Error.Error(common.CCErrCommDBDeleteFailed)
+	}
+
+	if len(task.events) > 0 {
+		if err := r.eventCli.Push(ctx, task.events...); nil != err {
+			blog.Errorf("push the events of the restored recycle item %d failed, err: %v, rid: %s", id, err, ctx.ReqID)
+		}
+	}
+	return task.result, nil
+}
+
+// insert write the restored records and keep the condition to remove them if the restore fails
+func (r *recycleBin) insert(ctx core.ContextParams, task *restoreTask, tableName string, cond mapstr.MapStr, docs interface{}) error {
+	if err := r.dbProxy.Table(tableName).Insert(ctx, docs); nil != err {
+		return err
+	}
+	task.inserted = append(task.inserted, restoredRecord{tableName: tableName, cond: cond})
+	return nil
+}
+
+// rollbackRestore remove the records written by the failed restore, so that the record is kept only in the recycle bin
+func (r *recycleBin) rollbackRestore(ctx core.ContextParams, task *restoreTask) {
+	for idx := len(task.inserted) - 1; idx >= 0; idx-- {
+		record := task.inserted[idx]
+		if err := r.dbProxy.Table(record.tableName).Delete(ctx, record.cond); nil != err {
+			blog.Errorf("rollback the restored records in %s by %v failed, err: %v, rid: %s", record.tableName, record.cond, err, ctx.ReqID)
+		}
+	}
+}
+
+func (r *recycleBin) restoreInstance(ctx core.ContextParams, task *restoreTask) error {
+	item := task.result.Item
+	modelCond := util.SetQueryOwner(mapstr.MapStr{common.BKObjIDField: item.ObjectID}, ctx.SupplierAccount)
+	cnt, err := r.dbProxy.Table(common.BKTableNameObjDes).Find(modelCond).Count(ctx)
+	if nil != err {
+		blog.Errorf("restore the instance %d of %s failed, err: %v, rid: %s", item.InstID, item.ObjectID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	if cnt == 0 {
+		return ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, common.BKObjIDField)
+	}
+
+	exist, err := r.instanceExist(ctx, item.ObjectID, item.InstID)
+	if nil != err {
+		return err
+	}
+	if exist {
+		return ctx.Error.Errorf(common.CCErrCommDuplicateItem, common.GetInstIDField(item.ObjectID))
+	}
+	// the instances created after the instance was deleted may have the same unique values
+	if err := r.dependent.ValidateInstanceUnique(ctx, item.ObjectID, item.Data); nil != err {
+		blog.Errorf("restore the instance %d of %s failed, validate unique failed, err: %v, rid: %s", item.InstID, item.ObjectID, err, ctx.ReqID)
+		return err
+	}
+	if err := r.insert(ctx, task, common.GetInstTableName(item.ObjectID), r.instanceCond(ctx, item.ObjectID, item.InstID), item.Data); nil != err {
+		blog.Errorf("restore the instance %d of %s failed, err: %v, rid: %s", item.InstID, item.ObjectID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+	}
+	if err := r.restoreFieldSources(ctx, task, item.ObjectID); nil != err {
+		return err
+	}
+	task.events = append(task.events, newRestoreEvent(ctx, metadata.EventTypeInstData, item.ObjectID, item.Data))
+
+	for _, asst := range item.Associations {
+		restore, err := r.canRestoreAssociation(ctx, item, asst)
+		if nil != err {
+			return err
+		}
+		if !restore {
+			task.result.SkippedAssociations++
+			continue
+		}
+		asstCond := util.SetQueryOwner(mapstr.MapStr{common.BKFieldID: asst[common.BKFieldID]}, ctx.SupplierAccount)
+		if err := r.insert(ctx, task, common.BKTableNameInstAsst, asstCond, asst); nil != err {
+			blog.Errorf("restore the association %v failed, err: %v, rid: %s", asst, err, ctx.ReqID)
+			return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+		}
+		task.result.RestoredAssociations++
+	}
+	return nil
+}
+
+// restoreFieldSources put back the sources of the fields of the restored record, the records recycled
+// without the sources are recorded as written by the source of the restore request
+func (r *recycleBin) restoreFieldSources(ctx core.ContextParams, task *restoreTask, objID string) error {
+	item := task.result.Item
+	var err error
+	if len(item.FieldSources) > 0 {
+		err = fieldsource.Set(ctx, r.dbProxy, ctx.SupplierAccount, objID, item.InstID, item.FieldSources)
+	} else {
+		err = fieldsource.Record(ctx, r.dbProxy, ctx.SupplierAccount, objID, []int64{item.InstID}, util.GetDataSource(ctx.Header), ctx.User, item.Data)
+	}
+	if nil != err {
+		blog.Errorf("restore the field sources of %s %d failed, err: %v, rid: %s", objID, item.InstID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+	}
+	cond := mapstr.MapStr{common.BKObjIDField: objID, common.BKInstIDField: item.InstID}
+	task.inserted = append(task.inserted, restoredRecord{tableName: common.BKTableNameInstFieldSource, cond: cond})
+	return nil
+}
+
+// newRestoreEvent the create event of the restored record
+func newRestoreEvent(ctx core.ContextParams, eventType, objType string, data interface{}) *metadata.EventInst {
+	event := eventclient.NewEventWithHeader(ctx.Header)
+	event.EventType = eventType
+	event.ObjType = objType
+	event.Action = metadata.EventActionCreate
+	event.Data = []metadata.EventData{{CurData: data}}
+	return event
+}
+
+// canRestoreAssociation the association can be restored if the model association and the peer instance still exist
+func (r *recycleBin) canRestoreAssociation(ctx core.ContextParams, item metadata.RecycleItem, asst mapstr.MapStr) (bool, error) {
+	objAsstID, _ := asst.String(common.AssociationObjAsstIDField)
+	cond := util.SetQueryOwner(mapstr.MapStr{common.AssociationObjAsstIDField: objAsstID}, ctx.SupplierAccount)
+	cnt, err := r.dbProxy.Table(common.BKTableNameObjAsst).Find(cond).Count(ctx)
+	if nil != err {
+		blog.Errorf("get the model association %s failed, err: %v, rid: %s", objAsstID, err, ctx.ReqID)
+		return false, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	if cnt == 0 {
+		return false, nil
+	}
+
+	peerObjID, _ := asst.String(common.BKAsstObjIDField)
+	peerInstID, _ := asst.Int64(common.BKAsstInstIDField)
+	if peerObjID == item.ObjectID && peerInstID == item.InstID {
+		peerObjID, _ = asst.String(common.BKObjIDField)
+		peerInstID, _ = asst.Int64(common.BKInstIDField)
+	}
+	return r.instanceExist(ctx, peerObjID, peerInstID)
+}
+
+func (r *recycleBin) instanceCond(ctx core.ContextParams, objID string, instID int64) mapstr.MapStr {
+	cond := mapstr.MapStr{common.GetInstIDField(objID): instID}
+	if !common.IsInnerModel(objID) {
+		cond[common.BKObjIDField] = objID
+	}
+	return util.SetQueryOwner(cond, ctx.SupplierAccount)
+}
+
+func (r *recycleBin) instanceExist(ctx core.ContextParams, objID string, instID int64) (bool, error) {
+	cnt, err := r.dbProxy.Table(common.GetInstTableName(objID)).Find(r.instanceCond(ctx, objID, instID)).Count(ctx)
+	if nil != err {
+		blog.Errorf("get the instance %d of %s failed, err: %v, rid: %s", instID, objID, err, ctx.ReqID)
+		return false, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	return cnt > 0, nil
+}
+
+// restoreHost put the host back to the modules still exist, or to the idle module of the resource pool if none of them exist
+func (r *recycleBin) restoreHost(ctx core.ContextParams, task *restoreTask) error {
+	result := task.result
+	item := result.Item
+	exist, err := r.instanceExist(ctx, common.BKInnerObjIDHost, item.InstID)
+	if nil != err {
+		return err
+	}
+	if exist {
+		return ctx.Error.Errorf(common.CCErrCommDuplicateItem, common.BKHostIDField)
+	}
+	// the hosts added after the host was deleted may have the same inner ip and cloud area
+	if err := r.dependent.ValidateInstanceUnique(ctx, common.BKInnerObjIDHost, item.Data); nil != err {
+		blog.Errorf("restore the host %d failed, validate unique failed, err: %v, rid: %s", item.InstID, err, ctx.ReqID)
+		return err
+	}
+	util.SetHostIPKeys(item.Data)
+	hostCond := r.instanceCond(ctx, common.BKInnerObjIDHost, item.InstID)
+	if err := r.insert(ctx, task, common.BKTableNameBaseHost, hostCond, item.Data); nil != err {
+		blog.Errorf("restore the host %d failed, err: %v, rid: %s", item.InstID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+	}
+	if err := r.restoreFieldSources(ctx, task, common.BKInnerObjIDHost); nil != err {
+		return err
+	}
+	task.events = append(task.events, newRestoreEvent(ctx, metadata.EventTypeInstData, common.BKInnerObjIDHost, item.Data))
+
+	relations := make([]mapstr.MapStr, 0)
+	for _, relation := range item.HostRelations {
+		bizID, _ := relation.Int64(common.BKAppIDField)
+		moduleID, _ := relation.Int64(common.BKModuleIDField)
+		cond := util.SetQueryOwner(mapstr.MapStr{common.BKAppIDField: bizID, common.BKModuleIDField: moduleID}, ctx.SupplierAccount)
+		cnt, err := r.dbProxy.Table(common.BKTableNameBaseModule).Find(cond).Count(ctx)
+		if nil != err {
+			blog.Errorf("get the module %d of the host %d failed, err: %v, rid: %s", moduleID, item.InstID, err, ctx.ReqID)
+			return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+		}
+		if cnt == 0 {
+			result.SkippedAssociations++
+			continue
+		}
+		relations = append(relations, relation)
+	}
+	if len(relations) == 0 {
+		relation, err := r.idleModuleRelation(ctx, item.InstID)
+		if nil != err {
+			return err
+		}
+		relations = append(relations, relation)
+	}
+	if err := r.insert(ctx, task, common.BKTableNameModuleHostConfig, hostCond, relations); nil != err {
+		blog.Errorf("restore the module relations of the host %d failed, err: %v, rid: %s", item.InstID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+	}
+	for _, relation := range relations {
+		task.events = append(task.events, newRestoreEvent(ctx, metadata.EventTypeRelation, metadata.EventObjTypeModuleTransfer, relation))
+	}
+	result.RestoredAssociations += len(relations)
+	return nil
+}
+
+// idleModuleRelation the relation between the host and the idle module of the resource pool
+func (r *recycleBin) idleModuleRelation(ctx core.ContextParams, hostID int64) (mapstr.MapStr, error) {
+	bizs := make([]metadata.BizInst, 0)
+	bizCond := util.SetQueryOwner(mapstr.MapStr{common.BKDefaultField: common.DefaultAppFlag}, ctx.SupplierAccount)
+	if err := r.dbProxy.Table(common.BKTableNameBaseApp).Find(bizCond).All(ctx, &bizs); nil != err {
+		blog.Errorf("get the resource pool failed, err: %v, rid: %s", err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	if len(bizs) == 0 {
+		return nil, ctx.Error.Error(common.CCErrCommNotFound)
+	}
+
+	modules := make([]struct {
+		SetID    int64 `bson:"bk_set_id"`
+		ModuleID int64 `bson:"bk_module_id"`
+	}, 0)
+	moduleCond := util.SetQueryOwner(mapstr.MapStr{
+		common.BKAppIDField:   bizs[0].BizID,
+		common.BKDefaultField: common.DefaultResModuleFlag,
+	}, ctx.SupplierAccount)
+	if err := r.dbProxy.Table(common.BKTableNameBaseModule).Find(moduleCond).All(ctx, &modules); nil != err {
+		blog.Errorf("get the idle module of the resource pool failed, err: %v, rid: %s", err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	if len(modules) == 0 {
+		return nil, ctx.Error.Error(common.CCErrCommNotFound)
+	}
+
+	return mapstr.MapStr{
+		common.BKAppIDField:      bizs[0].BizID,
+		common.BKSetIDField:      modules[0].SetID,
+		common.BKModuleIDField:   modules[0].ModuleID,
+		common.BKHostIDField:     hostID,
+		common.BkSupplierAccount: ctx.SupplierAccount,
+	}, nil
+}
+
+// restoreModel put the model back with its groups, attributes and uniques
+func (r *recycleBin) restoreModel(ctx core.ContextParams, task *restoreTask) error {
+	schema := task.result.Item.Schema
+	if schema == nil {
+		return ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, "schema")
+	}
+	objID := schema.Object.ObjectID
+
+	cond := util.SetQueryOwner(mapstr.MapStr{common.BKObjIDField: objID}, ctx.SupplierAccount)
+	cnt, err := r.dbProxy.Table(common.BKTableNameObjDes).Find(cond).Count(ctx)
+	if nil != err {
+		blog.Errorf("restore the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	if cnt > 0 {
+		return ctx.Error.Errorf(common.CCErrCommDuplicateItem, common.BKObjIDField)
+	}
+	clsCond := util.SetQueryOwner(mapstr.MapStr{common.BKClassificationIDField: schema.Object.ObjCls}, ctx.SupplierAccount)
+	cnt, err = r.dbProxy.Table(common.BKTableNameObjClassifiction).Find(clsCond).Count(ctx)
+	if nil != err {
+		blog.Errorf("restore the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	if cnt == 0 {
+		return ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, common.BKClassificationIDField)
+	}
+
+	if err := r.insert(ctx, task, common.BKTableNameObjDes, cond, schema.Object); nil != err {
+		blog.Errorf("restore the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+	}
+	if len(schema.Groups) > 0 {
+		if err := r.insert(ctx, task, common.BKTableNamePropertyGroup, cond, schema.Groups); nil != err {
+			blog.Errorf("restore the groups of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+			return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+		}
+	}
+	if len(schema.Attributes) > 0 {
+		if err := r.insert(ctx, task, common.BKTableNameObjAttDes, cond, schema.Attributes); nil != err {
+			blog.Errorf("restore the attributes of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+			return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+		}
+	}
+	if len(schema.Uniques) > 0 {
+		if err := r.insert(ctx, task, common.BKTableNameObjUnique, cond, schema.Uniques); nil != err {
+			blog.Errorf("restore the uniques of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+			return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+		}
+	}
+	return nil
+}
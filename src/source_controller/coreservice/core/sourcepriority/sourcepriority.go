@@ -0,0 +1,112 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sourcepriority
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/fieldsource"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/storage/dal"
+)
+
+type sourcePriorityManager struct {
+	dbProxy dal.RDB
+}
+
+// New create a new field source priority manager instance
+func New(dbProxy dal.RDB) core.FieldSourceOperation {
+	return &sourcePriorityManager{
+		dbProxy: dbProxy,
+	}
+}
+
+// SetFieldSourcePriority declare or replace the source priority of the model field
+func (m *sourcePriorityManager) SetFieldSourcePriority(ctx core.ContextParams, objID string, priority metadata.FieldSourcePriority) error {
+	priority.ObjectID = objID
+	if err := priority.Validate(); nil != err {
+		blog.Errorf("set the field source priority of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return ctx.Error.New(common.CCErrCommParamsInvalid, err.Error())
+	}
+
+	attrCond := util.SetQueryOwner(mapstr.MapStr{
+		common.BKObjIDField:      objID,
+		common.BKPropertyIDField: priority.PropertyID,
+	}, ctx.SupplierAccount)
+	cnt, err := m.dbProxy.Table(common.BKTableNameObjAttDes).Find(attrCond).Count(ctx)
+	if nil != err {
+		blog.Errorf("set the field source priority of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	if cnt == 0 {
+		return ctx.Error.Errorf(common.CCErrCommParamsIsInvalid, priority.PropertyID)
+	}
+
+	cond := util.SetModOwner(mapstr.MapStr{
+		common.BKObjIDField:      objID,
+		common.BKPropertyIDField: priority.PropertyID,
+	}, ctx.SupplierAccount)
+	priority.OwnerID = ctx.SupplierAccount
+	priority.LastTime = metadata.Now()
+	if err := m.dbProxy.Table(common.BKTableNameFieldSourcePriority).Upsert(ctx, cond, priority); nil != err {
+		blog.Errorf("set the field source priority of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return ctx.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+	}
+	return nil
+}
+
+func (m *sourcePriorityManager) GetFieldSourcePriorities(ctx core.ContextParams, objID string) ([]metadata.FieldSourcePriority, error) {
+	priorities, err := fieldsource.FindPriorities(ctx, m.dbProxy, ctx.SupplierAccount, objID)
+	if nil != err {
+		blog.Errorf("get the field source priorities of the model %s failed, err: %v, rid: %s", objID, err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	result := make([]metadata.FieldSourcePriority, 0, len(priorities))
+	for _, priority := range priorities {
+		result = append(result, priority)
+	}
+	return result, nil
+}
+
+// DeleteFieldSourcePriority remove the source priority of the model field, the field is overwritten by the last writer then
+func (m *sourcePriorityManager) DeleteFieldSourcePriority(ctx core.ContextParams, objID, propertyID string) error {
+	cond := util.SetModOwner(mapstr.MapStr{
+		common.BKObjIDField:      objID,
+		common.BKPropertyIDField: propertyID,
+	}, ctx.SupplierAccount)
+	if err := m.dbProxy.Table(common.BKTableNameFieldSourcePriority).Delete(ctx, cond); nil != err {
+		blog.Errorf("delete the field source priority %s of the model %s failed, err: %v, rid: %s", propertyID, objID, err, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBDeleteFailed)
+	}
+	return nil
+}
+
+// SearchInstFieldSources get the sources which last set the fields of the instance
+func (m *sourcePriorityManager) SearchInstFieldSources(ctx core.ContextParams, objID string, instID int64) (*metadata.InstFieldSource, error) {
+	cond := util.SetQueryOwner(mapstr.MapStr{
+		common.BKObjIDField:  objID,
+		common.BKInstIDField: instID,
+	}, ctx.SupplierAccount)
+	sources := make([]metadata.InstFieldSource, 0)
+	if err := m.dbProxy.Table(common.BKTableNameInstFieldSource).Find(cond).Limit(1).All(ctx, &sources); nil != err {
+		blog.Errorf("search the field sources of the %s instance %d failed, err: %v, rid: %s", objID, instID, err, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	if len(sources) == 0 {
+		return &metadata.InstFieldSource{ObjectID: objID, InstID: instID, Fields: map[string]metadata.FieldSource{}}, nil
+	}
+	return &sources[0], nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/source_controller/coreservice/core"
+)
+
+// CreateAPIKey save a api key issued by apiserver
+func (s *coreService) CreateAPIKey(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	apiKey := metadata.APIKey{}
+	if err := data.MarshalJSONInto(&apiKey); nil != err {
+		blog.Errorf("create api key, but failed to unmarshal the data, data: %+v, err: %s, rid: %s", data, err.Error(), params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommJSONUnmarshalFailed)
+	}
+	if apiKey.KeyHash == "" {
+		return nil, params.Error.Errorf(common.CCErrCommParamsNeedSet, "key_hash")
+	}
+	if apiKey.User == "" {
+		return nil, params.Error.Errorf(common.CCErrCommParamsNeedSet, "bk_username")
+	}
+
+	id, err := s.db.NextSequence(params.Context, common.BKTableNameAPIKey)
+	if err != nil {
+		blog.Errorf("create api key, but get id failed, err: %s, rid: %s", err.Error(), params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommDBInsertFailed)
+	}
+	apiKey.ID = int64(id)
+	apiKey.OwnerID = params.SupplierAccount
+	apiKey.CreateTime = time.Now()
+	if err := s.db.Table(common.BKTableNameAPIKey).Insert(params.Context, apiKey); err != nil {
+		blog.Errorf("create api key, but insert failed, err: %s, rid: %s", err.Error(), params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommDBInsertFailed)
+	}
+	return apiKey, nil
+}
+
+// SearchAPIKey search api key, the super owner can search api key of all owners
+func (s *coreService) SearchAPIKey(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.SearchAPIKeyRequest{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("search api key, but failed to unmarshal the data, data: %+v, err: %s, rid: %s", data, err.Error(), params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommJSONUnmarshalFailed)
+	}
+
+	cond := mapstr.New()
+	if input.ID != 0 {
+		cond.Set("id", input.ID)
+	}
+	if input.AppCode != "" {
+		cond.Set("bk_app_code", input.AppCode)
+	}
+	if input.User != "" {
+		cond.Set("bk_username", input.User)
+	}
+	if input.KeyHash != "" {
+		cond.Set("key_hash", input.KeyHash)
+	}
+	condMap := util.SetQueryOwner(cond, params.SupplierAccount)
+
+	cnt, err := s.db.Table(common.BKTableNameAPIKey).Find(condMap).Count(params.Context)
+	if err != nil {
+		blog.Errorf("search api key, but count failed, err: %s, rid: %s", err.Error(), params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommDBSelectFailed)
+	}
+	sort := input.Page.Sort
+	if sort == "" {
+		sort = "id"
+	}
+	info := make([]metadata.APIKey, 0)
+	err = s.db.Table(common.BKTableNameAPIKey).Find(condMap).Sort(sort).
+		Start(uint64(input.Page.Start)).Limit(uint64(input.Page.Limit)).All(params.Context, &info)
+	if err != nil {
+		blog.Errorf("search api key, but find failed, err: %s, rid: %s", err.Error(), params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommDBSelectFailed)
+	}
+	return mapstr.MapStr{"count": cnt, "info": info}, nil
+}
+
+// DeleteAPIKey revoke a api key
+func (s *coreService) DeleteAPIKey(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	id, err := strconv.ParseInt(pathParams("id"), 10, 64)
+	if err != nil {
+		blog.Errorf("delete api key, but got invalid id: %s, rid: %s", pathParams("id"), params.ReqID)
+		return nil, params.Error.Errorf(common.CCErrCommParamsInvalid, "id")
+	}
+	cond := util.SetModOwner(mapstr.MapStr{"id": id}, params.SupplierAccount)
+	if err := s.db.Table(common.BKTableNameAPIKey).Delete(params.Context, cond); err != nil {
+		blog.Errorf("delete api key, but delete failed, err: %s, rid: %s", err.Error(), params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommDBDeleteFailed)
+	}
+	return nil, nil
+}
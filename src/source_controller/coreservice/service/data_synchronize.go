@@ -97,3 +97,31 @@ func (s *coreService) SetIdentifierFlag(params core.ContextParams, pathParams, q
 	}
 	return nil, nil
 }
+
+func (s *coreService) SearchSynchronizeConflict(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	inputData := &metadata.SynchronizeConflictSearchParameter{}
+	if err := data.MarshalJSONInto(inputData); nil != err {
+		blog.Errorf("SearchSynchronizeConflict MarshalJSONInto error, err:%s,input:%v,rid:%s", err.Error(), data, params.ReqID)
+		return nil, err
+	}
+	info, cnt, err := s.core.DataSynchronizeOperation().SearchConflict(params, inputData)
+	if err != nil {
+		blog.Errorf("SearchSynchronizeConflict error, err:%s,input:%v,rid:%s", err.Error(), data, params.ReqID)
+		return nil, err
+	}
+	return mapstr.MapStr{"info": info, "count": cnt}, nil
+}
+
+func (s *coreService) ResolveSynchronizeConflict(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	inputData := &metadata.SynchronizeConflictResolveParameter{}
+	if err := data.MarshalJSONInto(inputData); nil != err {
+		blog.Errorf("ResolveSynchronizeConflict MarshalJSONInto error, err:%s,input:%v,rid:%s", err.Error(), data, params.ReqID)
+		return nil, err
+	}
+	exceptionArr, err := s.core.DataSynchronizeOperation().ResolveConflict(params, inputData)
+	if err != nil {
+		blog.Errorf("ResolveSynchronizeConflict error, err:%s,input:%v,rid:%s", err.Error(), data, params.ReqID)
+		return metadata.SynchronizeDataResult{Exceptions: exceptionArr}, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,28 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"configcenter/src/common/metadata"
+	"configcenter/src/source_controller/coreservice/core"
+)
+
+// UpdateInstance update the instances of the synchronize conflicts resolved by the source values
+func (s *coreService) UpdateInstance(ctx core.ContextParams, objID string, input metadata.UpdateOption) (*metadata.UpdatedCount, error) {
+	return s.core.InstanceOperation().UpdateModelInstance(ctx, objID, input)
+}
+
+// SaveAuditLog save the audit logs of the synchronize conflicts resolved by the source values
+func (s *coreService) SaveAuditLog(ctx core.ContextParams, logs ...metadata.SaveAuditLogParams) error {
+	return s.core.AuditOperation().CreateAuditLog(ctx, logs...)
+}
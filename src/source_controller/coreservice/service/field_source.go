@@ -0,0 +1,53 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/source_controller/coreservice/core"
+)
+
+// SetFieldSourcePriority declare or replace the source priority of the model field
+func (s *coreService) SetFieldSourcePriority(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.FieldSourcePriority{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("set field source priority failed, decode request body failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommJSONUnmarshalFailed)
+	}
+	return nil, s.core.FieldSourceOperation().SetFieldSourcePriority(params, pathParams(common.BKObjIDField), input)
+}
+
+// GetFieldSourcePriorities get the source priorities of the model fields
+func (s *coreService) GetFieldSourcePriorities(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	return s.core.FieldSourceOperation().GetFieldSourcePriorities(params, pathParams(common.BKObjIDField))
+}
+
+// DeleteFieldSourcePriority remove the source priority of the model field
+func (s *coreService) DeleteFieldSourcePriority(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	return nil, s.core.FieldSourceOperation().DeleteFieldSourcePriority(params, pathParams(common.BKObjIDField), pathParams(common.BKPropertyIDField))
+}
+
+// SearchInstFieldSources get the sources which last set the fields of the instance
+func (s *coreService) SearchInstFieldSources(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	instID, err := strconv.ParseInt(pathParams(common.BKInstIDField), 10, 64)
+	if nil != err {
+		blog.Errorf("search instance field sources failed, invalid instance id %s, rid: %s", pathParams(common.BKInstIDField), params.ReqID)
+		return nil, params.Error.Errorf(common.CCErrCommParamsNeedInt, common.BKInstIDField)
+	}
+	return s.core.FieldSourceOperation().SearchInstFieldSources(params, pathParams(common.BKObjIDField), instID)
+}
@@ -65,15 +65,17 @@ func (s *coreService) AddUserConfig(params core.ContextParams, pathParams, query
 
 	id := xid.New().String()
 	userQuery := meta.UserConfigMeta{
-		AppID:      addQuery.AppID,
-		Info:       addQuery.Info,
-		Name:       addQuery.Name,
-		ID:         id,
-		CreateTime: time.Now().UTC(),
-		CreateUser: addQuery.CreateUser,
-		OwnerID:    params.SupplierAccount,
-		ModifyUser: addQuery.CreateUser,
-		UpdateTime: time.Now().UTC(),
+		AppID:       addQuery.AppID,
+		Info:        addQuery.Info,
+		Name:        addQuery.Name,
+		ObjID:       addQuery.ObjID,
+		Materialize: &addQuery.Materialize,
+		ID:          id,
+		CreateTime:  time.Now().UTC(),
+		CreateUser:  addQuery.CreateUser,
+		OwnerID:     params.SupplierAccount,
+		ModifyUser:  addQuery.CreateUser,
+		UpdateTime:  time.Now().UTC(),
 	}
 
 	err = s.db.Table(common.BKTableNameUserAPI).Insert(params.Context, userQuery)
@@ -225,6 +227,85 @@ func (s *coreService) GetUserConfig(params core.ContextParams, pathParams, query
 	}, nil
 }
 
+// ListDynamicGroupInstances search the instances of the model which match the rules of the dynamic group
+func (s *coreService) ListDynamicGroupInstances(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	option := new(meta.ListDynamicGroupInstances)
+	if err := data.MarshalJSONInto(option); err != nil {
+		blog.Errorf("list dynamic group instances failed, decode body failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommJSONUnmarshalFailed)
+	}
+	if key, err := option.Validate(); err != nil {
+		blog.Errorf("list dynamic group instances failed, invalid option, key: %s, err: %v, rid: %s", key, err, params.ReqID)
+		return nil, params.Error.CCErrorf(common.CCErrCommParamsInvalid, key)
+	}
+
+	ruleFilter, key, err := option.Condition.ToMgo()
+	if err != nil {
+		blog.Errorf("list dynamic group instances failed, invalid condition, key: %s, err: %v, rid: %s", key, err, params.ReqID)
+		return nil, params.Error.CCErrorf(common.CCErrCommParamsInvalid, "condition."+key)
+	}
+	scopeFilter, err := s.dynamicGroupScopeFilter(params, option.ObjID, option.BizID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := util.SetQueryOwner(map[string]interface{}{
+		common.BKDBAND: []map[string]interface{}{scopeFilter, ruleFilter},
+	}, params.SupplierAccount)
+	tableName := common.GetInstTableName(option.ObjID)
+	count, err := s.db.Table(tableName).Find(filter).Count(params.Context)
+	if err != nil {
+		blog.Errorf("list dynamic group instances failed, count failed, filter: %+v, err: %v, rid: %s", filter, err, params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	instances := make([]mapstr.MapStr, 0)
+	query := s.db.Table(tableName).Find(filter).Fields(option.Fields...).Start(uint64(option.Page.Start)).Limit(uint64(option.Page.Limit))
+	if len(option.Page.Sort) > 0 {
+		query = query.Sort(option.Page.Sort)
+	}
+	if err := query.All(params.Context, &instances); err != nil {
+		blog.Errorf("list dynamic group instances failed, filter: %+v, err: %v, rid: %s", filter, err, params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	return meta.DynamicGroupInstances{Count: int(count), Info: instances}, nil
+}
+
+// dynamicGroupScopeFilter the instances of the dynamic group must be in the business
+func (s *coreService) dynamicGroupScopeFilter(params core.ContextParams, objID string, bizID int64) (map[string]interface{}, error) {
+	switch objID {
+	case common.BKInnerObjIDHost:
+		relations := make([]meta.ModuleHost, 0)
+		relationFilter := util.SetQueryOwner(map[string]interface{}{common.BKAppIDField: bizID}, params.SupplierAccount)
+		err := s.db.Table(common.BKTableNameModuleHostConfig).Find(relationFilter).Fields(common.BKHostIDField).All(params.Context, &relations)
+		if err != nil {
+			blog.Errorf("list dynamic group instances failed, get hosts of business %d failed, err: %v, rid: %s", bizID, err, params.ReqID)
+			return nil, params.Error.CCError(common.CCErrCommDBSelectFailed)
+		}
+		hostIDs := make([]int64, 0, len(relations))
+		for _, relation := range relations {
+			hostIDs = append(hostIDs, relation.HostID)
+		}
+		return map[string]interface{}{
+			common.BKHostIDField: map[string]interface{}{common.BKDBIN: util.IntArrayUnique(hostIDs)},
+		}, nil
+
+	case common.BKInnerObjIDApp, common.BKInnerObjIDSet, common.BKInnerObjIDModule, common.BKInnerObjIDProc:
+		return map[string]interface{}{common.BKAppIDField: bizID}, nil
+
+	default:
+		// the instances of the mainline models have the business id, and the others are labeled with it
+		return map[string]interface{}{
+			common.BKObjIDField: objID,
+			common.BKDBOR: []map[string]interface{}{
+				{common.BKAppIDField: bizID},
+				{common.MetadataLabelBiz: strconv.FormatInt(bizID, 10)},
+			},
+		}, nil
+	}
+}
+
 func (s *coreService) UserConfigDetail(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
 	id := pathParams("id")
 	appID, err := strconv.ParseInt(pathParams(common.BKAppIDField), 10, 64)
@@ -0,0 +1,236 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/source_controller/coreservice/core"
+)
+
+var dateBucketFormats = map[string]string{
+	metadata.DateBucketDay:   "%Y-%m-%d",
+	metadata.DateBucketMonth: "%Y-%m",
+	metadata.DateBucketYear:  "%Y",
+}
+
+// dateBucketLengths the prefix lengths of the date strings like 2019-09-01 for the date buckets
+var dateBucketLengths = map[string]int{
+	metadata.DateBucketDay:   len("2006-01-02"),
+	metadata.DateBucketMonth: len("2006-01"),
+	metadata.DateBucketYear:  len("2006"),
+}
+
+// SearchInstanceStatistics group the instances of the model and calculate the metrics of each group
+func (s *coreService) SearchInstanceStatistics(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	objID := pathParams(common.BKObjIDField)
+	option := new(metadata.StatisticsOption)
+	if err := data.MarshalJSONInto(option); err != nil {
+		blog.Errorf("search instance statistics failed, decode body failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommJSONUnmarshalFailed)
+	}
+	if key, err := option.Validate(); err != nil {
+		blog.Errorf("search instance statistics failed, invalid option, key: %s, err: %v, rid: %s", key, err, params.ReqID)
+		return nil, params.Error.CCErrorf(common.CCErrCommParamsInvalid, key)
+	}
+
+	stringDates, err := s.getStringDateFields(params, objID, option)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := buildStatisticsPipeline(objID, option, params.SupplierAccount, stringDates)
+	// the groups are counted before they are limited
+	counts := make([]struct {
+		Count int `bson:"count"`
+	}, 0)
+	countPipeline := make([]map[string]interface{}, 0, len(pipeline)+1)
+	countPipeline = append(countPipeline, pipeline...)
+	countPipeline = append(countPipeline,
+		map[string]interface{}{common.BKDBGroup: map[string]interface{}{"_id": nil, "count": map[string]interface{}{common.BKDBSum: 1}}})
+	if err := s.db.Table(common.GetInstTableName(objID)).AggregateAll(params.Context, countPipeline, &counts); err != nil {
+		blog.Errorf("search instance statistics failed, count pipeline: %+v, err: %v, rid: %s", countPipeline, err, params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	pipeline = append(pipeline,
+		map[string]interface{}{common.BKDBSort: map[string]interface{}{"_id": 1}},
+		map[string]interface{}{common.BKDBLimit: option.Limit},
+	)
+	items := make([]struct {
+		Key     mapstr.MapStr `bson:"_id"`
+		Metrics mapstr.MapStr `bson:",inline"`
+	}, 0)
+	if err := s.db.Table(common.GetInstTableName(objID)).AggregateAll(params.Context, pipeline, &items); err != nil {
+		blog.Errorf("search instance statistics failed, pipeline: %+v, err: %v, rid: %s", pipeline, err, params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	result := metadata.StatisticsResult{Info: make([]metadata.StatisticsGroup, 0, len(items))}
+	if len(counts) > 0 {
+		result.Count = counts[0].Count
+	}
+	for _, item := range items {
+		group := metadata.StatisticsGroup{Key: mapstr.New(), Metrics: mapstr.New()}
+		for idx, field := range option.GroupBy {
+			group.Key[field.Field] = item.Key[groupKey(idx)]
+		}
+		for idx, metric := range option.Metrics {
+			group.Metrics[metric.Name()] = item.Metrics[metricKey(idx)]
+		}
+		result.Info = append(result.Info, group)
+	}
+	return result, nil
+}
+
+// getStringDateFields get the date and time attributes of the date bucket fields, they are saved as strings like
+// 2019-09-01 and 2019-09-01 12:00:00, and the other date bucket fields must be the time fields saved as dates.
+func (s *coreService) getStringDateFields(params core.ContextParams, objID string, option *metadata.StatisticsOption) (map[string]bool, error) {
+	fields := make([]string, 0)
+	for _, field := range option.GroupBy {
+		if field.DateBucket != "" {
+			fields = append(fields, field.Field)
+		}
+	}
+	stringDates := make(map[string]bool)
+	if len(fields) == 0 {
+		return stringDates, nil
+	}
+
+	cond := metadata.QueryCondition{Condition: mapstr.MapStr{
+		common.BKObjIDField:      objID,
+		common.BKPropertyIDField: mapstr.MapStr{common.BKDBIN: fields},
+	}}
+	attrs, err := s.core.ModelOperation().SearchModelAttributes(params, objID, cond)
+	if err != nil {
+		blog.Errorf("search instance statistics failed, search attributes %v failed, err: %v, rid: %s", fields, err, params.ReqID)
+		return nil, err
+	}
+	for _, attr := range attrs.Info {
+		if attr.PropertyType == common.FieldTypeDate || attr.PropertyType == common.FieldTypeTime {
+			stringDates[attr.PropertyID] = true
+		}
+	}
+
+	for idx, field := range option.GroupBy {
+		if field.DateBucket == "" || stringDates[field.Field] {
+			continue
+		}
+		if field.Field != common.CreateTimeField && field.Field != common.LastTimeField {
+			blog.Errorf("search instance statistics failed, %s is not a date or time field, rid: %s", field.Field, params.ReqID)
+			return nil, params.Error.CCErrorf(common.CCErrCommParamsInvalid, "group_by["+strconv.Itoa(idx)+"].date_bucket")
+		}
+	}
+	return stringDates, nil
+}
+
+func groupKey(idx int) string {
+	return "k" + strconv.Itoa(idx)
+}
+
+func metricKey(idx int) string {
+	return "m" + strconv.Itoa(idx)
+}
+
+// buildStatisticsPipeline build the aggregate pipeline of the statistics, the hosts are joined with their relations
+// if they are grouped by the mainline parents, and each host is counted only once in one group. The pipeline ends
+// with the groups, which are not sorted and limited. stringDates are the date bucket fields saved as strings.
+func buildStatisticsPipeline(objID string, option *metadata.StatisticsOption, ownerID string,
+	stringDates map[string]bool) []map[string]interface{} {
+
+	condition := map[string]interface{}{}
+	for key, value := range option.Condition {
+		condition[key] = value
+	}
+	if !util.IsInnerObject(objID) {
+		condition[common.BKObjIDField] = objID
+	}
+	condition = util.SetQueryOwner(util.ConvParamsTime(condition), ownerID)
+	pipeline := []map[string]interface{}{{common.BKDBMatch: condition}}
+
+	joinRelation := false
+	if objID == common.BKInnerObjIDHost {
+		for _, field := range option.GroupBy {
+			if metadata.IsHostMainlineField(field.Field) {
+				joinRelation = true
+			}
+		}
+	}
+
+	groupID := make(map[string]interface{})
+	for idx, field := range option.GroupBy {
+		var expr interface{} = "$" + field.Field
+		if joinRelation && metadata.IsHostMainlineField(field.Field) {
+			expr = "$relation." + field.Field
+		}
+		if stringDates[field.Field] {
+			expr = map[string]interface{}{"$substr": []interface{}{expr, 0, dateBucketLengths[field.DateBucket]}}
+		} else if format, ok := dateBucketFormats[field.DateBucket]; ok {
+			expr = map[string]interface{}{"$dateToString": map[string]interface{}{"format": format, "date": expr}}
+		}
+		groupID[groupKey(idx)] = expr
+	}
+
+	group := map[string]interface{}{"_id": groupID}
+	if joinRelation {
+		pipeline = append(pipeline,
+			map[string]interface{}{common.BKDBLookUp: map[string]interface{}{
+				"from":         common.BKTableNameModuleHostConfig,
+				"localField":   common.BKHostIDField,
+				"foreignField": common.BKHostIDField,
+				"as":           "relation",
+			}},
+			map[string]interface{}{common.BKDBUnwind: "$relation"},
+		)
+
+		// the host in several modules of the group should be counted once
+		hostGroupID := map[string]interface{}{common.BKHostIDField: "$" + common.BKHostIDField}
+		hostGroup := map[string]interface{}{"_id": hostGroupID}
+		for key, expr := range groupID {
+			hostGroupID[key] = expr
+			groupID[key] = "$_id." + key
+		}
+		for idx, metric := range option.Metrics {
+			if metric.Func != metadata.StatisticsFuncCount {
+				hostGroup[metricKey(idx)] = map[string]interface{}{common.BKDBFirst: "$" + metric.Field}
+			}
+		}
+		pipeline = append(pipeline, map[string]interface{}{common.BKDBGroup: hostGroup})
+	}
+
+	for idx, metric := range option.Metrics {
+		source := "$" + metric.Field
+		if joinRelation {
+			source = "$" + metricKey(idx)
+		}
+		switch metric.Func {
+		case metadata.StatisticsFuncCount:
+			group[metricKey(idx)] = map[string]interface{}{common.BKDBSum: 1}
+		case metadata.StatisticsFuncSum:
+			group[metricKey(idx)] = map[string]interface{}{common.BKDBSum: source}
+		case metadata.StatisticsFuncAvg:
+			group[metricKey(idx)] = map[string]interface{}{common.BKDBAvg: source}
+		case metadata.StatisticsFuncMin:
+			group[metricKey(idx)] = map[string]interface{}{common.BKDBMin: source}
+		case metadata.StatisticsFuncMax:
+			group[metricKey(idx)] = map[string]interface{}{common.BKDBMax: source}
+		}
+	}
+
+	return append(pipeline, map[string]interface{}{common.BKDBGroup: group})
+}
@@ -0,0 +1,109 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildStatisticsPipeline(t *testing.T) {
+	count := map[string]interface{}{common.BKDBSum: 1}
+	tests := []struct {
+		name        string
+		objID       string
+		option      metadata.StatisticsOption
+		stringDates map[string]bool
+		// stages the stages after the match
+		stages []map[string]interface{}
+	}{
+		{
+			name:   "count by field",
+			objID:  common.BKInnerObjIDHost,
+			option: metadata.StatisticsOption{GroupBy: []metadata.StatisticsGroupField{{Field: "bk_os_type"}}},
+			stages: []map[string]interface{}{
+				{common.BKDBGroup: map[string]interface{}{
+					"_id": map[string]interface{}{"k0": "$bk_os_type"},
+					"m0":  count,
+				}},
+			},
+		},
+		{
+			name:  "date buckets of date string and time",
+			objID: "switch",
+			option: metadata.StatisticsOption{GroupBy: []metadata.StatisticsGroupField{
+				{Field: "buy_date", DateBucket: metadata.DateBucketMonth},
+				{Field: common.CreateTimeField, DateBucket: metadata.DateBucketYear},
+			}},
+			stringDates: map[string]bool{"buy_date": true},
+			stages: []map[string]interface{}{
+				{common.BKDBGroup: map[string]interface{}{
+					"_id": map[string]interface{}{
+						"k0": map[string]interface{}{"$substr": []interface{}{"$buy_date", 0, 7}},
+						"k1": map[string]interface{}{"$dateToString": map[string]interface{}{"format": "%Y", "date": "$" + common.CreateTimeField}},
+					},
+					"m0": count,
+				}},
+			},
+		},
+		{
+			name:  "hosts by module are counted once",
+			objID: common.BKInnerObjIDHost,
+			option: metadata.StatisticsOption{
+				GroupBy: []metadata.StatisticsGroupField{{Field: common.BKModuleIDField}},
+				Metrics: []metadata.StatisticsMetric{{Func: metadata.StatisticsFuncCount}, {Func: metadata.StatisticsFuncSum, Field: "bk_cpu"}},
+			},
+			stages: []map[string]interface{}{
+				{common.BKDBLookUp: map[string]interface{}{
+					"from":         common.BKTableNameModuleHostConfig,
+					"localField":   common.BKHostIDField,
+					"foreignField": common.BKHostIDField,
+					"as":           "relation",
+				}},
+				{common.BKDBUnwind: "$relation"},
+				{common.BKDBGroup: map[string]interface{}{
+					"_id": map[string]interface{}{common.BKHostIDField: "$" + common.BKHostIDField, "k0": "$relation." + common.BKModuleIDField},
+					"m1":  map[string]interface{}{common.BKDBFirst: "$bk_cpu"},
+				}},
+				{common.BKDBGroup: map[string]interface{}{
+					"_id": map[string]interface{}{"k0": "$_id.k0"},
+					"m0":  count,
+					"m1":  map[string]interface{}{common.BKDBSum: "$m1"},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := tt.option.Validate()
+			require.NoError(t, err, key)
+
+			pipeline := buildStatisticsPipeline(tt.objID, &tt.option, common.BKDefaultOwnerID, tt.stringDates)
+			match, ok := pipeline[0][common.BKDBMatch].(map[string]interface{})
+			require.True(t, ok)
+			require.Contains(t, match, common.BKOwnerIDField)
+			if tt.objID == common.BKInnerObjIDHost {
+				require.NotContains(t, match, common.BKObjIDField)
+			} else {
+				require.Equal(t, tt.objID, match[common.BKObjIDField])
+			}
+			// the groups are neither sorted nor limited, so that they can be counted
+			require.Equal(t, tt.stages, pipeline[1:])
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/source_controller/coreservice/core"
+)
+
+// SetObjectLifecycle declare or replace the lifecycle of the model
+func (s *coreService) SetObjectLifecycle(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.ObjectLifecycle{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("set object lifecycle failed, decode request body failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommJSONUnmarshalFailed)
+	}
+	return nil, s.core.LifecycleOperation().SetObjectLifecycle(params, pathParams(common.BKObjIDField), input)
+}
+
+// GetObjectLifecycle get the lifecycle of the model, nil if the model has no lifecycle
+func (s *coreService) GetObjectLifecycle(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	return s.core.LifecycleOperation().GetObjectLifecycle(params, pathParams(common.BKObjIDField))
+}
+
+// DeleteObjectLifecycle remove the lifecycle of the model, the states kept in the instances are left untouched
+func (s *coreService) DeleteObjectLifecycle(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	return nil, s.core.LifecycleOperation().DeleteObjectLifecycle(params, pathParams(common.BKObjIDField))
+}
+
+// SearchLifecycleHistory search the state transitions of the instances of the model
+func (s *coreService) SearchLifecycleHistory(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.QueryCondition{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("search lifecycle history failed, decode request body failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommJSONUnmarshalFailed)
+	}
+	return s.core.LifecycleOperation().SearchLifecycleHistory(params, pathParams(common.BKObjIDField), input)
+}
@@ -0,0 +1,141 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/storage/dal"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// the host state is aggregated from the db, cache it to avoid the db pressure of frequent scrapes
+const hostStateRefreshInterval = time.Minute
+
+// hostStateCollector collect the number of hosts of each business and module type
+type hostStateCollector struct {
+	db dal.RDB
+
+	hostCount   *prometheus.Desc
+	scrapeError *prometheus.Desc
+
+	lock       sync.Mutex
+	lastScrape time.Time
+	lastErr    error
+	counts     []hostStateCount
+}
+
+type hostStateCount struct {
+	ID struct {
+		BizID   int64 `bson:"biz"`
+		Default int64 `bson:"default"`
+	} `bson:"_id"`
+	Count int64 `bson:"count"`
+}
+
+func newHostStateCollector(db dal.RDB) *hostStateCollector {
+	return &hostStateCollector{
+		db: db,
+		hostCount: prometheus.NewDesc(
+			"cmdb_host_count",
+			"number of hosts in the business by the type of the module they belong to.",
+			[]string{"bk_biz_id", "module_type"}, nil,
+		),
+		scrapeError: prometheus.NewDesc(
+			"cmdb_host_count_scrape_error",
+			"describe whether the last aggregation of the host count is failed.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implement prometheus.Collector interface
+func (c *hostStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hostCount
+	ch <- c.scrapeError
+}
+
+// Collect implement prometheus.Collector interface
+func (c *hostStateCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if time.Since(c.lastScrape) > hostStateRefreshInterval {
+		c.lastScrape = time.Now()
+		c.lastErr = c.refresh()
+	}
+
+	for _, item := range c.counts {
+		ch <- prometheus.MustNewConstMetric(c.hostCount, prometheus.GaugeValue, float64(item.Count),
+			strconv.FormatInt(item.ID.BizID, 10), moduleType(item.ID.Default))
+	}
+	scrapeError := float64(0)
+	if c.lastErr != nil {
+		scrapeError = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.scrapeError, prometheus.GaugeValue, scrapeError)
+}
+
+// refresh count the distinct hosts grouped by the business and the default flag of the module
+func (c *hostStateCollector) refresh() error {
+	pipeline := []mapstr.MapStr{
+		{common.BKDBLookUp: mapstr.MapStr{
+			"from":         common.BKTableNameBaseModule,
+			"localField":   common.BKModuleIDField,
+			"foreignField": common.BKModuleIDField,
+			"as":           "module",
+		}},
+		{common.BKDBUnwind: "$module"},
+		{common.BKDBGroup: mapstr.MapStr{
+			"_id": mapstr.MapStr{
+				"biz":     "$" + common.BKAppIDField,
+				"default": "$module." + common.BKDefaultField,
+				"host":    "$" + common.BKHostIDField,
+			},
+		}},
+		{common.BKDBGroup: mapstr.MapStr{
+			"_id":   mapstr.MapStr{"biz": "$_id.biz", "default": "$_id.default"},
+			"count": mapstr.MapStr{common.BKDBSum: 1},
+		}},
+	}
+
+	counts := make([]hostStateCount, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := c.db.Table(common.BKTableNameModuleHostConfig).AggregateAll(ctx, pipeline, &counts); err != nil {
+		blog.Errorf("aggregate host count for metrics failed, err: %v", err)
+		return err
+	}
+	c.counts = counts
+	return nil
+}
+
+func moduleType(defaultFlag int64) string {
+	switch int(defaultFlag) {
+	case common.DefaultResModuleFlag:
+		return "idle"
+	case common.DefaultFaultModuleFlag:
+		return "fault"
+	case 0:
+		return "normal"
+	default:
+		return strconv.FormatInt(defaultFlag, 10)
+	}
+}
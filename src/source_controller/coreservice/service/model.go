@@ -17,9 +17,11 @@ import (
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
 	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/source_controller/coreservice/core/modelbundle"
 )
 
 func (s *coreService) CreateManyModelClassification(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
@@ -472,3 +474,89 @@ func (s *coreService) DeleteModelAttrUnique(params core.ContextParams, pathParam
 
 	return s.core.ModelOperation().DeleteModelAttrUnique(params, pathParams("bk_obj_id"), id, metadata.DeleteModelAttrUnique{Metadata: inputDatas.Metadata})
 }
+
+func (s *coreService) SearchModelSchemaVersion(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	inputData := metadata.QueryCondition{}
+	if err := data.MarshalJSONInto(&inputData); nil != err {
+		return nil, err
+	}
+	return s.core.ModelOperation().SearchModelSchemaVersion(params, pathParams("bk_obj_id"), inputData)
+}
+
+func (s *coreService) GetModelSchemaVersion(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	version, err := strconv.ParseInt(pathParams("version"), 10, 64)
+	if err != nil {
+		return nil, params.Error.Errorf(common.CCErrCommParamsNeedInt, "version")
+	}
+	return s.core.ModelOperation().GetModelSchemaVersion(params, pathParams("bk_obj_id"), version)
+}
+
+func (s *coreService) RollbackModelSchema(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	version, err := strconv.ParseInt(pathParams("version"), 10, 64)
+	if err != nil {
+		return nil, params.Error.Errorf(common.CCErrCommParamsNeedInt, "version")
+	}
+	return s.core.ModelOperation().RollbackModelSchema(params, pathParams("bk_obj_id"), version)
+}
+
+func (s *coreService) ExportModelBundle(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	inputData := metadata.ExportModelBundleOption{}
+	if err := data.MarshalJSONInto(&inputData); nil != err {
+		return nil, err
+	}
+	bundle, err := modelbundle.New(s.db, nil).Export(params.Context, params.SupplierAccount, inputData.ObjectIDs)
+	if nil != err {
+		blog.Errorf("export the model bundle failed, objects: %v, err: %v, rid: %s", inputData.ObjectIDs, err, params.ReqID)
+		return nil, params.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+	}
+	return bundle, nil
+}
+
+func (s *coreService) PlanModelBundle(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	inputData := metadata.ModelBundle{}
+	if err := data.MarshalJSONInto(&inputData); nil != err {
+		return nil, err
+	}
+	plan, err := s.modelBundleManager(params).Plan(params.Context, params.SupplierAccount, inputData)
+	if nil != err {
+		blog.Errorf("plan the model bundle failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, s.modelBundleError(params, err)
+	}
+	return plan, nil
+}
+
+func (s *coreService) ApplyModelBundle(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	inputData := metadata.ModelBundle{}
+	if err := data.MarshalJSONInto(&inputData); nil != err {
+		return nil, err
+	}
+	result, err := s.modelBundleManager(params).Apply(params.Context, params.SupplierAccount, params.User, inputData)
+	if nil != err {
+		blog.Errorf("apply the model bundle failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, s.modelBundleError(params, err)
+	}
+
+	objIDs := make([]string, 0, len(inputData.Objects))
+	for _, obj := range inputData.Objects {
+		objIDs = append(objIDs, obj.Object.ObjectID)
+	}
+	s.core.ModelOperation().SaveModelSchemaVersion(params, objIDs...)
+	return result, nil
+}
+
+// modelBundleManager returns the bundle manager checking the attributes as the model operations do
+func (s *coreService) modelBundleManager(params core.ContextParams) *modelbundle.Manager {
+	return modelbundle.New(s.db, func(attribute metadata.Attribute, create bool) error {
+		return s.core.ModelOperation().ValidateModelAttribute(params, attribute, create)
+	})
+}
+
+func (s *coreService) modelBundleError(params core.ContextParams, err error) error {
+	if ccErr, ok := err.(errors.CCErrorCoder); ok {
+		return ccErr
+	}
+	if modelbundle.IsInvalidBundle(err) {
+		return params.Error.Errorf(common.CCErrCommParamsInvalid, err.Error())
+	}
+	return params.Error.New(common.CCErrObjectDBOpErrno, err.Error())
+}
@@ -0,0 +1,119 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"net/http"
+
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/openapi"
+)
+
+// routeSchemas the typed request and response of the routes, which are used to generate the api document
+func routeSchemas() openapi.RouteSchemas {
+	schemas := openapi.RouteSchemas{}
+	schemas.Add(http.MethodPost, "/create/associationkind", metadata.CreateAssociationKind{}, metadata.CreatedOneOptionResult{})
+	schemas.Add(http.MethodPost, "/createmany/associationkind", metadata.CreateManyAssociationKind{}, metadata.CreatedManyOptionResult{})
+	schemas.Add(http.MethodPost, "/set/associationkind", metadata.SetAssociationKind{}, metadata.SetOptionResult{})
+	schemas.Add(http.MethodPost, "/setmany/associationkind", metadata.SetManyAssociationKind{}, metadata.SetOptionResult{})
+	schemas.Add(http.MethodPut, "/update/associationkind", metadata.UpdateOption{}, metadata.UpdatedOptionResult{})
+	schemas.Add(http.MethodDelete, "/delete/associationkind", metadata.DeleteOption{}, metadata.DeletedOptionResult{})
+	schemas.Add(http.MethodDelete, "/delete/associationkind/cascade", metadata.DeleteOption{}, metadata.DeletedOptionResult{})
+	schemas.Add(http.MethodPost, "/read/associationkind", metadata.QueryCondition{}, metadata.SearchAssociationTypeResult{})
+	schemas.Add(http.MethodPost, "/create/modelassociation", metadata.CreateModelAssociation{}, metadata.CreatedOneOptionResult{})
+	schemas.Add(http.MethodPost, "/create/mainlinemodelassociation", metadata.CreateModelAssociation{}, metadata.CreatedOneOptionResult{})
+	schemas.Add(http.MethodPost, "/set/modelassociation", metadata.SetModelAssociation{}, metadata.SetOptionResult{})
+	schemas.Add(http.MethodPut, "/update/modelassociation", metadata.UpdateOption{}, metadata.UpdatedOptionResult{})
+	schemas.Add(http.MethodPost, "/read/modelassociation", metadata.QueryCondition{}, metadata.ReadModelAssociationResult{})
+	schemas.Add(http.MethodDelete, "/delete/modelassociation", metadata.DeleteOption{}, metadata.DeletedOptionResult{})
+	schemas.Add(http.MethodDelete, "/delete/modelassociation/cascade", metadata.DeleteOption{}, metadata.DeletedOptionResult{})
+	schemas.Add(http.MethodPost, "/create/instanceassociation", metadata.CreateOneInstanceAssociation{}, metadata.CreatedOneOptionResult{})
+	schemas.Add(http.MethodPost, "/read/instanceassociation", metadata.QueryCondition{}, metadata.ReadInstAssociationResult{})
+	schemas.Add(http.MethodDelete, "/delete/instanceassociation", metadata.DeleteOption{}, metadata.DeletedOptionResult{})
+	schemas.Add(http.MethodPost, "/createmany/model/classification", metadata.CreateManyModelClassifiaction{}, metadata.CreatedManyOptionResult{})
+	schemas.Add(http.MethodPost, "/create/model/classification", metadata.CreateOneModelClassification{}, metadata.CreatedOneOptionResult{})
+	schemas.Add(http.MethodPost, "/setmany/model/classification", metadata.SetManyModelClassification{}, metadata.SetOptionResult{})
+	schemas.Add(http.MethodPost, "/set/model/classification", metadata.SetOneModelClassification{}, metadata.SetOptionResult{})
+	schemas.Add(http.MethodPut, "/update/model/classification", metadata.UpdateOption{}, metadata.UpdatedOptionResult{})
+	schemas.Add(http.MethodDelete, "/delete/model/classification", metadata.DeleteOption{}, metadata.DeletedOptionResult{})
+	schemas.Add(http.MethodDelete, "/delete/model/classification/cascade", metadata.DeleteOption{}, metadata.DeletedOptionResult{})
+	schemas.Add(http.MethodPost, "/read/model/classification", metadata.QueryCondition{}, metadata.ReadModelClassifitionResult{})
+	schemas.Add(http.MethodPost, "/create/model", metadata.CreateModel{}, metadata.CreatedOneOptionResult{})
+	schemas.Add(http.MethodPost, "/set/model", metadata.SetModel{}, metadata.SetOptionResult{})
+	schemas.Add(http.MethodPut, "/update/model", metadata.UpdateOption{}, metadata.UpdatedOptionResult{})
+	schemas.Add(http.MethodDelete, "/delete/model", metadata.DeleteOption{}, metadata.DeletedOptionResult{})
+	schemas.Add(http.MethodDelete, "/delete/model/cascade", metadata.DeleteOption{}, metadata.DeletedOptionResult{})
+	schemas.Add(http.MethodPost, "/read/model", metadata.QueryCondition{}, metadata.ReadModelResult{})
+	schemas.Add(http.MethodPost, "/create/model/{bk_obj_id}/attributes", metadata.CreateModelAttributes{}, metadata.CreatedManyOptionResult{})
+	schemas.Add(http.MethodPut, "/update/model/{bk_obj_id}/attributes", metadata.UpdateOption{}, metadata.UpdatedOptionResult{})
+	schemas.Add(http.MethodPut, "/update/model/attributes", metadata.UpdateOption{}, metadata.UpdatedOptionResult{})
+	schemas.Add(http.MethodPost, "/set/model/{bk_obj_id}/attributes", metadata.SetModelAttributes{}, metadata.SetOptionResult{})
+	schemas.Add(http.MethodDelete, "/delete/model/{bk_obj_id}/attributes", metadata.DeleteOption{}, metadata.DeletedOptionResult{})
+	schemas.Add(http.MethodPost, "/read/model/{bk_obj_id}/attributes", metadata.QueryCondition{}, metadata.ReadModelAttrResult{})
+	schemas.Add(http.MethodPost, "/read/model/attributes", metadata.QueryCondition{}, metadata.ReadModelAttrResult{})
+	schemas.Add(http.MethodPost, "/read/model/{bk_obj_id}/schema/versions", metadata.QueryCondition{}, metadata.ReadModelSchemaVersionResult{})
+	schemas.Add(http.MethodGet, "/read/model/{bk_obj_id}/schema/version/{version}", nil, metadata.ModelSchemaVersionResult{})
+	schemas.Add(http.MethodPost, "/rollback/model/{bk_obj_id}/schema/version/{version}", nil, metadata.ModelSchemaVersionResult{})
+	schemas.Add(http.MethodPost, "/read/model/bundle", metadata.ExportModelBundleOption{}, metadata.ExportModelBundleResult{})
+	schemas.Add(http.MethodPost, "/plan/model/bundle", metadata.ModelBundle{}, metadata.ModelBundlePlanResult{})
+	schemas.Add(http.MethodPost, "/apply/model/bundle", metadata.ModelBundle{}, metadata.ModelBundleApplyResult{})
+	schemas.Add(http.MethodPost, "/update/model/{bk_obj_id}/lifecycle", metadata.ObjectLifecycle{}, metadata.BaseResp{})
+	schemas.Add(http.MethodGet, "/read/model/{bk_obj_id}/lifecycle", nil, metadata.ObjectLifecycleResult{})
+	schemas.Add(http.MethodPost, "/read/model/{bk_obj_id}/lifecycle/history", metadata.QueryCondition{}, metadata.SearchLifecycleHistoryResult{})
+	schemas.Add(http.MethodPost, "/update/model/{bk_obj_id}/field_source/priority", metadata.FieldSourcePriority{}, metadata.BaseResp{})
+	schemas.Add(http.MethodGet, "/read/model/{bk_obj_id}/field_source/priority", nil, metadata.FieldSourcePriorityResult{})
+	schemas.Add(http.MethodGet, "/read/instance/{bk_obj_id}/{bk_inst_id}/field_source", nil, metadata.InstFieldSourceResult{})
+	schemas.Add(http.MethodPost, "/privilege/group/{bk_supplier_account}/search", nil, metadata.PermissionGroupListResult{})
+	schemas.Add(http.MethodGet, "/privilege/group/detail/{bk_supplier_account}/{group_id}", nil, metadata.GroupPriviResult{})
+	schemas.Add(http.MethodGet, "/system/{flag}/{bk_supplier_account}", nil, metadata.PermissionSystemResponse{})
+	schemas.Add(http.MethodPost, "/create/model/{bk_obj_id}/instance", metadata.CreateModelInstance{}, metadata.CreatedOneOptionResult{})
+	schemas.Add(http.MethodPost, "/createmany/model/{bk_obj_id}/instance", metadata.CreateManyModelInstance{}, metadata.CreatedManyOptionResult{})
+	schemas.Add(http.MethodPut, "/update/model/{bk_obj_id}/instance", metadata.UpdateOption{}, metadata.UpdatedOptionResult{})
+	schemas.Add(http.MethodPost, "/read/model/{bk_obj_id}/instances", metadata.QueryCondition{}, metadata.QueryConditionResult{})
+	schemas.Add(http.MethodPost, "/read/model/{bk_obj_id}/instances/statistics", metadata.StatisticsOption{}, metadata.StatisticsInstanceResult{})
+	schemas.Add(http.MethodDelete, "/delete/model/{bk_obj_id}/instance", metadata.DeleteOption{}, metadata.DeletedOptionResult{})
+	schemas.Add(http.MethodDelete, "/delete/model/{bk_obj_id}/instance/cascade", metadata.DeleteOption{}, metadata.DeletedOptionResult{})
+	schemas.Add(http.MethodPost, "/read/auditlog", metadata.QueryInput{}, metadata.AuditQueryResult{})
+	schemas.Add(http.MethodPost, "/create/cloud/sync/task", nil, metadata.Uint64DataResponse{})
+	schemas.Add(http.MethodPost, "/search/cloud/sync/task", nil, metadata.CloudTaskSearch{})
+	schemas.Add(http.MethodPost, "/create/cloud/confirm", nil, metadata.Uint64DataResponse{})
+	schemas.Add(http.MethodPost, "/check/cloud/task/name", nil, metadata.Uint64Response{})
+	schemas.Add(http.MethodPost, "/search/cloud/confirm", nil, metadata.FavoriteResult{})
+	schemas.Add(http.MethodPost, "/create/cloud/sync/history", nil, metadata.Uint64Response{})
+	schemas.Add(http.MethodPost, "/search/cloud/sync/history", nil, metadata.FavoriteResult{})
+	schemas.Add(http.MethodPost, "/search/cloud/confirm/history", nil, metadata.FavoriteResult{})
+	schemas.Add(http.MethodPost, "/set/module/host/relation/inner/module", metadata.TransferHostToInnerModule{}, metadata.OperaterException{})
+	schemas.Add(http.MethodPost, "/set/module/host/relation/module", metadata.HostsModuleRelation{}, metadata.OperaterException{})
+	schemas.Add(http.MethodDelete, "/delete/host/host_module_relations", metadata.RemoveHostsFromModuleOption{}, metadata.OperaterException{})
+	schemas.Add(http.MethodPost, "/set/module/host/relation/cross/business", metadata.TransferHostsCrossBusinessRequest{}, metadata.OperaterException{})
+	schemas.Add(http.MethodDelete, "/delete/host", metadata.DeleteHostRequest{}, metadata.OperaterException{})
+	schemas.Add(http.MethodPost, "/read/module/host/relation", metadata.HostModuleRelationRequest{}, metadata.HostConfig{})
+	schemas.Add(http.MethodPost, "/read/host/indentifier", metadata.SearchHostIdentifierParam{}, metadata.SearchHostIdentifierResult{})
+	schemas.Add(http.MethodGet, "/find/host/{bk_host_id}", nil, metadata.HostInstanceResult{})
+	schemas.Add(http.MethodPost, "/findmany/hosts/search", metadata.QueryInput{}, metadata.GetHostsResult{})
+	schemas.Add(http.MethodGet, "/find/host/snapshot/{bk_host_id}", nil, metadata.GetHostSnapResult{})
+	schemas.Add(http.MethodPost, "/find/host/lock", metadata.HostLockRequest{}, metadata.HostLockResponse{})
+	schemas.Add(http.MethodDelete, "/delete/host/lock", metadata.HostLockRequest{}, metadata.HostLockResponse{})
+	schemas.Add(http.MethodPost, "/findmany/host/lock/search", metadata.QueryHostLockRequest{}, metadata.HostLockQueryResponse{})
+	schemas.Add(http.MethodPost, "/create/userapi", metadata.UserConfig{}, metadata.IDResult{})
+	schemas.Add(http.MethodPost, "/findmany/userapi/search", metadata.QueryInput{}, metadata.GetUserConfigResult{})
+	schemas.Add(http.MethodGet, "/find/userapi/detail/{bk_biz_id}/{id}", nil, metadata.GetUserConfigDetailResult{})
+	schemas.Add(http.MethodPost, "/findmany/userapi/instances", metadata.ListDynamicGroupInstances{}, metadata.ListDynamicGroupInstancesResult{})
+	schemas.Add(http.MethodGet, "/find/usercustom/user/search/{bk_user}", nil, metadata.GetUserCustomResult{})
+	schemas.Add(http.MethodPost, "/find/usercustom/default/search/{bk_user}", nil, metadata.GetUserCustomResult{})
+	schemas.Add(http.MethodPost, "/create/hosts/favorites/{user}", metadata.FavouriteParms{}, metadata.IDResult{})
+	schemas.Add(http.MethodPost, "/findmany/hosts/favorites/search/{user}", metadata.QueryInput{}, metadata.GetHostFavoriteResult{})
+	schemas.Add(http.MethodGet, "/find/hosts/favorites/search/{user}/{id}", nil, metadata.GetHostFavoriteWithIDResult{})
+	schemas.Add(http.MethodPost, "/findmany/meta/hosts/modules/search", metadata.ModuleHostConfigParams{}, metadata.GetHostModuleIDsResult{})
+	return schemas
+}
@@ -0,0 +1,97 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/source_controller/coreservice/core"
+)
+
+// RecycleInstances save the instances into the recycle bin before they are deleted
+func (s *coreService) RecycleInstances(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.RecycleInstancesOption{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("recycle instances failed, decode request body failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommJSONUnmarshalFailed)
+	}
+	return s.core.RecycleBinOperation().RecycleInstances(params, pathParams(common.BKObjIDField), input.InstIDs)
+}
+
+// SearchRecycleItems search the records in the recycle bin
+func (s *coreService) SearchRecycleItems(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.QueryCondition{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("search recycle items failed, decode request body failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommJSONUnmarshalFailed)
+	}
+	return s.core.RecycleBinOperation().SearchRecycleItems(params, input)
+}
+
+// RestoreRecycleItem put the recycled record back
+func (s *coreService) RestoreRecycleItem(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	id, err := strconv.ParseInt(pathParams("id"), 10, 64)
+	if err != nil {
+		blog.Errorf("restore recycle item, but got invalid id: %s, rid: %s", pathParams("id"), params.ReqID)
+		return nil, params.Error.Errorf(common.CCErrCommParamsInvalid, "id")
+	}
+	result, err := s.core.RecycleBinOperation().RestoreRecycleItem(params, id)
+	if nil != err {
+		return nil, err
+	}
+	if result.Item.Kind == metadata.RecycleKindModel {
+		s.core.ModelOperation().SaveModelSchemaVersion(params, result.Item.ObjectID)
+	}
+	return result, nil
+}
+
+// PurgeRecycleItems delete the records in the recycle bin permanently
+func (s *coreService) PurgeRecycleItems(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+	input := metadata.PurgeRecycleItemsOption{}
+	if err := data.MarshalJSONInto(&input); nil != err {
+		blog.Errorf("purge recycle items failed, decode request body failed, err: %v, rid: %s", err, params.ReqID)
+		return nil, params.Error.CCError(common.CCErrCommJSONUnmarshalFailed)
+	}
+	return nil, s.core.RecycleBinOperation().PurgeRecycleItems(params, input.IDs)
+}
+
+// purgeExpiredRecycleItems delete the records kept longer than the retention days periodically
+func (s *coreService) purgeExpiredRecycleItems() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		header := make(http.Header)
+		header.Set(common.BKHTTPCCRequestID, util.GenerateRID())
+		params := core.ContextParams{
+			Context:         util.GetDBContext(context.Background(), header),
+			Header:          header,
+			SupplierAccount: common.BKSuperOwnerID,
+			User:            common.CCSystemOperatorUserName,
+			ReqID:           header.Get(common.BKHTTPCCRequestID),
+			Error:           s.err.CreateDefaultCCErrorIf("en"),
+			Lang:            s.language.CreateDefaultCCLanguageIf("en"),
+		}
+		if err := s.core.RecycleBinOperation().PurgeExpiredRecycleItems(params, s.cfg.RecycleRetentionDays); nil != err {
+			blog.Errorf("purge the expired recycle items failed, err: %v, rid: %s", err, params.ReqID)
+		}
+		<-ticker.C
+	}
+}
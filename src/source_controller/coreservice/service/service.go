@@ -29,8 +29,11 @@ import (
 	"configcenter/src/common/language"
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/openapi"
 	"configcenter/src/common/rdapi"
+	"configcenter/src/common/types"
 	"configcenter/src/common/util"
+	"configcenter/src/common/version"
 	"configcenter/src/source_controller/coreservice/app/options"
 	"configcenter/src/source_controller/coreservice/core"
 	"configcenter/src/source_controller/coreservice/core/association"
@@ -39,9 +42,12 @@ import (
 	"configcenter/src/source_controller/coreservice/core/host"
 	"configcenter/src/source_controller/coreservice/core/instances"
 	"configcenter/src/source_controller/coreservice/core/label"
+	"configcenter/src/source_controller/coreservice/core/lifecycle"
 	"configcenter/src/source_controller/coreservice/core/mainline"
 	"configcenter/src/source_controller/coreservice/core/model"
 	"configcenter/src/source_controller/coreservice/core/process"
+	"configcenter/src/source_controller/coreservice/core/recyclebin"
+	"configcenter/src/source_controller/coreservice/core/sourcepriority"
 	"configcenter/src/storage/dal"
 	"configcenter/src/storage/dal/mongo/local"
 	"configcenter/src/storage/dal/mongo/remote"
@@ -108,6 +114,10 @@ func (s *coreService) SetConfig(cfg options.Config, engin *backbone.Engine, err
 	s.db = db
 	s.cahce = cache
 
+	if regErr := engin.Metric().Registry().Register(newHostStateCollector(db)); regErr != nil {
+		blog.Errorf("register host state metrics failed, err: %v", regErr)
+	}
+
 	// connect the remote mongodb
 	s.core = core.New(
 		model.New(db, s),
@@ -119,7 +129,12 @@ func (s *coreService) SetConfig(cfg options.Config, engin *backbone.Engine, err
 		auditlog.New(db),
 		process.New(db, s),
 		label.New(db),
+		recyclebin.New(db, s, cache),
+		lifecycle.New(db),
+		sourcepriority.New(db),
 	)
+
+	go s.purgeExpiredRecycleItems()
 	return nil
 }
 
@@ -139,16 +154,17 @@ func (s *coreService) WebService() *restful.Container {
 
 	innerActions := s.Actions()
 
+	schemas := routeSchemas()
 	for _, actionItem := range innerActions {
 		switch actionItem.Verb {
 		case http.MethodPost:
-			api.Route(api.POST(actionItem.Path).To(actionItem.Handler))
+			api.Route(schemas.Apply(api.POST(actionItem.Path).To(actionItem.Handler), http.MethodPost, actionItem.Path))
 		case http.MethodDelete:
-			api.Route(api.DELETE(actionItem.Path).To(actionItem.Handler))
+			api.Route(schemas.Apply(api.DELETE(actionItem.Path).To(actionItem.Handler), http.MethodDelete, actionItem.Path))
 		case http.MethodPut:
-			api.Route(api.PUT(actionItem.Path).To(actionItem.Handler))
+			api.Route(schemas.Apply(api.PUT(actionItem.Path).To(actionItem.Handler), http.MethodPut, actionItem.Path))
 		case http.MethodGet:
-			api.Route(api.GET(actionItem.Path).To(actionItem.Handler))
+			api.Route(schemas.Apply(api.GET(actionItem.Path).To(actionItem.Handler), http.MethodGet, actionItem.Path))
 		default:
 			blog.Errorf(" the url (%s), the http method (%s) is not supported", actionItem.Path, actionItem.Verb)
 		}
@@ -158,6 +174,7 @@ func (s *coreService) WebService() *restful.Container {
 
 	healthzAPI := new(restful.WebService).Produces(restful.MIME_JSON)
 	healthzAPI.Route(healthzAPI.GET("/healthz").To(s.Healthz))
+	openapi.AddDocumentRoute(healthzAPI, openapi.Info{Title: types.CC_MODULE_CORESERVICE, Version: version.CCVersion}, container)
 	container.Add(healthzAPI)
 
 	return container
@@ -24,4 +24,6 @@ func (s *coreService) initDataSynchronize() {
 	s.addAction(http.MethodPost, "/read/synchronize", s.SynchronizeFind, nil)
 	s.addAction(http.MethodDelete, "/clear/synchronize/data", s.SynchronizeClearData, nil)
 	s.addAction(http.MethodPost, "/set/synchronize/identifier/flag", s.SetIdentifierFlag, nil)
+	s.addAction(http.MethodPost, "/read/synchronize/conflict", s.SearchSynchronizeConflict, nil)
+	s.addAction(http.MethodPost, "/update/synchronize/conflict/resolve", s.ResolveSynchronizeConflict, nil)
 }
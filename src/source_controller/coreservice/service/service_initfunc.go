@@ -55,6 +55,26 @@ func (s *coreService) initModel() {
 	s.addAction(http.MethodPost, "/read/model/{bk_obj_id}/attributes", s.SearchModelAttributes, nil)
 	s.addAction(http.MethodPost, "/read/model/attributes", s.SearchModelAttributesByCondition, nil)
 
+	// init model schema version methods
+	s.addAction(http.MethodPost, "/read/model/{bk_obj_id}/schema/versions", s.SearchModelSchemaVersion, nil)
+	s.addAction(http.MethodGet, "/read/model/{bk_obj_id}/schema/version/{version}", s.GetModelSchemaVersion, nil)
+	s.addAction(http.MethodPost, "/rollback/model/{bk_obj_id}/schema/version/{version}", s.RollbackModelSchema, nil)
+	s.addAction(http.MethodPost, "/read/model/bundle", s.ExportModelBundle, nil)
+	s.addAction(http.MethodPost, "/plan/model/bundle", s.PlanModelBundle, nil)
+	s.addAction(http.MethodPost, "/apply/model/bundle", s.ApplyModelBundle, nil)
+
+	// init model lifecycle methods
+	s.addAction(http.MethodPost, "/update/model/{bk_obj_id}/lifecycle", s.SetObjectLifecycle, nil)
+	s.addAction(http.MethodGet, "/read/model/{bk_obj_id}/lifecycle", s.GetObjectLifecycle, nil)
+	s.addAction(http.MethodDelete, "/delete/model/{bk_obj_id}/lifecycle", s.DeleteObjectLifecycle, nil)
+	s.addAction(http.MethodPost, "/read/model/{bk_obj_id}/lifecycle/history", s.SearchLifecycleHistory, nil)
+
+	// init field source priority methods
+	s.addAction(http.MethodPost, "/update/model/{bk_obj_id}/field_source/priority", s.SetFieldSourcePriority, nil)
+	s.addAction(http.MethodGet, "/read/model/{bk_obj_id}/field_source/priority", s.GetFieldSourcePriorities, nil)
+	s.addAction(http.MethodDelete, "/delete/model/{bk_obj_id}/field_source/priority/{bk_property_id}", s.DeleteFieldSourcePriority, nil)
+	s.addAction(http.MethodGet, "/read/instance/{bk_obj_id}/{bk_inst_id}/field_source", s.SearchInstFieldSources, nil)
+
 }
 
 func (s *coreService) initAttrUnique() {
@@ -69,6 +89,7 @@ func (s *coreService) initModelInstances() {
 	s.addAction(http.MethodPost, "/createmany/model/{bk_obj_id}/instance", s.CreateManyModelInstances, nil)
 	s.addAction(http.MethodPut, "/update/model/{bk_obj_id}/instance", s.UpdateModelInstances, nil)
 	s.addAction(http.MethodPost, "/read/model/{bk_obj_id}/instances", s.SearchModelInstances, nil)
+	s.addAction(http.MethodPost, "/read/model/{bk_obj_id}/instances/statistics", s.SearchInstanceStatistics, nil)
 	s.addAction(http.MethodDelete, "/delete/model/{bk_obj_id}/instance", s.DeleteModelInstances, nil)
 	s.addAction(http.MethodDelete, "/delete/model/{bk_obj_id}/instance/cascade", s.CascadeDeleteModelInstances, nil)
 }
@@ -134,6 +155,7 @@ func (s *coreService) host() {
 	s.addAction(http.MethodDelete, "/delete/userapi/{bk_biz_id}/{id}", s.DeleteUserConfig, nil)
 	s.addAction(http.MethodPost, "/findmany/userapi/search", s.GetUserConfig, nil)
 	s.addAction(http.MethodGet, "/find/userapi/detail/{bk_biz_id}/{id}", s.UserConfigDetail, nil)
+	s.addAction(http.MethodPost, "/findmany/userapi/instances", s.ListDynamicGroupInstances, nil)
 	s.addAction(http.MethodPost, "/create/usercustom/{bk_user}", s.AddUserCustom, nil)
 	s.addAction(http.MethodPut, "/update/usercustom/{bk_user}/{id}", s.UpdateUserCustomByID, nil)
 	s.addAction(http.MethodGet, "/find/usercustom/user/search/{bk_user}", s.GetUserCustomByUser, nil)
@@ -197,6 +219,19 @@ func (s *coreService) topographics() {
 	s.addAction(http.MethodPost, "/topographics/update", s.UpdateTopoGraphics, nil)
 }
 
+func (s *coreService) apiKey() {
+	s.addAction(http.MethodPost, "/create/apikey", s.CreateAPIKey, nil)
+	s.addAction(http.MethodPost, "/read/apikey", s.SearchAPIKey, nil)
+	s.addAction(http.MethodDelete, "/delete/apikey/{id}", s.DeleteAPIKey, nil)
+}
+
+func (s *coreService) recycleBin() {
+	s.addAction(http.MethodPost, "/create/recycle/instance/{bk_obj_id}", s.RecycleInstances, nil)
+	s.addAction(http.MethodPost, "/read/recycle/items", s.SearchRecycleItems, nil)
+	s.addAction(http.MethodPost, "/restore/recycle/item/{id}", s.RestoreRecycleItem, nil)
+	s.addAction(http.MethodPost, "/delete/recycle/items", s.PurgeRecycleItems, nil)
+}
+
 func (s *coreService) initService() {
 	s.initModelClassification()
 	s.initModel()
@@ -214,4 +249,6 @@ func (s *coreService) initService() {
 	s.label()
 	s.privilege()
 	s.topographics()
+	s.apiKey()
+	s.recycleBin()
 }
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	// "configcenter/src/common/blog"
+	"configcenter/src/common/tracing"
 	"configcenter/src/common/util"
 	"configcenter/src/storage/dal"
 	"configcenter/src/storage/types"
@@ -159,6 +160,7 @@ func (f *Find) Limit(limit uint64) dal.Find {
 // All 查询多个
 func (f *Find) All(ctx context.Context, result interface{}) error {
 	start := time.Now()
+	span := startSpan(ctx, "find", f.collName)
 	f.dbc.Refresh()
 	query := f.dbc.DB(f.dbname).C(f.collName).Find(f.filter)
 	query = query.Select(f.projection)
@@ -166,6 +168,7 @@ func (f *Find) All(ctx context.Context, result interface{}) error {
 	query = query.Limit(int(f.limit))
 	query = query.Sort(f.sort...)
 	err := query.All(result)
+	span.Finish(err)
 
 	rid := ctx.Value(common.ContextRequestIDField)
 	blog.V(5).InfoDepthf(1, "Find all cost %dms, rid: %v", time.Since(start)/time.Millisecond, rid)
@@ -176,10 +179,14 @@ func (f *Find) All(ctx context.Context, result interface{}) error {
 func (f *Find) One(ctx context.Context, result interface{}) error {
 	f.dbc.Refresh()
 	start := time.Now()
+	span := startSpan(ctx, "findOne", f.collName)
 	err := f.dbc.DB(f.dbname).C(f.collName).Find(f.filter).One(result)
 	if err == mgo.ErrNotFound {
 		err = dal.ErrDocumentNotFound
+	} else {
+		span.SetError(err)
 	}
+	span.End()
 	rid := ctx.Value(common.ContextRequestIDField)
 	blog.V(5).InfoDepthf(1, "Find one cost %dms, rid: %v", time.Since(start)/time.Millisecond, rid)
 	return err
@@ -187,21 +194,28 @@ func (f *Find) One(ctx context.Context, result interface{}) error {
 
 // Count 统计数量(非事务)
 func (f *Find) Count(ctx context.Context) (uint64, error) {
+	span := startSpan(ctx, "count", f.collName)
 	count, err := f.dbc.DB(f.dbname).C(f.collName).Find(f.filter).Count()
+	span.Finish(err)
 	return uint64(count), err
 }
 
 // Insert 插入数据, docs 可以为 单个数据 或者 多个数据
 func (c *Collection) Insert(ctx context.Context, docs interface{}) error {
 	c.dbc.Refresh()
-	return c.dbc.DB(c.dbname).C(c.collName).Insert(util.ConverToInterfaceSlice(docs)...)
+	span := startSpan(ctx, "insert", c.collName)
+	err := c.dbc.DB(c.dbname).C(c.collName).Insert(util.ConverToInterfaceSlice(docs)...)
+	span.Finish(err)
+	return err
 }
 
 // Update 更新数据
 func (c *Collection) Update(ctx context.Context, filter dal.Filter, doc interface{}) error {
 	c.dbc.Refresh()
 	data := bson.M{"$set": doc}
+	span := startSpan(ctx, "update", c.collName)
 	_, err := c.dbc.DB(c.dbname).C(c.collName).UpdateAll(filter, data)
+	span.Finish(err)
 	return err
 }
 
@@ -209,7 +223,9 @@ func (c *Collection) Update(ctx context.Context, filter dal.Filter, doc interfac
 func (c *Collection) Upsert(ctx context.Context, filter dal.Filter, doc interface{}) error {
 	c.dbc.Refresh()
 	data := bson.M{"$set": doc}
+	span := startSpan(ctx, "upsert", c.collName)
 	_, err := c.dbc.DB(c.dbname).C(c.collName).Upsert(filter, data)
+	span.Finish(err)
 	return err
 }
 
@@ -224,14 +240,18 @@ func (c *Collection) UpdateMultiModel(ctx context.Context, filter dal.Filter, up
 		data["$"+item.Op] = item.Doc
 	}
 
+	span := startSpan(ctx, "update", c.collName)
 	_, err := c.dbc.DB(c.dbname).C(c.collName).UpdateAll(filter, data)
+	span.Finish(err)
 	return err
 }
 
 // Delete 删除数据
 func (c *Collection) Delete(ctx context.Context, filter dal.Filter) error {
 	c.dbc.Refresh()
+	span := startSpan(ctx, "delete", c.collName)
 	_, err := c.dbc.DB(c.dbname).C(c.collName).RemoveAll(filter)
+	span.Finish(err)
 	return err
 }
 
@@ -250,7 +270,9 @@ func (c *Mongo) NextSequence(ctx context.Context, sequenceName string) (uint64,
 	}
 	doc := Idgen{}
 
+	span := startSpan(ctx, "nextSequence", "cc_idgenerator")
 	_, err := coll.Find(bson.M{"_id": sequenceName}).Apply(change, &doc)
+	span.Finish(err)
 	if err != nil {
 		return 0, err
 	}
@@ -396,10 +418,25 @@ func (c *Collection) DropColumn(ctx context.Context, field string) error {
 
 // AggregateAll aggregate all operation
 func (c *Collection) AggregateAll(ctx context.Context, pipeline interface{}, result interface{}) error {
-	return c.dbc.DB(c.dbname).C(c.collName).Pipe(pipeline).All(result)
+	span := startSpan(ctx, "aggregate", c.collName)
+	err := c.dbc.DB(c.dbname).C(c.collName).Pipe(pipeline).All(result)
+	span.Finish(err)
+	return err
 }
 
 // AggregateOne aggregate one operation
 func (c *Collection) AggregateOne(ctx context.Context, pipeline interface{}, result interface{}) error {
-	return c.dbc.DB(c.dbname).C(c.collName).Pipe(pipeline).One(result)
+	span := startSpan(ctx, "aggregateOne", c.collName)
+	err := c.dbc.DB(c.dbname).C(c.collName).Pipe(pipeline).One(result)
+	span.Finish(err)
+	return err
+}
+
+// startSpan start the span of the db operation, the parent is the span of the request the context belongs to
+func startSpan(ctx context.Context, operation, collName string) *tracing.Span {
+	_, span := tracing.StartSpan(ctx, "mongodb."+operation+" "+collName, tracing.KindClient)
+	span.SetAttribute("db.system", "mongodb")
+	span.SetAttribute("db.operation", operation)
+	span.SetAttribute("db.collection", collName)
+	return span
 }
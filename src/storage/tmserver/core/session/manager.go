@@ -40,6 +40,7 @@ type Manager struct {
 
 	eventChan   chan *types.Transaction
 	subscribers map[chan<- *types.Transaction]bool
+	metrics     *metrics
 
 	ctx          context.Context
 	sessionMutex sync.Mutex
@@ -56,6 +57,7 @@ func New(ctx context.Context, opt options.TransactionConfig, db mongodb.Client,
 
 		eventChan:   make(chan *types.Transaction, 2048),
 		subscribers: map[chan<- *types.Transaction]bool{},
+		metrics:     newMetrics(),
 
 		ctx: ctx,
 	}
@@ -119,6 +121,7 @@ func (tm *Manager) reconcileCache() {
 			for _, session := range tm.cache {
 				if time.Since(session.Txninst.LastTime) > tm.txnLifeLimit {
 					// ignore the abort error, cause the session will not be used again
+					tm.metrics.txnTimeout.Inc()
 					go tm.Abort(session.Txninst.TxnID)
 				}
 			}
@@ -259,6 +262,7 @@ func (tm *Manager) CreateTransaction(requestID string) (*Session, error) {
 	}
 
 	tm.storeSession(txn.TxnID, inst)
+	tm.metrics.observe(&txn)
 
 	return inst, nil
 }
@@ -286,6 +290,7 @@ func (tm *Manager) Commit(txnID string) error {
 	} else {
 		session.Txninst.Status = types.TxStatusCommitted
 	}
+	tm.metrics.observe(session.Txninst)
 	tm.eventChan <- session.Txninst
 
 	tranCond := mongo.NewCondition()
@@ -322,6 +327,7 @@ func (tm *Manager) Abort(txnID string) error {
 	} else {
 		session.Txninst.Status = types.TxStatusAborted
 	}
+	tm.metrics.observe(session.Txninst)
 	tm.eventChan <- session.Txninst
 	tranCond := mongo.NewCondition()
 	tranCond.Element(&mongo.Eq{Key: common.BKTxnIDField, Val: txnID})
@@ -0,0 +1,91 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package session
+
+import (
+	"time"
+
+	"configcenter/src/storage/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	txnTotal    *prometheus.CounterVec
+	txnDuration *prometheus.HistogramVec
+	txnTimeout  prometheus.Counter
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		txnTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "cmdb_txn_total",
+				Help: "number of the transactions by the status.",
+			},
+			[]string{"status"},
+		),
+		txnDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name: "cmdb_txn_duration_seconds",
+				Help: "duration of the transactions from started to committed or aborted.",
+			},
+			[]string{"status"},
+		),
+		txnTimeout: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "cmdb_txn_timeout_total",
+				Help: "number of the transactions aborted because of exceeding the lifetime.",
+			},
+		),
+	}
+}
+
+// RegisterMetrics register the transaction metrics to the registry
+func (tm *Manager) RegisterMetrics(registry prometheus.Registerer) {
+	registry.MustRegister(tm.metrics.txnTotal, tm.metrics.txnDuration, tm.metrics.txnTimeout)
+	registry.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "cmdb_txn_in_progress",
+			Help: "current number of the transactions in progress.",
+		},
+		func() float64 {
+			tm.sessionMutex.Lock()
+			defer tm.sessionMutex.Unlock()
+			return float64(len(tm.cache))
+		},
+	))
+}
+
+func (m *metrics) observe(txn *types.Transaction) {
+	status := statusLabel(txn.Status)
+	m.txnTotal.WithLabelValues(status).Inc()
+	if txn.Status != types.TxStatusOnProgress {
+		m.txnDuration.WithLabelValues(status).Observe(time.Since(txn.CreateTime).Seconds())
+	}
+}
+
+func statusLabel(status types.TxStatus) string {
+	switch status {
+	case types.TxStatusOnProgress:
+		return "started"
+	case types.TxStatusCommitted:
+		return "committed"
+	case types.TxStatusAborted:
+		return "aborted"
+	case types.TxStatusException:
+		return "exception"
+	default:
+		return "unknown"
+	}
+}
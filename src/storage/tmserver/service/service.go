@@ -72,6 +72,7 @@ func (s *coreService) SetConfig(engin *backbone.Engine, db mongodb.Client, txnCf
 	if err != nil {
 		return err
 	}
+	sess.RegisterMetrics(s.engine.Metric().Registry())
 	go func() {
 		if err := sess.Run(); err != nil {
 			blog.Errorf("tmserver stoped with error: %v", err)
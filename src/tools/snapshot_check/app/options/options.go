@@ -32,7 +32,7 @@ func NewServerOption() *ServerOption {
 
 // AddFlags add flags
 func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
-	fs.StringVar(&s.ServConf.RegDiscover, "regdiscv", "", "hosts of register and discover server. e.g: 127.0.0.1:2181")
+	fs.StringVar(&s.ServConf.RegDiscover, "regdiscv", "", "address of register and discover server. e.g: 127.0.0.1:2181, zk://127.0.0.1:2181, file:///data/cmdb/services.yaml or dns://cmdb.svc.cluster.local")
 	s.ServConf.DefaultAppID = *fs.Int("appID", 2, "blueking business id. e.g: 2")
 	s.ServConf.TriggerInterval = *fs.Int("interval", 10, "blueking business id. e.g: 2")
 }
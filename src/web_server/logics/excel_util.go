@@ -305,6 +305,13 @@ func productExcelHealer(ctx context.Context, fields map[string]Property, filter
 		if field.IsRequire {
 			// "(必填)"
 			isRequire = defLang.Language("web_excel_header_required")
+		} else if len(field.RequiredRules) > 0 {
+			// "(xx有值时必填)"
+			isRequire = defLang.Languagef("web_excel_header_required_when", getRequiredRuleFieldNames(field.RequiredRules, fields))
+		}
+		if field.Default != nil {
+			// "(默认值: xx)"
+			isRequire += defLang.Languagef("web_excel_header_default", getDefaultDisplayValue(field))
 		}
 		if util.Contains(filter, field.ID) {
 			continue
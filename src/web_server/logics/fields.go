@@ -41,6 +41,8 @@ type Property struct {
 	IsOnly        bool
 	AsstObjID     string
 	NotExport     bool
+	Default       interface{}
+	RequiredRules []metadata.RequiredRule
 }
 
 // PropertyGroup property group
@@ -236,17 +238,23 @@ func (lgc *Logics) getObjFieldIDsBySort(objID, sort string, header http.Header,
 		fieldIsPre := mapField.IsPre
 		fieldGroup := mapField.PropertyGroup
 		fieldIndex := mapField.PropertyIndex
+		fieldRequiredRules, err := metadata.ParseRequiredRules(mapField.RequiredRules)
+		if err != nil {
+			blog.Warnf("parse required rules of %s failed, err: %v, rid: %s", fieldID, err, rid)
+		}
 
 		ret = append(ret, Property{
-			ID:           fieldID,
-			Name:         fieldName,
-			PropertyType: fieldType,
-			IsRequire:    fieldIsRequire,
-			IsPre:        fieldIsPre,
-			Option:       fieldIsOption,
-			Group:        fieldGroup,
-			Index:        fieldIndex,
-			IsOnly:       fieldIsOnly,
+			ID:            fieldID,
+			Name:          fieldName,
+			PropertyType:  fieldType,
+			IsRequire:     fieldIsRequire,
+			IsPre:         fieldIsPre,
+			Option:        fieldIsOption,
+			Group:         fieldGroup,
+			Index:         fieldIndex,
+			IsOnly:        fieldIsOnly,
+			Default:       mapField.Default,
+			RequiredRules: fieldRequiredRules,
 		})
 	}
 	blog.V(5).Infof("getObjFieldIDsBySort ret count:%d, rid: %s", len(ret), rid)
@@ -169,7 +169,9 @@ func (lgc *Logics) ImportHosts(ctx context.Context, f *xlsx.File, header http.He
 		"bk_supplier_id": common.BKDefaultSupplierID,
 		"input_type":     common.InputTypeExcel,
 	}
-	result, resultErr := lgc.CoreAPI.ApiServer().AddHost(context.Background(), header, params)
+	excelHeader := util.CloneHeader(header)
+	excelHeader.Set(common.BKHTTPDataSource, common.FieldSourceExcel)
+	result, resultErr := lgc.CoreAPI.ApiServer().AddHost(context.Background(), excelHeader, params)
 	if nil != resultErr {
 		blog.Errorf("ImportHosts add host info  http request  error:%s, rid:%s", resultErr.Error(), util.GetHTTPCCRequestID(header))
 		return &metadata.ResponseDataMapStr{
@@ -136,7 +136,9 @@ func (lgc *Logics) ImportInsts(ctx context.Context, f *xlsx.File, objID string,
 	params["input_type"] = common.InputTypeExcel
 	params["BatchInfo"] = insts
 	params[common.MetadataField] = meta
-	result, resultErr := lgc.CoreAPI.ApiServer().AddInst(context.Background(), header, util.GetOwnerID(header), objID, params)
+	excelHeader := util.CloneHeader(header)
+	excelHeader.Set(common.BKHTTPDataSource, common.FieldSourceExcel)
+	result, resultErr := lgc.CoreAPI.ApiServer().AddInst(context.Background(), excelHeader, util.GetOwnerID(header), objID, params)
 	if nil != err {
 		blog.Errorf("ImportInsts add inst info  http request  error:%s, rid:%s", resultErr.Error(), util.GetHTTPCCRequestID(header))
 		return nil, common.CCErrCommHTTPDoRequestFailed, defErr.Error(common.CCErrCommHTTPDoRequestFailed)
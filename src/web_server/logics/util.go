@@ -17,6 +17,7 @@ import (
 	"strings"
 
 	"configcenter/src/common"
+	"configcenter/src/common/metadata"
 
 	"github.com/rentiansheng/xlsx"
 )
@@ -173,3 +174,37 @@ func addExtFields(fields map[string]Property, extFields map[string]string) map[s
 	}
 	return fields
 }
+
+// getRequiredRuleFieldNames returns the names of the fields the conditional required attribute depends on
+func getRequiredRuleFieldNames(rules []metadata.RequiredRule, fields map[string]Property) string {
+	names := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		name := rule.Field
+		if field, ok := fields[rule.Field]; ok {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, "/")
+}
+
+// getDefaultDisplayValue returns the default value of the field as it's filled in excel
+func getDefaultDisplayValue(field Property) string {
+	switch field.PropertyType {
+	case common.FieldTypeEnum:
+		if id, ok := field.Default.(string); ok {
+			if items, ok := field.Option.([]interface{}); ok {
+				if name := getEnumNameByID(id, items); name != "" {
+					return name
+				}
+			}
+			return id
+		}
+	case common.FieldTypeBool:
+		if val, ok := field.Default.(bool); ok && val {
+			return fieldTypeBoolTrue
+		}
+		return fieldTypeBoolFalse
+	}
+	return fmt.Sprint(field.Default)
+}